@@ -0,0 +1,101 @@
+// api/v1alpha1/accessdecision_types.go
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	// DecisionLabel labels AccessDecisions with their decision.
+	DecisionLabel = "netwatch.vtk.io/decision"
+	// RequestLabel labels AccessDecisions with the name of their AccessRequest, when it is a valid label value.
+	RequestLabel = "netwatch.vtk.io/request"
+)
+
+// AccessDecisionSpec records how an access request was decided. It is written once, when the request is decided,
+// and can't be changed afterwards, so it outlives the AccessRequest as its audit record.
+// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="AccessDecisions are immutable"
+type AccessDecisionSpec struct {
+	// Decision is "approved", "denied", "aborted" or "expired". Aborted requests were withdrawn by their requestor,
+	// expired ones deleted because nobody reviewed them in time.
+	// +kubebuilder:validation:Enum=approved;denied;aborted;expired
+	Decision string `json:"decision"`
+	// Request is the name of the AccessRequest decided.
+	Request string `json:"request"`
+	// DecidedBy is who approved, denied or aborted the request, its approvers comma-separated when it needed several,
+	// the auto-approval rule which approved it, or "netwatch" when it expired.
+	DecidedBy string `json:"decidedBy"`
+	// Comment is the approval comment, or the reason of a denial or abort.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+	// Changes lists how the approver narrowed the request before approving it.
+	// +optional
+	Changes []string `json:"changes,omitempty"`
+	// SubmittedAt is when the request was submitted.
+	SubmittedAt metav1.Time `json:"submittedAt"`
+	// DecidedAt is when the request was decided.
+	DecidedAt metav1.Time `json:"decidedAt"`
+	// AccessRequest is the request as it was decided, with the changes of its approver.
+	AccessRequest AccessRequestSpec `json:"accessRequest"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=ad
+//+kubebuilder:printcolumn:name="Decision",type=string,JSONPath=`.spec.decision`
+//+kubebuilder:printcolumn:name="Requestor",type=string,JSONPath=`.spec.accessRequest.requestor`
+//+kubebuilder:printcolumn:name="Decided By",type=string,JSONPath=`.spec.decidedBy`
+//+kubebuilder:printcolumn:name="Decided At",type=date,JSONPath=`.spec.decidedAt`
+
+type AccessDecision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AccessDecisionSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+type AccessDecisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AccessDecision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AccessDecision{}, &AccessDecisionList{})
+}
+
+// NewAccessDecision builds the record of a decision on a request. It is named after the RequestID, so a request is
+// only ever recorded once, and labeled with the decision and the request name. Requests approved at submission, never stored, were
+// submitted when decided.
+func NewAccessDecision(request *AccessRequest, decision, decidedBy, comment string) *AccessDecision {
+	name := request.Spec.RequestID
+	if name == "" {
+		name = request.Name
+	}
+	now := metav1.Now()
+	submitted := request.CreationTimestamp
+	if submitted.IsZero() {
+		submitted = now
+	}
+	labels := map[string]string{DecisionLabel: decision}
+	if len(validation.IsValidLabelValue(request.Name)) == 0 {
+		labels[RequestLabel] = request.Name
+	}
+	return &AccessDecision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: AccessDecisionSpec{
+			Decision:      decision,
+			Request:       request.Name,
+			DecidedBy:     decidedBy,
+			Comment:       comment,
+			SubmittedAt:   submitted,
+			DecidedAt:     now,
+			AccessRequest: *request.Spec.DeepCopy(),
+		},
+	}
+}
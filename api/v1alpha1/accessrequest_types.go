@@ -7,7 +7,10 @@ import (
 
 // AccessRequestSpec defines the desired state of AccessRequest
 type AccessRequestSpec struct {
-	Requestor     string `json:"requestor"`
+	Requestor string `json:"requestor"`
+	// FiledBy is the user who filed the request on behalf of the requestor, when it is not the requestor.
+	// +optional
+	FiledBy       string `json:"filedBy,omitempty"`
 	RequestType   string `json:"requestType"`
 	SourceService string `json:"sourceService,omitempty"`
 	TargetService string `json:"targetService,omitempty"`
@@ -18,6 +21,16 @@ type AccessRequestSpec struct {
 	Duration      int64  `json:"duration"`
 	// +optional
 	Description string `json:"description,omitempty"`
+	// Priority is "low", "normal" or "urgent". Urgent requests are listed first and reminded sooner.
+	// +optional
+	// +kubebuilder:validation:Enum=low;normal;urgent
+	Priority string `json:"priority,omitempty"`
+	// TicketRef is the Jira issue or ServiceNow record tracking the request, e.g. "NET-123".
+	// +optional
+	TicketRef string `json:"ticketRef,omitempty"`
+	// PreviousRequestID is the RequestID of the denied or expired request this one resubmits, chaining their history.
+	// +optional
+	PreviousRequestID string `json:"previousRequestID,omitempty"`
 	// Status indicates the current state of the request.
 	// Can be "PendingFull", "PendingTarget", "PendingSource".
 	Status string `json:"status"`
@@ -30,11 +43,42 @@ type AccessRequestSpec struct {
 	// TargetCloneName is the name of the service clone created in the target namespace.
 	// +optional
 	TargetCloneName string `json:"targetCloneName,omitempty"`
+	// RequiredApprovals is how many different approvers must approve the request before the accesses are created.
+	// Zero and one mean a single approval.
+	// +optional
+	RequiredApprovals int `json:"requiredApprovals,omitempty"`
+	// ApprovedBy lists the approvals collected so far by a request that needs several.
+	// +optional
+	ApprovedBy []Approval `json:"approvedBy,omitempty"`
+	// DualApproval requires an approval from each side, source and target, of a Service request spanning the
+	// namespaces of two teams before the accesses are created.
+	// +optional
+	DualApproval bool `json:"dualApproval,omitempty"`
+}
+
+// Approval records one approval of an AccessRequest.
+type Approval struct {
+	Approver   string      `json:"approver"`
+	ApprovedAt metav1.Time `json:"approvedAt"`
+	// Comment is the approver's optional note.
+	// +optional
+	Comment string `json:"comment,omitempty"`
+	// Side is "source" or "target", the side of a DualApproval request the approval is for.
+	// +optional
+	// +kubebuilder:validation:Enum=source;target
+	Side string `json:"side,omitempty"`
 }
 
 // AccessRequestStatus defines the observed state of AccessRequest
 type AccessRequestStatus struct {
 	Status string `json:"status,omitempty"`
+	// InterruptedCleanup lists the cleanup steps a manager could not finish before shutting down.
+	// The next leader resumes them when it starts.
+	// +optional
+	InterruptedCleanup []string `json:"interruptedCleanup,omitempty"`
+	// InterruptedAt is when the cleanup was interrupted.
+	// +optional
+	InterruptedAt *metav1.Time `json:"interruptedAt,omitempty"`
 }
 
 //+kubebuilder:object:root=true
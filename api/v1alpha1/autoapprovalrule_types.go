@@ -0,0 +1,57 @@
+// api/v1alpha1/autoapprovalrule_types.go
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AutoApprovalRuleSpec defines low-risk access requests that are provisioned without review.
+// A request matches when every field set on the rule allows it.
+type AutoApprovalRuleSpec struct {
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Groups lists the requestor groups the rule applies to.
+	Groups []string `json:"groups"`
+	// RequestType is "Service" or "External". Empty matches both.
+	// +optional
+	RequestType string `json:"requestType,omitempty"`
+	// SourceNamespaces lists the namespaces Service requests may come from. Empty matches any. External requests,
+	// which come from outside the cluster, only match when it is empty.
+	// +optional
+	SourceNamespaces []string `json:"sourceNamespaces,omitempty"`
+	// TargetNamespaces lists the namespaces of the target service of Service requests, or of the service of
+	// External requests. Empty matches any.
+	// +optional
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// ExternalCIDRs lists the blocks the CIDR of External requests must be within. External requests never
+	// match a rule without any.
+	// +optional
+	ExternalCIDRs []string `json:"externalCIDRs,omitempty"`
+	// MaxDuration is the longest duration matched. Requests without expiry never match.
+	MaxDuration metav1.Duration `json:"maxDuration"`
+	// Ports lists the ports requests may open, their service's ports when they set none. Empty matches any.
+	// +optional
+	Ports []int32 `json:"ports,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=aar
+
+type AutoApprovalRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec AutoApprovalRuleSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+type AutoApprovalRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AutoApprovalRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AutoApprovalRule{}, &AutoApprovalRuleList{})
+}
@@ -0,0 +1,57 @@
+// api/v1alpha1/preapproval_types.go
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PreApprovalSpec defines a window during which matching access requests are approved without review.
+type PreApprovalSpec struct {
+	// +optional
+	Description string `json:"description,omitempty"`
+	// Approver is the identity that granted the pre-approval. Matching requests are approved on its behalf,
+	// so its permissions are checked again at approval time.
+	Approver string `json:"approver"`
+	// +optional
+	ApproverGroups []string `json:"approverGroups,omitempty"`
+	// Users and Groups list who may self-serve the access. At least one of them must be set.
+	// +optional
+	Users []string `json:"users,omitempty"`
+	// +optional
+	Groups []string `json:"groups,omitempty"`
+	// RequestType is "Service" or "External".
+	RequestType string `json:"requestType"`
+	// SourceService and TargetService are "namespace/name" or "namespace/*" for Service requests.
+	// +optional
+	SourceService string `json:"sourceService,omitempty"`
+	// +optional
+	TargetService string `json:"targetService,omitempty"`
+	// Service is "namespace/name" or "namespace/*" for External requests.
+	// +optional
+	Service string `json:"service,omitempty"`
+	// Start and End bound the window. Approved accesses never outlive End.
+	Start metav1.Time `json:"start"`
+	End   metav1.Time `json:"end"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:scope=Cluster,shortName=pa
+
+type PreApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PreApprovalSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+type PreApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PreApproval `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PreApproval{}, &PreApprovalList{})
+}
@@ -0,0 +1,482 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+	netwatchclient "github.com/Banh-Canh/netwatch/pkg/client"
+)
+
+var (
+	cliServer  string
+	cliToken   string
+	cliAPIKey  string
+	cliTimeout time.Duration
+
+	cliRequest         netwatchclient.SubmitAccessRequestInput
+	cliRequestDuration time.Duration
+
+	cliApprove         netwatchclient.ApproveAccessRequestInput
+	cliApproveDuration time.Duration
+	cliDenyReason      string
+
+	cliUpdate         netwatchclient.UpdateAccessRequestInput
+	cliUpdateDuration time.Duration
+
+	cliListPending   bool
+	cliListUser      string
+	cliListNamespace string
+	cliListType      string
+	cliListStatus    string
+
+	cliRevokeExternal bool
+
+	cliOutput string
+	cliWatch  bool
+)
+
+// cliWatchRetryDelay is how long --watch waits before following the activity log again after losing it.
+const cliWatchRetryDelay = 5 * time.Second
+
+var cliCmd = &cobra.Command{
+	Use:   "cli",
+	Short: "Request, list, approve, deny and revoke accesses on a running Netwatch server.",
+	Long: `Talks to the REST API of a running Netwatch server, with an OIDC ID token sent as Bearer or with the
+static API key. Approving and denying requests needs a user identity, so they require a token.`,
+}
+
+var cliRequestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Submit an access request for review.",
+	Long: `Submits an access request, like the UI. Use --source and --target for a service-to-service request,
+or --service and --cidr for an external one. Services are given as namespace/name.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		client := newCLIClient()
+		cliRequest.Duration = int64(cliRequestDuration.Seconds())
+		result, ok := reportCLICommand(client.SubmitRequest(cmd.Context(), cliRequest))
+		if !ok {
+			os.Exit(1)
+		}
+		fmt.Printf("%s\t%s\t%s\n", result.Name, result.DisplayName, result.Status)
+	},
+}
+
+var cliListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List active accesses, or pending requests with --pending.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if cliListPending {
+			runCLIList(cmd.Context(), fetchPendingRequests)
+			return
+		}
+		runCLIList(cmd.Context(), fetchActiveAccesses)
+	},
+}
+
+var cliPendingCmd = &cobra.Command{
+	Use:   "pending",
+	Short: "List pending access requests, live with --watch.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCLIList(cmd.Context(), fetchPendingRequests)
+	},
+}
+
+var cliActiveCmd = &cobra.Command{
+	Use:   "active",
+	Short: "List active accesses, live with --watch.",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		runCLIList(cmd.Context(), fetchActiveAccesses)
+	},
+}
+
+var cliGetCmd = &cobra.Command{
+	Use:   "get <namespace>/<name>",
+	Short: "Show an access with its pair, service clones and provenance.",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		namespace, name, found := strings.Cut(args[0], "/")
+		if !found || namespace == "" || name == "" {
+			logger.Logger.Error("Accesses must be given as namespace/name", "access", args[0])
+			os.Exit(1)
+		}
+		client := newCLIClient()
+		detail, err := client.GetAccess(cmd.Context(), namespace, name)
+		if err != nil {
+			logger.Logger.Error("Failed to get the access", "access", args[0], "error", err)
+			os.Exit(1)
+		}
+		printCLIOutput(detail, func(w io.Writer, wide bool) {
+			printAccessDetail(w, *detail, wide)
+		})
+	},
+}
+
+var cliApproveCmd = &cobra.Command{
+	Use:   "approve <request>...",
+	Short: "Approve pending access requests.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cliApprove.Duration = int64(cliApproveDuration.Seconds())
+		runCLIDecision(cmd.Context(), args, func(client *netwatchclient.Client, ctx context.Context, request string) (*netwatchclient.CommandResult, error) {
+			return client.ApproveWithChanges(ctx, request, cliApprove)
+		})
+	},
+}
+
+var cliUpdateCmd = &cobra.Command{
+	Use:   "update <request>...",
+	Short: "Change the ports, duration or description of your pending requests.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cliUpdate.Duration = int64(cliUpdateDuration.Seconds())
+		runCLIDecision(cmd.Context(), args, func(client *netwatchclient.Client, ctx context.Context, request string) (*netwatchclient.CommandResult, error) {
+			return client.UpdateRequest(ctx, request, cliUpdate)
+		})
+	},
+}
+
+var cliDenyCmd = &cobra.Command{
+	Use:   "deny <request>...",
+	Short: "Deny pending access requests, or abort your own.",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		runCLIDecision(cmd.Context(), args, func(client *netwatchclient.Client, ctx context.Context, request string) (*netwatchclient.CommandResult, error) {
+			return client.DenyWithReason(ctx, request, cliDenyReason)
+		})
+	},
+}
+
+var cliRevokeCmd = &cobra.Command{
+	Use:   "revoke <namespace>/<name>...",
+	Short: "Revoke accesses, both sides of a service access at once.",
+	Long: `Revokes Access objects, or ExternalAccess objects with --external, as listed by 'netwatch cli list'.
+Revoking one side of a service-to-service access also revokes the other.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		client := newCLIClient()
+		revoke := client.RevokeAccess
+		if cliRevokeExternal {
+			revoke = client.RevokeExternalAccess
+		}
+		failed := false
+		for _, arg := range args {
+			namespace, name, found := strings.Cut(arg, "/")
+			if !found || namespace == "" || name == "" {
+				logger.Logger.Error("Accesses must be given as namespace/name", "access", arg)
+				failed = true
+				continue
+			}
+			result, ok := reportCLICommand(revoke(cmd.Context(), namespace, name))
+			if !ok {
+				failed = true
+				continue
+			}
+			for _, revoked := range result.Revoked {
+				fmt.Printf("%s\trevoked\n", revoked)
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+// cliFetch reads a list from the server and returns it with its table renderer.
+type cliFetch func(ctx context.Context, client *netwatchclient.Client) (any, func(w io.Writer, wide bool), error)
+
+func fetchPendingRequests(ctx context.Context, client *netwatchclient.Client) (any, func(w io.Writer, wide bool), error) {
+	requests, err := client.ListPending(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list pending requests: %w", err)
+	}
+	if requests == nil {
+		requests = []netwatchclient.AccessRequestPayload{}
+	}
+	return requests, func(w io.Writer, wide bool) { printPendingRequests(w, requests, wide) }, nil
+}
+
+func fetchActiveAccesses(ctx context.Context, client *netwatchclient.Client) (any, func(w io.Writer, wide bool), error) {
+	accesses, err := client.ListActiveAccesses(ctx, netwatchclient.ActiveAccessFilter{
+		User: cliListUser, Namespace: cliListNamespace, Type: cliListType, Status: cliListStatus,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not list active accesses: %w", err)
+	}
+	if accesses == nil {
+		accesses = []netwatchclient.ActiveAccessInfo{}
+	}
+	return accesses, func(w io.Writer, wide bool) { printActiveAccesses(w, accesses, wide) }, nil
+}
+
+// runCLIList prints a list once, or with --watch again whenever the activity log shows a change.
+func runCLIList(ctx context.Context, fetch cliFetch) {
+	client := newCLIClient()
+	if !cliWatch {
+		payload, table, err := fetch(ctx, client)
+		if err != nil {
+			logger.Logger.Error("Failed to list", "error", err)
+			os.Exit(1)
+		}
+		printCLIOutput(payload, table)
+		return
+	}
+
+	render := func() {
+		payload, table, err := fetch(ctx, client)
+		if err != nil {
+			logger.Logger.Warn("Failed to refresh", "error", err)
+			return
+		}
+		if cliOutput == "table" || cliOutput == "wide" {
+			// Redraw the table in place, like watch(1).
+			fmt.Print("\033[H\033[2J")
+			fmt.Printf("Updated %s, watching for changes...\n\n", time.Now().Format(time.TimeOnly))
+		}
+		printCLIOutput(payload, table)
+	}
+	render()
+	for {
+		changes, err := watchActivityLog(ctx, client)
+		if err != nil {
+			logger.Logger.Warn("Could not follow the activity log, retrying", "error", err)
+			time.Sleep(cliWatchRetryDelay)
+			continue
+		}
+		for range changes {
+			render()
+		}
+		// The stream ended, e.g. the server restarted: catch up on what was missed before following it again.
+		time.Sleep(cliWatchRetryDelay)
+		render()
+	}
+}
+
+// watchActivityLog follows the activity log stream of the server. The channel gets a value when entries were
+// written since the last one was read, and is closed when the stream ends.
+func watchActivityLog(ctx context.Context, client *netwatchclient.Client) (<-chan struct{}, error) {
+	entries, err := client.StreamLogs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer close(changes)
+		for range entries {
+			// Entries arriving while the list is refreshed only need one more refresh.
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// newCLIClient returns a client of the configured server, or exits when no credentials are set.
+func newCLIClient() *netwatchclient.Client {
+	if cliToken == "" {
+		cliToken = os.Getenv("NETWATCH_TOKEN")
+	}
+	if cliAPIKey == "" {
+		cliAPIKey = os.Getenv("NETWATCH_API_TOKEN")
+	}
+	client, err := netwatchclient.New(cliServer, cliToken, cliAPIKey, cliTimeout)
+	if err != nil {
+		logger.Logger.Error("Cannot authenticate to the server, use --token or --api-key", "error", err)
+		os.Exit(1)
+	}
+	return client
+}
+
+// reportCLICommand prints the activity log entries a command produced, which explain why it failed if it did.
+func reportCLICommand(result *netwatchclient.CommandResult, err error) (*netwatchclient.CommandResult, bool) {
+	if result != nil {
+		for _, message := range result.Messages {
+			fmt.Fprintln(os.Stderr, message.Payload)
+		}
+	}
+	if err != nil {
+		logger.Logger.Error("Command failed", "error", err)
+		return result, false
+	}
+	return result, true
+}
+
+// runCLIDecision approves or denies each request given, and exits with an error if any of them failed.
+func runCLIDecision(ctx context.Context, requests []string, decide func(*netwatchclient.Client, context.Context, string) (*netwatchclient.CommandResult, error)) {
+	client := newCLIClient()
+	failed := false
+	for _, request := range requests {
+		if _, ok := reportCLICommand(decide(client, ctx, request)); !ok {
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// printCLIOutput writes a REST payload in the format chosen with --output. JSON and YAML keep the field names of
+// the payload, so they can be scripted; table and wide are meant to be read.
+func printCLIOutput(payload any, table func(w io.Writer, wide bool)) {
+	switch cliOutput {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(payload); err != nil {
+			logger.Logger.Error("Could not write the output", "error", err)
+			os.Exit(1)
+		}
+	case "yaml":
+		out, err := yaml.Marshal(payload)
+		if err != nil {
+			logger.Logger.Error("Could not write the output", "error", err)
+			os.Exit(1)
+		}
+		os.Stdout.Write(out) //nolint:all
+	case "table", "wide":
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		table(w, cliOutput == "wide")
+		w.Flush() //nolint:all
+	default:
+		logger.Logger.Error("Unknown output format, use json, yaml, table or wide", "output", cliOutput)
+		os.Exit(1)
+	}
+}
+
+func printPendingRequests(w io.Writer, requests []netwatchclient.AccessRequestPayload, wide bool) {
+	if wide {
+		fmt.Fprintln(w, "NAME\tREQUESTOR\tREQUEST\tDIRECTION\tPORTS\tDURATION\tSTATUS\tSUBMITTED\tDESCRIPTION")
+	} else {
+		fmt.Fprintln(w, "NAME\tREQUESTOR\tREQUEST\tDURATION\tSTATUS")
+	}
+	for _, request := range requests {
+		if wide {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", request.RequestID, request.Requestor, request.DisplayName,
+				request.Direction, request.Ports, formatDuration(request.Duration), request.Status,
+				formatTime(request.Timestamp), request.Description)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", request.RequestID, request.Requestor, request.DisplayName,
+			formatDuration(request.Duration), request.Status)
+	}
+}
+
+func printActiveAccesses(w io.Writer, accesses []netwatchclient.ActiveAccessInfo, wide bool) {
+	if wide {
+		fmt.Fprintln(w, "TYPE\tACCESS\tSOURCE\tTARGET\tDIRECTION\tPORTS\tSTATUS\tOWNER\tREQUESTOR\tAPPROVED BY\tCREATED\tEXPIRES")
+	} else {
+		fmt.Fprintln(w, "TYPE\tACCESS\tSOURCE\tTARGET\tPORTS\tSTATUS\tOWNER\tEXPIRES")
+	}
+	for _, access := range accesses {
+		if wide {
+			fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", access.Type, access.Namespace, access.Name,
+				access.Source, access.Target, access.Direction, access.Ports, access.Status, access.Owner,
+				access.Requestor, access.ApprovedBy, formatTime(access.CreatedAt), formatExpiry(access.ExpiresAt))
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s\t%s\t%s\t%s\t%s\n", access.Type, access.Namespace, access.Name,
+			access.Source, access.Target, access.Ports, access.Status, access.Owner, formatExpiry(access.ExpiresAt))
+	}
+}
+
+func printAccessDetail(w io.Writer, detail netwatchclient.AccessDetail, wide bool) {
+	fmt.Fprintf(w, "Access:\t%s/%s\n", detail.Namespace, detail.Name)
+	fmt.Fprintf(w, "Request ID:\t%s\n", detail.RequestID)
+	fmt.Fprintf(w, "Owner:\t%s\n", detail.Owner)
+	fmt.Fprintf(w, "Requestor:\t%s\n", detail.Requestor)
+	fmt.Fprintf(w, "Approved by:\t%s\n", detail.ApprovedBy)
+	fmt.Fprintf(w, "Created:\t%s\n", formatTime(detail.CreatedAt))
+	fmt.Fprintf(w, "Expires:\t%s\n", formatExpiry(detail.ExpiresAt))
+	if detail.Pair != nil {
+		fmt.Fprintf(w, "Pair:\t%s/%s\n", detail.Pair.Namespace, detail.Pair.Name)
+	}
+	for _, clone := range detail.Clones {
+		if wide {
+			fmt.Fprintf(w, "Clone:\t%s/%s (of %s, ports %s)\n", clone.Namespace, clone.Name, clone.ClonedFrom, clone.Ports)
+			continue
+		}
+		fmt.Fprintf(w, "Clone:\t%s/%s (of %s)\n", clone.Namespace, clone.Name, clone.ClonedFrom)
+	}
+}
+
+// formatTime prints a Unix timestamp, empty when it is unknown.
+func formatTime(unix int64) string {
+	if unix <= 0 {
+		return ""
+	}
+	return time.Unix(unix, 0).Format(time.RFC3339)
+}
+
+// formatExpiry prints when an access expires, "never" for an access without expiry.
+func formatExpiry(unix int64) string {
+	if unix <= 0 {
+		return "never"
+	}
+	return formatTime(unix)
+}
+
+// formatDuration prints a duration in seconds, or "none" for a request without expiry.
+func formatDuration(seconds int64) string {
+	if seconds <= 0 {
+		return "none"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func init() {
+	cliCmd.PersistentFlags().StringVar(&cliServer, "server", "http://localhost:3000", "Base URL of the Netwatch server")
+	cliCmd.PersistentFlags().StringVar(&cliToken, "token", "", "OIDC ID token sent as Bearer (defaults to NETWATCH_TOKEN)")
+	cliCmd.PersistentFlags().StringVar(&cliAPIKey, "api-key", "", "Static API key (defaults to NETWATCH_API_TOKEN)")
+	cliCmd.PersistentFlags().DurationVar(&cliTimeout, "timeout", 30*time.Second, "Timeout of each API call")
+	cliCmd.PersistentFlags().StringVarP(&cliOutput, "output", "o", "table", "Output format of list and get: json, yaml, table or wide")
+
+	cliRequestCmd.Flags().StringVar(&cliRequest.SourceService, "source", "", "Source service, as namespace/name")
+	cliRequestCmd.Flags().StringVar(&cliRequest.TargetService, "target", "", "Target service, as namespace/name")
+	cliRequestCmd.Flags().StringVar(&cliRequest.Service, "service", "", "Service of an external access, as namespace/name")
+	cliRequestCmd.Flags().StringVar(&cliRequest.Cidr, "cidr", "", "External IP or CIDR of an external access")
+	cliRequestCmd.Flags().StringVar(&cliRequest.Direction, "direction", "", "ingress, egress or all (defaults to all)")
+	cliRequestCmd.Flags().StringVar(&cliRequest.Ports, "ports", "", "Comma-separated ports, instead of the ports of the services")
+	cliRequestCmd.Flags().DurationVar(&cliRequestDuration, "duration", time.Hour, "How long the access lasts once approved")
+	cliRequestCmd.Flags().StringVar(&cliRequest.Description, "description", "", "Why the access is needed")
+	cliRequestCmd.Flags().StringToStringVar(&cliRequest.Labels, "label", nil, "Label of the request as key=value, can be repeated")
+	cliRequestCmd.Flags().StringVar(&cliRequest.OnBehalfOf, "on-behalf-of", "", "Email of the user to file the request for")
+
+	cliApproveCmd.Flags().StringVar(&cliApprove.Comment, "comment", "", "Comment kept in the activity log and on the accesses created")
+	cliApproveCmd.Flags().StringVar(&cliApprove.Direction, "direction", "", "Narrow all traffic to ingress or egress before approving")
+	cliApproveCmd.Flags().StringVar(&cliApprove.Ports, "ports", "", "Keep only these comma-separated ports of the request")
+	cliApproveCmd.Flags().DurationVar(&cliApproveDuration, "duration", 0, "Shorten the requested duration")
+	cliUpdateCmd.Flags().StringVar(&cliUpdate.Ports, "ports", "", "New comma-separated ports of the request")
+	cliUpdateCmd.Flags().DurationVar(&cliUpdateDuration, "duration", 0, "New duration of the request")
+	cliUpdateCmd.Flags().StringVar(&cliUpdate.Description, "description", "", "New description of the request")
+	cliDenyCmd.Flags().StringVar(&cliDenyReason, "reason", "", "Why the requests are denied, told to their requestors")
+
+	cliListCmd.Flags().BoolVar(&cliListPending, "pending", false, "List pending access requests instead of active accesses")
+	for _, cmd := range []*cobra.Command{cliListCmd, cliActiveCmd} {
+		cmd.Flags().StringVar(&cliListUser, "user", "", "Only accesses owned by this user, by email or username")
+		cmd.Flags().StringVar(&cliListNamespace, "namespace", "", "Only accesses whose object is in this namespace")
+		cmd.Flags().StringVar(&cliListType, "type", "", "Only accesses of this type (Service or External)")
+		cmd.Flags().StringVar(&cliListStatus, "status", "", "Only accesses in this state (Active, Pending or Paused)")
+	}
+	for _, cmd := range []*cobra.Command{cliListCmd, cliPendingCmd, cliActiveCmd} {
+		cmd.Flags().BoolVarP(&cliWatch, "watch", "w", false, "Print the list again whenever the activity log shows a change")
+	}
+
+	cliRevokeCmd.Flags().BoolVar(&cliRevokeExternal, "external", false, "Revoke ExternalAccess objects instead of Access objects")
+
+	cliCmd.AddCommand(cliRequestCmd, cliListCmd, cliPendingCmd, cliActiveCmd, cliGetCmd, cliApproveCmd, cliUpdateCmd, cliDenyCmd, cliRevokeCmd)
+}
@@ -0,0 +1,7 @@
+package main
+
+import "github.com/Banh-Canh/netwatch/cmd"
+
+func main() {
+	cmd.ExecuteKubectlPlugin()
+}
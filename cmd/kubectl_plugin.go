@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+var (
+	pluginKubeconfig string
+	pluginContext    string
+	pluginNamespace  string
+	pluginConfigMap  string
+)
+
+// ExecuteKubectlPlugin runs the cli commands as the kubectl-netwatch plugin. The server address is read from a
+// ConfigMap of the current kubeconfig context unless --server is set, and the credentials of the context are
+// used when no token or API key is given.
+func ExecuteKubectlPlugin() {
+	RootCmd.RemoveCommand(cliCmd)
+	cliCmd.Use = "kubectl-netwatch"
+	cliCmd.Annotations = map[string]string{cobra.CommandDisplayNameAnnotation: "kubectl netwatch"}
+	cliCmd.PersistentPreRun = func(cmd *cobra.Command, args []string) {
+		initConfig()
+		if err := applyKubeconfigDefaults(cmd); err != nil {
+			logger.Logger.Error("Cannot find the Netwatch server from the kubeconfig, use --server", "error", err)
+			os.Exit(1)
+		}
+	}
+	cliCmd.PersistentFlags().StringVar(&pluginKubeconfig, "kubeconfig", "", "Path to the kubeconfig file (defaults to KUBECONFIG or ~/.kube/config)")
+	cliCmd.PersistentFlags().StringVar(&pluginContext, "context", "", "Kubeconfig context to use (defaults to the current context)")
+	cliCmd.PersistentFlags().StringVar(&pluginNamespace, "netwatch-namespace", "netwatch-system", "Namespace of the ConfigMap holding the server address")
+	cliCmd.PersistentFlags().StringVar(&pluginConfigMap, "netwatch-configmap", "netwatch-cli", "ConfigMap holding the server address in its 'server' key")
+	cliCmd.PersistentFlags().StringVarP(&logLevelFlag, "log-level", "l", "", "Override log level (e.g., 'debug')")
+
+	if err := cliCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// applyKubeconfigDefaults fills the server address and the credentials the user did not set from the kubeconfig.
+func applyKubeconfigDefaults(cmd *cobra.Command) error {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = pluginKubeconfig
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		rules, &clientcmd.ConfigOverrides{CurrentContext: pluginContext},
+	).ClientConfig()
+	if err != nil {
+		if cmd.Flags().Changed("server") {
+			return nil
+		}
+		return err
+	}
+
+	if cliToken == "" && cliAPIKey == "" && os.Getenv("NETWATCH_TOKEN") == "" && os.Getenv("NETWATCH_API_TOKEN") == "" {
+		cliToken = kubeconfigToken(restConfig)
+	}
+	if cmd.Flags().Changed("server") {
+		return nil
+	}
+
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), cliTimeout)
+	defer cancel()
+	configMap, err := clientset.CoreV1().ConfigMaps(pluginNamespace).Get(ctx, pluginConfigMap, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("could not read ConfigMap %s/%s: %w", pluginNamespace, pluginConfigMap, err)
+	}
+	server := configMap.Data["server"]
+	if server == "" {
+		return fmt.Errorf("ConfigMap %s/%s has no 'server' key", pluginNamespace, pluginConfigMap)
+	}
+	cliServer = server
+	return nil
+}
+
+// kubeconfigToken returns the static bearer token or the OIDC ID token of a kubeconfig user, empty when it
+// authenticates otherwise.
+func kubeconfigToken(restConfig *rest.Config) string {
+	if restConfig.BearerToken != "" {
+		return restConfig.BearerToken
+	}
+	if provider := restConfig.AuthProvider; provider != nil && provider.Name == "oidc" {
+		return provider.Config["id-token"]
+	}
+	return ""
+}
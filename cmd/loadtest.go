@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/spf13/cobra"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+var (
+	loadtestServer     string
+	loadtestToken      string
+	loadtestClients    int
+	loadtestIterations int
+	loadtestScenario   string
+	loadtestSource     string
+	loadtestTarget     string
+	loadtestApprove    bool
+	loadtestTimeout    time.Duration
+)
+
+var loadtestCmd = &cobra.Command{
+	Use:   "loadtest",
+	Short: "Generate load against a running Netwatch server.",
+	Long: `Simulates N concurrent clients against a running Netwatch server (backed by envtest, kind or
+any other cluster) and reports latency percentiles per operation.
+
+The 'read' scenario polls the REST endpoints the UI refreshes periodically. The 'submit' scenario opens
+one WebSocket per client and submits access requests, optionally approving each one afterwards.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if loadtestToken == "" {
+			loadtestToken = os.Getenv("NETWATCH_LOADTEST_TOKEN")
+		}
+		if loadtestToken == "" {
+			logger.Logger.Error("A Bearer token is required, use --token or NETWATCH_LOADTEST_TOKEN")
+			os.Exit(1)
+		}
+		if loadtestClients <= 0 || loadtestIterations <= 0 {
+			logger.Logger.Error("--clients and --iterations must be greater than zero")
+			os.Exit(1)
+		}
+
+		var run func(client int, rec *latencyRecorder)
+		switch loadtestScenario {
+		case "read":
+			run = runReadScenario
+		case "submit":
+			if loadtestSource == "" || loadtestTarget == "" {
+				logger.Logger.Error("The submit scenario requires --source and --target (namespace/name)")
+				os.Exit(1)
+			}
+			run = runSubmitScenario
+		default:
+			logger.Logger.Error("Unknown scenario", "scenario", loadtestScenario)
+			os.Exit(1)
+		}
+
+		logger.Logger.Info("Starting load test", "server", loadtestServer, "scenario", loadtestScenario, "clients", loadtestClients)
+		rec := newLatencyRecorder()
+		start := time.Now()
+		var wg sync.WaitGroup
+		for i := range loadtestClients {
+			wg.Add(1)
+			go func(client int) {
+				defer wg.Done()
+				run(client, rec)
+			}(i)
+		}
+		wg.Wait()
+		rec.print(time.Since(start))
+	},
+}
+
+// runReadScenario polls the read endpoints used by the UI.
+func runReadScenario(client int, rec *latencyRecorder) {
+	httpClient := &http.Client{Timeout: loadtestTimeout}
+	endpoints := []string{"/api/pending-requests", "/api/active-accesses", "/api/services"}
+	for range loadtestIterations {
+		for _, endpoint := range endpoints {
+			req, err := http.NewRequest(http.MethodGet, strings.TrimRight(loadtestServer, "/")+endpoint, nil)
+			if err != nil {
+				rec.fail("GET " + endpoint)
+				continue
+			}
+			req.Header.Set("Authorization", "Bearer "+loadtestToken)
+			start := time.Now()
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				logger.Logger.Debug("Request failed", "client", client, "endpoint", endpoint, "error", err)
+				rec.fail("GET " + endpoint)
+				continue
+			}
+			resp.Body.Close() //nolint:all
+			if resp.StatusCode != http.StatusOK {
+				rec.fail("GET " + endpoint)
+				continue
+			}
+			rec.observe("GET "+endpoint, time.Since(start))
+		}
+	}
+}
+
+// runSubmitScenario submits (and optionally approves) access requests over a dedicated WebSocket.
+func runSubmitScenario(client int, rec *latencyRecorder) {
+	conn, err := dialLoadtestWebSocket()
+	if err != nil {
+		logger.Logger.Error("Could not open WebSocket", "client", client, "error", err)
+		rec.fail("submitAccessRequest")
+		return
+	}
+	defer conn.Close()
+
+	for i := range loadtestIterations {
+		description := fmt.Sprintf("netwatch loadtest client=%d iteration=%d run=%d", client, i, time.Now().UnixNano())
+		start := time.Now()
+		err := sendAndAwaitResult(conn, map[string]any{
+			"command":       "submitAccessRequest",
+			"sourceService": loadtestSource,
+			"targetService": loadtestTarget,
+			"direction":     "all",
+			"duration":      60,
+			"description":   description,
+		})
+		if err != nil {
+			logger.Logger.Debug("Submission failed", "client", client, "error", err)
+			rec.fail("submitAccessRequest")
+			continue
+		}
+		rec.observe("submitAccessRequest", time.Since(start))
+
+		if !loadtestApprove {
+			continue
+		}
+		requestID, err := findLoadtestRequest(description)
+		if err != nil {
+			logger.Logger.Debug("Could not find submitted request", "client", client, "error", err)
+			rec.fail("approveAccessRequest")
+			continue
+		}
+		start = time.Now()
+		if err := sendAndAwaitResult(conn, map[string]any{"command": "approveAccessRequest", "requestID": requestID}); err != nil {
+			logger.Logger.Debug("Approval failed", "client", client, "error", err)
+			rec.fail("approveAccessRequest")
+			continue
+		}
+		rec.observe("approveAccessRequest", time.Since(start))
+	}
+}
+
+func dialLoadtestWebSocket() (*websocket.Conn, error) {
+	u, err := url.Parse(loadtestServer)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	default:
+		u.Scheme = "ws"
+	}
+	u.Path = "/ws"
+	dialer := websocket.Dialer{HandshakeTimeout: loadtestTimeout}
+	conn, _, err := dialer.Dial(u.String(), http.Header{"Authorization": []string{"Bearer " + loadtestToken}})
+	return conn, err
+}
+
+// sendAndAwaitResult sends a command and blocks until the server answers with an applyResult or commandError frame.
+func sendAndAwaitResult(conn *websocket.Conn, command map[string]any) error {
+	conn.SetWriteDeadline(time.Now().Add(loadtestTimeout)) //nolint:all
+	if err := conn.WriteJSON(command); err != nil {
+		return err
+	}
+	conn.SetReadDeadline(time.Now().Add(loadtestTimeout)) //nolint:all
+	for {
+		var entry struct {
+			Payload   string `json:"payload"`
+			ClassName string `json:"className"`
+			Type      string `json:"type"`
+			// Message is only set on commandError frames.
+			Message string `json:"message"`
+		}
+		if err := conn.ReadJSON(&entry); err != nil {
+			return err
+		}
+		if entry.Type == "commandError" {
+			return fmt.Errorf("%s", entry.Message)
+		}
+		if entry.Type != "applyResult" {
+			continue
+		}
+		if entry.ClassName == "log-error" {
+			return fmt.Errorf("%s", entry.Payload)
+		}
+		return nil
+	}
+}
+
+// findLoadtestRequest looks up the name of a pending request by its unique description.
+func findLoadtestRequest(description string) (string, error) {
+	httpClient := &http.Client{Timeout: loadtestTimeout}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimRight(loadtestServer, "/")+"/api/pending-requests", nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+loadtestToken)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close() //nolint:all
+
+	var pending []struct {
+		RequestID   string `json:"requestID"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pending); err != nil {
+		return "", err
+	}
+	for _, p := range pending {
+		if p.Description == description {
+			return p.RequestID, nil
+		}
+	}
+	return "", fmt.Errorf("request not found")
+}
+
+// latencyRecorder collects latency samples and failures per operation.
+type latencyRecorder struct {
+	mu       sync.Mutex
+	samples  map[string][]time.Duration
+	failures map[string]int
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{samples: make(map[string][]time.Duration), failures: make(map[string]int)}
+}
+
+func (r *latencyRecorder) observe(op string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.samples[op] = append(r.samples[op], d)
+}
+
+func (r *latencyRecorder) fail(op string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failures[op]++
+}
+
+func (r *latencyRecorder) print(elapsed time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ops := make(map[string]struct{})
+	for op := range r.samples {
+		ops[op] = struct{}{}
+	}
+	for op := range r.failures {
+		ops[op] = struct{}{}
+	}
+	names := make([]string, 0, len(ops))
+	for op := range ops {
+		names = append(names, op)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "OPERATION\tOK\tFAILED\tP50\tP90\tP95\tP99\tMAX\n")
+	for _, op := range names {
+		samples := r.samples[op]
+		sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", op, len(samples), r.failures[op],
+			percentile(samples, 50), percentile(samples, 90), percentile(samples, 95), percentile(samples, 99), percentile(samples, 100))
+	}
+	w.Flush() //nolint:all
+	fmt.Printf("\nTotal elapsed: %s\n", elapsed.Round(time.Millisecond))
+}
+
+// percentile returns the nearest-rank percentile of already sorted samples.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Microsecond)
+}
+
+func init() {
+	loadtestCmd.Flags().StringVar(&loadtestServer, "server", "http://localhost:3000", "Base URL of the Netwatch server")
+	loadtestCmd.Flags().StringVar(&loadtestToken, "token", "", "OIDC ID token sent as Bearer (defaults to NETWATCH_LOADTEST_TOKEN)")
+	loadtestCmd.Flags().IntVar(&loadtestClients, "clients", 10, "Number of concurrent clients")
+	loadtestCmd.Flags().IntVar(&loadtestIterations, "iterations", 10, "Number of iterations per client")
+	loadtestCmd.Flags().StringVar(&loadtestScenario, "scenario", "read", "Scenario to run: 'read' or 'submit'")
+	loadtestCmd.Flags().StringVar(&loadtestSource, "source", "", "Source service (namespace/name) for the submit scenario")
+	loadtestCmd.Flags().StringVar(&loadtestTarget, "target", "", "Target service (namespace/name) for the submit scenario")
+	loadtestCmd.Flags().BoolVar(&loadtestApprove, "approve", false, "Approve each submitted request (submit scenario only)")
+	loadtestCmd.Flags().DurationVar(&loadtestTimeout, "timeout", 30*time.Second, "Timeout for a single operation")
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"os"
 	"strconv"
+	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
 	"github.com/joho/godotenv"
@@ -52,30 +53,64 @@ resources and manages the lifecycle of associated service clones.`,
 			logger.Logger.Warn("Leader election is DISABLED. This should only be used for local development.")
 		}
 
-		mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-			Scheme:                 scheme,
-			HealthProbeBindAddress: ":8081",
-			LeaderElection:         enableLeaderElection,
-			LeaderElectionID:       "netwatch-controller-leader-lock",
+		drainTimeout := controller.DefaultDrainTimeout
+		if v := os.Getenv("NETWATCH_MANAGER_DRAIN_TIMEOUT"); v != "" {
+			parsed, err := time.ParseDuration(v)
+			if err != nil || parsed <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_MANAGER_DRAIN_TIMEOUT", "value", v, "error", err)
+				os.Exit(1)
+			}
+			drainTimeout = parsed
+		}
+		var pendingExpiry time.Duration
+		if v := os.Getenv("NETWATCH_PENDING_EXPIRY_SECONDS"); v != "" {
+			seconds, err := strconv.Atoi(v)
+			if err != nil || seconds < 0 {
+				logger.Logger.Error("Invalid NETWATCH_PENDING_EXPIRY_SECONDS", "value", v, "error", err)
+				os.Exit(1)
+			}
+			pendingExpiry = time.Duration(seconds) * time.Second
+		}
+		// Leaves time to record the cleanups still unfinished once the drain timeout expires.
+		gracefulShutdownTimeout := drainTimeout + 10*time.Second
+
+		restConfig := ctrl.GetConfigOrDie()
+		kubeClientLimitsFromEnv("NETWATCH_KUBE").Apply(restConfig)
+
+		mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+			Scheme:                  scheme,
+			HealthProbeBindAddress:  ":8081",
+			LeaderElection:          enableLeaderElection,
+			LeaderElectionID:        "netwatch-controller-leader-lock",
+			GracefulShutdownTimeout: &gracefulShutdownTimeout,
 		})
 		if err != nil {
 			logger.Logger.Error("Unable to start controller manager", "error", err)
 			os.Exit(1)
 		}
 
-		if err = (&controller.NetwatchCleanupReconciler{
-			Client: mgr.GetClient(),
-			Scheme: mgr.GetScheme(),
-		}).SetupWithManager(mgr); err != nil {
+		reconciler := &controller.NetwatchCleanupReconciler{
+			Client:        mgr.GetClient(),
+			Scheme:        mgr.GetScheme(),
+			DrainTimeout:  drainTimeout,
+			PendingExpiry: pendingExpiry,
+			Recorder:      mgr.GetEventRecorderFor("netwatch-cleanup-controller"),
+		}
+		if err = reconciler.SetupWithManager(mgr); err != nil {
 			logger.Logger.Error("Unable to create cleanup controller", "error", err)
 			os.Exit(1)
 		}
 
+		if err := mgr.AddMetricsServerExtraHandler(controller.ReconcileStatePath, controller.ReconcileStateHandler()); err != nil {
+			logger.Logger.Error("Unable to set up reconcile state endpoint", "error", err)
+			os.Exit(1)
+		}
+
 		if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 			logger.Logger.Error("Unable to set up health check", "error", err)
 			os.Exit(1)
 		}
-		if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		if err := mgr.AddReadyzCheck("readyz", reconciler.Ready); err != nil {
 			logger.Logger.Error("Unable to set up ready check", "error", err)
 			os.Exit(1)
 		}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Banh-Canh/netwatch/internal/handlers"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+	netwatchclient "github.com/Banh-Canh/netwatch/pkg/client"
+)
+
+var (
+	reportServer    string
+	reportToken     string
+	reportAPIKey    string
+	reportOutput    string
+	reportVerifyKey string
+	reportTimeout   time.Duration
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Generate reports from a running Netwatch server.",
+}
+
+var reportExposureCmd = &cobra.Command{
+	Use:   "exposure",
+	Short: "Export a signed snapshot of every network path currently open.",
+	Long: `Fetches a point-in-time snapshot of every network path opened through Netwatch (sources, targets,
+CIDRs, ports, owners and expiry). The snapshot is signed by the server with HMAC-SHA256 and can be
+attached to compliance evidence requests. Use --verify-key to check the signature locally.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if reportToken == "" {
+			reportToken = os.Getenv("NETWATCH_TOKEN")
+		}
+		if reportAPIKey == "" {
+			reportAPIKey = os.Getenv("NETWATCH_API_TOKEN")
+		}
+		client, err := netwatchclient.New(reportServer, reportToken, reportAPIKey, reportTimeout)
+		if err != nil {
+			logger.Logger.Error("Cannot authenticate to the server, use --token or --api-key", "error", err)
+			os.Exit(1)
+		}
+
+		signed, err := client.ExposureReport(cmd.Context())
+		if err != nil {
+			logger.Logger.Error("Failed to fetch exposure report", "error", err)
+			os.Exit(1)
+		}
+
+		if reportVerifyKey != "" {
+			expected, err := handlers.SignExposureReport(signed.Report, []byte(reportVerifyKey))
+			if err != nil || !hmac.Equal([]byte(expected), []byte(signed.Signature)) {
+				logger.Logger.Error("Exposure report signature does not match")
+				os.Exit(1)
+			}
+			// Logs go to stdout, which may carry the report itself.
+			fmt.Fprintln(os.Stderr, "Exposure report signature verified.")
+		}
+
+		out := os.Stdout
+		if reportOutput != "" && reportOutput != "-" {
+			f, err := os.Create(reportOutput)
+			if err != nil {
+				logger.Logger.Error("Could not create output file", "error", err)
+				os.Exit(1)
+			}
+			defer f.Close() //nolint:all
+			out = f
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(signed); err != nil {
+			logger.Logger.Error("Could not write exposure report", "error", err)
+			os.Exit(1)
+		}
+		if out != os.Stdout {
+			logger.Logger.Info("Exposure report written", "file", reportOutput, "entries", len(signed.Report.Entries))
+		}
+	},
+}
+
+func init() {
+	reportCmd.PersistentFlags().StringVar(&reportServer, "server", "http://localhost:3000", "Base URL of the Netwatch server")
+	reportCmd.PersistentFlags().StringVar(&reportToken, "token", "", "OIDC ID token sent as Bearer (defaults to NETWATCH_TOKEN)")
+	reportCmd.PersistentFlags().StringVar(&reportAPIKey, "api-key", "", "Static API key (defaults to NETWATCH_API_TOKEN)")
+	reportCmd.PersistentFlags().DurationVar(&reportTimeout, "timeout", 30*time.Second, "Timeout of the API call")
+	reportExposureCmd.Flags().StringVarP(&reportOutput, "output", "o", "", "File to write the report to (defaults to stdout)")
+	reportExposureCmd.Flags().StringVar(&reportVerifyKey, "verify-key", "", "Signing key used to verify the report signature locally")
+	reportCmd.AddCommand(reportExposureCmd)
+}
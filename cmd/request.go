@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+	netwatchclient "github.com/Banh-Canh/netwatch/pkg/client"
+)
+
+var (
+	requestServer  string
+	requestToken   string
+	requestAPIKey  string
+	requestFiles   []string
+	requestTimeout time.Duration
+	requestFor     string
+)
+
+var requestCmd = &cobra.Command{
+	Use:   "request",
+	Short: "Manage access requests on a running Netwatch server.",
+}
+
+var requestImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Submit access requests from AccessRequest manifests.",
+	Long: `Submits each AccessRequest manifest (YAML or JSON, matching the CRD schema) to the server, which
+validates it and handles it like a submission from the UI. Keep the manifests in your repository to
+submit the same requests reproducibly. Server-managed fields (status, requestID, clone names) must be left empty.
+With --on-behalf-of, or another user as spec.requestor, the requests are filed for that user: this requires
+the file-on-behalf verb on accessrequests, or being an admin.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if requestToken == "" {
+			requestToken = os.Getenv("NETWATCH_TOKEN")
+		}
+		if requestAPIKey == "" {
+			requestAPIKey = os.Getenv("NETWATCH_API_TOKEN")
+		}
+		client, err := netwatchclient.New(requestServer, requestToken, requestAPIKey, requestTimeout)
+		if err != nil {
+			logger.Logger.Error("Cannot authenticate to the server, use --token or --api-key", "error", err)
+			os.Exit(1)
+		}
+
+		failed := false
+		for _, file := range requestFiles {
+			manifest, err := os.ReadFile(file)
+			if err != nil {
+				logger.Logger.Error("Could not read manifest", "file", file, "error", err)
+				failed = true
+				continue
+			}
+			result, err := client.ImportRequest(cmd.Context(), manifest, requestFor)
+			for _, message := range result.Messages {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", file, message.Payload)
+			}
+			if err != nil {
+				logger.Logger.Error("Access request was not submitted", "file", file, "error", err)
+				failed = true
+				continue
+			}
+			fmt.Printf("%s\t%s\t%s\n", file, result.DisplayName, result.Status)
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	requestCmd.PersistentFlags().StringVar(&requestServer, "server", "http://localhost:3000", "Base URL of the Netwatch server")
+	requestCmd.PersistentFlags().StringVar(&requestToken, "token", "", "OIDC ID token sent as Bearer (defaults to NETWATCH_TOKEN)")
+	requestCmd.PersistentFlags().StringVar(&requestAPIKey, "api-key", "", "Static API key (defaults to NETWATCH_API_TOKEN)")
+	requestCmd.PersistentFlags().DurationVar(&requestTimeout, "timeout", 30*time.Second, "Timeout of each API call")
+	requestImportCmd.Flags().StringSliceVarP(&requestFiles, "filename", "f", nil, "AccessRequest manifest to submit, can be repeated")
+	requestImportCmd.Flags().StringVar(&requestFor, "on-behalf-of", "", "Email of the user to file the requests for")
+	requestImportCmd.MarkFlagRequired("filename") //nolint:all
+	requestCmd.AddCommand(requestImportCmd)
+}
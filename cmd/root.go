@@ -14,6 +14,7 @@ var (
 	versionFlag  bool
 	logLevelFlag string
 	version      = "dev"
+	buildDate    = ""
 )
 
 var RootCmd = &cobra.Command{
@@ -51,6 +52,10 @@ func initConfig() {
 func init() {
 	RootCmd.AddCommand(serverCmd)
 	RootCmd.AddCommand(managerCmd)
+	RootCmd.AddCommand(loadtestCmd)
+	RootCmd.AddCommand(reportCmd)
+	RootCmd.AddCommand(requestCmd)
+	RootCmd.AddCommand(cliCmd)
 	RootCmd.Flags().BoolVarP(&versionFlag, "version", "v", false, "Display version information")
 	RootCmd.PersistentFlags().StringVarP(&logLevelFlag, "log-level", "l", "", "Override log level (e.g., 'debug')")
 }
@@ -3,22 +3,29 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/netip"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/boj/redistore"
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/cobra"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "github.com/Banh-Canh/netwatch/docs"
+	"github.com/Banh-Canh/netwatch/internal/auth"
+	"github.com/Banh-Canh/netwatch/internal/diagnostics"
 	"github.com/Banh-Canh/netwatch/internal/handlers"
 	"github.com/Banh-Canh/netwatch/internal/k8s"
 	"github.com/Banh-Canh/netwatch/internal/middleware"
+	"github.com/Banh-Canh/netwatch/internal/migrations"
 	"github.com/Banh-Canh/netwatch/internal/utils/logger"
 )
 
@@ -28,7 +35,7 @@ import (
 // @contact.url http://www.swagger.io/support
 // @license.name Apache 2.0
 // @license.url http://www.apache.org/licenses/LICENSE-2.0.html
-// @BasePath /api
+// @BasePath /api/v1
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
@@ -49,10 +56,87 @@ var serverCmd = &cobra.Command{
 		oidcClientSecret := os.Getenv("OIDC_CLIENT_SECRET")
 		staticToken := os.Getenv("NETWATCH_API_TOKEN")
 		ttlStr := os.Getenv("NETWATCH_SESSION_TTL")
+		sessionIdleTimeoutStr := os.Getenv("NETWATCH_SESSION_IDLE_TIMEOUT")
 		redisAddr := os.Getenv("REDIS_ADDR")
 		redisUser := os.Getenv("REDIS_USERNAME")
 		redisPass := os.Getenv("REDIS_PASSWORD")
 		port := os.Getenv("NETWATCH_PORT")
+		grpcPort := os.Getenv("NETWATCH_GRPC_PORT")
+		diagnosticsIntervalStr := os.Getenv("NETWATCH_DIAGNOSTICS_INTERVAL")
+		attachmentMaxBytesStr := os.Getenv("NETWATCH_ATTACHMENT_MAX_BYTES")
+		requestLabelKeysStr := os.Getenv("NETWATCH_REQUEST_LABEL_KEYS")
+		slackSigningSecret := os.Getenv("NETWATCH_SLACK_SIGNING_SECRET")
+		reportSigningKey := os.Getenv("NETWATCH_REPORT_SIGNING_KEY")
+		graphqlEnabled := os.Getenv("NETWATCH_GRAPHQL_ENABLED")
+		autoExtendUsageURL := os.Getenv("NETWATCH_AUTO_EXTEND_USAGE_URL")
+		autoExtendIncrementStr := os.Getenv("NETWATCH_AUTO_EXTEND_INCREMENT")
+		autoExtendMaxStr := os.Getenv("NETWATCH_AUTO_EXTEND_MAX")
+		driftIntervalStr := os.Getenv("NETWATCH_DRIFT_CHECK_INTERVAL")
+		usernameStrategy := os.Getenv("NETWATCH_USERNAME_STRATEGY")
+		usernameTemplate := os.Getenv("NETWATCH_USERNAME_TEMPLATE")
+		usernameClaim := os.Getenv("NETWATCH_USERNAME_CLAIM")
+		generateRequestNames := os.Getenv("NETWATCH_REQUEST_GENERATE_NAME")
+		pendingCacheTTLStr := os.Getenv("NETWATCH_PENDING_REQUESTS_CACHE_TTL")
+		adminGroupsStr := os.Getenv("NETWATCH_ADMIN_GROUPS")
+		adminAllowedCIDRsStr := os.Getenv("NETWATCH_ADMIN_ALLOWED_CIDRS")
+		apiTokenAdmin := os.Getenv("NETWATCH_API_TOKEN_ADMIN")
+		trustedProxiesStr := os.Getenv("NETWATCH_TRUSTED_PROXIES")
+		managerURL := os.Getenv("NETWATCH_MANAGER_URL")
+		oidcAudiencesStr := os.Getenv("NETWATCH_OIDC_AUDIENCES")
+		oidcClockSkewStr := os.Getenv("NETWATCH_OIDC_CLOCK_SKEW")
+		oidcEmailClaim := os.Getenv("NETWATCH_OIDC_EMAIL_CLAIM")
+		oidcGroupsClaim := os.Getenv("NETWATCH_OIDC_GROUPS_CLAIM")
+		serviceAccountAuth := os.Getenv("NETWATCH_SERVICE_ACCOUNT_AUTH")
+		serviceAccountAudiencesStr := os.Getenv("NETWATCH_SERVICE_ACCOUNT_AUDIENCES")
+		clientCertHeader := os.Getenv("NETWATCH_CLIENT_CERT_HEADER")
+		clientCAFile := os.Getenv("NETWATCH_CLIENT_CA_FILE")
+		automationIdentitiesFile := os.Getenv("NETWATCH_AUTOMATION_IDENTITIES_FILE")
+		heartbeatGraceStr := os.Getenv("NETWATCH_HEARTBEAT_GRACE")
+		offboardedUsersFile := os.Getenv("NETWATCH_OFFBOARDED_USERS_FILE")
+		offboardingIntervalStr := os.Getenv("NETWATCH_OFFBOARDING_INTERVAL")
+		logRetentionStr := os.Getenv("NETWATCH_LOG_RETENTION")
+		maxAccessDurationStr := os.Getenv("NETWATCH_MAX_ACCESS_DURATION")
+		approvalQuorumStr := os.Getenv("NETWATCH_APPROVAL_QUORUM")
+		approvalQuorumDurationsStr := os.Getenv("NETWATCH_APPROVAL_QUORUM_DURATIONS")
+		twoPersonRule := os.Getenv("NETWATCH_TWO_PERSON_RULE")
+		approverGroupsStr := os.Getenv("NETWATCH_APPROVER_GROUPS")
+		dualApproval := os.Getenv("NETWATCH_DUAL_APPROVAL")
+		requireDenialReason := os.Getenv("NETWATCH_REQUIRE_DENIAL_REASON")
+		reminderAfterStr := os.Getenv("NETWATCH_REMINDER_AFTER")
+		escalateAfterStr := os.Getenv("NETWATCH_ESCALATE_AFTER")
+		escalationGroupsStr := os.Getenv("NETWATCH_ESCALATION_GROUPS")
+		urgentReminderAfterStr := os.Getenv("NETWATCH_URGENT_REMINDER_AFTER")
+		urgentEscalateAfterStr := os.Getenv("NETWATCH_URGENT_ESCALATE_AFTER")
+		reviewSLAStr := os.Getenv("NETWATCH_REVIEW_SLA")
+		urgentReviewSLAStr := os.Getenv("NETWATCH_URGENT_REVIEW_SLA")
+		annotationDecisions := os.Getenv("NETWATCH_ANNOTATION_DECISIONS")
+		decisionLinkSecret := os.Getenv("NETWATCH_DECISION_LINK_SECRET")
+		publicURL := os.Getenv("NETWATCH_PUBLIC_URL")
+		decisionLinkTTLStr := os.Getenv("NETWATCH_DECISION_LINK_TTL")
+		teamsWebhookURL := os.Getenv("NETWATCH_TEAMS_WEBHOOK_URL")
+		teamsNamespaceWebhooksStr := os.Getenv("NETWATCH_TEAMS_NAMESPACE_WEBHOOKS")
+		webhookURLsStr := os.Getenv("NETWATCH_WEBHOOK_URLS")
+		webhookSecret := os.Getenv("NETWATCH_WEBHOOK_SECRET")
+		requireTicket := os.Getenv("NETWATCH_REQUIRE_TICKET")
+		minDescriptionLengthStr := os.Getenv("NETWATCH_MIN_DESCRIPTION_LENGTH")
+		namespaceMinDescriptionLengthStr := os.Getenv("NETWATCH_NAMESPACE_MIN_DESCRIPTION_LENGTH")
+		ticketingSystem := os.Getenv("NETWATCH_TICKETING_SYSTEM")
+		securityHeaders := middleware.DefaultSecurityHeaders()
+		securityHeaderOverrides := map[string]*string{
+			"NETWATCH_CONTENT_SECURITY_POLICY":   &securityHeaders.ContentSecurityPolicy,
+			"NETWATCH_STRICT_TRANSPORT_SECURITY": &securityHeaders.StrictTransportSecurity,
+			"NETWATCH_CONTENT_TYPE_OPTIONS":      &securityHeaders.ContentTypeOptions,
+			"NETWATCH_REFERRER_POLICY":           &securityHeaders.ReferrerPolicy,
+		}
+		for env, header := range securityHeaderOverrides {
+			// "off" disables the header, any other value replaces the default.
+			if value, ok := os.LookupEnv(env); ok {
+				*header = value
+				if value == "off" {
+					*header = ""
+				}
+			}
+		}
 
 		ttl, err := strconv.Atoi(ttlStr)
 		if err != nil || ttl <= 0 {
@@ -61,6 +145,127 @@ var serverCmd = &cobra.Command{
 		if redisAddr == "" {
 			redisAddr = "localhost:6379"
 		}
+		if attachmentMaxBytesStr != "" {
+			attachmentMaxBytes, err := strconv.ParseInt(attachmentMaxBytesStr, 10, 64)
+			if err != nil || attachmentMaxBytes <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_ATTACHMENT_MAX_BYTES", "value", attachmentMaxBytesStr, "error", err)
+				os.Exit(1)
+			}
+			handlers.SetAttachmentMaxBytes(attachmentMaxBytes)
+		}
+		if err := handlers.SetAttachmentStorage(handlers.AttachmentStorageConfig{
+			Endpoint:  os.Getenv("NETWATCH_ATTACHMENT_ENDPOINT"),
+			Bucket:    os.Getenv("NETWATCH_ATTACHMENT_BUCKET"),
+			Region:    os.Getenv("NETWATCH_ATTACHMENT_REGION"),
+			AccessKey: os.Getenv("NETWATCH_ATTACHMENT_ACCESS_KEY"),
+			SecretKey: os.Getenv("NETWATCH_ATTACHMENT_SECRET_KEY"),
+		}); err != nil {
+			logger.Logger.Error("Invalid attachment storage configuration", "error", err)
+			os.Exit(1)
+		}
+		go handlers.StartAttachmentSweeper(context.Background(), time.Hour)
+		handlers.SetReportSigningKey(reportSigningKey)
+		handlers.SetGenerateRequestNames(generateRequestNames == "true")
+		if adminGroupsStr != "" {
+			handlers.SetAdminGroups(strings.Split(adminGroupsStr, ","))
+		}
+		if adminAllowedCIDRsStr != "" {
+			var cidrs []netip.Prefix
+			for _, cidrStr := range strings.Split(adminAllowedCIDRsStr, ",") {
+				cidr, err := netip.ParsePrefix(strings.TrimSpace(cidrStr))
+				if err != nil {
+					logger.Logger.Error("Invalid NETWATCH_ADMIN_ALLOWED_CIDRS", "value", cidrStr, "error", err)
+					os.Exit(1)
+				}
+				cidrs = append(cidrs, cidr.Masked())
+			}
+			handlers.SetAdminAllowedCIDRs(cidrs)
+		}
+		handlers.SetAPIKeyAdmin(apiTokenAdmin == "true")
+		handlers.SetManagerURL(managerURL)
+		if pendingCacheTTLStr != "" {
+			pendingCacheTTL, err := time.ParseDuration(pendingCacheTTLStr)
+			if err != nil || pendingCacheTTL < 0 {
+				logger.Logger.Error("Invalid NETWATCH_PENDING_REQUESTS_CACHE_TTL", "value", pendingCacheTTLStr, "error", err)
+				os.Exit(1)
+			}
+			handlers.SetPendingRequestsCacheTTL(pendingCacheTTL)
+		}
+		if err := handlers.SetUsernameMapping(handlers.UsernameMappingConfig{
+			Strategy: usernameStrategy,
+			Template: usernameTemplate,
+			Claim:    usernameClaim,
+		}); err != nil {
+			logger.Logger.Error("Invalid username mapping configuration", "error", err)
+			os.Exit(1)
+		}
+		approvalQuorum, err := handlers.ParseApprovalQuorum(approvalQuorumStr, approvalQuorumDurationsStr)
+		if err != nil {
+			logger.Logger.Error("Invalid approval quorum configuration", "error", err)
+			os.Exit(1)
+		}
+		handlers.SetApprovalQuorum(approvalQuorum)
+		handlers.SetTwoPersonRule(twoPersonRule == "true")
+		approverGroups, err := handlers.ParseApproverGroups(approverGroupsStr)
+		if err != nil {
+			logger.Logger.Error("Invalid NETWATCH_APPROVER_GROUPS", "value", approverGroupsStr, "error", err)
+			os.Exit(1)
+		}
+		handlers.SetApproverGroups(approverGroups)
+		handlers.SetDualApproval(dualApproval == "true")
+		handlers.SetDenialReasonRequired(requireDenialReason == "true")
+		justificationPolicy, err := handlers.ParseJustificationPolicy(minDescriptionLengthStr, namespaceMinDescriptionLengthStr)
+		if err != nil {
+			logger.Logger.Error("Invalid description length policy", "error", err)
+			os.Exit(1)
+		}
+		handlers.SetJustificationPolicy(justificationPolicy)
+		if teamsWebhookURL != "" {
+			if err := handlers.ValidateNotificationURL(teamsWebhookURL); err != nil {
+				logger.Logger.Error("Invalid NETWATCH_TEAMS_WEBHOOK_URL", "error", err)
+				os.Exit(1)
+			}
+		}
+		teamsNamespaceWebhooks, err := handlers.ParseNamespaceURLs(teamsNamespaceWebhooksStr)
+		if err != nil {
+			logger.Logger.Error("Invalid NETWATCH_TEAMS_NAMESPACE_WEBHOOKS", "error", err)
+			os.Exit(1)
+		}
+		handlers.SetTeamsNotifications(handlers.TeamsConfig{WebhookURL: teamsWebhookURL, NamespaceWebhookURLs: teamsNamespaceWebhooks})
+		var webhookURLs []string
+		for u := range strings.SplitSeq(webhookURLsStr, ",") {
+			if u = strings.TrimSpace(u); u == "" {
+				continue
+			}
+			if err := handlers.ValidateNotificationURL(u); err != nil {
+				logger.Logger.Error("Invalid NETWATCH_WEBHOOK_URLS", "error", err)
+				os.Exit(1)
+			}
+			webhookURLs = append(webhookURLs, u)
+		}
+		if len(webhookURLs) > 0 && webhookSecret == "" {
+			logger.Logger.Error("NETWATCH_WEBHOOK_URLS requires NETWATCH_WEBHOOK_SECRET")
+			os.Exit(1)
+		}
+		handlers.SetWebhookNotifications(handlers.WebhookConfig{URLs: webhookURLs, Secret: webhookSecret})
+		if err := handlers.SetTicketing(handlers.TicketingConfig{
+			System:          ticketingSystem,
+			URL:             os.Getenv("NETWATCH_TICKETING_URL"),
+			User:            os.Getenv("NETWATCH_TICKETING_USER"),
+			Token:           os.Getenv("NETWATCH_TICKETING_TOKEN"),
+			JiraProject:     os.Getenv("NETWATCH_JIRA_PROJECT"),
+			JiraIssueType:   os.Getenv("NETWATCH_JIRA_ISSUE_TYPE"),
+			ServiceNowTable: os.Getenv("NETWATCH_SERVICENOW_TABLE"),
+			Required:        requireTicket == "true",
+		}); err != nil {
+			logger.Logger.Error("Invalid ticketing configuration", "system", ticketingSystem, "error", err)
+			os.Exit(1)
+		}
+		if requestLabelKeysStr != "" {
+			handlers.SetRequestLabelKeys(strings.Split(requestLabelKeysStr, ","))
+		}
+
+		k8s.SetClientLimits(kubeClientLimitsFromEnv("NETWATCH_KUBE"), kubeClientLimitsFromEnv("NETWATCH_KUBE_IMPERSONATION"))
 
 		if err := k8s.InitKubeClient(); err != nil {
 			logger.Logger.Error("Fatal error initializing Kubernetes client", "error", err)
@@ -77,8 +282,194 @@ var serverCmd = &cobra.Command{
 			os.Exit(1)
 		}
 		handlers.SetRedisClient(redisClient)
+		auth.SetCache(redisClient)
+		handlers.SetDenyWindow(denyWindowFromEnv())
+		if lockout := authLockoutFromEnv(); lockout.MaxFailures > 0 {
+			middleware.EnableLockout(redisClient, lockout)
+			middleware.SetLockoutAuditor(handlers.RecordAuthLockout)
+		}
+
+		if err := migrations.Run(context.Background(), redisClient); err != nil {
+			logger.Logger.Error("Failed to run migrations", "error", err)
+			os.Exit(1)
+		}
+
+		if logRetentionStr != "" {
+			logRetention, err := time.ParseDuration(logRetentionStr)
+			if err != nil || logRetention <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_LOG_RETENTION", "value", logRetentionStr, "error", err)
+				os.Exit(1)
+			}
+			handlers.SetLogRetention(logRetention)
+		}
+
+		if autoExtendUsageURL != "" {
+			autoExtendIncrement, err := time.ParseDuration(autoExtendIncrementStr)
+			if err != nil || autoExtendIncrement <= 0 {
+				autoExtendIncrement = 15 * time.Minute
+			}
+			autoExtendMax, err := strconv.Atoi(autoExtendMaxStr)
+			if err != nil || autoExtendMax <= 0 {
+				autoExtendMax = 2
+			}
+			go handlers.StartAutoExtender(context.Background(), handlers.AutoExtendConfig{
+				UsageURL:      autoExtendUsageURL,
+				Increment:     autoExtendIncrement,
+				MaxExtensions: autoExtendMax,
+				Interval:      time.Minute,
+			})
+		}
+
+		driftInterval, err := time.ParseDuration(driftIntervalStr)
+		if err != nil || driftInterval <= 0 {
+			driftInterval = 5 * time.Minute
+		}
+		go handlers.StartDriftDetector(context.Background(), driftInterval)
+
+		heartbeatGrace := 2 * time.Minute
+		if heartbeatGraceStr != "" {
+			heartbeatGrace, err = time.ParseDuration(heartbeatGraceStr)
+			if err != nil || heartbeatGrace <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_HEARTBEAT_GRACE", "value", heartbeatGraceStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		handlers.SetHeartbeatGrace(heartbeatGrace)
 
-		go handlers.StartLogJanitor(context.Background(), redisClient, 5*time.Minute, time.Hour)
+		if maxAccessDurationStr != "" {
+			maxAccessDuration, err := time.ParseDuration(maxAccessDurationStr)
+			if err != nil || maxAccessDuration <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_MAX_ACCESS_DURATION", "value", maxAccessDurationStr, "error", err)
+				os.Exit(1)
+			}
+			handlers.SetMaxAccessDuration(maxAccessDuration)
+		}
+		if sessionIdleTimeoutStr != "" {
+			sessionIdleTimeout, err := time.ParseDuration(sessionIdleTimeoutStr)
+			if err != nil || sessionIdleTimeout < 0 {
+				logger.Logger.Error("Invalid NETWATCH_SESSION_IDLE_TIMEOUT", "value", sessionIdleTimeoutStr, "error", err)
+				os.Exit(1)
+			}
+			handlers.SetSessionIdleTimeout(sessionIdleTimeout)
+		}
+		go handlers.StartHeartbeatMonitor(context.Background(), min(max(heartbeatGrace/4, time.Second), 30*time.Second))
+		go handlers.StartExpiryNotifier(context.Background(), time.Minute)
+		if offboardedUsersFile != "" {
+			offboardingInterval := time.Minute
+			if offboardingIntervalStr != "" {
+				offboardingInterval, err = time.ParseDuration(offboardingIntervalStr)
+				if err != nil || offboardingInterval <= 0 {
+					logger.Logger.Error("Invalid NETWATCH_OFFBOARDING_INTERVAL", "value", offboardingIntervalStr, "error", err)
+					os.Exit(1)
+				}
+			}
+			go handlers.StartOffboardingWatcher(context.Background(), offboardedUsersFile, offboardingInterval)
+		}
+		if annotationDecisions == "true" {
+			go handlers.StartAnnotationDecisions(context.Background(), 15*time.Second)
+		}
+
+		var reminders handlers.ReminderConfig
+		if reminderAfterStr != "" {
+			reminders.RemindAfter, err = time.ParseDuration(reminderAfterStr)
+			if err != nil || reminders.RemindAfter < 0 {
+				logger.Logger.Error("Invalid NETWATCH_REMINDER_AFTER", "value", reminderAfterStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		if escalateAfterStr != "" {
+			reminders.EscalateAfter, err = time.ParseDuration(escalateAfterStr)
+			if err != nil || reminders.EscalateAfter < 0 {
+				logger.Logger.Error("Invalid NETWATCH_ESCALATE_AFTER", "value", escalateAfterStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		if urgentReminderAfterStr != "" {
+			reminders.UrgentRemindAfter, err = time.ParseDuration(urgentReminderAfterStr)
+			if err != nil || reminders.UrgentRemindAfter <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_URGENT_REMINDER_AFTER", "value", urgentReminderAfterStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		if urgentEscalateAfterStr != "" {
+			reminders.UrgentEscalateAfter, err = time.ParseDuration(urgentEscalateAfterStr)
+			if err != nil || reminders.UrgentEscalateAfter <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_URGENT_ESCALATE_AFTER", "value", urgentEscalateAfterStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		for group := range strings.SplitSeq(escalationGroupsStr, ",") {
+			if group = strings.TrimSpace(group); group != "" {
+				reminders.EscalationGroups = append(reminders.EscalationGroups, group)
+			}
+		}
+		if (reminders.EscalateAfter > 0 || reminders.UrgentEscalateAfter > 0) && len(reminders.EscalationGroups) == 0 {
+			logger.Logger.Error("NETWATCH_ESCALATE_AFTER and NETWATCH_URGENT_ESCALATE_AFTER require NETWATCH_ESCALATION_GROUPS")
+			os.Exit(1)
+		}
+		handlers.SetRequestReminders(reminders)
+		if reminders.RemindAfter > 0 || reminders.EscalateAfter > 0 || reminders.UrgentRemindAfter > 0 || reminders.UrgentEscalateAfter > 0 {
+			go handlers.StartRequestReminders(context.Background(), time.Minute)
+		}
+
+		var reviewSLA handlers.ReviewSLAConfig
+		if reviewSLAStr != "" {
+			reviewSLA.Target, err = time.ParseDuration(reviewSLAStr)
+			if err != nil || reviewSLA.Target <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_REVIEW_SLA", "value", reviewSLAStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		if urgentReviewSLAStr != "" {
+			reviewSLA.UrgentTarget, err = time.ParseDuration(urgentReviewSLAStr)
+			if err != nil || reviewSLA.UrgentTarget <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_URGENT_REVIEW_SLA", "value", urgentReviewSLAStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		handlers.SetReviewSLA(reviewSLA)
+		go handlers.StartReviewSLAMonitor(context.Background(), time.Minute)
+
+		if diagnosticsIntervalStr != "" {
+			diagnosticsInterval, err := time.ParseDuration(diagnosticsIntervalStr)
+			if err != nil || diagnosticsInterval <= 0 {
+				logger.Logger.Error("Invalid NETWATCH_DIAGNOSTICS_INTERVAL", "value", diagnosticsIntervalStr, "error", err)
+				os.Exit(1)
+			}
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "websocket_connections",
+				Read: func() float64 { return float64(handlers.OpenWebSocketCount()) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "impersonating_clients",
+				Read: func() float64 { return float64(k8s.ImpersonatingClientCacheSize()) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "redis_pool_total_conns",
+				Read: func() float64 { return float64(redisClient.PoolStats().TotalConns) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "redis_pool_idle_conns",
+				Read: func() float64 { return float64(redisClient.PoolStats().IdleConns) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "redis_pool_timeouts",
+				Read: func() float64 { return float64(redisClient.PoolStats().Timeouts) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "enforcement_drift_entries",
+				Read: func() float64 { return float64(handlers.DriftCount()) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "heartbeat_accesses",
+				Read: func() float64 { return float64(handlers.HeartbeatCount()) },
+			})
+			diagnostics.RegisterProbe(diagnostics.Probe{
+				Name: "self_approval_cache_entries",
+				Read: func() float64 { return float64(handlers.SelfApprovalCacheSize()) },
+			})
+			go diagnostics.StartSampler(context.Background(), diagnosticsInterval)
+		}
 
 		store, err := redistore.NewRediStore(10, "tcp", redisAddr, redisUser, redisPass, []byte(sessionSecret))
 		if err != nil {
@@ -93,8 +484,61 @@ var serverCmd = &cobra.Command{
 		store.SetMaxAge(ttl)
 		handlers.SetSessionStore(store)
 
+		authConfig := auth.Config{
+			IssuerURL:   oidcIssuerURL,
+			ClientID:    oidcClientID,
+			EmailClaim:  oidcEmailClaim,
+			GroupsClaim: oidcGroupsClaim,
+		}
+		if oidcAudiencesStr != "" {
+			authConfig.Audiences = strings.Split(oidcAudiencesStr, ",")
+		}
+		if oidcClockSkewStr != "" {
+			authConfig.ClockSkew, err = time.ParseDuration(oidcClockSkewStr)
+			if err != nil || authConfig.ClockSkew < 0 {
+				logger.Logger.Error("Invalid NETWATCH_OIDC_CLOCK_SKEW", "value", oidcClockSkewStr, "error", err)
+				os.Exit(1)
+			}
+		}
+		if err := auth.Init(context.Background(), authConfig); err != nil {
+			logger.Logger.Error("Could not initialize OIDC token verification", "error", err)
+			os.Exit(1)
+		}
+		if clientCertHeader != "" {
+			caPEM, err := os.ReadFile(clientCAFile)
+			if err != nil {
+				logger.Logger.Error("Could not read NETWATCH_CLIENT_CA_FILE", "path", clientCAFile, "error", err)
+				os.Exit(1)
+			}
+			if err := auth.EnableClientCertificates(clientCertHeader, caPEM); err != nil {
+				logger.Logger.Error("Could not enable client certificate authentication", "error", err)
+				os.Exit(1)
+			}
+			logger.Logger.Info("Client certificate authentication enabled", "header", clientCertHeader)
+		}
+		if automationIdentitiesFile != "" {
+			data, err := os.ReadFile(automationIdentitiesFile)
+			if err != nil {
+				logger.Logger.Error("Could not read NETWATCH_AUTOMATION_IDENTITIES_FILE", "path", automationIdentitiesFile, "error", err)
+				os.Exit(1)
+			}
+			count, err := auth.LoadAutomationIdentities(data)
+			if err != nil {
+				logger.Logger.Error("Could not load automation identities", "error", err)
+				os.Exit(1)
+			}
+			logger.Logger.Info("Automation identities loaded", "count", count)
+		}
+		if serviceAccountAuth == "true" {
+			var audiences []string
+			if serviceAccountAudiencesStr != "" {
+				audiences = strings.Split(serviceAccountAudiencesStr, ",")
+			}
+			auth.RegisterProvider(auth.NewServiceAccountProvider(k8s.GetAppKubeClient(), audiences))
+			logger.Logger.Info("Service account token authentication enabled")
+		}
+
 		oidcHandlerConfig := handlers.OIDCConfig{
-			IssuerURL:    oidcIssuerURL,
 			ClientID:     oidcClientID,
 			ClientSecret: oidcClientSecret,
 			SessionTTL:   ttl,
@@ -103,32 +547,89 @@ var serverCmd = &cobra.Command{
 			logger.Logger.Error("Could not initialize OIDC handlers", "error", err)
 			os.Exit(1)
 		}
-		if err := middleware.InitOIDCVerifier(oidcIssuerURL, oidcClientID); err != nil {
-			logger.Logger.Error("Could not initialize API middleware OIDC verifier", "error", err)
-			os.Exit(1)
-		}
 
 		router := gin.New()
+		// Client IPs feed the lockout and the admin allowlist: X-Forwarded-For is only read from trusted proxies.
+		var trustedProxies []string
+		for _, proxy := range strings.Split(trustedProxiesStr, ",") {
+			if proxy = strings.TrimSpace(proxy); proxy != "" {
+				trustedProxies = append(trustedProxies, proxy)
+			}
+		}
+		if err := router.SetTrustedProxies(trustedProxies); err != nil {
+			logger.Logger.Error("Invalid NETWATCH_TRUSTED_PROXIES", "value", trustedProxiesStr, "error", err)
+			os.Exit(1)
+		}
 		router.Use(gin.Recovery())
 		router.Use(customLoggerMiddleware())
+		router.Use(middleware.SecurityHeaders(securityHeaders))
 
 		router.LoadHTMLGlob("templates/*.html")
 		router.Static("/static", "./static")
 		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+		router.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
 		router.GET("/", handlers.HandleMainPage(version))
 		router.GET("/login", handlers.HandleLogin)
 		router.GET("/logout", handlers.HandleLogout)
 		router.GET("/auth/callback", handlers.HandleCallback)
-		router.GET("/ws", handlers.HandleWebSocket)
+		router.GET("/ws", middleware.AuthMiddleware(staticToken), handlers.HandleWebSocket)
+		router.GET("/calendar/:token", handlers.HandleCalendarFeed)
+		router.GET("/shared/requests/:token", handlers.HandleSharedRequest)
+
+		if slackSigningSecret != "" {
+			handlers.SetSlackSigningSecret(slackSigningSecret)
+			router.POST("/slack/commands", handlers.HandleSlackCommand)
+			router.GET("/slack/link", handlers.HandleSlackLink)
+			logger.Logger.Info("Slack slash command enabled", "path", "/slack/commands")
+		}
+
+		if decisionLinkSecret != "" {
+			if publicURL == "" {
+				logger.Logger.Error("NETWATCH_DECISION_LINK_SECRET requires NETWATCH_PUBLIC_URL")
+				os.Exit(1)
+			}
+			decisionLinkTTL := 24 * time.Hour
+			if decisionLinkTTLStr != "" {
+				decisionLinkTTL, err = time.ParseDuration(decisionLinkTTLStr)
+				if err != nil || decisionLinkTTL <= 0 {
+					logger.Logger.Error("Invalid NETWATCH_DECISION_LINK_TTL", "value", decisionLinkTTLStr, "error", err)
+					os.Exit(1)
+				}
+			}
+			handlers.SetDecisionLinks(handlers.DecisionLinkConfig{Secret: decisionLinkSecret, BaseURL: publicURL, TTL: decisionLinkTTL})
+			router.GET("/decisions/:token", handlers.HandleDecisionLink)
+			router.POST("/decisions/:token", handlers.HandleDecisionLink)
+			logger.Logger.Info("Decision links enabled", "path", "/decisions", "ttl", decisionLinkTTL)
+		}
+
+		if graphqlEnabled == "true" {
+			handlers.SetGraphQLRouter(router)
+			logger.Logger.Info("GraphQL endpoint enabled", "path", "/api/graphql")
+		}
 
-		api := router.Group("/api")
-		api.Use(middleware.AuthMiddleware(staticToken))
-		{
-			api.GET("/services", handlers.GetServices)
-			api.GET("/active-accesses", handlers.GetActiveAccesses)
-			api.GET("/logs", handlers.GetLogs)
-			api.GET("/pending-requests", handlers.GetPendingRequests)
+		// /api/v1 is the stable API. /api stays an alias of the current version for existing clients.
+		for _, prefix := range []string{"/api/" + handlers.APIVersion, "/api"} {
+			api := router.Group(prefix)
+			api.Use(middleware.AuthMiddleware(staticToken))
+			api.Use(handlers.Idempotent())
+			registerAPIRoutes(api, version, buildDate)
+		}
+
+		// The gRPC API runs next to the REST API on its own port, its RPCs served by the REST endpoints above.
+		if grpcPort != "" {
+			listener, err := net.Listen("tcp", ":"+grpcPort)
+			if err != nil {
+				logger.Logger.Error("Could not listen for the gRPC API", "port", grpcPort, "error", err)
+				os.Exit(1)
+			}
+			go func() {
+				logger.Logger.Info("Netwatch gRPC server starting", "address", listener.Addr().String())
+				if err := handlers.NewGRPCServer(router).Serve(listener); err != nil {
+					logger.Logger.Error("Could not start gRPC server", "error", err)
+					os.Exit(1)
+				}
+			}()
 		}
 
 		if port == "" {
@@ -144,6 +645,55 @@ var serverCmd = &cobra.Command{
 	},
 }
 
+// kubeClientLimitsFromEnv reads the <prefix>_QPS, <prefix>_BURST and <prefix>_TIMEOUT variables. Invalid values are ignored.
+func kubeClientLimitsFromEnv(prefix string) k8s.ClientLimits {
+	var limits k8s.ClientLimits
+	if qps, err := strconv.ParseFloat(os.Getenv(prefix+"_QPS"), 32); err == nil && qps > 0 {
+		limits.QPS = float32(qps)
+	}
+	if burst, err := strconv.Atoi(os.Getenv(prefix + "_BURST")); err == nil && burst > 0 {
+		limits.Burst = burst
+	}
+	if timeout, err := time.ParseDuration(os.Getenv(prefix + "_TIMEOUT")); err == nil && timeout > 0 {
+		limits.Timeout = timeout
+	}
+	return limits
+}
+
+// authLockoutFromEnv reads the brute-force protection settings. Setting NETWATCH_AUTH_LOCKOUT_THRESHOLD to 0 disables it.
+func authLockoutFromEnv() middleware.LockoutConfig {
+	cfg := middleware.LockoutConfig{MaxFailures: 10, Window: 5 * time.Minute, BaseBan: time.Minute, MaxBan: time.Hour}
+	if threshold, err := strconv.Atoi(os.Getenv("NETWATCH_AUTH_LOCKOUT_THRESHOLD")); err == nil && threshold >= 0 {
+		cfg.MaxFailures = threshold
+	}
+	if window, err := time.ParseDuration(os.Getenv("NETWATCH_AUTH_LOCKOUT_WINDOW")); err == nil && window > 0 {
+		cfg.Window = window
+	}
+	if ban, err := time.ParseDuration(os.Getenv("NETWATCH_AUTH_LOCKOUT_BAN")); err == nil && ban > 0 {
+		cfg.BaseBan = ban
+	}
+	if maxBan, err := time.ParseDuration(os.Getenv("NETWATCH_AUTH_LOCKOUT_MAX_BAN")); err == nil && maxBan > 0 {
+		cfg.MaxBan = maxBan
+	}
+	cfg.MaxBan = max(cfg.MaxBan, cfg.BaseBan)
+	return cfg
+}
+
+// denyWindowFromEnv reads when repeatedly denied requests are held. Setting NETWATCH_DENY_WINDOW_THRESHOLD to 0 disables it.
+func denyWindowFromEnv() handlers.DenyWindowConfig {
+	cfg := handlers.DenyWindowConfig{MaxDenials: 3, Window: 24 * time.Hour, Cooldown: 24 * time.Hour}
+	if threshold, err := strconv.Atoi(os.Getenv("NETWATCH_DENY_WINDOW_THRESHOLD")); err == nil && threshold >= 0 {
+		cfg.MaxDenials = threshold
+	}
+	if window, err := time.ParseDuration(os.Getenv("NETWATCH_DENY_WINDOW")); err == nil && window > 0 {
+		cfg.Window = window
+	}
+	if cooldown, err := time.ParseDuration(os.Getenv("NETWATCH_DENY_WINDOW_COOLDOWN")); err == nil && cooldown > 0 {
+		cfg.Cooldown = cooldown
+	}
+	return cfg
+}
+
 func customLoggerMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
@@ -170,3 +720,66 @@ func customLoggerMiddleware() gin.HandlerFunc {
 		)
 	}
 }
+
+// registerAPIRoutes registers the REST endpoints on an API group, which already authenticates its requests.
+func registerAPIRoutes(api *gin.RouterGroup, version, buildDate string) {
+	api.GET("/services", handlers.GetServices)
+	api.GET("/services/:namespace/:name/reachability", handlers.GetServiceReachability)
+	api.GET("/namespaces", handlers.GetNamespaces)
+	api.GET("/permissions/explain", handlers.ExplainPermissions)
+	api.GET("/active-accesses", handlers.GetActiveAccesses)
+	api.GET("/active-accesses/export", handlers.ExportActiveAccesses)
+	api.GET("/topology", handlers.GetTopology)
+	api.GET("/stats", handlers.GetStats)
+	api.GET("/enforcement-drift", handlers.GetEnforcementDrift)
+	api.POST("/heartbeats/:id", handlers.SendHeartbeat)
+	api.DELETE("/heartbeats/:id", handlers.StopHeartbeat)
+	api.GET("/logs", handlers.GetLogs)
+	api.GET("/logs/export", handlers.ExportLogs)
+	api.GET("/logs/search", handlers.SearchLogs)
+	api.GET("/logs/stream", handlers.StreamLogs)
+	if handlers.GraphQLEnabled() {
+		api.POST("/graphql", handlers.GraphQL)
+	}
+	api.GET("/retention", handlers.GetRetentionPolicy)
+	api.GET("/version", handlers.GetVersion(version, buildDate))
+	api.GET("/schema", handlers.GetWebSocketSchema)
+	api.GET("/bootstrap", handlers.GetBootstrap(version))
+	api.POST("/calendar/token", handlers.CreateCalendarToken)
+	api.GET("/reports/exposure", handlers.GetExposureReport)
+	api.GET("/pending-requests", handlers.GetPendingRequests)
+	api.GET("/pending-requests/:id", handlers.GetRequestDetail)
+	api.GET("/my-requests", handlers.GetMyRequests)
+	api.GET("/my-accesses", handlers.GetMyAccesses)
+	api.DELETE("/my-accesses", handlers.RevokeMyAccesses)
+	api.POST("/pending-requests/:id/share", handlers.ShareRequest)
+	api.POST("/access-requests/import", handlers.ImportAccessRequest)
+	api.POST("/access-requests", handlers.SubmitAccessRequest)
+	api.POST("/pending-requests/:id/approve", handlers.ApproveAccessRequest)
+	api.POST("/pending-requests/:id/deny", handlers.DenyAccessRequest)
+	api.PATCH("/pending-requests/:id", handlers.UpdateAccessRequest)
+	api.POST("/accesses", handlers.CreateClusterAccess)
+	api.POST("/accesses/preview", handlers.PreviewAccess)
+	api.POST("/accesses/revoke-batch", handlers.RevokeBatch)
+	api.GET("/accesses/:namespace/:name", handlers.GetAccessDetail)
+	api.DELETE("/accesses/:namespace/:name", handlers.RevokeClusterAccess)
+	api.POST("/accesses/:namespace/:name/pause", handlers.PauseAccess)
+	api.POST("/external-accesses", handlers.CreateExternalAccess)
+	api.DELETE("/external-accesses/:namespace/:name", handlers.RevokeExternalAccess)
+	api.POST("/external-accesses/:namespace/:name/pause", handlers.PauseExternalAccess)
+	api.POST("/paused-accesses/:id/resume", handlers.ResumeAccess)
+	api.GET("/pre-approvals", handlers.ListPreApprovals)
+	api.POST("/pre-approvals", handlers.CreatePreApproval)
+	api.DELETE("/pre-approvals/:name", handlers.DeletePreApproval)
+	api.POST("/pending-requests/:id/attachments", handlers.UploadAttachment)
+	api.GET("/pending-requests/:id/attachments/:attachmentID", handlers.DownloadAttachment)
+
+	admin := api.Group("/admin")
+	admin.Use(handlers.RequireAdmin())
+	{
+		admin.GET("/reconcile-state", handlers.GetReconcileState)
+		admin.POST("/users/:email/offboard", handlers.OffboardUser)
+		admin.GET("/decisions", handlers.ListAccessDecisions)
+		admin.GET("/search", handlers.Search)
+	}
+}
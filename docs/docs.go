@@ -15,29 +15,196 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/active-accesses": {
-            "get": {
+        "/access-requests": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Submits an access request for review, like the submitAccessRequest WebSocket command, without a browser session. When the caller may create one side of a service-to-service access, that side is created at once and listed in accesses, and the request (status PendingSource or PendingTarget) waits for the approval of the other side only. Pre-approvals apply.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Submit an access request",
+                "parameters": [
+                    {
+                        "description": "Access request to submit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SubmitAccessRequestInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/access-requests/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Submits an AccessRequest described by a YAML or JSON manifest matching the CRD schema, with the same checks as a submission from the UI. metadata.name is ignored: names are generated. Request labels go in metadata.labels. spec.requestor may be omitted. Another user as spec.requestor, or the onBehalfOf parameter, files the request on their behalf: this requires the file-on-behalf verb on accessrequests, or being an admin. Server-managed fields (status, requestID, filedBy, clone names) must be empty.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Submit an access request from a manifest",
+                "parameters": [
+                    {
+                        "description": "AccessRequest manifest, YAML or JSON",
+                        "name": "manifest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email of the user to file the request for",
+                        "name": "onBehalfOf",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/accesses": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all active and partially-created (pending) access policies managed by Netwatch.",
+                "description": "Creates the service clones and Access objects between two services directly, like the requestClusterAccess WebSocket command: the caller needs to create services and accesses in both namespaces. Users without these permissions must submit an access request instead.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Access Policies"
                 ],
-                "summary": "List active access policies",
+                "summary": "Create a cluster access",
+                "parameters": [
+                    {
+                        "description": "Access to create",
+                        "name": "access",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateAccessInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.ActiveAccessInfo"
-                            }
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "401": {
@@ -46,38 +213,67 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
                     }
                 }
             }
         },
-        "/logs": {
-            "get": {
+        "/accesses/preview": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all persisted log entries from the application.",
+                "description": "Builds the service clones and the Access or ExternalAccess objects exactly like creating the access directly would, and returns them as YAML without creating anything. Names derive from a request ID that is generated again on creation, so they will differ. Permissions are not checked.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Access Policies"
+                ],
+                "summary": "Preview the manifests of an access",
+                "parameters": [
+                    {
+                        "description": "Access to preview",
+                        "name": "access",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PreviewAccessInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get global activity log",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.LogEntry"
-                            }
+                            "$ref": "#/definitions/handlers.AccessPreview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "401": {
@@ -86,6 +282,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -95,29 +297,52 @@ const docTemplate = `{
                 }
             }
         },
-        "/pending-requests": {
-            "get": {
+        "/accesses/revoke-batch": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves all pending AccessRequest custom resources and enriches them with the current user's permissions.",
+                "description": "Revokes the accesses of every request listed, and of every request with an Access or ExternalAccess matching the label selector, like the revokeClusterAccess and revokeExternalAccess WebSocket commands: both sides of a Service access are deleted, with the caller's own permissions. Each request gets its own result, a failure does not stop the others.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Requests"
+                    "Access Policies"
+                ],
+                "summary": "Revoke accesses in bulk",
+                "parameters": [
+                    {
+                        "description": "Accesses to revoke",
+                        "name": "revocation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevokeBatchInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
                 ],
-                "summary": "List pending access requests",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.AccessRequestPayload"
-                            }
+                            "$ref": "#/definitions/handlers.RevokeBatchResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
                     "401": {
@@ -126,6 +351,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -135,29 +366,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/services": {
+        "/accesses/{namespace}/{name}": {
             "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Retrieves a list of all services in the cluster, filtered to exclude system and clone services.",
+                "description": "Returns the Access spec, its paired Access, both service clones with the services they were cloned from, who requested and approved the access, when it was created and when it expires.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Access Policies"
+                ],
+                "summary": "Get an access policy with its provenance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace of the Access",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the Access",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "List all Kubernetes services",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handlers.ServiceInfo"
-                            }
+                            "$ref": "#/definitions/handlers.AccessDetail"
                         }
                     },
                     "401": {
@@ -166,6 +410,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handlers.HTTPError"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -173,135 +423,4673 @@ const docTemplate = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the Access and every Access sharing its request ID, like the revokeClusterAccess WebSocket command. The result lists the Access objects deleted and the service clones the controller removes with them. When some deletions fail, the objects that were deleted are still listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Revoke a cluster access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace of the Access",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the Access",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
             }
-        }
-    },
-    "definitions": {
-        "handlers.AccessRequestPayload": {
-            "type": "object",
-            "properties": {
-                "canSelfApprove": {
-                    "type": "boolean"
-                },
-                "cidr": {
-                    "type": "string"
-                },
-                "description": {
-                    "type": "string"
-                },
-                "direction": {
-                    "type": "string"
-                },
-                "duration": {
-                    "type": "integer"
-                },
-                "ports": {
-                    "type": "string"
-                },
-                "requestID": {
-                    "type": "string"
-                },
-                "requestType": {
-                    "type": "string"
-                },
-                "requestor": {
-                    "type": "string"
-                },
-                "service": {
-                    "type": "string"
-                },
-                "sourceService": {
-                    "type": "string"
-                },
+        },
+        "/accesses/{namespace}/{name}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the Access pair while keeping its service clones and remaining duration, like the pauseAccess WebSocket command. Resume it with its request ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Pause a cluster access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace of the Access",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the Access",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/active-accesses": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves all active, paused and partially-created (pending) access policies managed by Netwatch. The user and namespace filters are applied by the Kubernetes API server through label and namespace selectors.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "List active access policies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only accesses owned by this user, by email or username",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only accesses whose Access or ExternalAccess object is in this namespace",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Service",
+                            "External"
+                        ],
+                        "type": "string",
+                        "description": "Only accesses of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Active",
+                            "Pending",
+                            "Paused"
+                        ],
+                        "type": "string",
+                        "description": "Only accesses in this state",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ActiveAccessInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/active-accesses/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Downloads the access policies managed by Netwatch, with who requested and approved them, their source, target, ports, direction, creation and expiry, for compliance audits. The CSV has RFC 3339 timestamps, empty when the access never expires; the JSON has the same fields as the active access list. It takes the same filters as the active access list.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Export active access policies",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Format of the report, csv by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only accesses owned by this user, by email or username",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only accesses whose Access or ExternalAccess object is in this namespace",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Service",
+                            "External"
+                        ],
+                        "type": "string",
+                        "description": "Only accesses of this type",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Active",
+                            "Pending",
+                            "Paused"
+                        ],
+                        "type": "string",
+                        "description": "Only accesses in this state",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ActiveAccessInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/decisions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the audit records of the decisions on access requests, newest first: approvals, including by pre-approval or auto-approval rule, denials, aborts and expiries. Restricted to administrators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List access decisions",
+                "parameters": [
+                    {
+                        "enum": [
+                            "approved",
+                            "denied",
+                            "aborted",
+                            "expired"
+                        ],
+                        "type": "string",
+                        "description": "Only return this decision",
+                        "name": "decision",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return the decisions on the requests of this user",
+                        "name": "requestor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.AccessDecisionInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconcile-state": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists AccessRequests, Accesses and ExternalAccesses with their finalizers, deletion state and pending cleanup actions, merged with the last reconcile time and error reported by the controller manager. Restricted to administrators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get controller reconcile state",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only return resources whose name contains this value",
+                        "name": "name",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/controller.ReconcileState"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Case-insensitive search over request descriptions, requestors, service names, ticket references, labels and activity log payloads.\nPending requests are matched by submission time, decisions by decision time, with their approvers and comments too. Activity log matches are bounded by the log retention. Restricted to administrators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Search requests and activity history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return results created after this time (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only return results created before this time (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of results per category (default 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SearchResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{email}/offboard": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Ends every login session of the user, revokes their calendar feed token, linked Slack identities, heartbeat tokens and share links, and deletes their pending AccessRequests, Accesses and ExternalAccesses. The offboarding watcher does the same for the users listed in NETWATCH_OFFBOARDED_USERS_FILE; this endpoint offboards a user on demand. Restricted to administrators.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Offboard a deleted user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email of the deleted user",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.OffboardResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/bootstrap": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the data the main page is rendered with: the logged-in user, the session expiry, the server version and the optional features enabled. Callers authenticating with a bearer token get the identity of the token and its expiry.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get frontend bootstrap data",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Bootstrap"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/calendar/token": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a token-authenticated iCal feed URL listing the caller's access windows and upcoming expiries. Any previously issued feed URL is revoked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Generate a personal calendar feed",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CalendarFeedInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/enforcement-drift": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every Access/ExternalAccess whose NetworkPolicies or service clones were missing during the last periodic check.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "List enforcement drift",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.EnforcementDrift"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/external-accesses": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates the service clone and ExternalAccess object directly, like the requestExternalAccess WebSocket command: the caller needs to create services and externalaccesses in the namespace.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Create an external access",
+                "parameters": [
+                    {
+                        "description": "External access to create",
+                        "name": "access",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CreateExternalAccessInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/external-accesses/{namespace}/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the ExternalAccess, like the revokeExternalAccess WebSocket command. The result lists it and the service clone the controller removes with it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Revoke an external access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace of the ExternalAccess",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the ExternalAccess",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/external-accesses/{namespace}/{name}/pause": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the ExternalAccess while keeping its service clone and remaining duration, like the pauseAccess WebSocket command. Resume it with its request ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Pause an external access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace of the ExternalAccess",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the ExternalAccess",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Runs a GraphQL query, so a dashboard can be fetched in one round-trip. Only queries are supported, without fragments or directives, selections can be nested up to 10 levels deep, and a query can have up to 10 top-level fields and 10 aliases. The top-level fields are services, namespaces, activeAccesses, pendingRequests, myRequests and logs; they take the query parameters of the matching REST endpoints as arguments, and their objects have the fields of its JSON payloads. Each field is checked like a call to its endpoint: a field that fails is null, with the reason in errors. Disabled unless NETWATCH_GRAPHQL_ENABLED is true.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Query with GraphQL",
+                "parameters": [
+                    {
+                        "description": "GraphQL query",
+                        "name": "query",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GraphQLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.GraphQLResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/heartbeats/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Keeps an access opened with ` + "`" + `heartbeat: true` + "`" + ` alive. Without heartbeats, the access is revoked once the grace period elapses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Heartbeats"
+                ],
+                "summary": "Send a heartbeat for an access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Request ID of the access",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Heartbeat token returned when the access was opened",
+                        "name": "X-Netwatch-Heartbeat-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HeartbeatStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes an access opened with ` + "`" + `heartbeat: true` + "`" + ` without waiting for the grace period.",
+                "tags": [
+                    "Heartbeats"
+                ],
+                "summary": "Revoke a heartbeat-bound access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Request ID of the access",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Heartbeat token returned when the access was opened",
+                        "name": "X-Netwatch-Heartbeat-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the persisted log entries that are still within the retention period.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get global activity log",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LogEntry"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Streams every persisted activity log entry, oldest first, as NDJSON (one JSON entry per line) or CSV, to archive the log before retention trims it. Pass the timestamp of the last archived entry as since to only get what is newer.",
+                "produces": [
+                    "application/x-ndjson",
+                    "text/csv"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Export the activity log",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ndjson",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "Format of the export, ndjson by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only entries written at or after this Unix timestamp, in milliseconds",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LogEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/search": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the most recent persisted activity log entries matching every filter given, oldest first. The search runs on the server over the whole log kept in Redis.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Search the activity log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only entries whose payload contains this text, case-insensitive",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries of this log type, e.g. Service, External, Request or Global",
+                        "name": "logType",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries of this class, e.g. log-error",
+                        "name": "className",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries produced by, or mentioning, this user",
+                        "name": "user",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only entries written at or after this Unix timestamp, in milliseconds",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of entries, 100 by default and at most 1000",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LogEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/logs/stream": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Sends every activity log entry written after the call, or after since, as a Server-Sent Event named \"log\" whose data is the JSON entry. Every access created, approved, paused or revoked writes entries, so clients can refresh their lists on each event instead of polling them.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Stream the activity log",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Also send the entries written at or after this Unix timestamp, in milliseconds",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.LogEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/my-accesses": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the active, paused and partially-created access policies requested by the current user, whoever approved them. Accesses approved by the caller for someone else are not listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "List my access policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ActiveAccessInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes every Access and ExternalAccess requested by the current user, including partially-created ones, but not those the caller approved for someone else. Giving up one's own accesses does not require delete permissions on them. Paused accesses are left alone.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Revoke all my access policies",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RevokeAllResult"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/my-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the pending AccessRequests whose requestor is the current user, or that the user filed on someone else's behalf, newest first. Each comes with its status and when it was submitted and first looked at by an approver. Approved and denied requests are no longer listed, see the request detail for their history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "List my access requests",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.AccessRequestPayload"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/namespaces": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the namespaces of the cluster with their labels, excluding kube-system, sorted by name. With scoped, namespaces where the current user cannot get services are left out, as checked by a SubjectAccessReview per namespace.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "List Kubernetes namespaces",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Kubernetes label selector the namespaces must match, e.g. team=payments",
+                        "name": "labelSelector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only namespaces where the current user can get services",
+                        "name": "scoped",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.NamespaceInfo"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/paused-accesses/{id}/resume": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Recreates a paused Access pair or ExternalAccess with the duration it had left, like the resumeAccess WebSocket command.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Resume a paused access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Request ID of the paused access",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves all pending AccessRequest custom resources and enriches them with the current user's permissions, urgent requests first, then oldest first. Requests routed to the owners of their namespaces by the netwatch.vtk.io/owners annotation, or to approver groups by NETWATCH_APPROVER_GROUPS, are only listed to them and to their requestor. Only they get canSelfApprove.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "List pending access requests",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Only return requests carrying this label (key=value). Can be repeated.",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.AccessRequestPayload"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns an access request, which of its resources already exist and the direction each side enforces, what approving it would create, a risk score, the payload it was submitted with and how long it waited for review and decision. The submission and timing are kept after the request is approved or denied. Only the requestor and users allowed to approve the request can see it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Get access request details",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AccessRequestDetail"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Changes the ports, duration or description of a PendingFull access request of the user, until someone approves it, like the updateAccessRequest WebSocket command. Unlike an approver's changes, the requestor's may widen the request.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Update a pending access request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Changes",
+                        "name": "update",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UpdateAccessRequestInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approves a pending access request, or the side of a partial request still waiting, like the approveAccessRequest WebSocket command. The approver is the user of the Bearer token, e.g. a ChatOps bot's service account or the person it acts for, and needs the permissions to create the accesses. The static API key is rejected. The body is optional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Approve an access request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Approval comment",
+                        "name": "approval",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ApproveAccessRequestInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests/{id}/attachments": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Uploads a small, size-capped file (e.g. an architecture diagram or an approval email) to the attachment bucket and links it to the request. Only the requestor can attach files.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Attach a file to a pending request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "File to attach",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.AttachmentInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests/{id}/attachments/{attachmentID}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the raw content of a file attached to an access request, as a download. Only the requestor and the users allowed to approve the request can download it.",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Download a request attachment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Attachment ID",
+                        "name": "attachmentID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests/{id}/deny": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Denies a pending access request, or aborts it when called by its requestor or whoever filed it, like the denyAccessRequest WebSocket command. The reviewer is the user of the Bearer token and needs delete on the AccessRequest. The static API key is rejected. The reason is told to the requestor in the activity log, and denials without one are refused when NETWATCH_REQUIRE_DENIAL_REASON is set.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Deny or abort an access request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Denial reason",
+                        "name": "denial",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.DenyAccessRequestInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.CommandResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/pending-requests/{id}/share": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a time-limited link to the details of one request, to paste to an approver. Anyone with the link can read that request, and only that request, whatever their permissions. Only the requestor and users allowed to approve the request can share it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Requests"
+                ],
+                "summary": "Share an access request",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "AccessRequest name",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Link validity",
+                        "name": "share",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RequestShareInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RequestShareLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/permissions/explain": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists every RBAC permission creating the access directly requires, the same ones the UI checks, with whether the current user has each of them. Set source and target for a service-to-service access, or service for an external one. Missing permissions are what an approver has to provide, or what to ask the cluster administrators for.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Explain the permissions needed for an access",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Source service, as namespace/name",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Target service, as namespace/name",
+                        "name": "target",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service of an external access, as namespace/name",
+                        "name": "service",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PermissionExplanation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pre-approvals": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the pre-approval windows that have not ended yet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Requests"
+                ],
+                "summary": "List pre-approvals",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.PreApprovalInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a PreApproval: requests submitted by the listed users or groups during the window that match the services are approved automatically, on behalf of the caller. The caller must be able to approve such requests.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Requests"
+                ],
+                "summary": "Pre-approve access for a maintenance window",
+                "parameters": [
+                    {
+                        "description": "Pre-approval window",
+                        "name": "preApproval",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PreApprovalInput"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the first response to a retry with the same key, for 24 hours",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.PreApprovalInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/pre-approvals/{name}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a pre-approval. Allowed for its approver and anyone who could approve the requests it covers. Accesses already approved are kept.",
+                "tags": [
+                    "Access Requests"
+                ],
+                "summary": "Withdraw a pre-approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pre-approval name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/exposure": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Produces a point-in-time snapshot of every network path currently open through Netwatch (sources, targets, CIDRs, ports, owners, expiry),\nsigned with HMAC-SHA256 over the JSON encoding of the 'report' field. Suitable as compliance evidence.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Generate a signed exposure report",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SignedExposureReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/retention": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns how long activity log entries, request submissions and attachments are kept. Redis data is expired by Redis and trimmed on every write. The content of attachments is deleted from the bucket by an hourly sweep.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get the data retention policy",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RetentionPolicy"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/schema": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a JSON Schema of every command accepted on the /ws WebSocket and of the messages it sends back, for client generators. Commands are validated against it: a command that does not match is answered with a commandError message instead of running.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get the WebSocket message schema",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.WebSocketSchema"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/services": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a list of all services in the cluster, filtered to exclude system and clone services. With scoped, services the user cannot get are left out, as checked by a SubjectAccessReview per namespace. With limit, the list is paginated: pass the X-Netwatch-Continue response header as continue to get the next page, until it is absent.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "List all Kubernetes services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only services whose namespace/name contains this value, case-insensitive",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only services of this namespace",
+                        "name": "namespace",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Kubernetes label selector the services must match, e.g. app=web,tier!=cache",
+                        "name": "labelSelector",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of services per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Token of the page to fetch, from the X-Netwatch-Continue header of the previous one",
+                        "name": "continue",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only services in namespaces where the current user can get services",
+                        "name": "scoped",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/handlers.ServiceInfo"
+                            }
+                        },
+                        "headers": {
+                            "X-Netwatch-Continue": {
+                                "type": "string",
+                                "description": "Token of the next page, absent on the last one"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{namespace}/{name}/reachability": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aggregates the active Access and ExternalAccess objects managed by Netwatch that touch the service, as source or target, with their peers, directions, ports and expiry. Pending and paused accesses are left out, as they do not open anything.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "List who can reach a service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Namespace of the service",
+                        "name": "namespace",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name of the service",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.ServiceReachability"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Aggregates the access requests submitted, approved and denied per day, the active accesses per namespace, the top requestors, the average approval latency and the time to decision against the review SLA. Requests are tracked for 90 days, so longer periods are cut to that.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get usage statistics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days covered, 30 by default and at most 90",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.UsageStats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/topology": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the services and external CIDRs connected by active accesses managed by Netwatch as nodes, and the accesses as edges with their direction, ports and expiry, to render a live connectivity map. Pending and paused accesses are left out.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Access Policies"
+                ],
+                "summary": "Get the connectivity graph",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only accesses whose Access or ExternalAccess object is in this namespace",
+                        "name": "namespace",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.Topology"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the Netwatch version and build date, the versions of critical dependencies compiled in, and the versions of the netwatch and maxtac APIs served by the cluster.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get version information",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.VersionInfo"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handlers.HTTPError"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "controller.ReconcileState": {
+            "type": "object",
+            "properties": {
+                "deleting": {
+                    "type": "boolean"
+                },
+                "finalizers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "interruptedCleanup": {
+                    "description": "InterruptedCleanup is what a previous manager recorded as unfinished when it shut down.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "lastError": {
+                    "type": "string"
+                },
+                "lastReconcile": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "pendingCleanup": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.AccessDecisionInfo": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "spec": {
+                    "$ref": "#/definitions/v1alpha1.AccessDecisionSpec"
+                }
+            }
+        },
+        "handlers.AccessDetail": {
+            "type": "object",
+            "properties": {
+                "approvalComment": {
+                    "description": "ApprovalComment is the comment left by the approvers, if any.",
+                    "type": "string",
+                    "example": "Approved for the checkout incident"
+                },
+                "approvedBy": {
+                    "type": "string",
+                    "example": "john.roe@example.com"
+                },
+                "clones": {
+                    "description": "Clones are the service clones of both halves, with the service each one was cloned from.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.CloneInfo"
+                    }
+                },
+                "createdAt": {
+                    "type": "integer",
+                    "example": 1718000000
+                },
+                "description": {
+                    "description": "Description is why an access created without a request was needed, if its creator said.",
+                    "type": "string",
+                    "example": "Debugging the checkout flow"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is -1 when the access never expires.",
+                    "type": "integer",
+                    "example": 1718003600
+                },
+                "name": {
+                    "type": "string",
+                    "example": "access-nc-1a2b3c4d-61626364"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "owner": {
+                    "description": "Owner is the user the access is labelled with, the approver for accesses created by an approval.",
+                    "type": "string",
+                    "example": "jane.doe-example.com"
+                },
+                "pair": {
+                    "description": "Pair is the other half of a Service access, nil while it is not created yet.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.AccessDetailPair"
+                        }
+                    ]
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "requestor": {
+                    "description": "Requestor and ApprovedBy are empty for accesses created before they were recorded.",
+                    "type": "string",
+                    "example": "jane.doe@example.com"
+                },
+                "spec": {
+                    "type": "object"
+                }
+            }
+        },
+        "handlers.AccessDetailPair": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "spec": {
+                    "type": "object"
+                }
+            }
+        },
+        "handlers.AccessPreview": {
+            "type": "object",
+            "properties": {
+                "manifests": {
+                    "description": "Manifests are the service clones, then the Access or ExternalAccess objects, as a multi-document YAML.",
+                    "type": "string"
+                },
+                "requestID": {
+                    "description": "RequestID is only an example: creating the access generates a new one, and new clone names with it.",
+                    "type": "string",
+                    "example": "0b7f8a4e-2f4c-4c1e-9f0a-6f1d2b3c4d5e"
+                }
+            }
+        },
+        "handlers.AccessRequestDetail": {
+            "type": "object",
+            "properties": {
+                "effect": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "request": {
+                    "$ref": "#/definitions/handlers.AccessRequestPayload"
+                },
+                "risk": {
+                    "$ref": "#/definitions/handlers.RequestRisk"
+                },
+                "sides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RequestSideState"
+                    }
+                },
+                "submission": {
+                    "$ref": "#/definitions/handlers.RequestSubmission"
+                },
+                "timing": {
+                    "$ref": "#/definitions/handlers.RequestTiming"
+                }
+            }
+        },
+        "handlers.AccessRequestPayload": {
+            "type": "object",
+            "properties": {
+                "age": {
+                    "description": "Age is how long the request has been waiting for a decision, in seconds, and SLABreached whether that is\nlonger than its review SLA, see NETWATCH_REVIEW_SLA.",
+                    "type": "integer",
+                    "example": 5400
+                },
+                "approvedBy": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "approvedSides": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AttachmentInfo"
+                    }
+                },
+                "canSelfApprove": {
+                    "type": "boolean"
+                },
+                "cidr": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "dualApproval": {
+                    "description": "DualApproval is set on requests needing an approval from each side, and ApprovedSides maps the sides approved\nso far, source or target, to their approver.",
+                    "type": "boolean"
+                },
+                "duration": {
+                    "type": "integer"
+                },
+                "filedBy": {
+                    "description": "FiledBy is set when someone else filed the request on behalf of the requestor.",
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PriorDecision"
+                    }
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "permissionCheckFailed": {
+                    "description": "PermissionCheckFailed is set when CanSelfApprove could not be determined.",
+                    "type": "boolean"
+                },
+                "ports": {
+                    "type": "string"
+                },
+                "previousRequestID": {
+                    "description": "PreviousRequestID is the RequestID of the request this one resubmits, and History the decisions on its earlier\nsubmissions, the latest first.",
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string",
+                    "example": "normal"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "requestType": {
+                    "type": "string"
+                },
+                "requestor": {
+                    "type": "string"
+                },
+                "requiredApprovals": {
+                    "description": "RequiredApprovals and ApprovedBy are only set on requests that need several approvals.",
+                    "type": "integer"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "slaBreached": {
+                    "type": "boolean"
+                },
+                "sourceService": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "targetService": {
+                    "type": "string"
+                },
+                "ticketRef": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "timing": {
+                    "description": "Timing is only set in the requestor's own list, see GetMyRequests.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.RequestTiming"
+                        }
+                    ]
+                }
+            }
+        },
+        "handlers.ActiveAccessInfo": {
+            "type": "object",
+            "properties": {
+                "approvedBy": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "integer"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "drift": {
+                    "description": "Drift lists the enforcement problems found by the last drift check, if any.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expiresAt": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "ports": {
+                    "type": "string"
+                },
+                "remaining": {
+                    "type": "integer"
+                },
+                "requestID": {
+                    "description": "RequestID and Remaining are only set for paused accesses.",
+                    "type": "string"
+                },
+                "requestor": {
+                    "description": "Requestor and ApprovedBy are empty for accesses created before they were recorded, ApprovedBy also for\naccesses created without review.",
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ApproveAccessRequestInput": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "description": "Comment is kept in the activity log and on the accesses created, for audit.",
+                    "type": "string",
+                    "example": "Approved for the checkout incident"
+                },
+                "direction": {
+                    "description": "Direction, Ports and Duration narrow the request before it is approved: one direction instead of all\ntraffic, some of its ports, or a shorter duration in seconds. Empty fields keep the request as is.",
+                    "type": "string",
+                    "example": "ingress"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 1800
+                },
+                "ports": {
+                    "type": "string",
+                    "example": "443"
+                }
+            }
+        },
+        "handlers.AttachmentInfo": {
+            "type": "object",
+            "properties": {
+                "contentType": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "uploadedAt": {
+                    "type": "integer"
+                },
+                "uploadedBy": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Bootstrap": {
+            "type": "object",
+            "properties": {
+                "features": {
+                    "$ref": "#/definitions/handlers.Features"
+                },
+                "sessionExpiresAt": {
+                    "description": "SessionExpiresAt is when the session ends, as a Unix timestamp.",
+                    "type": "integer",
+                    "example": 1760000000
+                },
+                "user": {
+                    "type": "string",
+                    "example": "jane@example.com"
+                },
+                "userIP": {
+                    "type": "string",
+                    "example": "10.0.0.12"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.4.0"
+                }
+            }
+        },
+        "handlers.CalendarFeedInfo": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CloneInfo": {
+            "type": "object",
+            "properties": {
+                "clonedFrom": {
+                    "type": "string",
+                    "example": "default/frontend"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "nc-1a2b3c4d-61626364"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "default"
+                },
+                "ports": {
+                    "type": "string",
+                    "example": "8080/TCP"
+                }
+            }
+        },
+        "handlers.CommandResult": {
+            "type": "object",
+            "properties": {
+                "accesses": {
+                    "description": "Accesses are the namespace/name of the Access or ExternalAccess objects created.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "frontend/access-nc-1a2b3c4d5e6f-30623766"
+                    ]
+                },
+                "clones": {
+                    "description": "Clones are the namespace/name of the service clones the controller removes with the revoked objects.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "frontend/nc-1a2b3c4d5e6f-30623766"
+                    ]
+                },
+                "displayName": {
+                    "type": "string"
+                },
+                "messages": {
+                    "description": "Messages are the activity log entries produced while running the command.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LogEntry"
+                    }
+                },
+                "name": {
+                    "description": "Name is the AccessRequest submitted, or its request ID when it was approved at once by a pre-approval.",
+                    "type": "string"
+                },
+                "requestID": {
+                    "description": "RequestID identifies the accesses created, or the access request submitted.",
+                    "type": "string",
+                    "example": "0b7f8a4e-2f4c-4c1e-9f0a-6f1d2b3c4d5e"
+                },
+                "revoked": {
+                    "description": "Revoked are the namespace/name of the Access or ExternalAccess objects deleted by a revocation.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "frontend/access-nc-1a2b3c4d5e6f-30623766"
+                    ]
+                },
+                "status": {
+                    "description": "Status is the state of a submitted request: \"PendingFull\", \"PendingSource\", \"PendingTarget\", or \"Approved\".",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.CreateAccessInput": {
+            "type": "object",
+            "required": [
+                "sourceService",
+                "targetService"
+            ],
+            "properties": {
+                "description": {
+                    "description": "Description says why the access is needed, see NETWATCH_MIN_DESCRIPTION_LENGTH.",
+                    "type": "string",
+                    "example": "Debugging the checkout flow"
+                },
+                "direction": {
+                    "description": "Direction is ingress, egress or all, all when empty.",
+                    "type": "string",
+                    "example": "all"
+                },
+                "duration": {
+                    "description": "Duration is in seconds, 0 for an access that does not expire when allowed.",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "heartbeat": {
+                    "description": "Heartbeat binds the access to heartbeats, the token is in the messages. See SendHeartbeat.",
+                    "type": "boolean"
+                },
+                "ports": {
+                    "description": "Ports overrides the ports of the services, comma-separated.",
+                    "type": "string",
+                    "example": "8080,9090"
+                },
+                "sourceService": {
+                    "type": "string",
+                    "example": "frontend/web"
+                },
+                "targetService": {
+                    "type": "string",
+                    "example": "backend/api"
+                }
+            }
+        },
+        "handlers.CreateExternalAccessInput": {
+            "type": "object",
+            "required": [
+                "cidr",
+                "service"
+            ],
+            "properties": {
+                "cidr": {
+                    "description": "Cidr is the external IP or CIDR allowed.",
+                    "type": "string",
+                    "example": "203.0.113.0/24"
+                },
+                "description": {
+                    "description": "Description says why the access is needed, see NETWATCH_MIN_DESCRIPTION_LENGTH.",
+                    "type": "string",
+                    "example": "Partner API tests"
+                },
+                "direction": {
+                    "type": "string",
+                    "example": "ingress"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "heartbeat": {
+                    "type": "boolean"
+                },
+                "ports": {
+                    "type": "string",
+                    "example": "443"
+                },
+                "service": {
+                    "type": "string",
+                    "example": "backend/api"
+                }
+            }
+        },
+        "handlers.DailyRequestStats": {
+            "type": "object",
+            "properties": {
+                "approved": {
+                    "type": "integer",
+                    "example": 9
+                },
+                "date": {
+                    "type": "string",
+                    "example": "2025-06-10"
+                },
+                "denied": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "submitted": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "handlers.DenyAccessRequestInput": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Reason is told to the requestor in the activity log and kept in the request timing.",
+                    "type": "string",
+                    "example": "Use the staging database instead"
+                }
+            }
+        },
+        "handlers.EnforcementDrift": {
+            "type": "object",
+            "properties": {
+                "checkedAt": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ExposureReport": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ActiveAccessInfo"
+                    }
+                },
+                "generatedAt": {
+                    "type": "string"
+                },
+                "generatedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.Features": {
+            "type": "object",
+            "properties": {
+                "attachmentMaxBytes": {
+                    "type": "integer"
+                },
+                "attachments": {
+                    "type": "boolean"
+                },
+                "exposureReports": {
+                    "type": "boolean"
+                },
+                "generatedRequestNames": {
+                    "type": "boolean"
+                },
+                "graphql": {
+                    "type": "boolean"
+                },
+                "heartbeatGraceSeconds": {
+                    "type": "integer"
+                },
+                "idleTimeoutSeconds": {
+                    "description": "IdleTimeoutSeconds is how long a login session may stay unused before it ends, 0 when only the session TTL applies.",
+                    "type": "integer"
+                },
+                "maxAccessDurationSeconds": {
+                    "description": "MaxAccessDurationSeconds is the longest duration accepted, 0 when only maxtac's own limit applies.",
+                    "type": "integer"
+                },
+                "requestLabelKeys": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "slack": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handlers.GraphQLError": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.GraphQLRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string",
+                    "example": "{ services { name namespace } activeAccesses(status: \"Active\") { name source target expiresAt } }"
+                },
+                "variables": {
+                    "type": "object"
+                }
+            }
+        },
+        "handlers.GraphQLResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.GraphQLError"
+                    }
+                }
+            }
+        },
+        "handlers.HTTPError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Error message"
+                }
+            }
+        },
+        "handlers.HeartbeatStatus": {
+            "type": "object",
+            "properties": {
+                "requestID": {
+                    "type": "string"
+                },
+                "revokeAt": {
+                    "description": "RevokeAt is when the access is revoked if no further heartbeat is received, as a Unix timestamp.",
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.JSONSchema": {
+            "type": "object",
+            "properties": {
+                "additionalProperties": {
+                    "description": "AdditionalProperties is false for messages, which refuse unknown fields, and the schema of the values for maps."
+                },
+                "const": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "enum": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "examples": {
+                    "type": "array",
+                    "items": {}
+                },
+                "items": {
+                    "$ref": "#/definitions/handlers.JSONSchema"
+                },
+                "properties": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.JSONSchema"
+                    }
+                },
+                "required": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "example": "object"
+                }
+            }
+        },
+        "handlers.LogEntry": {
+            "type": "object",
+            "properties": {
+                "apiVersion": {
+                    "description": "APIVersion and SchemaVersion are only set on the hello entry sent when a WebSocket connects.",
+                    "type": "string"
+                },
+                "className": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "Groups are the approver groups a submission or reminder is routed to, see SetApproverGroups, or the owner\ngroups of its namespaces.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "logType": {
+                    "type": "string"
+                },
+                "owners": {
+                    "description": "Owners are the users a submission or reminder is routed to as owners of its namespaces.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requestID": {
+                    "description": "RequestID is set on denials, so the requestor can resubmit the request.",
+                    "type": "string"
+                },
+                "requestor": {
+                    "description": "Requestor and Reason are set on denials, so that the requestor can be told why. Requestor is also set on expiries.",
+                    "type": "string"
+                },
+                "schemaVersion": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user": {
+                    "description": "User is the email of the user whose command produced the entry, empty for background jobs.",
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.NamespaceInfo": {
+            "type": "object",
+            "properties": {
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.OffboardResult": {
+            "type": "object",
+            "properties": {
+                "accesses": {
+                    "description": "Accesses is the number of Access and ExternalAccess objects deleted.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "requests": {
+                    "description": "Requests is the number of their pending AccessRequests deleted.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "sessions": {
+                    "description": "Sessions is the number of login sessions ended.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "tokens": {
+                    "description": "Tokens is the number of per-user tokens revoked: the calendar feed token, the linked Slack identities, the\nheartbeat tokens of their accesses and the share links of their requests.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "user": {
+                    "type": "string",
+                    "example": "leaver@example.com"
+                }
+            }
+        },
+        "handlers.PermissionCheck": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "group": {
+                    "type": "string",
+                    "example": "maxtac.vtk.io"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "resource": {
+                    "type": "string",
+                    "example": "accesses"
+                },
+                "verb": {
+                    "type": "string",
+                    "example": "create"
+                }
+            }
+        },
+        "handlers.PermissionExplanation": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "description": "Allowed is true when the user has every permission, and can create the access without review.",
+                    "type": "boolean",
+                    "example": false
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.PermissionCheck"
+                    }
+                }
+            }
+        },
+        "handlers.PreApprovalInfo": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "spec": {
+                    "$ref": "#/definitions/v1alpha1.PreApprovalSpec"
+                }
+            }
+        },
+        "handlers.PreApprovalInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "end": {
+                    "type": "string",
+                    "example": "2026-10-17T06:00:00Z"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requestType": {
+                    "type": "string",
+                    "example": "Service"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "sourceService": {
+                    "type": "string",
+                    "example": "team-x/*"
+                },
+                "start": {
+                    "type": "string",
+                    "example": "2026-10-17T02:00:00Z"
+                },
+                "targetService": {
+                    "type": "string",
+                    "example": "prod/db"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.PreviewAccessInput": {
+            "type": "object",
+            "properties": {
+                "cidr": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string",
+                    "example": "all"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "ports": {
+                    "type": "string",
+                    "example": "8080,9090"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "sourceService": {
+                    "type": "string",
+                    "example": "frontend/web"
+                },
+                "targetService": {
+                    "type": "string",
+                    "example": "backend/api"
+                }
+            }
+        },
+        "handlers.PriorDecision": {
+            "type": "object",
+            "properties": {
+                "decidedAt": {
+                    "type": "integer",
+                    "example": 1760000600
+                },
+                "decidedBy": {
+                    "type": "string",
+                    "example": "approver@example.com"
+                },
+                "decision": {
+                    "type": "string",
+                    "example": "denied"
+                },
+                "reason": {
+                    "description": "Reason is why the request was denied, when it was said.",
+                    "type": "string",
+                    "example": "Use the staging database instead"
+                },
+                "request": {
+                    "description": "Request is the name the AccessRequest had.",
+                    "type": "string"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "submittedAt": {
+                    "type": "integer",
+                    "example": 1760000000
+                }
+            }
+        },
+        "handlers.ReachabilityPeer": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "type": "string",
+                    "example": "all"
+                },
+                "expiresAt": {
+                    "description": "ExpiresAt is -1 when the access never expires.",
+                    "type": "integer",
+                    "example": 1718003600
+                },
+                "name": {
+                    "description": "Name and Namespace identify the Access or ExternalAccess object.",
+                    "type": "string",
+                    "example": "access-nc-1a2b3c4d5e6f-30623766"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "backend"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "jane.doe-example.com"
+                },
+                "peer": {
+                    "description": "Peer is the other service, as namespace/name, or the CIDRs of an external access.",
+                    "type": "string",
+                    "example": "frontend/web"
+                },
+                "ports": {
+                    "type": "string",
+                    "example": "8080"
+                },
+                "role": {
+                    "description": "Role is the side of the access the queried service is on: \"source\" or \"target\".",
+                    "type": "string",
+                    "example": "target"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "Service"
+                }
+            }
+        },
+        "handlers.RequestRisk": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handlers.RequestShareInput": {
+            "type": "object",
+            "properties": {
+                "ttl": {
+                    "description": "TTL is a duration such as \"2h\". It defaults to 24h and is capped to 7 days.",
+                    "type": "string",
+                    "example": "2h"
+                }
+            }
+        },
+        "handlers.RequestShareLink": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "description": "ExpiresAt is when the link stops working, as a Unix timestamp.",
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RequestSideState": {
+            "type": "object",
+            "properties": {
+                "accessExists": {
+                    "type": "boolean"
+                },
+                "accessName": {
+                    "type": "string"
+                },
+                "cloneExists": {
+                    "type": "boolean"
+                },
+                "cloneName": {
+                    "type": "string"
+                },
+                "direction": {
+                    "description": "Direction is what the side's Access or ExternalAccess enforces, as derived from the request's direction.\nIt is empty when the request's direction is invalid and approval would be refused.",
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "side": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RequestSubmission": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "requestName": {
+                    "type": "string"
+                },
+                "spec": {
+                    "$ref": "#/definitions/v1alpha1.AccessRequestSpec"
+                },
+                "submittedAt": {
+                    "type": "integer"
+                },
+                "submittedBy": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.RequestTiming": {
+            "type": "object",
+            "properties": {
+                "decidedAt": {
+                    "type": "integer",
+                    "example": 1760000600
+                },
+                "decidedBy": {
+                    "type": "string",
+                    "example": "approver@example.com"
+                },
+                "decision": {
+                    "description": "Decision is approved, denied, aborted or expired. Aborted requests are withdrawn by their requestor, expired ones\ndeleted because nobody reviewed them in time. Neither is counted in the metrics.",
+                    "type": "string",
+                    "example": "approved"
+                },
+                "firstReviewAt": {
+                    "type": "integer",
+                    "example": 1760000300
+                },
+                "firstReviewer": {
+                    "type": "string",
+                    "example": "approver@example.com"
+                },
+                "reason": {
+                    "description": "Reason is why the request was denied or aborted, when it was said.",
+                    "type": "string",
+                    "example": "Use the staging database instead"
+                },
+                "submittedAt": {
+                    "type": "integer",
+                    "example": 1760000000
+                },
+                "timeToDecisionSeconds": {
+                    "type": "integer",
+                    "example": 600
+                },
+                "timeToFirstReviewSeconds": {
+                    "type": "integer",
+                    "example": 300
+                }
+            }
+        },
+        "handlers.RequestorStats": {
+            "type": "object",
+            "properties": {
+                "requestor": {
+                    "type": "string",
+                    "example": "jane.doe@example.com"
+                },
+                "requests": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "handlers.RetentionPolicy": {
+            "type": "object",
+            "properties": {
+                "activityLogSeconds": {
+                    "description": "ActivityLogSeconds applies to every activity log entry, counted from when it was written.",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "attachmentSeconds": {
+                    "description": "AttachmentSeconds applies to the documents attached to access requests, counted from their upload. Their\nmetadata expires from Redis, and an hourly sweep deletes their content from the bucket.",
+                    "type": "integer",
+                    "example": 2592000
+                },
+                "requestSubmissionSeconds": {
+                    "description": "RequestSubmissionSeconds applies to the payload stored with each access request.",
+                    "type": "integer",
+                    "example": 7776000
+                }
+            }
+        },
+        "handlers.ReviewSLAStats": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "description": "Breached counts the requests decided after their SLA, or expired without a review, during the period.",
+                    "type": "integer",
+                    "example": 2
+                },
+                "decisions": {
+                    "description": "Decisions counts the requests approved or denied during the period, and the percentiles their time to\ndecision.",
+                    "type": "integer",
+                    "example": 11
+                },
+                "medianDecisionSeconds": {
+                    "type": "integer",
+                    "example": 720
+                },
+                "p90DecisionSeconds": {
+                    "type": "integer",
+                    "example": 9000
+                },
+                "pendingOverdue": {
+                    "description": "PendingOverdue counts the requests still pending past their SLA.",
+                    "type": "integer",
+                    "example": 1
+                },
+                "targetSeconds": {
+                    "description": "TargetSeconds and UrgentTargetSeconds are the SLAs of normal and urgent requests, 0 when no SLA is set.",
+                    "type": "integer",
+                    "example": 14400
+                },
+                "urgentTargetSeconds": {
+                    "type": "integer",
+                    "example": 3600
+                }
+            }
+        },
+        "handlers.RevokeAllResult": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "description": "Deleted is the number of Access and ExternalAccess objects deleted.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "requestIDs": {
+                    "description": "RequestIDs are the requests whose accesses were revoked.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handlers.RevokeBatchInput": {
+            "type": "object",
+            "properties": {
+                "labelSelector": {
+                    "description": "LabelSelector is matched against the labels of the Access and ExternalAccess objects, e.g.\nnetwatch.vtk.io/user=jane.doe-example.com for all the accesses of a user.",
+                    "type": "string",
+                    "example": "netwatch.vtk.io/user=jane.doe-example.com"
+                },
+                "requestIDs": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "0b7f8a4e-2f4c-4c1e-9f0a-6f1d2b3c4d5e"
+                    ]
+                }
+            }
+        },
+        "handlers.RevokeBatchItem": {
+            "type": "object",
+            "properties": {
+                "clones": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "frontend/nc-1a2b3c4d5e6f-30623766"
+                    ]
+                },
+                "error": {
+                    "description": "Error is why the revocation failed. Part of the accesses may still have been revoked.",
+                    "type": "string"
+                },
+                "requestID": {
+                    "type": "string"
+                },
+                "revoked": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "frontend/access-nc-1a2b3c4d5e6f-30623766"
+                    ]
+                },
                 "status": {
+                    "description": "Status is \"revoked\", \"failed\", or \"notFound\" when the request has no access left.",
+                    "type": "string",
+                    "example": "revoked"
+                }
+            }
+        },
+        "handlers.RevokeBatchResult": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RevokeBatchItem"
+                    }
+                }
+            }
+        },
+        "handlers.SearchResults": {
+            "type": "object",
+            "properties": {
+                "decisions": {
+                    "description": "Decisions are the requests approved, denied, aborted or expired, which are no longer pending.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AccessDecisionInfo"
+                    }
+                },
+                "logs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.LogEntry"
+                    }
+                },
+                "query": {
+                    "type": "string"
+                },
+                "requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.AccessRequestPayload"
+                    }
+                }
+            }
+        },
+        "handlers.ServiceInfo": {
+            "type": "object",
+            "properties": {
+                "compound": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "namespace": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.ServiceReachability": {
+            "type": "object",
+            "properties": {
+                "peers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.ReachabilityPeer"
+                    }
+                },
+                "service": {
+                    "type": "string",
+                    "example": "backend/api"
+                }
+            }
+        },
+        "handlers.SignedExposureReport": {
+            "type": "object",
+            "properties": {
+                "algorithm": {
+                    "type": "string"
+                },
+                "report": {
+                    "$ref": "#/definitions/handlers.ExposureReport"
+                },
+                "signature": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SubmitAccessRequestInput": {
+            "type": "object",
+            "properties": {
+                "cidr": {
                     "type": "string"
                 },
-                "targetService": {
+                "description": {
+                    "type": "string",
+                    "example": "Debugging the checkout flow"
+                },
+                "direction": {
+                    "type": "string",
+                    "example": "all"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 3600
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "onBehalfOf": {
+                    "description": "OnBehalfOf files the request for another user, see the file-on-behalf verb.",
                     "type": "string"
                 },
-                "timestamp": {
-                    "type": "integer"
+                "ports": {
+                    "type": "string"
+                },
+                "previousRequestID": {
+                    "description": "PreviousRequestID is the denied or expired request this one resubmits, by RequestID or name. It must be for the\nsame requestor, source and target.",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority is low, normal or urgent, normal when empty.",
+                    "type": "string",
+                    "example": "urgent"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "sourceService": {
+                    "type": "string",
+                    "example": "frontend/web"
+                },
+                "targetService": {
+                    "type": "string",
+                    "example": "backend/api"
+                },
+                "ticketRef": {
+                    "description": "TicketRef is the Jira issue or ServiceNow record tracking the request.",
+                    "type": "string",
+                    "example": "NET-123"
                 }
             }
         },
-        "handlers.ActiveAccessInfo": {
+        "handlers.Topology": {
+            "type": "object",
+            "properties": {
+                "edges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TopologyEdge"
+                    }
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.TopologyNode"
+                    }
+                }
+            }
+        },
+        "handlers.TopologyEdge": {
             "type": "object",
             "properties": {
                 "direction": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "all"
                 },
                 "expiresAt": {
-                    "type": "integer"
+                    "description": "ExpiresAt is -1 when the access never expires.",
+                    "type": "integer",
+                    "example": 1718003600
                 },
                 "name": {
-                    "type": "string"
+                    "description": "Name and Namespace identify the Access or ExternalAccess object.",
+                    "type": "string",
+                    "example": "access-nc-1a2b3c4d5e6f-30623766"
                 },
                 "namespace": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "backend"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "jane.doe-example.com"
                 },
                 "ports": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "8080"
                 },
                 "source": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "frontend/web"
                 },
                 "target": {
-                    "type": "string"
+                    "type": "string",
+                    "example": "backend/api"
                 },
                 "type": {
+                    "type": "string",
+                    "example": "Service"
+                }
+            }
+        },
+        "handlers.TopologyNode": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "ID is namespace/name for a service, the CIDRs for external peers.",
+                    "type": "string",
+                    "example": "backend/api"
+                },
+                "kind": {
+                    "description": "Kind is \"Service\" or \"CIDR\".",
+                    "type": "string",
+                    "example": "Service"
+                },
+                "namespace": {
+                    "type": "string",
+                    "example": "backend"
+                }
+            }
+        },
+        "handlers.UpdateAccessRequestInput": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "example": "Debugging the checkout flow, TLS ports too"
+                },
+                "duration": {
+                    "type": "integer",
+                    "example": 7200
+                },
+                "ports": {
+                    "type": "string",
+                    "example": "443,8443"
+                }
+            }
+        },
+        "handlers.UsageStats": {
+            "type": "object",
+            "properties": {
+                "activeAccessesByNamespace": {
+                    "description": "ActiveAccessesByNamespace counts the active accesses by the namespace of their Access or ExternalAccess object.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "averageApprovalSeconds": {
+                    "description": "AverageApprovalSeconds is the average time between submission and approval of the requests approved during\nthe period, 0 when there were none.",
+                    "type": "integer",
+                    "example": 840
+                },
+                "days": {
+                    "description": "Days are the days of the period, oldest first. Requests are counted on the day they were submitted, approvals\nand denials on the day they were decided.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.DailyRequestStats"
+                    }
+                },
+                "reviewSLA": {
+                    "description": "ReviewSLA measures the time to decision of the requests decided during the period.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handlers.ReviewSLAStats"
+                        }
+                    ]
+                },
+                "topRequestors": {
+                    "description": "TopRequestors ranks who submitted the most requests during the period.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handlers.RequestorStats"
+                    }
+                }
+            }
+        },
+        "handlers.VersionInfo": {
+            "type": "object",
+            "properties": {
+                "apiVersions": {
+                    "description": "APIVersions maps API groups to the versions the cluster serves, preferred first. Empty if the CRDs are missing.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "apiVersionsError": {
+                    "description": "APIVersionsError is set when the cluster could not be queried.",
+                    "type": "string"
+                },
+                "buildDate": {
+                    "type": "string",
+                    "example": "2026-10-01T12:00:00Z"
+                },
+                "dependencies": {
+                    "description": "Dependencies maps critical module paths to the version compiled in.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "goVersion": {
+                    "type": "string",
+                    "example": "go1.24.5"
+                },
+                "modified": {
+                    "type": "boolean"
+                },
+                "platform": {
+                    "type": "string",
+                    "example": "linux/amd64"
+                },
+                "revision": {
+                    "description": "Revision and Modified describe the VCS state the binary was built from, when known.",
                     "type": "string"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.4.0"
                 }
             }
         },
-        "handlers.HTTPError": {
+        "handlers.WebSocketSchema": {
             "type": "object",
             "properties": {
-                "error": {
+                "$schema": {
                     "type": "string",
-                    "example": "Error message"
+                    "example": "https://json-schema.org/draft/2020-12/schema"
+                },
+                "commands": {
+                    "description": "Commands maps each command to the schema of its message.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.JSONSchema"
+                    }
+                },
+                "events": {
+                    "description": "Events maps the messages the server sends to their schema: logEntry for everything but commandError.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/handlers.JSONSchema"
+                    }
+                },
+                "schemaVersion": {
+                    "description": "SchemaVersion is the version of the command messages, also sent in the hello message.",
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
-        "handlers.LogEntry": {
+        "v1alpha1.AccessDecisionSpec": {
             "type": "object",
             "properties": {
-                "className": {
+                "accessRequest": {
+                    "description": "AccessRequest is the request as it was decided, with the changes of its approver.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/v1alpha1.AccessRequestSpec"
+                        }
+                    ]
+                },
+                "changes": {
+                    "description": "Changes lists how the approver narrowed the request before approving it.\n+optional",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "comment": {
+                    "description": "Comment is the approval comment, or the reason of a denial or abort.\n+optional",
                     "type": "string"
                 },
-                "logType": {
+                "decidedAt": {
+                    "description": "DecidedAt is when the request was decided.",
                     "type": "string"
                 },
-                "payload": {
+                "decidedBy": {
+                    "description": "DecidedBy is who approved, denied or aborted the request, its approvers comma-separated when it needed several,\nthe auto-approval rule which approved it, or \"netwatch\" when it expired.",
                     "type": "string"
                 },
-                "timestamp": {
+                "decision": {
+                    "description": "Decision is \"approved\", \"denied\", \"aborted\" or \"expired\". Aborted requests were withdrawn by their requestor,\nexpired ones deleted because nobody reviewed them in time.\n+kubebuilder:validation:Enum=approved;denied;aborted;expired",
+                    "type": "string"
+                },
+                "request": {
+                    "description": "Request is the name of the AccessRequest decided.",
+                    "type": "string"
+                },
+                "submittedAt": {
+                    "description": "SubmittedAt is when the request was submitted.",
+                    "type": "string"
+                }
+            }
+        },
+        "v1alpha1.AccessRequestSpec": {
+            "type": "object",
+            "properties": {
+                "approvedBy": {
+                    "description": "ApprovedBy lists the approvals collected so far by a request that needs several.\n+optional",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1alpha1.Approval"
+                    }
+                },
+                "cidr": {
+                    "type": "string"
+                },
+                "description": {
+                    "description": "+optional",
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "dualApproval": {
+                    "description": "DualApproval requires an approval from each side, source and target, of a Service request spanning the\nnamespaces of two teams before the accesses are created.\n+optional",
+                    "type": "boolean"
+                },
+                "duration": {
                     "type": "integer"
                 },
-                "type": {
+                "filedBy": {
+                    "description": "FiledBy is the user who filed the request on behalf of the requestor, when it is not the requestor.\n+optional",
+                    "type": "string"
+                },
+                "ports": {
+                    "type": "string"
+                },
+                "previousRequestID": {
+                    "description": "PreviousRequestID is the RequestID of the denied or expired request this one resubmits, chaining their history.\n+optional",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "Priority is \"low\", \"normal\" or \"urgent\". Urgent requests are listed first and reminded sooner.\n+optional\n+kubebuilder:validation:Enum=low;normal;urgent",
+                    "type": "string"
+                },
+                "requestID": {
+                    "description": "RequestID is the unique ID shared by the final Access objects, generated at submission time.\n+optional",
+                    "type": "string"
+                },
+                "requestType": {
+                    "type": "string"
+                },
+                "requestor": {
+                    "type": "string"
+                },
+                "requiredApprovals": {
+                    "description": "RequiredApprovals is how many different approvers must approve the request before the accesses are created.\nZero and one mean a single approval.\n+optional",
+                    "type": "integer"
+                },
+                "service": {
+                    "type": "string"
+                },
+                "sourceCloneName": {
+                    "description": "SourceCloneName is the name of the service clone created in the source namespace.\n+optional",
+                    "type": "string"
+                },
+                "sourceService": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status indicates the current state of the request.\nCan be \"PendingFull\", \"PendingTarget\", \"PendingSource\".",
+                    "type": "string"
+                },
+                "targetCloneName": {
+                    "description": "TargetCloneName is the name of the service clone created in the target namespace.\n+optional",
+                    "type": "string"
+                },
+                "targetService": {
+                    "type": "string"
+                },
+                "ticketRef": {
+                    "description": "TicketRef is the Jira issue or ServiceNow record tracking the request, e.g. \"NET-123\".\n+optional",
                     "type": "string"
                 }
             }
         },
-        "handlers.ServiceInfo": {
+        "v1alpha1.Approval": {
             "type": "object",
             "properties": {
-                "compound": {
+                "approvedAt": {
                     "type": "string"
                 },
-                "labels": {
-                    "type": "object",
-                    "additionalProperties": {
+                "approver": {
+                    "type": "string"
+                },
+                "comment": {
+                    "description": "Comment is the approver's optional note.\n+optional",
+                    "type": "string"
+                },
+                "side": {
+                    "description": "Side is \"source\" or \"target\", the side of a DualApproval request the approval is for.\n+optional\n+kubebuilder:validation:Enum=source;target",
+                    "type": "string"
+                }
+            }
+        },
+        "v1alpha1.PreApprovalSpec": {
+            "type": "object",
+            "properties": {
+                "approver": {
+                    "description": "Approver is the identity that granted the pre-approval. Matching requests are approved on its behalf,\nso its permissions are checked again at approval time.",
+                    "type": "string"
+                },
+                "approverGroups": {
+                    "description": "+optional",
+                    "type": "array",
+                    "items": {
                         "type": "string"
                     }
                 },
-                "name": {
+                "description": {
+                    "description": "+optional",
                     "type": "string"
                 },
-                "namespace": {
+                "end": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "+optional",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requestType": {
+                    "description": "RequestType is \"Service\" or \"External\".",
+                    "type": "string"
+                },
+                "service": {
+                    "description": "Service is \"namespace/name\" or \"namespace/*\" for External requests.\n+optional",
+                    "type": "string"
+                },
+                "sourceService": {
+                    "description": "SourceService and TargetService are \"namespace/name\" or \"namespace/*\" for Service requests.\n+optional",
                     "type": "string"
+                },
+                "start": {
+                    "description": "Start and End bound the window. Approved accesses never outlive End.",
+                    "type": "string"
+                },
+                "targetService": {
+                    "description": "+optional",
+                    "type": "string"
+                },
+                "users": {
+                    "description": "Users and Groups list who may self-serve the access. At least one of them must be set.\n+optional",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
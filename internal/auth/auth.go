@@ -0,0 +1,167 @@
+// Package auth verifies the tokens of every entry point of the server: the API middleware,
+// the login callback and the per-request permission checks all go through Verify. OIDC ID tokens
+// are always accepted; service account tokens and client certificates can be enabled on top.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// Config holds the token verification settings shared by every caller.
+type Config struct {
+	IssuerURL string
+	ClientID  string
+	// Audiences lists extra accepted "aud" values on top of the client ID, e.g. for tokens minted for a CLI client.
+	Audiences []string
+	// ClockSkew is how long an expired token is still accepted, to absorb clock drift with the identity provider.
+	ClockSkew time.Duration
+	// EmailClaim and GroupsClaim name the claims holding the user's identity. They default to "email" and "groups".
+	EmailClaim  string
+	GroupsClaim string
+}
+
+// Identity is the verified identity carried by a token. Email is the name the user is impersonated as.
+type Identity struct {
+	Email  string
+	Groups []string
+	// Claims holds every claim of the ID token, e.g. a directory-provided employee ID.
+	Claims map[string]any
+	// Provider names the identity provider that authenticated the token, e.g. "oidc" or "serviceaccount".
+	Provider string
+	// Scope restricts what the identity can request. It is nil for users, who are only bound by RBAC.
+	Scope *Scope
+}
+
+// Provider authenticates tokens that are not OIDC ID tokens.
+type Provider interface {
+	Name() string
+	Authenticate(ctx context.Context, token string) (*Identity, error)
+}
+
+var (
+	mu       sync.RWMutex
+	config   Config
+	verifier *oidc.IDTokenVerifier
+	endpoint oauth2.Endpoint
+	extra    []Provider
+)
+
+// RegisterProvider adds an identity provider tried, in registration order, for tokens the OIDC verifier rejects.
+func RegisterProvider(p Provider) {
+	mu.Lock()
+	defer mu.Unlock()
+	extra = append(extra, p)
+}
+
+// Init discovers the identity provider and builds the shared verifier. It must be called once at startup,
+// after SetCache when the discovery document should be shared through Redis.
+func Init(ctx context.Context, cfg Config) error {
+	if cfg.EmailClaim == "" {
+		cfg.EmailClaim = "email"
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	providerConfig, err := loadProviderConfig(ctx, cfg.IssuerURL)
+	if err != nil {
+		return err
+	}
+	algorithms := supportedAlgorithms(providerConfig.Algorithms)
+	keySet := &cachedKeySet{jwksURL: providerConfig.JWKSURL, algorithms: signatureAlgorithms(algorithms)}
+	oidcConfig := &oidc.Config{
+		ClientID:             cfg.ClientID,
+		SupportedSigningAlgs: algorithms,
+		// The audience is checked in Verify, against the client ID and the extra audiences.
+		SkipClientIDCheck: true,
+		Now:               func() time.Time { return time.Now().Add(-cfg.ClockSkew) },
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	config = cfg
+	verifier = oidc.NewVerifier(providerConfig.IssuerURL, keySet, oidcConfig)
+	endpoint = oauth2.Endpoint{AuthURL: providerConfig.AuthURL, TokenURL: providerConfig.TokenURL, DeviceAuthURL: providerConfig.DeviceAuthURL}
+	return nil
+}
+
+// Endpoint returns the OAuth2 endpoints of the identity provider, for the login flow.
+func Endpoint() oauth2.Endpoint {
+	mu.RLock()
+	defer mu.RUnlock()
+	return endpoint
+}
+
+// Verify authenticates a raw token and extracts the user's identity. OIDC ID tokens are tried first, then
+// every registered provider. When all of them reject the token, the OIDC error is returned.
+func Verify(ctx context.Context, rawToken string) (*Identity, error) {
+	mu.RLock()
+	v, cfg, providers := verifier, config, extra
+	mu.RUnlock()
+
+	identity, err := verifyIDToken(ctx, v, cfg, rawToken)
+	if err == nil {
+		return identity, nil
+	}
+	for _, p := range providers {
+		if identity, providerErr := p.Authenticate(ctx, rawToken); providerErr == nil {
+			identity.Provider = p.Name()
+			return identity, nil
+		}
+	}
+	return nil, err
+}
+
+// verifyIDToken checks the signature, expiry and audience of an OIDC ID token.
+func verifyIDToken(ctx context.Context, v *oidc.IDTokenVerifier, cfg Config, rawIDToken string) (*Identity, error) {
+	if v == nil {
+		return nil, errors.New("token verification failed: OIDC is not initialized")
+	}
+
+	idToken, err := v.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("token verification failed: %w", err)
+	}
+	if !audienceAllowed(idToken.Audience, cfg) {
+		return nil, fmt.Errorf("token verification failed: audience %v is not accepted", idToken.Audience)
+	}
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("parsing claims failed: %w", err)
+	}
+	email, _ := claims[cfg.EmailClaim].(string)
+	return &Identity{Email: email, Groups: stringList(claims[cfg.GroupsClaim]), Claims: claims, Provider: "oidc"}, nil
+}
+
+func audienceAllowed(audience []string, cfg Config) bool {
+	for _, aud := range audience {
+		if aud == cfg.ClientID || slices.Contains(cfg.Audiences, aud) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList reads a claim that is either a list of strings or a single string.
+func stringList(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	oidcDiscoveryKeyPrefix = "netwatch:oidc:discovery:"
+	oidcJWKSKeyPrefix      = "netwatch:oidc:jwks:"
+	// oidcCacheTTL is how long the discovery document and keys are shared through Redis.
+	oidcCacheTTL = time.Hour
+	// jwksLocalTTL is how long a replica reuses keys before checking Redis again.
+	jwksLocalTTL = time.Minute
+)
+
+var (
+	oidcRedis *redis.Client
+	oidcHTTP  = &http.Client{Timeout: 10 * time.Second}
+)
+
+// SetCache shares the OIDC discovery document and signing keys between replicas through Redis,
+// so bursts of token verifications don't all reach the identity provider.
+func SetCache(client *redis.Client) {
+	oidcRedis = client
+}
+
+// loadProviderConfig reads the discovery document from Redis, or fetches it from the issuer and caches it.
+func loadProviderConfig(ctx context.Context, issuerURL string) (*oidc.ProviderConfig, error) {
+	var providerConfig oidc.ProviderConfig
+	if oidcRedis != nil {
+		if cached, err := oidcRedis.Get(ctx, oidcDiscoveryKeyPrefix+issuerURL).Bytes(); err == nil {
+			if err := json.Unmarshal(cached, &providerConfig); err == nil {
+				return &providerConfig, nil
+			}
+		}
+	}
+
+	body, err := fetchOIDCDocument(ctx, strings.TrimSuffix(issuerURL, "/")+"/.well-known/openid-configuration")
+	if err != nil {
+		return nil, fmt.Errorf("oidc provider failed: %w", err)
+	}
+	if err := json.Unmarshal(body, &providerConfig); err != nil {
+		return nil, fmt.Errorf("oidc provider failed: could not decode discovery document: %w", err)
+	}
+	if providerConfig.IssuerURL != issuerURL {
+		return nil, fmt.Errorf("oidc provider failed: issuer did not match, expected %q got %q", issuerURL, providerConfig.IssuerURL)
+	}
+	if oidcRedis != nil {
+		if err := oidcRedis.Set(ctx, oidcDiscoveryKeyPrefix+issuerURL, body, oidcCacheTTL).Err(); err != nil {
+			logger.Logger.Warn("Failed to cache OIDC discovery document", "error", err)
+		}
+	}
+	return &providerConfig, nil
+}
+
+func fetchOIDCDocument(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := oidcHTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:all
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	return body, nil
+}
+
+// supportedAlgorithms keeps the asymmetric algorithms advertised by the provider, defaulting to RS256.
+func supportedAlgorithms(algs []string) []string {
+	var result []string
+	for _, alg := range algs {
+		switch alg {
+		case oidc.RS256, oidc.RS384, oidc.RS512, oidc.ES256, oidc.ES384, oidc.ES512, oidc.PS256, oidc.PS384, oidc.PS512, oidc.EdDSA:
+			result = append(result, alg)
+		}
+	}
+	if len(result) == 0 {
+		return []string{oidc.RS256}
+	}
+	return result
+}
+
+func signatureAlgorithms(algs []string) []jose.SignatureAlgorithm {
+	result := make([]jose.SignatureAlgorithm, 0, len(algs))
+	for _, alg := range algs {
+		result = append(result, jose.SignatureAlgorithm(alg))
+	}
+	return result
+}
+
+// cachedKeySet verifies token signatures with keys shared through Redis. Keys are fetched again from the
+// identity provider when a token is signed by an unknown key, which happens after a key rotation.
+type cachedKeySet struct {
+	jwksURL    string
+	algorithms []jose.SignatureAlgorithm
+
+	mu        sync.Mutex
+	keys      *jose.JSONWebKeySet
+	fetchedAt time.Time
+}
+
+func (s *cachedKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	jws, err := jose.ParseSigned(jwt, s.algorithms)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: malformed jwt: %w", err)
+	}
+	keys, err := s.currentKeys(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+	if payload, ok := verifyWithKeys(jws, keys); ok {
+		return payload, nil
+	}
+	keys, err = s.currentKeys(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if payload, ok := verifyWithKeys(jws, keys); ok {
+		return payload, nil
+	}
+	return nil, fmt.Errorf("failed to verify id token signature")
+}
+
+func verifyWithKeys(jws *jose.JSONWebSignature, keys *jose.JSONWebKeySet) ([]byte, bool) {
+	keyID := ""
+	if len(jws.Signatures) > 0 {
+		keyID = jws.Signatures[0].Header.KeyID
+	}
+	for _, key := range keys.Keys {
+		if keyID == "" || key.KeyID == keyID {
+			if payload, err := jws.Verify(&key); err == nil {
+				return payload, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// currentKeys returns the signing keys, from memory, Redis or the identity provider. With refresh, the
+// keys are always fetched from the identity provider.
+func (s *cachedKeySet) currentKeys(ctx context.Context, refresh bool) (*jose.JSONWebKeySet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !refresh && s.keys != nil && time.Since(s.fetchedAt) < jwksLocalTTL {
+		return s.keys, nil
+	}
+	if !refresh && oidcRedis != nil {
+		if cached, err := oidcRedis.Get(ctx, oidcJWKSKeyPrefix+s.jwksURL).Bytes(); err == nil {
+			var keys jose.JSONWebKeySet
+			if err := json.Unmarshal(cached, &keys); err == nil {
+				s.keys, s.fetchedAt = &keys, time.Now()
+				return s.keys, nil
+			}
+		}
+	}
+
+	body, err := fetchOIDCDocument(ctx, s.jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: fetching keys: %w", err)
+	}
+	var keys jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil, fmt.Errorf("oidc: decoding keys: %w", err)
+	}
+	if oidcRedis != nil {
+		if err := oidcRedis.Set(ctx, oidcJWKSKeyPrefix+s.jwksURL, body, oidcCacheTTL).Err(); err != nil {
+			logger.Logger.Warn("Failed to cache OIDC signing keys", "error", err)
+		}
+	}
+	s.keys, s.fetchedAt = &keys, time.Now()
+	return s.keys, nil
+}
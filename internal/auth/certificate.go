@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// certificateSessionTTL is how long the token of a client certificate stays valid after its last use. Tokens only
+// live in memory and are only handed to the request (or WebSocket) that presented the certificate.
+const certificateSessionTTL = 15 * time.Minute
+
+// certificateProvider maps client certificates verified by the ingress to identities, following the
+// Kubernetes convention: the common name is the user and the organizations are the groups.
+type certificateProvider struct {
+	header string
+	roots  *x509.CertPool
+
+	mu       sync.Mutex
+	sessions map[string]*certificateSession
+	// tokens maps the SHA-256 fingerprint of a verified certificate to its token, so each certificate is verified
+	// and given a token once, not on every request.
+	tokens map[string]string
+}
+
+type certificateSession struct {
+	identity    Identity
+	fingerprint string
+	notAfter    time.Time
+	lastUsed    time.Time
+}
+
+func (s *certificateSession) valid(now time.Time) bool {
+	return now.Before(s.notAfter) && now.Sub(s.lastUsed) <= certificateSessionTTL
+}
+
+var certificates *certificateProvider
+
+// EnableClientCertificates accepts client certificates forwarded by the ingress in header as a URL-encoded PEM,
+// e.g. "ssl-client-cert" for ingress-nginx. Certificates must chain to caPEM. The ingress must verify the client
+// and always overwrite the header, otherwise anyone could present someone else's certificate.
+func EnableClientCertificates(header string, caPEM []byte) error {
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caPEM) {
+		return errors.New("no CA certificate found for client certificate authentication")
+	}
+	certificates = &certificateProvider{
+		header: header, roots: roots, sessions: make(map[string]*certificateSession), tokens: make(map[string]string),
+	}
+	RegisterProvider(certificates)
+	return nil
+}
+
+// TokenFromCertificate authenticates the client certificate forwarded with r and returns a token standing for
+// its identity. ok is false when client certificates are disabled or no certificate was forwarded. A certificate
+// keeps its token until it expires or goes unused for certificateSessionTTL.
+func TokenFromCertificate(r *http.Request) (token string, identity *Identity, ok bool, err error) {
+	p := certificates
+	if p == nil || r.Header.Get(p.header) == "" {
+		return "", nil, false, nil
+	}
+	cert, err := certificateFromHeader(r.Header.Get(p.header))
+	if err != nil {
+		return "", nil, true, err
+	}
+	sum := sha256.Sum256(cert.Raw)
+	fingerprint := hex.EncodeToString(sum[:])
+	now := time.Now()
+
+	p.mu.Lock()
+	if session, cached := p.sessions[p.tokens[fingerprint]]; cached && session.valid(now) {
+		session.lastUsed = now
+		token, identity := p.tokens[fingerprint], session.identity
+		p.mu.Unlock()
+		return token, &identity, true, nil
+	}
+	p.mu.Unlock()
+
+	identity, err = p.verify(cert)
+	if err != nil {
+		return "", nil, true, err
+	}
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", nil, true, err
+	}
+	token = "x509." + base64.RawURLEncoding.EncodeToString(buf)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// Only a certificate seen for the first time, or again after its token expired, gets here.
+	for t, s := range p.sessions {
+		if !s.valid(now) {
+			delete(p.sessions, t)
+			delete(p.tokens, s.fingerprint)
+		}
+	}
+	if previous, exists := p.tokens[fingerprint]; exists {
+		delete(p.sessions, previous)
+	}
+	p.tokens[fingerprint] = token
+	p.sessions[token] = &certificateSession{identity: *identity, fingerprint: fingerprint, notAfter: cert.NotAfter, lastUsed: now}
+	return token, identity, true, nil
+}
+
+func certificateFromHeader(value string) (*x509.Certificate, error) {
+	decoded, err := url.QueryUnescape(value)
+	if err != nil {
+		return nil, errors.New("malformed client certificate header")
+	}
+	block, _ := pem.Decode([]byte(decoded))
+	if block == nil {
+		return nil, errors.New("malformed client certificate header")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func (p *certificateProvider) verify(cert *x509.Certificate) (*Identity, error) {
+	if _, err := cert.Verify(x509.VerifyOptions{Roots: p.roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		return nil, err
+	}
+	if cert.Subject.CommonName == "" {
+		return nil, errors.New("client certificate has no common name")
+	}
+	return &Identity{Email: cert.Subject.CommonName, Groups: cert.Subject.Organization, Provider: p.Name()}, nil
+}
+
+func (p *certificateProvider) Name() string {
+	return "certificate"
+}
+
+func (p *certificateProvider) Authenticate(_ context.Context, token string) (*Identity, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	session, ok := p.sessions[token]
+	if !ok || !session.valid(time.Now()) {
+		return nil, errors.New("unknown client certificate session")
+	}
+	session.lastUsed = time.Now()
+	identity := session.identity
+	return &identity, nil
+}
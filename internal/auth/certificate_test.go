@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// testCertificates issues a client certificate for alice from a throwaway CA, and makes the provider trust it.
+func testCertificates(t *testing.T) (header string) {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "test-ca"},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour),
+		IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, _ := x509.ParseCertificate(caDER)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2), Subject: pkix.Name{CommonName: "alice@example.com", Organization: []string{"team-a"}},
+		NotBefore: time.Now().Add(-time.Hour), NotAfter: time.Now().Add(time.Hour),
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	previous := certificates
+	certificates = &certificateProvider{
+		header: "ssl-client-cert", roots: roots, sessions: make(map[string]*certificateSession), tokens: make(map[string]string),
+	}
+	t.Cleanup(func() { certificates = previous })
+	return url.QueryEscape(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})))
+}
+
+func certificateRequest(header string) *http.Request {
+	r, _ := http.NewRequest(http.MethodGet, "/api/v1/me", nil)
+	r.Header.Set("ssl-client-cert", header)
+	return r
+}
+
+func TestTokenFromCertificateReusesTheToken(t *testing.T) {
+	header := testCertificates(t)
+
+	first, identity, ok, err := TokenFromCertificate(certificateRequest(header))
+	if !ok || err != nil {
+		t.Fatalf("TokenFromCertificate: ok=%v err=%v", ok, err)
+	}
+	if identity.Email != "alice@example.com" || len(identity.Groups) != 1 || identity.Groups[0] != "team-a" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	second, _, _, err := TokenFromCertificate(certificateRequest(header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Fatal("the same certificate got a new token")
+	}
+	if len(certificates.sessions) != 1 {
+		t.Fatalf("got %d sessions, want 1", len(certificates.sessions))
+	}
+	if got, err := certificates.Authenticate(context.Background(), first); err != nil || got.Email != "alice@example.com" {
+		t.Fatalf("Authenticate: %+v, %v", got, err)
+	}
+}
+
+func TestTokenFromCertificateReplacesAnIdleToken(t *testing.T) {
+	header := testCertificates(t)
+
+	first, _, _, err := TokenFromCertificate(certificateRequest(header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificates.sessions[first].lastUsed = time.Now().Add(-2 * certificateSessionTTL)
+	if _, err := certificates.Authenticate(context.Background(), first); err == nil {
+		t.Fatal("an idle token was accepted")
+	}
+
+	second, _, _, err := TokenFromCertificate(certificateRequest(header))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second == first {
+		t.Fatal("the idle token was handed out again")
+	}
+	if len(certificates.sessions) != 1 || len(certificates.tokens) != 1 {
+		t.Fatalf("got %d sessions and %d tokens, want 1 each", len(certificates.sessions), len(certificates.tokens))
+	}
+}
+
+func TestTokenFromCertificateRejectsUnknownCA(t *testing.T) {
+	header := testCertificates(t)
+	testCertificates(t) // trust another CA only
+
+	if _, _, ok, err := TokenFromCertificate(certificateRequest(header)); !ok || err == nil {
+		t.Fatalf("a certificate from an untrusted CA was accepted: ok=%v err=%v", ok, err)
+	}
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	authnv1 "k8s.io/api/authentication/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// tokenReviewCacheTTL bounds how long a successful TokenReview is reused, so a single API call that checks
+// the token several times only reaches the apiserver once.
+const tokenReviewCacheTTL = time.Minute
+
+// serviceAccountProvider authenticates Kubernetes service account tokens with a TokenReview, so in-cluster
+// bots can call the API without an OIDC identity. They are impersonated as their service account.
+type serviceAccountProvider struct {
+	client    client.Client
+	audiences []string
+
+	mu    sync.Mutex
+	cache map[[sha256.Size]byte]tokenReviewEntry
+}
+
+type tokenReviewEntry struct {
+	identity  Identity
+	expiresAt time.Time
+}
+
+// NewServiceAccountProvider returns a provider validating tokens with the TokenReview API. When audiences is
+// empty, the apiserver's own audience is expected.
+func NewServiceAccountProvider(c client.Client, audiences []string) Provider {
+	return &serviceAccountProvider{client: c, audiences: audiences, cache: make(map[[sha256.Size]byte]tokenReviewEntry)}
+}
+
+func (p *serviceAccountProvider) Name() string {
+	return "serviceaccount"
+}
+
+func (p *serviceAccountProvider) Authenticate(ctx context.Context, token string) (*Identity, error) {
+	key := sha256.Sum256([]byte(token))
+	now := time.Now()
+
+	p.mu.Lock()
+	entry, ok := p.cache[key]
+	p.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		identity := entry.identity
+		return &identity, nil
+	}
+
+	review := &authnv1.TokenReview{Spec: authnv1.TokenReviewSpec{Token: token, Audiences: p.audiences}}
+	if err := p.client.Create(ctx, review); err != nil {
+		return nil, err
+	}
+	if !review.Status.Authenticated {
+		return nil, errors.New("service account token rejected: " + review.Status.Error)
+	}
+	if !strings.HasPrefix(review.Status.User.Username, "system:serviceaccount:") {
+		return nil, errors.New("token does not belong to a service account")
+	}
+	identity := Identity{Email: review.Status.User.Username, Groups: review.Status.User.Groups}
+
+	p.mu.Lock()
+	for k, e := range p.cache {
+		if now.After(e.expiresAt) {
+			delete(p.cache, k)
+		}
+	}
+	p.cache[key] = tokenReviewEntry{identity: identity, expiresAt: now.Add(tokenReviewCacheTTL)}
+	p.mu.Unlock()
+	return &identity, nil
+}
@@ -0,0 +1,36 @@
+package controller
+
+import (
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// accessExpiredReason is the reason of the Event recorded for each access deleted once expired, as opposed to
+	// revoked. The server relays these Events to the notification targets.
+	accessExpiredReason = "AccessExpired"
+	userAnnotation      = "netwatch.vtk.io/user"
+)
+
+// recordAccessExpiry records an Event when an Access or ExternalAccess being deleted has expired. Paused accesses
+// are deleted before they expire and record nothing.
+func (r *NetwatchCleanupReconciler) recordAccessExpiry(obj client.Object) {
+	var expiration *metav1.Time
+	switch access := obj.(type) {
+	case *vtkiov1alpha1.Access:
+		expiration = access.Status.ExpirationTimestamp
+	case *vtkiov1alpha1.ExternalAccess:
+		expiration = access.Status.ExpirationTimestamp
+	}
+	if expiration == nil || expiration.After(obj.GetDeletionTimestamp().Time) {
+		return
+	}
+	r.Recorder.AnnotatedEventf(obj,
+		map[string]string{
+			requestIDAnnotation: obj.GetLabels()["netwatch.vtk.io/request-id"],
+			userAnnotation:      obj.GetLabels()["netwatch.vtk.io/user"],
+		},
+		corev1.EventTypeNormal, accessExpiredReason, "The access expired at %s", expiration.UTC().Format("2006-01-02 15:04:05 MST"))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
@@ -11,10 +12,12 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
@@ -24,20 +27,36 @@ import (
 const (
 	accessFinalizerName        = "netwatch.vtk.io/access-cleanup-finalizer"
 	accessRequestFinalizerName = "netwatch.vtk.io/request-cleanup-finalizer"
+	pausedAnnotation           = "netwatch.vtk.io/paused"
 )
 
 // NetwatchCleanupReconciler reconciles all Netwatch-related resources for cleanup.
 type NetwatchCleanupReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
+	// DrainTimeout is how long in-flight cleanups may keep running once the manager shuts down.
+	DrainTimeout time.Duration
+	// PendingExpiry is how long a request may wait for review before it is deleted. Zero keeps requests forever.
+	PendingExpiry time.Duration
+	// Recorder records the expiry of pending requests and accesses.
+	Recorder record.EventRecorder
+
+	drain *drainer
 }
 
 // Reconcile is the main loop that determines which resource type triggered the event and acts accordingly.
 func (r *NetwatchCleanupReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	// No new work is started while draining, the next leader picks it up.
+	if r.drain.isDraining() {
+		return reconcile.Result{}, nil
+	}
+
 	// Priority 1: Check if it's an AccessRequest event.
 	accessRequest := &netwatchv1alpha1.AccessRequest{}
 	if err := r.Get(ctx, req.NamespacedName, accessRequest); err == nil {
-		return r.reconcileAccessRequest(ctx, accessRequest)
+		result, err := r.reconcileAccessRequest(ctx, accessRequest)
+		recordReconcile("AccessRequest", accessRequest, err)
+		return result, err
 	} else if !errors.IsNotFound(err) {
 		logger.Logger.Error("failed to get AccessRequest resource", "error", err, "name", req.Name)
 		return reconcile.Result{}, err
@@ -46,7 +65,9 @@ func (r *NetwatchCleanupReconciler) Reconcile(ctx context.Context, req reconcile
 	// Priority 2: Check if it's an Access event.
 	access := &vtkiov1alpha1.Access{}
 	if err := r.Get(ctx, req.NamespacedName, access); err == nil {
-		return r.reconcileAccessFinalizer(ctx, access)
+		result, err := r.reconcileAccessFinalizer(ctx, "Access", access)
+		recordReconcile("Access", access, err)
+		return result, err
 	} else if !errors.IsNotFound(err) {
 		logger.Logger.Error("failed to get Access resource", "error", err, "name", req.Name, "namespace", req.Namespace)
 		return reconcile.Result{}, err
@@ -55,12 +76,15 @@ func (r *NetwatchCleanupReconciler) Reconcile(ctx context.Context, req reconcile
 	// Priority 3: Check if it's an ExternalAccess event.
 	extAccess := &vtkiov1alpha1.ExternalAccess{}
 	if err := r.Get(ctx, req.NamespacedName, extAccess); err == nil {
-		return r.reconcileAccessFinalizer(ctx, extAccess)
+		result, err := r.reconcileAccessFinalizer(ctx, "ExternalAccess", extAccess)
+		recordReconcile("ExternalAccess", extAccess, err)
+		return result, err
 	} else if !errors.IsNotFound(err) {
 		logger.Logger.Error("failed to get ExternalAccess resource", "error", err, "name", req.Name, "namespace", req.Namespace)
 		return reconcile.Result{}, err
 	}
 
+	forgetReconcile(req.Namespace, req.Name)
 	return reconcile.Result{}, nil
 }
 
@@ -84,6 +108,9 @@ func (r *NetwatchCleanupReconciler) reconcileAccessRequest(
 	} else {
 		// The object is being deleted.
 		if controllerutil.ContainsFinalizer(request, accessRequestFinalizerName) {
+			ctx, done := r.drain.track(ctx, "AccessRequest", request)
+			defer done()
+
 			// Our finalizer is present, so let's handle any external dependency cleanup.
 			if err := r.cleanupPartialAccess(ctx, request); err != nil {
 				// if fail to delete the external dependency here, return with error
@@ -102,10 +129,18 @@ func (r *NetwatchCleanupReconciler) reconcileAccessRequest(
 		return reconcile.Result{}, nil
 	}
 
+	// --- Pending Expiry Logic ---
+	expiresIn, expired, err := r.expirePendingRequest(ctx, request)
+	if expired || err != nil {
+		return reconcile.Result{}, err
+	}
+	// Requeued to expire the request on time, unless something else triggers a reconcile first.
+	result := reconcile.Result{RequeueAfter: expiresIn}
+
 	// --- Orphan Check Logic ---
 	// This logic only applies to requests that are partially completed.
 	if request.Spec.Status != "PendingTarget" && request.Spec.Status != "PendingSource" {
-		return reconcile.Result{}, nil
+		return result, nil
 	}
 
 	var cloneName, accessName, namespace string
@@ -119,7 +154,7 @@ func (r *NetwatchCleanupReconciler) reconcileAccessRequest(
 	accessName = fmt.Sprintf("access-%s", cloneName)
 
 	var existingAccess vtkiov1alpha1.Access
-	err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: accessName}, &existingAccess)
+	err = r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: accessName}, &existingAccess)
 
 	if err != nil && errors.IsNotFound(err) {
 		// The Access object is GONE. This AccessRequest is an orphan and must be deleted.
@@ -129,13 +164,14 @@ func (r *NetwatchCleanupReconciler) reconcileAccessRequest(
 			return reconcile.Result{}, err
 		}
 		log.Info("Successfully deleted orphaned AccessRequest.")
+		return reconcile.Result{}, nil
 	} else if err != nil {
 		// A real error occurred (e.g., RBAC). Requeue the request.
 		log.Error("Failed to check for orphaned access request", "error", err)
 		return reconcile.Result{}, err
 	}
 
-	return reconcile.Result{}, nil
+	return result, nil
 }
 
 // cleanupPartialAccess finds and deletes the single Access object associated with a deleted AccessRequest.
@@ -181,7 +217,7 @@ func (r *NetwatchCleanupReconciler) cleanupPartialAccess(ctx context.Context, re
 }
 
 // reconcileAccessFinalizer contains the original logic for cleaning up Service clones based on finalizers.
-func (r *NetwatchCleanupReconciler) reconcileAccessFinalizer(ctx context.Context, obj client.Object) (reconcile.Result, error) {
+func (r *NetwatchCleanupReconciler) reconcileAccessFinalizer(ctx context.Context, kind string, obj client.Object) (reconcile.Result, error) {
 	log := logger.Logger.With("resource", obj.GetName(), "namespace", obj.GetNamespace())
 
 	if obj.GetDeletionTimestamp().IsZero() {
@@ -197,15 +233,22 @@ func (r *NetwatchCleanupReconciler) reconcileAccessFinalizer(ctx context.Context
 	}
 
 	if controllerutil.ContainsFinalizer(obj, accessFinalizerName) {
+		ctx, done := r.drain.track(ctx, kind, obj)
+		defer done()
+
 		log.Info("Resource is being deleted, running cleanup...")
 		reqID, ok := obj.GetLabels()["netwatch.vtk.io/request-id"]
 		if !ok {
 			log.Warn("Resource is missing request-id label, cannot perform cleanup.")
+		} else if obj.GetAnnotations()[pausedAnnotation] == "true" {
+			// A paused access keeps its clones so it can be resumed later.
+			log.Info("Resource was paused, keeping its service clones.")
 		} else {
 			if err := r.deleteClonedServices(ctx, reqID); err != nil {
 				log.Error("cleanup failed during service deletion", "error", err)
 				return reconcile.Result{}, err
 			}
+			r.recordAccessExpiry(obj)
 		}
 
 		controllerutil.RemoveFinalizer(obj, accessFinalizerName)
@@ -245,7 +288,17 @@ func (r *NetwatchCleanupReconciler) deleteClonedServices(ctx context.Context, re
 }
 
 // SetupWithManager sets up the controller with the Manager to watch all relevant resources.
+// Cleanups in flight when the manager shuts down are drained, and the ones recorded as unfinished by the previous
+// leader are resumed first.
 func (r *NetwatchCleanupReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.drain = newDrainer()
+	if err := mgr.Add(manager.RunnableFunc(r.drainOnShutdown)); err != nil {
+		return err
+	}
+	if err := mgr.Add(manager.RunnableFunc(r.resumeInterruptedCleanups)); err != nil {
+		return err
+	}
+
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &netwatchv1alpha1.AccessRequest{}, "spec.requestID", func(rawObj client.Object) []string {
 		req := rawObj.(*netwatchv1alpha1.AccessRequest)
 		if req.Spec.RequestID == "" {
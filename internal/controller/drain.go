@@ -0,0 +1,210 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// interruptedCleanupAnnotation records on maxtac objects, whose status is not ours, the cleanup a manager
+	// could not finish before shutting down.
+	interruptedCleanupAnnotation = "netwatch.vtk.io/interrupted-cleanup"
+	// DefaultDrainTimeout is how long in-flight cleanups may keep running once the manager shuts down.
+	DefaultDrainTimeout = 20 * time.Second
+	// recordTimeout bounds the writes recording unfinished cleanups, after the drain timeout expired.
+	recordTimeout = 5 * time.Second
+)
+
+var errDraining = errors.New("manager is shutting down")
+
+// drainer lets in-flight cleanups finish once the manager shuts down, for at most the drain timeout.
+type drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight map[string]inFlightCleanup
+	// ctx outlives the manager's context, it is cancelled when the drain timeout expires.
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+type inFlightCleanup struct {
+	kind string
+	obj  client.Object
+}
+
+func newDrainer() *drainer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &drainer{inFlight: make(map[string]inFlightCleanup), ctx: ctx, cancel: cancel}
+}
+
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.draining
+}
+
+// track registers a cleanup and returns the context it must run with: it survives the shutdown of the manager
+// until the drain timeout expires. The returned function must be called once the cleanup is over.
+func (d *drainer) track(ctx context.Context, kind string, obj client.Object) (context.Context, func()) {
+	key := stateKey(kind, obj.GetNamespace(), obj.GetName())
+	d.mu.Lock()
+	d.inFlight[key] = inFlightCleanup{kind: kind, obj: obj}
+	d.mu.Unlock()
+
+	cleanupCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(d.ctx, cancel)
+	return cleanupCtx, func() {
+		stop()
+		cancel()
+		d.mu.Lock()
+		delete(d.inFlight, key)
+		d.mu.Unlock()
+	}
+}
+
+// wait blocks until no cleanup is in flight or the timeout expires, and returns the cleanups left unfinished.
+func (d *drainer) wait(timeout time.Duration) []inFlightCleanup {
+	deadline := time.Now().Add(timeout)
+	for {
+		d.mu.Lock()
+		remaining := len(d.inFlight)
+		d.mu.Unlock()
+		if remaining == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	unfinished := make([]inFlightCleanup, 0, len(d.inFlight))
+	for _, cleanup := range d.inFlight {
+		unfinished = append(unfinished, cleanup)
+	}
+	return unfinished
+}
+
+// Ready fails once the manager is shutting down, so the pod is taken out of rotation while it drains.
+func (r *NetwatchCleanupReconciler) Ready(_ *http.Request) error {
+	if r.drain.isDraining() {
+		return errDraining
+	}
+	return nil
+}
+
+// drainOnShutdown waits for the manager to shut down, then lets in-flight cleanups finish within the drain timeout.
+// The cleanups still running after it are recorded on their object for the next leader.
+func (r *NetwatchCleanupReconciler) drainOnShutdown(ctx context.Context) error {
+	<-ctx.Done()
+	r.drain.mu.Lock()
+	r.drain.draining = true
+	r.drain.mu.Unlock()
+
+	timeout := r.DrainTimeout
+	if timeout <= 0 {
+		timeout = DefaultDrainTimeout
+	}
+	logger.Logger.Info("Manager shutting down, draining in-flight cleanups", "timeout", timeout)
+	unfinished := r.drain.wait(timeout)
+	r.drain.cancel()
+	if len(unfinished) == 0 {
+		logger.Logger.Info("All in-flight cleanups finished")
+		return nil
+	}
+
+	recordCtx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+	for _, cleanup := range unfinished {
+		steps := PendingCleanup(cleanup.obj)
+		logger.Logger.Warn("Cleanup interrupted by shutdown", "kind", cleanup.kind, "name", cleanup.obj.GetName(),
+			"namespace", cleanup.obj.GetNamespace(), "pendingCleanup", steps)
+		if err := r.recordInterruptedCleanup(recordCtx, cleanup.obj, steps); err != nil {
+			logger.Logger.Error("Failed to record interrupted cleanup", "error", err, "kind", cleanup.kind, "name", cleanup.obj.GetName())
+		}
+	}
+	return nil
+}
+
+// recordInterruptedCleanup stores the unfinished cleanup steps on the status of an AccessRequest, or in an annotation
+// of a maxtac object.
+func (r *NetwatchCleanupReconciler) recordInterruptedCleanup(ctx context.Context, obj client.Object, steps []string) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	if request, ok := obj.(*netwatchv1alpha1.AccessRequest); ok {
+		patched := request.DeepCopy()
+		patched.Status.InterruptedCleanup = steps
+		now := metav1.Now()
+		patched.Status.InterruptedAt = &now
+		return r.Status().Patch(ctx, patched, client.MergeFrom(request))
+	}
+	patched := obj.DeepCopyObject().(client.Object)
+	annotations := patched.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[interruptedCleanupAnnotation] = strings.Join(steps, "; ")
+	patched.SetAnnotations(annotations)
+	return r.Patch(ctx, patched, client.MergeFrom(obj))
+}
+
+// interruptedCleanup returns the cleanup steps a previous manager recorded as unfinished on an object.
+func interruptedCleanup(obj client.Object) []string {
+	if request, ok := obj.(*netwatchv1alpha1.AccessRequest); ok {
+		return request.Status.InterruptedCleanup
+	}
+	if steps := obj.GetAnnotations()[interruptedCleanupAnnotation]; steps != "" {
+		return strings.Split(steps, "; ")
+	}
+	return nil
+}
+
+// resumeInterruptedCleanups reconciles first the objects whose cleanup the previous leader recorded as unfinished.
+func (r *NetwatchCleanupReconciler) resumeInterruptedCleanups(ctx context.Context) error {
+	var requests netwatchv1alpha1.AccessRequestList
+	var accesses vtkiov1alpha1.AccessList
+	var extAccesses vtkiov1alpha1.ExternalAccessList
+	for _, list := range []client.ObjectList{&requests, &accesses, &extAccesses} {
+		if err := r.List(ctx, list); err != nil {
+			logger.Logger.Error("Failed to list objects with interrupted cleanups", "error", err)
+			return nil
+		}
+	}
+
+	var objects []client.Object
+	for i := range requests.Items {
+		objects = append(objects, &requests.Items[i])
+	}
+	for i := range accesses.Items {
+		objects = append(objects, &accesses.Items[i])
+	}
+	for i := range extAccesses.Items {
+		objects = append(objects, &extAccesses.Items[i])
+	}
+	for _, obj := range objects {
+		steps := interruptedCleanup(obj)
+		if len(steps) == 0 {
+			continue
+		}
+		logger.Logger.Info("Resuming cleanup interrupted by the previous manager", "name", obj.GetName(),
+			"namespace", obj.GetNamespace(), "pendingCleanup", steps)
+		req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}}
+		if _, err := r.Reconcile(ctx, req); err != nil {
+			logger.Logger.Error("Failed to resume interrupted cleanup, the controller will retry", "error", err, "name", obj.GetName())
+		}
+	}
+	return nil
+}
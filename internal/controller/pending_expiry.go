@@ -0,0 +1,71 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// pendingExpiredReason is the reason of the Event recorded for each expired request. The server relays these
+	// Events to the activity log, so requestors learn what happened to their request.
+	pendingExpiredReason = "PendingExpired"
+	requestorAnnotation  = "netwatch.vtk.io/requestor"
+	requestIDAnnotation  = "netwatch.vtk.io/request-id"
+	namespacesAnnotation = "netwatch.vtk.io/namespaces"
+	ticketRefAnnotation  = "netwatch.vtk.io/ticket-ref"
+)
+
+// expirePendingRequest deletes a request still pending after PendingExpiry, which cleans up its partial resources
+// through the finalizer. It reports whether the request expired and, otherwise, how long it has left.
+func (r *NetwatchCleanupReconciler) expirePendingRequest(
+	ctx context.Context,
+	request *netwatchv1alpha1.AccessRequest,
+) (time.Duration, bool, error) {
+	if r.PendingExpiry <= 0 || !strings.HasPrefix(request.Spec.Status, "Pending") {
+		return 0, false, nil
+	}
+	if remaining := time.Until(request.CreationTimestamp.Add(r.PendingExpiry)); remaining > 0 {
+		return remaining, false, nil
+	}
+
+	log := logger.Logger.With("resource", request.Name)
+	log.Info("Pending access request expired without review, deleting it", "status", request.Spec.Status,
+		"requestor", request.Spec.Requestor, "pendingExpiry", r.PendingExpiry)
+	if err := r.Delete(ctx, request); err != nil && !errors.IsNotFound(err) {
+		log.Error("Failed to delete expired AccessRequest", "error", err)
+		return 0, true, err
+	}
+	comment := "Nobody reviewed the request within " + r.PendingExpiry.String()
+	if err := r.Create(ctx, netwatchv1alpha1.NewAccessDecision(request, "expired", "netwatch", comment)); err != nil && !errors.IsAlreadyExists(err) {
+		log.Error("Failed to record the expiry of an AccessRequest", "error", err)
+	}
+	r.Recorder.AnnotatedEventf(request,
+		map[string]string{
+			requestorAnnotation:  request.Spec.Requestor,
+			requestIDAnnotation:  request.Spec.RequestID,
+			namespacesAnnotation: strings.Join(requestNamespaces(request.Spec), ","),
+			ticketRefAnnotation:  request.Spec.TicketRef,
+		},
+		corev1.EventTypeWarning, pendingExpiredReason,
+		"Nobody reviewed the request of %s within %s, it was deleted", request.Spec.Requestor, r.PendingExpiry)
+	return 0, true, nil
+}
+
+// requestNamespaces lists the namespaces of the services of a request, so its expiry can be routed to their
+// notification targets.
+func requestNamespaces(spec netwatchv1alpha1.AccessRequestSpec) []string {
+	var namespaces []string
+	for _, service := range []string{spec.SourceService, spec.TargetService, spec.Service} {
+		if ns, _, ok := strings.Cut(service, "/"); ok {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+// ReconcileStatePath is where the manager serves its reconcile state, next to /metrics.
+const ReconcileStatePath = "/reconcile-state"
+
+// ReconcileState is the manager's view of a single resource, used to diagnose resources stuck terminating.
+type ReconcileState struct {
+	Kind           string     `json:"kind"`
+	Namespace      string     `json:"namespace,omitempty"`
+	Name           string     `json:"name"`
+	LastReconcile  *time.Time `json:"lastReconcile,omitempty"`
+	LastError      string     `json:"lastError,omitempty"`
+	Finalizers     []string   `json:"finalizers,omitempty"`
+	Deleting       bool       `json:"deleting"`
+	PendingCleanup []string   `json:"pendingCleanup,omitempty"`
+	// InterruptedCleanup is what a previous manager recorded as unfinished when it shut down.
+	InterruptedCleanup []string `json:"interruptedCleanup,omitempty"`
+}
+
+var (
+	statesMu sync.RWMutex
+	states   = make(map[string]ReconcileState)
+)
+
+func stateKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// DescribeObject builds the state of a resource from the object itself, without reconcile history.
+func DescribeObject(kind string, obj client.Object) ReconcileState {
+	return ReconcileState{
+		Kind:               kind,
+		Namespace:          obj.GetNamespace(),
+		Name:               obj.GetName(),
+		Finalizers:         obj.GetFinalizers(),
+		Deleting:           !obj.GetDeletionTimestamp().IsZero(),
+		PendingCleanup:     PendingCleanup(obj),
+		InterruptedCleanup: interruptedCleanup(obj),
+	}
+}
+
+// PendingCleanup lists what the manager still has to do before it removes its finalizer from a deleted resource.
+func PendingCleanup(obj client.Object) []string {
+	if obj.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+	if request, ok := obj.(*netwatchv1alpha1.AccessRequest); ok {
+		if !controllerutil.ContainsFinalizer(request, accessRequestFinalizerName) {
+			return nil
+		}
+		switch request.Spec.Status {
+		case "PendingTarget":
+			return []string{fmt.Sprintf("delete partial Access %s/access-%s", strings.Split(request.Spec.SourceService, "/")[0], request.Spec.SourceCloneName)}
+		case "PendingSource":
+			return []string{fmt.Sprintf("delete partial Access %s/access-%s", strings.Split(request.Spec.TargetService, "/")[0], request.Spec.TargetCloneName)}
+		}
+		return nil
+	}
+	if !controllerutil.ContainsFinalizer(obj, accessFinalizerName) {
+		return nil
+	}
+	reqID, ok := obj.GetLabels()["netwatch.vtk.io/request-id"]
+	if !ok || obj.GetAnnotations()[pausedAnnotation] == "true" {
+		return []string{"remove finalizer"}
+	}
+	return []string{fmt.Sprintf("delete service clones of request %s", reqID), "remove finalizer"}
+}
+
+// recordReconcile stores the outcome of a reconcile for the state endpoint.
+func recordReconcile(kind string, obj client.Object, err error) {
+	state := DescribeObject(kind, obj)
+	now := time.Now()
+	state.LastReconcile = &now
+	if err != nil {
+		state.LastError = err.Error()
+	}
+	statesMu.Lock()
+	states[stateKey(kind, obj.GetNamespace(), obj.GetName())] = state
+	statesMu.Unlock()
+}
+
+// forgetReconcile drops the state of a resource that no longer exists.
+func forgetReconcile(namespace, name string) {
+	statesMu.Lock()
+	defer statesMu.Unlock()
+	for _, kind := range []string{"AccessRequest", "Access", "ExternalAccess"} {
+		delete(states, stateKey(kind, namespace, name))
+	}
+}
+
+// ReconcileStateHandler serves the recorded reconcile states as JSON.
+func ReconcileStateHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statesMu.RLock()
+		list := make([]ReconcileState, 0, len(states))
+		for _, state := range states {
+			list = append(list, state)
+		}
+		statesMu.RUnlock()
+		sort.Slice(list, func(i, j int) bool {
+			return stateKey(list[i].Kind, list[i].Namespace, list[i].Name) < stateKey(list[j].Kind, list[j].Namespace, list[j].Name)
+		})
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(list) //nolint:all
+	})
+}
@@ -0,0 +1,89 @@
+// Package diagnostics implements the soak-mode sampler used to spot resource leaks in long-running servers.
+package diagnostics
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// growthWarningSamples is the number of consecutive increases after which a probe is reported as a suspected leak.
+const growthWarningSamples = 5
+
+// Probe reads a single numeric value to be sampled, e.g. a goroutine count or a cache size.
+type Probe struct {
+	Name string
+	Read func() float64
+}
+
+var (
+	probesMu sync.Mutex
+	probes   = []Probe{
+		{Name: "goroutines", Read: func() float64 { return float64(runtime.NumGoroutine()) }},
+	}
+
+	probeGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "netwatch_diagnostics_value",
+		Help: "Latest value sampled by the diagnostics mode for each probe.",
+	}, []string{"probe"})
+)
+
+func init() {
+	prometheus.MustRegister(probeGauge)
+}
+
+// RegisterProbe adds a probe to the sampler. Packages owning a resource (connections, pools, caches) register it at startup.
+func RegisterProbe(p Probe) {
+	probesMu.Lock()
+	defer probesMu.Unlock()
+	probes = append(probes, p)
+}
+
+// StartSampler periodically samples every registered probe, logs the deltas since the previous sample
+// and warns when a probe keeps growing. It blocks until the context is cancelled.
+func StartSampler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting diagnostics sampler", "interval", interval)
+
+	previous := make(map[string]float64)
+	growth := make(map[string]int)
+
+	for {
+		select {
+		case <-ticker.C:
+			probesMu.Lock()
+			current := append([]Probe(nil), probes...)
+			probesMu.Unlock()
+
+			attrs := make([]any, 0, len(current)*2)
+			for _, p := range current {
+				value := p.Read()
+				probeGauge.WithLabelValues(p.Name).Set(value)
+
+				prev, seen := previous[p.Name]
+				delta := value - prev
+				if seen && delta > 0 {
+					growth[p.Name]++
+				} else {
+					growth[p.Name] = 0
+				}
+				previous[p.Name] = value
+				attrs = append(attrs, p.Name, map[string]float64{"value": value, "delta": delta})
+
+				if growth[p.Name] == growthWarningSamples {
+					logger.Logger.Warn("Diagnostics probe keeps growing, possible leak", "probe", p.Name, "value", value, "samples", growthWarningSamples)
+				}
+			}
+			logger.Logger.Info("Diagnostics sample", attrs...)
+		case <-ctx.Done():
+			logger.Logger.Info("Stopping diagnostics sampler.")
+			return
+		}
+	}
+}
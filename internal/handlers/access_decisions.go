@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// AccessDecisionInfo is the audit record of a decision on a request, as returned by the API.
+type AccessDecisionInfo struct {
+	Name string                              `json:"name"`
+	Spec netwatchv1alpha1.AccessDecisionSpec `json:"spec"`
+}
+
+// recordAccessDecision keeps the decision on a request as an AccessDecision, its audit record once the request is
+// deleted. A failure is logged, the decision itself is already made.
+func recordAccessDecision(
+	ctx context.Context,
+	request *netwatchv1alpha1.AccessRequest,
+	decision, decidedBy, comment string,
+	changes []string,
+) {
+	record := netwatchv1alpha1.NewAccessDecision(request, decision, decidedBy, comment)
+	record.Spec.Changes = changes
+	if err := k8s.CreateAccessDecisionAsApp(ctx, record); err != nil && !k8s.IsAlreadyExists(err) {
+		logger.Logger.Error("Failed to record the decision on a request", "error", err, "request", request.Name,
+			"requestID", request.Spec.RequestID, "decision", decision)
+	}
+}
+
+// ListAccessDecisions godoc
+// @Summary      List access decisions
+// @Description  Lists the audit records of the decisions on access requests, newest first: approvals, including by pre-approval or auto-approval rule, denials, aborts and expiries. Restricted to administrators.
+// @Tags         Admin
+// @Produce      json
+// @Param        decision   query     string  false  "Only return this decision"  Enums(approved, denied, aborted, expired)
+// @Param        requestor  query     string  false  "Only return the decisions on the requests of this user"
+// @Success      200  {array}   handlers.AccessDecisionInfo
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /admin/decisions [get]
+func ListAccessDecisions(c *gin.Context) {
+	var opts []client.ListOption
+	if decision := c.Query("decision"); decision != "" {
+		if !slices.Contains([]string{"approved", "denied", "aborted", "expired"}, decision) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'decision' parameter"})
+			return
+		}
+		opts = append(opts, client.MatchingLabels{netwatchv1alpha1.DecisionLabel: decision})
+	}
+	list, err := k8s.ListAccessDecisionsAsApp(c.Request.Context(), opts...)
+	if err != nil {
+		logger.Logger.Error("Failed to list access decisions", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not list access decisions"})
+		return
+	}
+	requestor := c.Query("requestor")
+	result := []AccessDecisionInfo{}
+	for _, record := range list.Items {
+		if requestor == "" || record.Spec.AccessRequest.Requestor == requestor {
+			result = append(result, AccessDecisionInfo{Name: record.Name, Spec: record.Spec})
+		}
+	}
+	slices.SortFunc(result, func(a, b AccessDecisionInfo) int {
+		return b.Spec.DecidedAt.Compare(a.Spec.DecidedAt.Time)
+	})
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// requestorAnnotation and approvedByAnnotation record on Access and ExternalAccess objects who asked for them
+// and who approved them. Accesses created without a request have no approver. approvalCommentAnnotation keeps
+// the approvers' comments, when they left one, and descriptionAnnotation why an access created directly was needed.
+const (
+	requestorAnnotation       = "netwatch.vtk.io/requestor"
+	approvedByAnnotation      = "netwatch.vtk.io/approved-by"
+	approvalCommentAnnotation = "netwatch.vtk.io/approval-comment"
+	descriptionAnnotation     = "netwatch.vtk.io/description"
+)
+
+// provenanceAnnotations returns the annotations recording who asked for an access and who approved it.
+func provenanceAnnotations(requestor, approvedBy string) map[string]string {
+	annotations := map[string]string{requestorAnnotation: requestor}
+	if approvedBy != "" {
+		annotations[approvedByAnnotation] = approvedBy
+	}
+	return annotations
+}
+
+// requestedBy reports whether a user asked for an access. Its user label names whoever created it, the approver
+// of a request, so only accesses created before their requestor was recorded fall back to it.
+func requestedBy(requestor, owner, email, username string) bool {
+	if requestor != "" {
+		return strings.EqualFold(requestor, email)
+	}
+	return owner == username
+}
+
+// directAccessAnnotations returns the provenance annotations of an access created without a request, with its
+// description.
+func directAccessAnnotations(requestor, description string) map[string]string {
+	annotations := provenanceAnnotations(requestor, "")
+	if description = strings.TrimSpace(description); description != "" {
+		annotations[descriptionAnnotation] = description
+	}
+	return annotations
+}
+
+// copyProvenance keeps the provenance annotations of an access that is recreated, e.g. on resume.
+func copyProvenance(annotations map[string]string) map[string]string {
+	copied := make(map[string]string)
+	for _, key := range []string{requestorAnnotation, approvedByAnnotation, approvalCommentAnnotation, descriptionAnnotation} {
+		if value, ok := annotations[key]; ok {
+			copied[key] = value
+		}
+	}
+	if len(copied) == 0 {
+		return nil
+	}
+	return copied
+}
+
+// CloneInfo describes a service clone backing an access.
+type CloneInfo struct {
+	Name       string `json:"name" example:"nc-1a2b3c4d-61626364"`
+	Namespace  string `json:"namespace" example:"default"`
+	ClonedFrom string `json:"clonedFrom" example:"default/frontend"`
+	Ports      string `json:"ports,omitempty" example:"8080/TCP"`
+}
+
+// AccessDetail is an Access with everything known about where it comes from.
+type AccessDetail struct {
+	Name      string                   `json:"name" example:"access-nc-1a2b3c4d-61626364"`
+	Namespace string                   `json:"namespace" example:"default"`
+	RequestID string                   `json:"requestID,omitempty"`
+	Spec      vtkiov1alpha1.AccessSpec `json:"spec" swaggertype:"object"`
+	// Pair is the other half of a Service access, nil while it is not created yet.
+	Pair *AccessDetailPair `json:"pair,omitempty"`
+	// Clones are the service clones of both halves, with the service each one was cloned from.
+	Clones []CloneInfo `json:"clones"`
+	// Owner is the user the access is labelled with, the approver for accesses created by an approval.
+	Owner string `json:"owner,omitempty" example:"jane.doe-example.com"`
+	// Requestor and ApprovedBy are empty for accesses created before they were recorded.
+	Requestor  string `json:"requestor,omitempty" example:"jane.doe@example.com"`
+	ApprovedBy string `json:"approvedBy,omitempty" example:"john.roe@example.com"`
+	// ApprovalComment is the comment left by the approvers, if any.
+	ApprovalComment string `json:"approvalComment,omitempty" example:"Approved for the checkout incident"`
+	// Description is why an access created without a request was needed, if its creator said.
+	Description string `json:"description,omitempty" example:"Debugging the checkout flow"`
+	CreatedAt   int64  `json:"createdAt" example:"1718000000"`
+	// ExpiresAt is -1 when the access never expires.
+	ExpiresAt int64 `json:"expiresAt" example:"1718003600"`
+}
+
+// AccessDetailPair is the paired Access of a Service access.
+type AccessDetailPair struct {
+	Name      string                   `json:"name"`
+	Namespace string                   `json:"namespace"`
+	Spec      vtkiov1alpha1.AccessSpec `json:"spec" swaggertype:"object"`
+}
+
+// GetAccessDetail returns an Access with its pair, service clones and provenance.
+// GetAccessDetail godoc
+// @Summary      Get an access policy with its provenance
+// @Description  Returns the Access spec, its paired Access, both service clones with the services they were cloned from, who requested and approved the access, when it was created and when it expires.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace  path      string  true  "Namespace of the Access"
+// @Param        name       path      string  true  "Name of the Access"
+// @Success      200  {object}  handlers.AccessDetail
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /accesses/{namespace}/{name} [get]
+func GetAccessDetail(c *gin.Context) {
+	ctx := c.Request.Context()
+	namespace, name := c.Param("namespace"), c.Param("name")
+
+	access, err := k8s.GetAccessAsApp(ctx, namespace, name)
+	if err != nil {
+		if k8s.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Access not found"})
+			return
+		}
+		logger.Logger.Error("Failed to get access", "error", err, "namespace", namespace, "name", name)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get access"})
+		return
+	}
+	if access.Labels["app.kubernetes.io/managed-by"] != "netwatch" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Access not found"})
+		return
+	}
+
+	detail := AccessDetail{
+		Name:            access.Name,
+		Namespace:       access.Namespace,
+		RequestID:       access.Labels["netwatch.vtk.io/request-id"],
+		Spec:            access.Spec,
+		Clones:          []CloneInfo{},
+		Owner:           access.Labels["netwatch.vtk.io/user"],
+		Requestor:       access.Annotations[requestorAnnotation],
+		ApprovedBy:      access.Annotations[approvedByAnnotation],
+		ApprovalComment: access.Annotations[approvalCommentAnnotation],
+		Description:     access.Annotations[descriptionAnnotation],
+		CreatedAt:       access.CreationTimestamp.Unix(),
+		ExpiresAt:       -1,
+	}
+	if expiry, ok := accessExpiry(access.CreationTimestamp.Time, access.Spec.Duration, access.Status.ExpirationTimestamp); ok {
+		detail.ExpiresAt = expiry.Unix()
+	}
+
+	if detail.RequestID != "" {
+		accesses, err := k8s.ListAllAccessesWithLabelAsApp(ctx, detail.RequestID)
+		if err != nil {
+			logger.Logger.Error("Failed to list paired access", "error", err, "requestID", detail.RequestID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list paired access"})
+			return
+		}
+		for _, other := range accesses.Items {
+			if other.Namespace == access.Namespace && other.Name == access.Name {
+				continue
+			}
+			detail.Pair = &AccessDetailPair{Name: other.Name, Namespace: other.Namespace, Spec: other.Spec}
+			break
+		}
+
+		services, err := k8s.ListAllServices(ctx, client.MatchingLabels{"netwatch.vtk.io/request-id": detail.RequestID})
+		if err != nil {
+			logger.Logger.Error("Failed to list service clones", "error", err, "requestID", detail.RequestID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list service clones"})
+			return
+		}
+		for _, svc := range services.Items {
+			detail.Clones = append(detail.Clones, CloneInfo{
+				Name:       svc.Name,
+				Namespace:  svc.Namespace,
+				ClonedFrom: svc.Annotations["netwatch.vtk.io/cloned-from"],
+				Ports:      svc.Annotations["netwatch.vtk.io/ports"],
+			})
+		}
+		sort.Slice(detail.Clones, func(i, j int) bool {
+			return detail.Clones[i].Namespace+"/"+detail.Clones[i].Name < detail.Clones[j].Namespace+"/"+detail.Clones[j].Name
+		})
+	}
+
+	c.JSON(http.StatusOK, detail)
+}
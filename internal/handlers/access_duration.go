@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxtacMaxDurationSeconds is the longest duration maxtac can enforce. It parses durations into a time.Duration,
+// and a longer one fails to parse: maxtac then logs an error and never expires the access.
+const maxtacMaxDurationSeconds = int64(math.MaxInt64 / int64(time.Second))
+
+// maxAccessDuration caps the duration of accesses. Zero only applies maxtac's own limit.
+var maxAccessDuration time.Duration
+
+// SetMaxAccessDuration caps the duration of requested accesses. Once set, accesses without a duration are refused.
+func SetMaxAccessDuration(max time.Duration) {
+	maxAccessDuration = max
+}
+
+// parseMaxtacDuration parses a duration the way maxtac does, accepting a "d" suffix for days.
+func parseMaxtacDuration(s string) (time.Duration, error) {
+	if daysStr, ok := strings.CutSuffix(s, "d"); ok {
+		days, err := strconv.Atoi(daysStr)
+		if err != nil {
+			return 0, err
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
+// validateAccessDuration checks at submission time that a duration in seconds will be enforced as requested.
+// Zero means no expiry.
+func validateAccessDuration(seconds int64) error {
+	switch {
+	case seconds < 0:
+		return fmt.Errorf("duration cannot be negative")
+	case seconds == 0 && maxAccessDuration > 0:
+		return fmt.Errorf("accesses without a duration are not allowed, the maximum is %s", maxAccessDuration)
+	case seconds == 0:
+		return nil
+	case seconds > maxtacMaxDurationSeconds:
+		return fmt.Errorf("duration exceeds the maximum maxtac can enforce (%s)", time.Duration(math.MaxInt64).Round(time.Hour))
+	case maxAccessDuration > 0 && time.Duration(seconds)*time.Second > maxAccessDuration:
+		return fmt.Errorf("duration %s exceeds the maximum of %s", time.Duration(seconds)*time.Second, maxAccessDuration)
+	}
+	// The duration is written as "<seconds>s"; make sure maxtac reads back the same value.
+	parsed, err := parseMaxtacDuration(fmt.Sprintf("%ds", seconds))
+	if err != nil || parsed != time.Duration(seconds)*time.Second {
+		return fmt.Errorf("duration %ds would not be enforced by maxtac", seconds)
+	}
+	return nil
+}
+
+// invalidDuration reports an unenforceable duration to the user.
+func (p *webSocketCommandProcessor) invalidDuration(seconds int64, logType string) bool {
+	if err := validateAccessDuration(seconds); err != nil {
+		p.sendError("Invalid duration", err, logType)
+		return true
+	}
+	return false
+}
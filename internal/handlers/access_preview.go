@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// accessPlan holds the objects a direct access creation applies: the service clones first, then either the Access
+// on each side or the ExternalAccess.
+type accessPlan struct {
+	requestID      string
+	clones         []*corev1.Service
+	accesses       []*vtkiov1alpha1.Access
+	externalAccess *vtkiov1alpha1.ExternalAccess
+}
+
+// planClusterAccess builds the service clones and Access objects createClusterAccess creates, without creating them.
+func (p *webSocketCommandProcessor) planClusterAccess(payload webSocketPayload) (*accessPlan, error) {
+	overridePorts, err := getOverridePorts(payload.Ports)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port override: %w", err)
+	}
+
+	sourceParts := strings.Split(payload.SourceService, "/")
+	targetParts := strings.Split(payload.TargetService, "/")
+	if len(sourceParts) != 2 || len(targetParts) != 2 {
+		return nil, fmt.Errorf("invalid service format: expected 'namespace/name'")
+	}
+	sourceNs, sourceName := sourceParts[0], sourceParts[1]
+	targetNs, targetName := targetParts[0], targetParts[1]
+
+	cloneID := uuid.New().String()
+	randSuffix := hex.EncodeToString([]byte(cloneID))[:8]
+	sourceCloneName := fmt.Sprintf("nc-%s-%s", shortHash(sourceName), randSuffix)
+	targetCloneName := fmt.Sprintf("nc-%s-%s", shortHash(targetName), randSuffix)
+	commonRequestLabel := map[string]string{"netwatch.vtk.io/request-id": cloneID}
+
+	sourceClone, err := k8s.BuildServiceClone(p.ctx, sourceNs, sourceName, sourceCloneName, commonRequestLabel, overridePorts)
+	if err != nil {
+		return nil, err
+	}
+	targetClone, err := k8s.BuildServiceClone(p.ctx, targetNs, targetName, targetCloneName, commonRequestLabel, overridePorts)
+	if err != nil {
+		return nil, err
+	}
+
+	var durationStr string
+	if payload.Duration > 0 {
+		durationStr = fmt.Sprintf("%ds", payload.Duration)
+	}
+	annotations := directAccessAnnotations(p.userInfo.Email, payload.Description)
+	commonAccessLabels := map[string]string{
+		"app.kubernetes.io/managed-by": "netwatch",
+		"netwatch.vtk.io/user":         p.sanitizedUsername,
+		"netwatch.vtk.io/request-id":   cloneID,
+	}
+	sourceAccess := &vtkiov1alpha1.Access{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("access-%s", sourceCloneName),
+			Namespace:   sourceNs,
+			Labels:      commonAccessLabels,
+			Annotations: annotations,
+		},
+		Spec: vtkiov1alpha1.AccessSpec{
+			Duration:        durationStr,
+			Direction:       sideDirection(payload.Direction, true),
+			ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
+			Targets:         []vtkiov1alpha1.AccessPoint{{ServiceName: targetClone.Name, Namespace: targetClone.Namespace}},
+		},
+	}
+	targetAccess := &vtkiov1alpha1.Access{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("access-%s", targetCloneName),
+			Namespace:   targetNs,
+			Labels:      commonAccessLabels,
+			Annotations: annotations,
+		},
+		Spec: vtkiov1alpha1.AccessSpec{
+			Duration:        durationStr,
+			Direction:       sideDirection(payload.Direction, false),
+			ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
+			Targets:         []vtkiov1alpha1.AccessPoint{{ServiceName: sourceClone.Name, Namespace: sourceClone.Namespace}},
+		},
+	}
+
+	return &accessPlan{
+		requestID: cloneID,
+		clones:    []*corev1.Service{sourceClone, targetClone},
+		accesses:  []*vtkiov1alpha1.Access{sourceAccess, targetAccess},
+	}, nil
+}
+
+// planExternalAccess builds the service clone and ExternalAccess handleRequestExternalAccess creates, without
+// creating them.
+func (p *webSocketCommandProcessor) planExternalAccess(payload webSocketPayload) (*accessPlan, error) {
+	serviceParts := strings.Split(payload.Service, "/")
+	if len(serviceParts) != 2 {
+		return nil, fmt.Errorf("invalid service format: expected 'namespace/name'")
+	}
+	serviceNs, serviceName := serviceParts[0], serviceParts[1]
+
+	overridePorts, err := getOverridePorts(payload.Ports)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port override: %w", err)
+	}
+
+	trimmedCIDR := strings.TrimSpace(payload.Cidr)
+	if _, _, err := net.ParseCIDR(trimmedCIDR); err != nil {
+		if net.ParseIP(trimmedCIDR) == nil {
+			return nil, fmt.Errorf("invalid source IP / CIDR: '%s' is not a valid IP address or CIDR block", payload.Cidr)
+		}
+	}
+
+	var durationStr string
+	if payload.Duration > 0 {
+		durationStr = fmt.Sprintf("%ds", payload.Duration)
+	}
+
+	cloneID := uuid.New().String()
+	randSuffix := hex.EncodeToString([]byte(cloneID))[:8]
+	cloneName := fmt.Sprintf("nc-%s-%s", shortHash(serviceName), randSuffix)
+	cloneLabel := map[string]string{"netwatch.vtk.io/request-id": cloneID}
+
+	clone, err := k8s.BuildServiceClone(p.ctx, serviceNs, serviceName, cloneName, cloneLabel, overridePorts)
+	if err != nil {
+		return nil, err
+	}
+
+	ea := &vtkiov1alpha1.ExternalAccess{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("ea-%s", cloneName),
+			Namespace: serviceNs,
+			Labels: map[string]string{
+				"app.kubernetes.io/managed-by": "netwatch",
+				"netwatch.vtk.io/user":         p.sanitizedUsername,
+				"netwatch.vtk.io/request-id":   cloneID,
+			},
+			Annotations: directAccessAnnotations(p.userInfo.Email, payload.Description),
+		},
+		Spec: vtkiov1alpha1.ExternalAccessSpec{
+			TargetCIDRs:     []string{payload.Cidr},
+			Direction:       payload.Direction,
+			Duration:        durationStr,
+			ServiceSelector: &metav1.LabelSelector{MatchLabels: cloneLabel},
+		},
+	}
+
+	return &accessPlan{requestID: cloneID, clones: []*corev1.Service{clone}, externalAccess: ea}, nil
+}
+
+// manifests renders the objects of the plan as a multi-document YAML, in the order they are applied.
+func (plan *accessPlan) manifests() (string, error) {
+	var objects []any
+	for _, clone := range plan.clones {
+		clone.TypeMeta = metav1.TypeMeta{APIVersion: "v1", Kind: "Service"}
+		objects = append(objects, clone)
+	}
+	for _, access := range plan.accesses {
+		access.TypeMeta = metav1.TypeMeta{APIVersion: vtkiov1alpha1.GroupVersion.String(), Kind: "Access"}
+		objects = append(objects, access)
+	}
+	if ea := plan.externalAccess; ea != nil {
+		ea.TypeMeta = metav1.TypeMeta{APIVersion: vtkiov1alpha1.GroupVersion.String(), Kind: "ExternalAccess"}
+		objects = append(objects, ea)
+	}
+
+	var b strings.Builder
+	for _, object := range objects {
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("---\n")
+		b.Write(out)
+	}
+	return b.String(), nil
+}
+
+// PreviewAccessInput describes an access to preview. Set sourceService and targetService for a service-to-service
+// access, or service and cidr for an external one.
+type PreviewAccessInput struct {
+	SourceService string `json:"sourceService,omitempty" example:"frontend/web"`
+	TargetService string `json:"targetService,omitempty" example:"backend/api"`
+	Service       string `json:"service,omitempty"`
+	Cidr          string `json:"cidr,omitempty"`
+	Direction     string `json:"direction,omitempty" example:"all"`
+	Ports         string `json:"ports,omitempty" example:"8080,9090"`
+	Duration      int64  `json:"duration" example:"3600"`
+}
+
+// AccessPreview is what creating an access would apply.
+type AccessPreview struct {
+	// RequestID is only an example: creating the access generates a new one, and new clone names with it.
+	RequestID string `json:"requestID" example:"0b7f8a4e-2f4c-4c1e-9f0a-6f1d2b3c4d5e"`
+	// Manifests are the service clones, then the Access or ExternalAccess objects, as a multi-document YAML.
+	Manifests string `json:"manifests"`
+}
+
+// PreviewAccess returns the manifests creating an access would apply, without applying them.
+// PreviewAccess godoc
+// @Summary      Preview the manifests of an access
+// @Description  Builds the service clones and the Access or ExternalAccess objects exactly like creating the access directly would, and returns them as YAML without creating anything. Names derive from a request ID that is generated again on creation, so they will differ. Permissions are not checked.
+// @Tags         Access Policies
+// @Accept       json
+// @Produce      json
+// @Param        access           body    handlers.PreviewAccessInput  true   "Access to preview"
+// @Param        Idempotency-Key  header  string                       false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.AccessPreview
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /accesses/preview [post]
+func PreviewAccess(c *gin.Context) {
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input PreviewAccessInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	direction, err := normalizeDirection(input.Direction)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid direction: " + err.Error()})
+		return
+	}
+	if err := validateAccessDuration(input.Duration); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid duration: " + err.Error()})
+		return
+	}
+	payload := webSocketPayload{
+		SourceService: input.SourceService,
+		TargetService: input.TargetService,
+		Service:       input.Service,
+		Cidr:          input.Cidr,
+		Direction:     direction,
+		Ports:         input.Ports,
+		Duration:      input.Duration,
+	}
+
+	var plan *accessPlan
+	switch {
+	case input.SourceService != "" && input.TargetService != "":
+		plan, err = runner.processor.planClusterAccess(payload)
+	case input.Service != "" && input.Cidr != "":
+		plan, err = runner.processor.planExternalAccess(payload)
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set sourceService and targetService, or service and cidr"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not prepare the access: " + err.Error()})
+		return
+	}
+
+	manifests, err := plan.manifests()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not render the manifests: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, AccessPreview{RequestID: plan.requestID, Manifests: manifests})
+}
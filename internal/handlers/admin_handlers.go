@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/controller"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+var (
+	adminGroups       []string
+	adminAllowedCIDRs []netip.Prefix
+	apiKeyAdmin       bool
+	managerURL        string
+)
+
+// SetAdminGroups sets the OIDC groups allowed to use the /api/admin endpoints.
+func SetAdminGroups(groups []string) {
+	adminGroups = groups
+}
+
+// SetAPIKeyAdmin lets the static API key use the /api/admin endpoints. It has no admin rights otherwise.
+func SetAPIKeyAdmin(enabled bool) {
+	apiKeyAdmin = enabled
+}
+
+// SetAdminAllowedCIDRs restricts the /api/admin endpoints to clients in one of the CIDRs, whatever their
+// credentials. An empty list allows every source.
+func SetAdminAllowedCIDRs(cidrs []netip.Prefix) {
+	adminAllowedCIDRs = cidrs
+}
+
+// adminSourceAllowed reports whether the client IP is within the admin allowlist. The client IP is the address of
+// the connection unless it comes from one of NETWATCH_TRUSTED_PROXIES, see cmd/server.go.
+func adminSourceAllowed(clientIP string) bool {
+	if len(adminAllowedCIDRs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(clientIP)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(adminAllowedCIDRs, func(cidr netip.Prefix) bool { return cidr.Contains(addr.Unmap()) })
+}
+
+// SetManagerURL sets the base URL of the controller manager's metrics server, e.g.
+// http://netwatch-cleanup-controller-metrics-service.netwatch-system:8080.
+func SetManagerURL(url string) {
+	managerURL = strings.TrimSuffix(url, "/")
+}
+
+// RequireAdmin only lets through users in one of the admin groups, and the static API key when SetAPIKeyAdmin
+// allows it, from an allowed source.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !adminSourceAllowed(c.ClientIP()) {
+			logger.Logger.Warn("Admin endpoint called from a source outside the allowlist", "ip", c.ClientIP(), "path", c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint is not reachable from your network"})
+			return
+		}
+		if c.GetString("user") == "api-key-user" {
+			if !apiKeyAdmin {
+				logger.Logger.Warn("Static API key used on an admin endpoint without admin rights", "ip", c.ClientIP(), "path", c.Request.URL.Path)
+				c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "The static API key has no admin rights"})
+				return
+			}
+			c.Next()
+			return
+		}
+		idToken, err := getUserIdToken(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		userInfo, err := k8s.GetUserInfoFromToken(c.Request.Context(), idToken)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+			return
+		}
+		if isAdmin(userInfo) {
+			c.Next()
+			return
+		}
+		logger.Logger.Warn("Non-admin user tried to use an admin endpoint", "user", userInfo.Email, "path", c.Request.URL.Path)
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "This endpoint is restricted to administrators"})
+	}
+}
+
+// GetReconcileState returns the controller's view of netwatch resources.
+// GetReconcileState godoc
+// @Summary      Get controller reconcile state
+// @Description  Lists AccessRequests, Accesses and ExternalAccesses with their finalizers, deletion state and pending cleanup actions, merged with the last reconcile time and error reported by the controller manager. Restricted to administrators.
+// @Tags         Admin
+// @Produce      json
+// @Param        name  query     string  false  "Only return resources whose name contains this value"
+// @Success      200  {array}   controller.ReconcileState
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /admin/reconcile-state [get]
+func GetReconcileState(c *gin.Context) {
+	ctx := c.Request.Context()
+	nameFilter := c.Query("name")
+
+	var states []controller.ReconcileState
+	requests, err := k8s.ListAccessRequestsAsApp(ctx)
+	if err != nil {
+		logger.Logger.Error("Failed to list AccessRequests for reconcile state", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not list access requests"})
+		return
+	}
+	for i := range requests.Items {
+		states = append(states, controller.DescribeObject("AccessRequest", &requests.Items[i]))
+	}
+	var accessList vtkiov1alpha1.AccessList
+	if err := k8s.ListNetwatchAccesses(ctx, &accessList); err != nil {
+		logger.Logger.Error("Failed to list Accesses for reconcile state", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not list accesses"})
+		return
+	}
+	for i := range accessList.Items {
+		states = append(states, controller.DescribeObject("Access", &accessList.Items[i]))
+	}
+	var extList vtkiov1alpha1.ExternalAccessList
+	if err := k8s.ListNetwatchExternalAccesses(ctx, &extList); err != nil {
+		logger.Logger.Error("Failed to list ExternalAccesses for reconcile state", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not list external accesses"})
+		return
+	}
+	for i := range extList.Items {
+		states = append(states, controller.DescribeObject("ExternalAccess", &extList.Items[i]))
+	}
+
+	if managerURL != "" {
+		managerStates, err := fetchManagerStates(ctx)
+		if err != nil {
+			logger.Logger.Warn("Could not fetch reconcile state from the manager", "error", err)
+		}
+		for i := range states {
+			if ms, ok := managerStates[states[i].Kind+"/"+states[i].Namespace+"/"+states[i].Name]; ok {
+				states[i].LastReconcile, states[i].LastError = ms.LastReconcile, ms.LastError
+			}
+		}
+	}
+
+	filtered := make([]controller.ReconcileState, 0, len(states))
+	for _, state := range states {
+		if nameFilter == "" || strings.Contains(state.Name, nameFilter) {
+			filtered = append(filtered, state)
+		}
+	}
+	c.JSON(http.StatusOK, filtered)
+}
+
+func fetchManagerStates(ctx context.Context) (map[string]controller.ReconcileState, error) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, managerURL+controller.ReconcileStatePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:all
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("manager returned status %d", resp.StatusCode)
+	}
+	var list []controller.ReconcileState
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]controller.ReconcileState, len(list))
+	for _, state := range list {
+		byKey[state.Kind+"/"+state.Namespace+"/"+state.Name] = state
+	}
+	return byKey, nil
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireAdminChecksTheSourceBehindTrustedProxiesOnly(t *testing.T) {
+	SetAdminAllowedCIDRs([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+	SetAPIKeyAdmin(true)
+	t.Cleanup(func() {
+		SetAdminAllowedCIDRs(nil)
+		SetAPIKeyAdmin(false)
+	})
+
+	tests := []struct {
+		name           string
+		trustedProxies []string
+		remoteAddr     string
+		forwardedFor   string
+		want           int
+	}{
+		{"direct client in the allowlist", nil, "10.1.2.3:4000", "", http.StatusOK},
+		{"direct client outside the allowlist", nil, "203.0.113.5:4000", "", http.StatusForbidden},
+		{"spoofed X-Forwarded-For without trusted proxies", nil, "203.0.113.5:4000", "10.1.2.3", http.StatusForbidden},
+		{"spoofed X-Forwarded-For from an untrusted peer", []string{"192.168.0.0/16"}, "203.0.113.5:4000", "10.1.2.3", http.StatusForbidden},
+		{"client in the allowlist behind a trusted proxy", []string{"192.168.0.0/16"}, "192.168.1.1:4000", "10.1.2.3", http.StatusOK},
+		{"client outside the allowlist behind a trusted proxy", []string{"192.168.0.0/16"}, "192.168.1.1:4000", "203.0.113.5", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			if err := router.SetTrustedProxies(tt.trustedProxies); err != nil {
+				t.Fatal(err)
+			}
+			router.GET("/api/admin/ping", func(c *gin.Context) { c.Set("user", "api-key-user") }, RequireAdmin(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/admin/ping", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireAdminGrantsTheStaticAPIKeyOnlyWhenAllowed(t *testing.T) {
+	tests := []struct {
+		name        string
+		apiKeyAdmin bool
+		want        int
+	}{
+		{"static API key by default", false, http.StatusForbidden},
+		{"static API key with admin rights", true, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			SetAPIKeyAdmin(tt.apiKeyAdmin)
+			t.Cleanup(func() { SetAPIKeyAdmin(false) })
+			router := gin.New()
+			router.GET("/api/admin/ping", func(c *gin.Context) { c.Set("user", "api-key-user") }, RequireAdmin(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/admin/ping", nil))
+			if rec.Code != tt.want {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// Annotations deciding on an AccessRequest with kubectl or GitOps. The approve and deny annotations name the user
+// taking the decision, the comment annotation is the approval comment or the denial reason. The error annotation is
+// set by Netwatch when a decision fails.
+const (
+	approveAnnotation         = "netwatch.vtk.io/approve"
+	denyAnnotation            = "netwatch.vtk.io/deny"
+	decisionCommentAnnotation = "netwatch.vtk.io/decision-comment"
+	decisionErrorAnnotation   = "netwatch.vtk.io/decision-error"
+)
+
+// StartAnnotationDecisions periodically applies the decisions annotated on pending AccessRequests.
+func StartAnnotationDecisions(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting annotation decisions", "interval", interval)
+	for {
+		select {
+		case <-ticker.C:
+			list, err := k8s.ListAccessRequestsAsApp(ctx)
+			if err != nil {
+				logger.Logger.Error("Annotation decisions failed to list access requests", "error", err)
+				continue
+			}
+			for i := range list.Items {
+				request := &list.Items[i]
+				if !request.DeletionTimestamp.IsZero() || !strings.HasPrefix(request.Spec.Status, "Pending") {
+					continue
+				}
+				if request.Annotations[approveAnnotation] != "" || request.Annotations[denyAnnotation] != "" {
+					applyAnnotationDecision(ctx, request)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// applyAnnotationDecision approves or denies a request as the user named by its annotation, through the same path
+// as the UI.
+//
+// Kubernetes doesn't record who set a field, only the field manager of the client. The annotation is only trusted
+// while the admission policy rejecting those not naming the user setting them is enforced, and the named user must
+// still be allowed to update AccessRequests. They are impersonated for the decision, so the annotation grants nothing
+// beyond their permissions. Groups can't be known without their token: their RBAC must be bound to the user.
+func applyAnnotationDecision(ctx context.Context, request *netwatchv1alpha1.AccessRequest) {
+	approver, denier := request.Annotations[approveAnnotation], request.Annotations[denyAnnotation]
+	comment := request.Annotations[decisionCommentAnnotation]
+	key := approveAnnotation
+	if denier != "" {
+		key = denyAnnotation
+	}
+	manager := annotationManager(request.ManagedFields, key)
+
+	// Removing the annotations claims the decision: with several replicas, the others get a conflict.
+	delete(request.Annotations, approveAnnotation)
+	delete(request.Annotations, denyAnnotation)
+	delete(request.Annotations, decisionCommentAnnotation)
+	delete(request.Annotations, decisionErrorAnnotation)
+	if err := k8s.UpdateAccessRequestAsApp(ctx, request); err != nil {
+		if !k8s.IsConflict(err) {
+			logger.Logger.Error("Failed to claim an annotation decision", "error", err, "request", request.Name)
+		}
+		return
+	}
+	invalidateAccessRequestCache()
+
+	user := approver
+	if approver != "" && denier != "" {
+		failAnnotationDecision(ctx, request.Name, "both the approve and deny annotations are set")
+		return
+	} else if denier != "" {
+		user = denier
+	}
+	enforced, err := k8s.AnnotationDecisionPolicyEnforced(ctx)
+	if err != nil || !enforced {
+		if err != nil {
+			logger.Logger.Error("Failed to check the annotation decision policy", "error", err)
+		}
+		failAnnotationDecision(ctx, request.Name, fmt.Sprintf(
+			"the %s admission policy isn't enforced, so nothing proves %s set the annotation", k8s.AnnotationDecisionPolicy, user))
+		return
+	}
+	userInfo := &k8s.UserInfo{Email: user, Provider: "annotation"}
+	allowed, err := k8s.CanPerformAction(ctx, userInfo, "update", "netwatch.vtk.io", "accessrequests", "", request.Name)
+	if err != nil || !allowed {
+		failAnnotationDecision(ctx, request.Name, fmt.Sprintf("%s is not allowed to update AccessRequests", user))
+		return
+	}
+
+	logger.Logger.Info("Applying an annotation decision", "request", request.Name, "annotation", key, "user", user, "fieldManager", manager)
+	var failure string
+	processor := &webSocketCommandProcessor{
+		ctx:               ctx,
+		userInfo:          userInfo,
+		sanitizedUsername: sanitizeUsername(user),
+		logAndBroadcast: func(entry LogEntry) {
+			entry.User = user
+			persistLogEntry(entry)
+		},
+		sendPrivate: func(LogEntry) {},
+		sendError: func(msg string, err error, logType string) {
+			if err != nil {
+				msg = fmt.Sprintf("%s - %s", msg, err.Error())
+			}
+			failure = msg
+			persistLogEntry(LogEntry{
+				Payload: "REQUEST FAILED: " + msg, ClassName: "log-error", LogType: logType, Type: "applyResult", User: user,
+			})
+		},
+		channel: "annotation",
+	}
+	processor.logAndBroadcast(LogEntry{
+		Payload:   fmt.Sprintf("%s set %s on request %s (field manager %s).", user, key, requestDisplayName(request), manager),
+		ClassName: "log-info",
+		LogType:   "Request",
+		Type:      "applyResult",
+	})
+	if denier != "" {
+		processor.handleDenyAccessRequest(webSocketPayload{Command: "denyAccessRequest", RequestID: request.Name, Reason: comment})
+	} else {
+		processor.handleApproveAccessRequest(webSocketPayload{Command: "approveAccessRequest", RequestID: request.Name, Comment: comment})
+	}
+	if failure != "" {
+		failAnnotationDecision(ctx, request.Name, failure)
+	}
+}
+
+// failAnnotationDecision tells kubectl users why a decision failed, on the request when it still exists.
+func failAnnotationDecision(ctx context.Context, name, reason string) {
+	logger.Logger.Warn("Annotation decision failed", "request", name, "reason", reason)
+	request, err := k8s.GetAccessRequestAsApp(ctx, name)
+	if err != nil {
+		return
+	}
+	if request.Annotations == nil {
+		request.Annotations = make(map[string]string)
+	}
+	request.Annotations[decisionErrorAnnotation] = reason
+	if err := k8s.UpdateAccessRequestAsApp(ctx, request); err != nil {
+		logger.Logger.Error("Failed to record an annotation decision failure", "error", err, "request", name)
+	}
+}
+
+// annotationManager returns the field manager that last set an annotation, from the managed fields of an object.
+func annotationManager(entries []metav1.ManagedFieldsEntry, annotation string) string {
+	manager := "unknown"
+	var latest time.Time
+	for _, entry := range entries {
+		if entry.FieldsV1 == nil {
+			continue
+		}
+		var fields struct {
+			Metadata struct {
+				Annotations map[string]json.RawMessage `json:"f:annotations"`
+			} `json:"f:metadata"`
+		}
+		if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+			continue
+		}
+		if _, ok := fields.Metadata.Annotations["f:"+annotation]; !ok {
+			continue
+		}
+		if entry.Time == nil || !entry.Time.Time.Before(latest) {
+			manager = entry.Manager
+			if entry.Time != nil {
+				latest = entry.Time.Time
+			}
+		}
+	}
+	return manager
+}
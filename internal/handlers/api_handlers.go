@@ -5,18 +5,23 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
-	"golang.org/x/sync/errgroup"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
 	"github.com/Banh-Canh/netwatch/internal/k8s"
 	"github.com/Banh-Canh/netwatch/internal/utils/logger"
 )
@@ -24,7 +29,7 @@ import (
 // GetLogs handles fetching persisted logs from Redis.
 // GetLogs godoc
 // @Summary      Get global activity log
-// @Description  Retrieves all persisted log entries from the application.
+// @Description  Retrieves the persisted log entries that are still within the retention period.
 // @Tags         System
 // @Produce      json
 // @Success      200  {array}   LogEntry
@@ -34,7 +39,7 @@ import (
 // @Router       /logs [get]
 func GetLogs(c *gin.Context) {
 	ctx := context.Background()
-	logData, err := redisClient.ZRange(ctx, logKey, 0, -1).Result()
+	logData, err := redisClient.ZRangeByScore(ctx, logKey, &redis.ZRangeBy{Min: logRetentionCutoff(), Max: "+inf"}).Result()
 	if err != nil {
 		if err == redis.Nil {
 			c.JSON(http.StatusOK, []LogEntry{})
@@ -61,10 +66,12 @@ func GetLogs(c *gin.Context) {
 // GetPendingRequests lists AccessRequest CRs and enriches them with the current user's permissions.
 // GetPendingRequests godoc
 // @Summary      List pending access requests
-// @Description  Retrieves all pending AccessRequest custom resources and enriches them with the current user's permissions.
+// @Description  Retrieves all pending AccessRequest custom resources and enriches them with the current user's permissions, urgent requests first, then oldest first. Requests routed to the owners of their namespaces by the netwatch.vtk.io/owners annotation, or to approver groups by NETWATCH_APPROVER_GROUPS, are only listed to them and to their requestor. Only they get canSelfApprove.
 // @Tags         Requests
 // @Produce      json
+// @Param        label  query     []string  false  "Only return requests carrying this label (key=value). Can be repeated."  collectionFormat(multi)
 // @Success      200  {array}   AccessRequestPayload
+// @Failure      400  {object}  handlers.HTTPError
 // @Failure      401  {object}  handlers.HTTPError
 // @Failure      500  {object}  handlers.HTTPError
 // @Security     ApiKeyAuth
@@ -83,151 +90,383 @@ func GetPendingRequests(c *gin.Context) {
 		return
 	}
 
-	requestList, err := k8s.ListAccessRequestsAsApp(ctx)
+	labelFilter, err := requestLabelFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	requests, err := cachedAccessRequests(ctx, labelFilter)
 	if err != nil {
 		logger.Logger.Error("Failed to list AccessRequests from cluster", "error", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve pending requests"})
 		return
 	}
 
-	var pendingRequests []AccessRequestPayload
-	g, gCtx := errgroup.WithContext(ctx)
-	resultsChan := make(chan AccessRequestPayload, len(requestList.Items))
-
-	for _, item := range requestList.Items {
-		request := item
-		g.Go(func() error {
-			var canSelfApprove bool
-			var requiredPerms []k8s.PermissionRequest
-
-			if request.Spec.RequestType == "Service" {
-				sourceParts := strings.Split(request.Spec.SourceService, "/")
-				targetParts := strings.Split(request.Spec.TargetService, "/")
-				if len(sourceParts) == 2 && len(targetParts) == 2 {
-					requiredPerms = append(requiredPerms,
-						k8s.PermissionRequest{Verb: "create", Resource: "services", Namespace: sourceParts[0]},
-						k8s.PermissionRequest{Verb: "create", Resource: "services", Namespace: targetParts[0]},
-						k8s.PermissionRequest{Verb: "create", Group: "maxtac.vtk.io", Resource: "accesses", Namespace: sourceParts[0]},
-						k8s.PermissionRequest{Verb: "create", Group: "maxtac.vtk.io", Resource: "accesses", Namespace: targetParts[0]},
-					)
-				}
-			} else {
-				serviceParts := strings.Split(request.Spec.Service, "/")
-				if len(serviceParts) == 2 {
-					requiredPerms = append(requiredPerms,
-						k8s.PermissionRequest{Verb: "create", Resource: "services", Namespace: serviceParts[0]},
-						k8s.PermissionRequest{Verb: "create", Group: "maxtac.vtk.io", Resource: "externalaccesses", Namespace: serviceParts[0]},
-					)
-				}
-			}
+	// Requests routed to namespace owners or approver groups are only listed to them, the escalation groups once
+	// escalated, and to their requestor.
+	requests = slices.DeleteFunc(slices.Clone(requests), func(request netwatchv1alpha1.AccessRequest) bool {
+		return !isRequestOwner(userInfo.Email, request.Spec) && !isRoutedApprover(ctx, userInfo, &request)
+	})
+
+	// Each request gets its own slot, so a failed permission check degrades that row instead of the whole list.
+	pendingRequests := make([]AccessRequestPayload, len(requests))
+	var wg sync.WaitGroup
 
-			if len(requiredPerms) > 0 {
-				allowed, checkErr := k8s.CanPerformAllActions(gCtx, userInfo, requiredPerms)
+	for i := range requests {
+		request := &requests[i]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var canSelfApprove, permissionCheckFailed bool
+			requiredPerms := approvalPermissions(request.Spec)
+
+			if len(requiredPerms) > 0 && canApproveOwnRequest(request.Spec) && isRoutedApprover(ctx, userInfo, request) {
+				allowed, checkErr := cachedCanSelfApprove(ctx, userInfo, request, requiredPerms)
 				if checkErr != nil {
 					logger.Logger.Error("Failed to check self-approval permissions", "error", checkErr, "request", request.Name)
-					canSelfApprove = false
+					permissionCheckFailed = true
 				} else {
 					canSelfApprove = allowed
 				}
 			}
 
-			resultsChan <- AccessRequestPayload{
-				RequestID:      request.Name,
-				Requestor:      request.Spec.Requestor,
-				Timestamp:      request.CreationTimestamp.Unix(),
-				RequestType:    request.Spec.RequestType,
-				SourceService:  request.Spec.SourceService,
-				TargetService:  request.Spec.TargetService,
-				Cidr:           request.Spec.Cidr,
-				Service:        request.Spec.Service,
-				Direction:      request.Spec.Direction,
-				Ports:          request.Spec.Ports,
-				Duration:       request.Spec.Duration,
-				Description:    request.Spec.Description,
-				CanSelfApprove: canSelfApprove,
-				Status:         request.Spec.Status,
+			age, overdue := requestAging(request)
+			pendingRequests[i] = AccessRequestPayload{
+				RequestID:             request.Name,
+				DisplayName:           requestDisplayName(request),
+				Requestor:             request.Spec.Requestor,
+				FiledBy:               request.Spec.FiledBy,
+				Timestamp:             request.CreationTimestamp.Unix(),
+				RequestType:           request.Spec.RequestType,
+				SourceService:         request.Spec.SourceService,
+				TargetService:         request.Spec.TargetService,
+				Cidr:                  request.Spec.Cidr,
+				Service:               request.Spec.Service,
+				Direction:             request.Spec.Direction,
+				Ports:                 request.Spec.Ports,
+				Duration:              request.Spec.Duration,
+				Description:           request.Spec.Description,
+				TicketRef:             request.Spec.TicketRef,
+				Priority:              requestPriority(request.Spec),
+				PreviousRequestID:     request.Spec.PreviousRequestID,
+				History:               priorDecisions(ctx, request.Spec),
+				CanSelfApprove:        canSelfApprove,
+				PermissionCheckFailed: permissionCheckFailed,
+				Status:                request.Spec.Status,
+				Attachments:           listRequestAttachments(ctx, request.Name),
+				Labels:                fromRequestObjectLabels(request.Labels),
+				RequiredApprovals:     request.Spec.RequiredApprovals,
+				ApprovedBy:            approverNames(request.Spec),
+				DualApproval:          request.Spec.DualApproval,
+				ApprovedSides:         approvedSides(request.Spec),
+				Age:                   age,
+				SLABreached:           overdue,
 			}
-			return nil
-		})
-	}
-
-	if err := g.Wait(); err != nil {
-		logger.Logger.Error("Error during permission checks for pending requests", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not process pending requests"})
-		return
-	}
-	close(resultsChan)
-
-	for payload := range resultsChan {
-		pendingRequests = append(pendingRequests, payload)
+		}()
 	}
+	wg.Wait()
 
+	// Urgent requests come first, then the oldest ones.
 	sort.Slice(pendingRequests, func(i, j int) bool {
+		if ri, rj := priorityRank(pendingRequests[i].Priority), priorityRank(pendingRequests[j].Priority); ri != rj {
+			return ri < rj
+		}
 		return pendingRequests[i].Timestamp < pendingRequests[j].Timestamp
 	})
 
 	c.JSON(http.StatusOK, pendingRequests)
 }
 
+// ContinueHeader carries the token to fetch the next page of a paginated list. It is absent on the last page.
+const ContinueHeader = "X-Netwatch-Continue"
+
 // GetServices lists all usable services in the cluster.
 // GetServices godoc
 // @Summary      List all Kubernetes services
-// @Description  Retrieves a list of all services in the cluster, filtered to exclude system and clone services.
+// @Description  Retrieves a list of all services in the cluster, filtered to exclude system and clone services. With scoped, services the user cannot get are left out, as checked by a SubjectAccessReview per namespace. With limit, the list is paginated: pass the X-Netwatch-Continue response header as continue to get the next page, until it is absent.
 // @Tags         System
 // @Produce      json
+// @Param        q              query     string  false  "Only services whose namespace/name contains this value, case-insensitive"
+// @Param        namespace      query     string  false  "Only services of this namespace"
+// @Param        labelSelector  query     string  false  "Kubernetes label selector the services must match, e.g. app=web,tier!=cache"
+// @Param        limit          query     int     false  "Maximum number of services per page"
+// @Param        continue       query     string  false  "Token of the page to fetch, from the X-Netwatch-Continue header of the previous one"
+// @Param        scoped         query     bool    false  "Only services in namespaces where the current user can get services"
 // @Success      200  {array}   ServiceInfo
+// @Header       200  {string}  X-Netwatch-Continue  "Token of the next page, absent on the last one"
+// @Failure      400  {object}  handlers.HTTPError
 // @Failure      401  {object}  handlers.HTTPError
 // @Failure      500  {object}  handlers.HTTPError
 // @Security     ApiKeyAuth
 // @Router       /services [get]
 func GetServices(c *gin.Context) {
-	serviceList, err := k8s.ListAllServices(c.Request.Context())
-	if err != nil {
-		logger.Logger.Error("Failed to list services", "error", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve services from cluster"})
+	query := strings.ToLower(strings.TrimSpace(c.Query("q")))
+	opts := []client.ListOption{client.InNamespace(c.Query("namespace"))}
+	if selector := c.Query("labelSelector"); selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'labelSelector' parameter: " + err.Error()})
+			return
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: parsed})
+	}
+	limit := 0
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' parameter"})
+			return
+		}
+	}
+	continueToken := c.Query("continue")
+	if continueToken != "" && limit == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "'continue' requires 'limit'"})
 		return
 	}
 
-	var serviceInfos []ServiceInfo
-	for _, svc := range serviceList.Items {
-		if svc.Namespace == "kube-system" || strings.HasPrefix(svc.Name, "nc-") {
-			continue
+	var userInfo *k8s.UserInfo
+	if c.Query("scoped") == "true" {
+		idToken, err := getUserIdToken(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		if userInfo, err = k8s.GetUserInfoFromToken(c.Request.Context(), idToken); err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+			return
+		}
+	}
+	allowedNamespaces := make(map[string]bool)
+
+	serviceInfos := make([]ServiceInfo, 0)
+	for {
+		pageOpts := opts
+		if limit > 0 {
+			// Only ask for what is missing, so every service listed is either returned or filtered out, and the
+			// continue token resumes right after the page.
+			pageOpts = append(slices.Clone(opts), client.Limit(int64(limit-len(serviceInfos))), client.Continue(continueToken))
+		}
+		serviceList, err := k8s.ListAllServices(c.Request.Context(), pageOpts...)
+		if err != nil {
+			if k8s.IsResourceExpired(err) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "The 'continue' token expired, list again from the first page"})
+				return
+			}
+			logger.Logger.Error("Failed to list services", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve services from cluster"})
+			return
+		}
+		if userInfo != nil {
+			if err := checkNamespaces(c.Request.Context(), userInfo, serviceList.Items, allowedNamespaces); err != nil {
+				logger.Logger.Error("Failed to check service permissions", "error", err, "user", userInfo.Email)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check which services you can get"})
+				return
+			}
+		}
+		for _, svc := range serviceList.Items {
+			if svc.Namespace == "kube-system" || strings.HasPrefix(svc.Name, "nc-") {
+				continue
+			}
+			if userInfo != nil && !allowedNamespaces[svc.Namespace] {
+				continue
+			}
+			compound := fmt.Sprintf("%s/%s", svc.Namespace, svc.Name)
+			if query != "" && !strings.Contains(strings.ToLower(compound), query) {
+				continue
+			}
+			serviceInfos = append(serviceInfos, ServiceInfo{
+				Name:      svc.Name,
+				Namespace: svc.Namespace,
+				Compound:  compound,
+				Labels:    svc.Labels,
+			})
+		}
+		continueToken = serviceList.Continue
+		if limit == 0 || continueToken == "" || len(serviceInfos) >= limit {
+			break
 		}
-		serviceInfos = append(serviceInfos, ServiceInfo{
-			Name:      svc.Name,
-			Namespace: svc.Namespace,
-			Compound:  fmt.Sprintf("%s/%s", svc.Namespace, svc.Name),
-			Labels:    svc.Labels,
-		})
 	}
 
 	sort.Slice(serviceInfos, func(i, j int) bool {
 		return serviceInfos[i].Compound < serviceInfos[j].Compound
 	})
 
+	if continueToken != "" {
+		c.Header(ContinueHeader, continueToken)
+	}
 	c.JSON(http.StatusOK, serviceInfos)
 }
 
+// GetNamespaces lists the namespaces services can be requested in.
+// GetNamespaces godoc
+// @Summary      List Kubernetes namespaces
+// @Description  Retrieves the namespaces of the cluster with their labels, excluding kube-system, sorted by name. With scoped, namespaces where the current user cannot get services are left out, as checked by a SubjectAccessReview per namespace.
+// @Tags         System
+// @Produce      json
+// @Param        labelSelector  query     string  false  "Kubernetes label selector the namespaces must match, e.g. team=payments"
+// @Param        scoped         query     bool    false  "Only namespaces where the current user can get services"
+// @Success      200  {array}   NamespaceInfo
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /namespaces [get]
+func GetNamespaces(c *gin.Context) {
+	ctx := c.Request.Context()
+	var opts []client.ListOption
+	if selector := c.Query("labelSelector"); selector != "" {
+		parsed, err := labels.Parse(selector)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'labelSelector' parameter: " + err.Error()})
+			return
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: parsed})
+	}
+
+	namespaceList, err := k8s.ListNamespaces(ctx, opts...)
+	if err != nil {
+		logger.Logger.Error("Failed to list namespaces", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve namespaces from cluster"})
+		return
+	}
+	var names []string
+	for _, ns := range namespaceList.Items {
+		if ns.Name != "kube-system" {
+			names = append(names, ns.Name)
+		}
+	}
+
+	var allowed map[string]bool
+	if c.Query("scoped") == "true" {
+		idToken, err := getUserIdToken(c)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+			return
+		}
+		if allowed, err = k8s.AllowedNamespaces(ctx, userInfo, "get", "", "services", names); err != nil {
+			logger.Logger.Error("Failed to check namespace permissions", "error", err, "user", userInfo.Email)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check which namespaces you can use"})
+			return
+		}
+	}
+
+	namespaces := make([]NamespaceInfo, 0, len(names))
+	for _, ns := range namespaceList.Items {
+		if ns.Name == "kube-system" || (allowed != nil && !allowed[ns.Name]) {
+			continue
+		}
+		namespaces = append(namespaces, NamespaceInfo{Name: ns.Name, Labels: ns.Labels})
+	}
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i].Name < namespaces[j].Name })
+	c.JSON(http.StatusOK, namespaces)
+}
+
+// checkNamespaces records in allowed whether the user can get services in the namespaces of the given services,
+// only checking the namespaces not seen on a previous page.
+func checkNamespaces(ctx context.Context, userInfo *k8s.UserInfo, services []corev1.Service, allowed map[string]bool) error {
+	var unchecked []string
+	for _, svc := range services {
+		if _, seen := allowed[svc.Namespace]; !seen && !slices.Contains(unchecked, svc.Namespace) {
+			unchecked = append(unchecked, svc.Namespace)
+		}
+	}
+	if len(unchecked) == 0 {
+		return nil
+	}
+	result, err := k8s.AllowedNamespaces(ctx, userInfo, "get", "", "services", unchecked)
+	if err != nil {
+		return err
+	}
+	maps.Copy(allowed, result)
+	return nil
+}
+
 // GetActiveAccesses lists all active Netwatch-managed access policies.
 // GetActiveAccesses godoc
 // @Summary      List active access policies
-// @Description  Retrieves all active and partially-created (pending) access policies managed by Netwatch.
+// @Description  Retrieves all active, paused and partially-created (pending) access policies managed by Netwatch. The user and namespace filters are applied by the Kubernetes API server through label and namespace selectors.
 // @Tags         Access Policies
 // @Produce      json
+// @Param        user       query     string  false  "Only accesses owned by this user, by email or username"
+// @Param        namespace  query     string  false  "Only accesses whose Access or ExternalAccess object is in this namespace"
+// @Param        type       query     string  false  "Only accesses of this type"  Enums(Service, External)
+// @Param        status     query     string  false  "Only accesses in this state"  Enums(Active, Pending, Paused)
 // @Success      200  {array}   ActiveAccessInfo
+// @Failure      400  {object}  handlers.HTTPError
 // @Failure      401  {object}  handlers.HTTPError
 // @Failure      500  {object}  handlers.HTTPError
 // @Security     ApiKeyAuth
 // @Router       /active-accesses [get]
 func GetActiveAccesses(c *gin.Context) {
-	ctx := c.Request.Context()
+	filter, ok := activeAccessFilterFromQuery(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, listActiveAccesses(c.Request.Context(), filter))
+}
+
+// activeAccessFilterFromQuery reads the user, namespace, type and status query parameters. It answers 400 itself
+// and returns false when they are invalid.
+func activeAccessFilterFromQuery(c *gin.Context) (activeAccessFilter, bool) {
+	filter := activeAccessFilter{Namespace: c.Query("namespace"), Type: c.Query("type"), Status: c.Query("status")}
+	if !slices.Contains([]string{"", "Service", "External"}, filter.Type) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be Service or External"})
+		return filter, false
+	}
+	if !slices.Contains([]string{"", "Active", "Pending", "Paused"}, filter.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be Active, Pending or Paused"})
+		return filter, false
+	}
+	// Accesses are labelled with the username, resolve it from an email.
+	filter.Owner = c.Query("user")
+	if strings.Contains(filter.Owner, "@") {
+		filter.Owner = sanitizeUsername(filter.Owner)
+	}
+	return filter, true
+}
+
+// activeAccessFilter narrows the active access list down. Empty fields match everything.
+type activeAccessFilter struct {
+	// Owner is the username accesses are labelled with.
+	Owner string
+	// Requestor and RequestorUsername select the accesses a user asked for, see requestedBy.
+	Requestor         string
+	RequestorUsername string
+	Namespace         string
+	Type              string
+	Status            string
+}
+
+// labels returns the labels the listed Access and ExternalAccess objects must carry.
+func (f activeAccessFilter) labels() map[string]string {
+	if f.Owner == "" {
+		return nil
+	}
+	return map[string]string{"netwatch.vtk.io/user": f.Owner}
+}
+
+// matches checks an access against the filter, for what the API server could not select.
+func (f activeAccessFilter) matches(info ActiveAccessInfo) bool {
+	return (f.Owner == "" || info.Owner == f.Owner) &&
+		(f.Requestor == "" || requestedBy(info.Requestor, info.Owner, f.Requestor, f.RequestorUsername)) &&
+		(f.Namespace == "" || info.Namespace == f.Namespace) &&
+		(f.Type == "" || info.Type == f.Type) && (f.Status == "" || info.Status == f.Status)
+}
+
+// listActiveAccesses gathers the Netwatch-managed access policies matching a filter, including partial and paused ones.
+func listActiveAccesses(ctx context.Context, filter activeAccessFilter) []ActiveAccessInfo {
 	infos := make([]ActiveAccessInfo, 0)
 
 	allServices, err := k8s.ListAllServices(ctx)
 	if err != nil {
 		logger.Logger.Error("Failed to list services for active access list", "error", err)
-		c.JSON(http.StatusOK, []ActiveAccessInfo{})
-		return
+		return infos
 	}
 
 	clonesByReqID := make(map[string][]corev1.Service)
@@ -237,105 +476,121 @@ func GetActiveAccesses(c *gin.Context) {
 		}
 	}
 
-	var accessList vtkiov1alpha1.AccessList
-	if err := k8s.ListNetwatchAccesses(ctx, &accessList); err != nil {
-		logger.Logger.Error("Failed to list active service accesses", "error", err)
-	} else {
-		processedAccesses := make(map[string]ActiveAccessInfo)
+	if filter.Type != "External" && filter.Status != "Paused" {
+		var accessList vtkiov1alpha1.AccessList
+		if err := k8s.ListNetwatchAccessesMatching(ctx, &accessList, filter.Namespace, filter.labels()); err != nil {
+			logger.Logger.Error("Failed to list active service accesses", "error", err)
+		} else {
+			processedAccesses := make(map[string]ActiveAccessInfo)
 
-		for _, access := range accessList.Items {
-			reqID, ok := access.Labels["netwatch.vtk.io/request-id"]
-			if !ok {
-				continue
-			}
+			for _, access := range accessList.Items {
+				reqID, ok := access.Labels["netwatch.vtk.io/request-id"]
+				if !ok {
+					continue
+				}
 
-			clones, clonesFound := clonesByReqID[reqID]
-			if !clonesFound || len(clones) == 0 {
-				logger.Logger.Warn("Found Access object with no corresponding Service clones, skipping display.", "request-id", reqID, "access-name", access.Name)
-				continue
-			}
+				clones, clonesFound := clonesByReqID[reqID]
+				if !clonesFound || len(clones) == 0 {
+					logger.Logger.Warn("Found Access object with no corresponding Service clones, skipping display.", "request-id", reqID, "access-name", access.Name)
+					continue
+				}
 
-			var expiresAt int64 = -1
-			if access.Spec.Duration != "" {
-				duration, err := time.ParseDuration(access.Spec.Duration)
-				if err == nil {
-					expiresAt = access.CreationTimestamp.Time.Add(duration).Unix()
+				var expiresAt int64 = -1
+				if access.Spec.Duration != "" {
+					duration, err := time.ParseDuration(access.Spec.Duration)
+					if err == nil {
+						expiresAt = access.CreationTimestamp.Time.Add(duration).Unix()
+					}
 				}
-			}
 
-			info := ActiveAccessInfo{
-				Type:      "Service",
-				Name:      access.Name,
-				Namespace: access.Namespace,
-				ExpiresAt: expiresAt,
-				Direction: access.Spec.Direction,
-			}
+				info := ActiveAccessInfo{
+					Type:       "Service",
+					Name:       access.Name,
+					Namespace:  access.Namespace,
+					ExpiresAt:  expiresAt,
+					Direction:  access.Spec.Direction,
+					Owner:      access.Labels["netwatch.vtk.io/user"],
+					Requestor:  access.Annotations[requestorAnnotation],
+					ApprovedBy: access.Annotations[approvedByAnnotation],
+					CreatedAt:  access.CreationTimestamp.Unix(),
+					Drift:      lookupDrift("Service", access.Namespace, access.Name),
+				}
 
-			if len(clones) == 2 {
-				// This is a complete pair.
-				info.Status = "Active"
-				clone1, clone2 := clones[0], clones[1]
-				if access.Spec.Targets[0].ServiceName == clone1.Name && access.Spec.Targets[0].Namespace == clone1.Namespace {
-					info.Target = clone1.Annotations["netwatch.vtk.io/cloned-from"]
-					info.Source = clone2.Annotations["netwatch.vtk.io/cloned-from"]
-					info.Ports = clone2.Annotations["netwatch.vtk.io/ports"]
-				} else if access.Spec.Targets[0].ServiceName == clone2.Name && access.Spec.Targets[0].Namespace == clone2.Namespace {
-					info.Target = clone2.Annotations["netwatch.vtk.io/cloned-from"]
-					info.Source = clone1.Annotations["netwatch.vtk.io/cloned-from"]
-					info.Ports = clone1.Annotations["netwatch.vtk.io/ports"]
+				if len(clones) == 2 {
+					// This is a complete pair.
+					info.Status = "Active"
+					clone1, clone2 := clones[0], clones[1]
+					if access.Spec.Targets[0].ServiceName == clone1.Name && access.Spec.Targets[0].Namespace == clone1.Namespace {
+						info.Target = clone1.Annotations["netwatch.vtk.io/cloned-from"]
+						info.Source = clone2.Annotations["netwatch.vtk.io/cloned-from"]
+						info.Ports = clone2.Annotations["netwatch.vtk.io/ports"]
+					} else if access.Spec.Targets[0].ServiceName == clone2.Name && access.Spec.Targets[0].Namespace == clone2.Namespace {
+						info.Target = clone2.Annotations["netwatch.vtk.io/cloned-from"]
+						info.Source = clone1.Annotations["netwatch.vtk.io/cloned-from"]
+						info.Ports = clone1.Annotations["netwatch.vtk.io/ports"]
+					}
+				} else if len(clones) == 1 {
+					// This is a partial (pending) access.
+					info.Status = "Pending"
+					clone := clones[0]
+					info.Source = clone.Annotations["netwatch.vtk.io/cloned-from"]
+					targetSvcString := fmt.Sprintf("%s/%s", access.Spec.Targets[0].Namespace, strings.Replace(strings.Replace(access.Spec.Targets[0].ServiceName, "nc-", "", 1), "-"+hex.EncodeToString([]byte(reqID))[:8], "", 1))
+					info.Target = fmt.Sprintf("%s (Pending Approval)", targetSvcString)
+					info.Ports = clone.Annotations["netwatch.vtk.io/ports"]
+				} else {
+					// Any other state ( >2 clones) is inconsistent and should be skipped.
+					logger.Logger.Warn("Found Access object with an inconsistent number of clones, skipping display.", "request-id", reqID, "clone-count", len(clones))
+					continue
 				}
-			} else if len(clones) == 1 {
-				// This is a partial (pending) access.
-				info.Status = "Pending"
-				clone := clones[0]
-				info.Source = clone.Annotations["netwatch.vtk.io/cloned-from"]
-				targetSvcString := fmt.Sprintf("%s/%s", access.Spec.Targets[0].Namespace, strings.Replace(strings.Replace(access.Spec.Targets[0].ServiceName, "nc-", "", 1), "-"+hex.EncodeToString([]byte(reqID))[:8], "", 1))
-				info.Target = fmt.Sprintf("%s (Pending Approval)", targetSvcString)
-				info.Ports = clone.Annotations["netwatch.vtk.io/ports"]
-			} else {
-				// Any other state ( >2 clones) is inconsistent and should be skipped.
-				logger.Logger.Warn("Found Access object with an inconsistent number of clones, skipping display.", "request-id", reqID, "clone-count", len(clones))
-				continue
-			}
 
-			processedAccesses[reqID] = info
-		}
+				processedAccesses[reqID] = info
+			}
 
-		for _, info := range processedAccesses {
-			infos = append(infos, info)
+			for _, info := range processedAccesses {
+				infos = append(infos, info)
+			}
 		}
 	}
 
-	var extList vtkiov1alpha1.ExternalAccessList
-	if err := k8s.ListNetwatchExternalAccesses(ctx, &extList); err != nil {
-		logger.Logger.Error("Failed to list active external accesses", "error", err)
-	} else {
-		for _, access := range extList.Items {
-			var expiresAt int64 = -1
-			if access.Spec.Duration != "" {
-				duration, err := time.ParseDuration(access.Spec.Duration)
-				if err == nil {
-					expiresAt = access.CreationTimestamp.Time.Add(duration).Unix()
+	if filter.Type != "Service" && filter.Status != "Pending" && filter.Status != "Paused" {
+		var extList vtkiov1alpha1.ExternalAccessList
+		if err := k8s.ListNetwatchExternalAccessesMatching(ctx, &extList, filter.Namespace, filter.labels()); err != nil {
+			logger.Logger.Error("Failed to list active external accesses", "error", err)
+		} else {
+			for _, access := range extList.Items {
+				var expiresAt int64 = -1
+				if access.Spec.Duration != "" {
+					duration, err := time.ParseDuration(access.Spec.Duration)
+					if err == nil {
+						expiresAt = access.CreationTimestamp.Time.Add(duration).Unix()
+					}
 				}
-			}
 
-			targetInfo := fmt.Sprintf("%s/* (label)", access.Namespace)
-			portsInfo := "All"
+				targetInfo := fmt.Sprintf("%s/* (label)", access.Namespace)
+				portsInfo := "All"
 
-			if reqID, ok := access.Labels["netwatch.vtk.io/request-id"]; ok {
-				if clones, ok := clonesByReqID[reqID]; ok && len(clones) == 1 {
-					clone := clones[0]
-					targetInfo = clone.Annotations["netwatch.vtk.io/cloned-from"]
-					portsInfo = clone.Annotations["netwatch.vtk.io/ports"]
+				if reqID, ok := access.Labels["netwatch.vtk.io/request-id"]; ok {
+					if clones, ok := clonesByReqID[reqID]; ok && len(clones) == 1 {
+						clone := clones[0]
+						targetInfo = clone.Annotations["netwatch.vtk.io/cloned-from"]
+						portsInfo = clone.Annotations["netwatch.vtk.io/ports"]
+					}
 				}
-			}
 
-			infos = append(infos, ActiveAccessInfo{
-				Type: "External", Name: access.Name, Namespace: access.Namespace, Source: strings.Join(access.Spec.TargetCIDRs, ", "), Target: targetInfo, ExpiresAt: expiresAt, Direction: access.Spec.Direction, Ports: portsInfo, Status: "Active",
-			})
+				infos = append(infos, ActiveAccessInfo{
+					Type: "External", Name: access.Name, Namespace: access.Namespace, Source: strings.Join(access.Spec.TargetCIDRs, ", "), Target: targetInfo, ExpiresAt: expiresAt,
+					Direction: access.Spec.Direction, Ports: portsInfo, Status: "Active", Owner: access.Labels["netwatch.vtk.io/user"],
+					Requestor: access.Annotations[requestorAnnotation], ApprovedBy: access.Annotations[approvedByAnnotation],
+					CreatedAt: access.CreationTimestamp.Unix(), Drift: lookupDrift("External", access.Namespace, access.Name),
+				})
+			}
 		}
 	}
 
+	infos = append(infos, listPausedAccesses(ctx)...)
+	// Statuses, and the owner and namespace of paused accesses, are only known once listed.
+	infos = slices.DeleteFunc(infos, func(info ActiveAccessInfo) bool { return !filter.matches(info) })
+
 	sort.Slice(infos, func(i, j int) bool {
 		if infos[i].ExpiresAt == -1 {
 			return false
@@ -346,25 +601,5 @@ func GetActiveAccesses(c *gin.Context) {
 		return infos[i].ExpiresAt < infos[j].ExpiresAt
 	})
 
-	c.JSON(http.StatusOK, infos)
-}
-
-// StartLogJanitor runs a background goroutine to clean up old logs from Redis.
-func StartLogJanitor(ctx context.Context, client *redis.Client, interval, retention time.Duration) {
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
-	logger.Logger.Info("Starting Redis log janitor", "interval", interval, "retention", retention)
-
-	for {
-		select {
-		case <-ticker.C:
-			maxScore := time.Now().Add(-retention).UnixMilli()
-			if err := client.ZRemRangeByScore(ctx, logKey, "-inf", strconv.FormatInt(maxScore, 10)).Err(); err != nil {
-				logger.Logger.Error("Failed to clean up old logs from Redis", "error", err)
-			}
-		case <-ctx.Done():
-			logger.Logger.Info("Stopping Redis log janitor.")
-			return
-		}
-	}
+	return infos
 }
@@ -0,0 +1,40 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+// approvalComment joins the comments of the approvals of a request, ending with the current one. With several
+// approvals, each comment is prefixed with its approver.
+func approvalComment(request *netwatchv1alpha1.AccessRequest, approver, comment string) string {
+	if len(request.Spec.ApprovedBy) == 0 {
+		return comment
+	}
+	var comments []string
+	for _, approval := range append(request.Spec.ApprovedBy, netwatchv1alpha1.Approval{Approver: approver, Comment: comment}) {
+		if approval.Comment != "" {
+			comments = append(comments, fmt.Sprintf("%s: %s", approval.Approver, approval.Comment))
+		}
+	}
+	return strings.Join(comments, "\n")
+}
+
+// approvalAnnotations returns the provenance annotations of the accesses created by approving a request.
+func (p *webSocketCommandProcessor) approvalAnnotations(request *netwatchv1alpha1.AccessRequest, comment string) map[string]string {
+	annotations := provenanceAnnotations(request.Spec.Requestor, p.approvers(request))
+	if joined := approvalComment(request, p.userInfo.Email, comment); joined != "" {
+		annotations[approvalCommentAnnotation] = joined
+	}
+	return annotations
+}
+
+// commentSuffix quotes an approval comment at the end of an activity log entry.
+func commentSuffix(comment string) string {
+	if comment == "" {
+		return ""
+	}
+	return fmt.Sprintf(" Comment: %q", comment)
+}
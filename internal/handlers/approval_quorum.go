@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// ApprovalQuorumConfig sets how many approvals sensitive requests need before their accesses are created.
+// A request needs the highest quorum among the rules it matches.
+type ApprovalQuorumConfig struct {
+	// Namespaces maps a namespace to the approvals of requests touching it.
+	Namespaces map[string]int
+	// Durations lists the approvals of requests longer than a duration. Requests without expiry match them all.
+	Durations []DurationQuorum
+}
+
+// DurationQuorum requires Approvals approvals for requests longer than Above.
+type DurationQuorum struct {
+	Above     time.Duration
+	Approvals int
+}
+
+var approvalQuorum ApprovalQuorumConfig
+
+// SetApprovalQuorum configures the approvals sensitive requests need.
+func SetApprovalQuorum(cfg ApprovalQuorumConfig) {
+	approvalQuorum = cfg
+}
+
+// ParseApprovalQuorum reads the quorum rules of NETWATCH_APPROVAL_QUORUM ("namespace=2,...") and
+// NETWATCH_APPROVAL_QUORUM_DURATIONS ("24h=2,168h=3").
+func ParseApprovalQuorum(namespaces, durations string) (ApprovalQuorumConfig, error) {
+	cfg := ApprovalQuorumConfig{Namespaces: make(map[string]int)}
+	for key, approvals := range quorumRules(namespaces) {
+		n, err := parseQuorum(approvals)
+		if err != nil {
+			return cfg, fmt.Errorf("namespace %q: %w", key, err)
+		}
+		cfg.Namespaces[key] = n
+	}
+	for key, approvals := range quorumRules(durations) {
+		above, err := time.ParseDuration(key)
+		if err != nil || above < 0 {
+			return cfg, fmt.Errorf("invalid duration %q", key)
+		}
+		n, err := parseQuorum(approvals)
+		if err != nil {
+			return cfg, fmt.Errorf("duration %q: %w", key, err)
+		}
+		cfg.Durations = append(cfg.Durations, DurationQuorum{Above: above, Approvals: n})
+	}
+	return cfg, nil
+}
+
+// quorumRules splits a comma-separated list of key=approvals rules. A rule without '=' has an empty quorum,
+// which parseQuorum refuses.
+func quorumRules(rules string) map[string]string {
+	parsed := make(map[string]string)
+	for rule := range strings.SplitSeq(rules, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		key, approvals, _ := strings.Cut(rule, "=")
+		parsed[strings.TrimSpace(key)] = strings.TrimSpace(approvals)
+	}
+	return parsed
+}
+
+func parseQuorum(approvals string) (int, error) {
+	n, err := strconv.Atoi(approvals)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of approvals %q", approvals)
+	}
+	return n, nil
+}
+
+// requiredApprovals returns the approvals a request needs under the configured quorum rules, at least one.
+func requiredApprovals(spec netwatchv1alpha1.AccessRequestSpec) int {
+	required := 1
+	for _, ns := range requestNamespaces(spec) {
+		required = max(required, approvalQuorum.Namespaces[ns])
+	}
+	for _, rule := range approvalQuorum.Durations {
+		if spec.Duration == 0 || time.Duration(spec.Duration)*time.Second > rule.Above {
+			required = max(required, rule.Approvals)
+		}
+	}
+	return required
+}
+
+// approverNames lists who approved a request that needs several approvals so far.
+func approverNames(spec netwatchv1alpha1.AccessRequestSpec) []string {
+	var names []string
+	for _, approval := range spec.ApprovedBy {
+		names = append(names, approval.Approver)
+	}
+	return names
+}
+
+// approvers lists who approved a request, ending with the current user, for the approved-by annotation.
+func (p *webSocketCommandProcessor) approvers(request *netwatchv1alpha1.AccessRequest) string {
+	return strings.Join(append(approverNames(request.Spec), p.userInfo.Email), ",")
+}
+
+// collectApproval records the approval of a request that needs several, as long as it is not the last one.
+// It reports whether the approval was handled: recorded, or refused. Otherwise the quorum is met and the accesses
+// can be created.
+func (p *webSocketCommandProcessor) collectApproval(request *netwatchv1alpha1.AccessRequest, comment string, changes []string) bool {
+	// collectSideApproval counts the approvals of DualApproval requests.
+	if request.Spec.RequiredApprovals <= 1 || request.Spec.DualApproval {
+		return false
+	}
+	if isRequestOwner(p.userInfo.Email, request.Spec) {
+		p.sendError("Requests needing several approvals cannot be approved by their requestor", nil, "Request")
+		return true
+	}
+	if slices.ContainsFunc(request.Spec.ApprovedBy, func(a netwatchv1alpha1.Approval) bool { return a.Approver == p.userInfo.Email }) {
+		p.sendError("You already approved this request, it needs another approver", nil, "Request")
+		return true
+	}
+	if len(request.Spec.ApprovedBy)+1 >= request.Spec.RequiredApprovals {
+		return false
+	}
+
+	// Nothing is created yet, so the approver's permissions are checked explicitly.
+	allowed, err := k8s.CanPerformAllActions(p.ctx, p.userInfo, approvalPermissions(request.Spec))
+	if err != nil {
+		p.sendError("Could not verify permissions for approving the request", err, "Request")
+		return true
+	}
+	if !allowed {
+		p.sendError("Permission denied. You lack the permissions to approve this request.", nil, "Request")
+		return true
+	}
+
+	request.Spec.ApprovedBy = append(request.Spec.ApprovedBy, netwatchv1alpha1.Approval{Approver: p.userInfo.Email, ApprovedAt: metav1.Now(), Comment: comment})
+	// The update fails on a conflict, so two approvals at once can't overwrite each other. It also keeps the changes
+	// made by this approver for the next ones.
+	if err := k8s.UpdateAccessRequestAsApp(p.ctx, request); err != nil {
+		p.sendError("Could not record the approval, please retry", err, "Request")
+		return true
+	}
+	invalidateAccessRequestCache()
+	logger.Logger.Info("Approval recorded, waiting for the quorum", "request", request.Name, "approver", p.userInfo.Email,
+		"approvals", len(request.Spec.ApprovedBy), "required", request.Spec.RequiredApprovals, "changes", changes)
+	p.logAndBroadcast(LogEntry{
+		Payload: fmt.Sprintf("Request from %s approved by %s (%d of %d approvals).%s%s", requestorLabel(request.Spec),
+			p.userInfo.Email, len(request.Spec.ApprovedBy), request.Spec.RequiredApprovals, changesSuffix(changes), commentSuffix(comment)),
+		ClassName: "log-info",
+		LogType:   "Request",
+		Type:      "applyResult",
+	})
+	return true
+}
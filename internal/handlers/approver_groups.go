@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// approverGroups maps namespaces to the OIDC groups reviewing the requests touching them.
+var approverGroups map[string][]string
+
+// SetApproverGroups routes the requests touching a namespace to the approvers of its groups.
+func SetApproverGroups(groups map[string][]string) {
+	approverGroups = groups
+}
+
+// ParseApproverGroups reads the routing rules of NETWATCH_APPROVER_GROUPS ("namespace=group,..."). A namespace
+// repeated in several rules is routed to all their groups.
+func ParseApproverGroups(rules string) (map[string][]string, error) {
+	groups := make(map[string][]string)
+	for rule := range strings.SplitSeq(rules, ",") {
+		if rule = strings.TrimSpace(rule); rule == "" {
+			continue
+		}
+		namespace, group, ok := strings.Cut(rule, "=")
+		namespace, group = strings.TrimSpace(namespace), strings.TrimSpace(group)
+		if !ok || namespace == "" || group == "" {
+			return nil, fmt.Errorf("invalid rule %q, expected namespace=group", rule)
+		}
+		if !slices.Contains(groups[namespace], group) {
+			groups[namespace] = append(groups[namespace], group)
+		}
+	}
+	return groups, nil
+}
+
+// pendingNamespaces lists the namespaces of a request still waiting for an approver. The requestor already created
+// their side of partial requests.
+func pendingNamespaces(spec netwatchv1alpha1.AccessRequestSpec) []string {
+	switch spec.Status {
+	case "PendingTarget":
+		return requestNamespaces(netwatchv1alpha1.AccessRequestSpec{TargetService: spec.TargetService})
+	case "PendingSource":
+		return requestNamespaces(netwatchv1alpha1.AccessRequestSpec{SourceService: spec.SourceService})
+	default:
+		return requestNamespaces(spec)
+	}
+}
+
+// routedGroups lists the groups a request is routed to: the owner groups of its namespaces declaring owners, and
+// the approver groups of the others. It is empty when none of its namespaces is routed.
+func routedGroups(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) []string {
+	var groups []string
+	for _, ns := range pendingNamespaces(spec) {
+		nsGroups := approverGroups[ns]
+		if owners, ok := ownersOf(ctx, ns); ok {
+			nsGroups = owners.groups
+		}
+		for _, group := range nsGroups {
+			if !slices.Contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+	return groups
+}
+
+// routedOwners lists the users declared as owners of the namespaces of a request.
+func routedOwners(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) []string {
+	var users []string
+	for _, ns := range pendingNamespaces(spec) {
+		owners, _ := ownersOf(ctx, ns)
+		for _, user := range owners.users {
+			if !slices.Contains(users, user) {
+				users = append(users, user)
+			}
+		}
+	}
+	return users
+}
+
+// isRoutedApprover reports whether a user may review a request: an owner of every namespace it waits on declaring
+// owners, and a member of the approver groups of every other mapped namespace, or of the escalation groups once it
+// is escalated. Namespaces without owners or approver groups are left to RBAC. Each side of a DualApproval request
+// is reviewed on its own, so reviewing either namespace is enough.
+func isRoutedApprover(ctx context.Context, userInfo *k8s.UserInfo, request *netwatchv1alpha1.AccessRequest) bool {
+	if isEscalationApprover(userInfo, request) {
+		return true
+	}
+	reviews := func(ns string) bool { return isNamespaceReviewer(ctx, userInfo, ns) }
+	if request.Spec.DualApproval {
+		return slices.ContainsFunc(pendingNamespaces(request.Spec), reviews)
+	}
+	return !slices.ContainsFunc(pendingNamespaces(request.Spec), func(ns string) bool { return !reviews(ns) })
+}
+
+// isNamespaceReviewer reports whether a user reviews the requests touching a namespace: one of its owners when it
+// declares some, a member of its approver groups when it is mapped, anyone otherwise.
+func isNamespaceReviewer(ctx context.Context, userInfo *k8s.UserInfo, ns string) bool {
+	if owners, ok := ownersOf(ctx, ns); ok {
+		return owners.includes(userInfo)
+	}
+	groups, mapped := approverGroups[ns]
+	return !mapped || slices.ContainsFunc(userInfo.Groups, func(group string) bool { return slices.Contains(groups, group) })
+}
+
+// namespaceReviewers lists who reviews the requests touching a namespace, for error messages.
+func namespaceReviewers(ctx context.Context, ns string) []string {
+	if owners, ok := ownersOf(ctx, ns); ok {
+		return append(slices.Clone(owners.users), owners.groups...)
+	}
+	if groups, ok := approverGroups[ns]; ok {
+		return groups
+	}
+	return []string{"its RBAC approvers"}
+}
+
+// notRoutedApprover reports, and rejects, a decision on a request routed to owners or approver groups the user is
+// not part of.
+func (p *webSocketCommandProcessor) notRoutedApprover(request *netwatchv1alpha1.AccessRequest) bool {
+	if isRoutedApprover(p.ctx, p.userInfo, request) {
+		return false
+	}
+	reviewers := append(routedOwners(p.ctx, request.Spec), routedGroups(p.ctx, request.Spec)...)
+	p.sendError(fmt.Sprintf("This request is reviewed by %s", strings.Join(reviewers, ", ")), nil, "Request")
+	return true
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	attachmentKeyPrefix        = "netwatch:attachment:"
+	requestAttachmentKeyPrefix = "netwatch:request_attachments:"
+	// attachmentRetention keeps evidence around after the request itself is approved or denied.
+	attachmentRetention = 30 * 24 * time.Hour
+	// maxAttachmentsPerRequest bounds the Redis footprint of a single request.
+	maxAttachmentsPerRequest = 5
+)
+
+// attachmentMaxBytes is the maximum size of a single attachment. It can be overridden with SetAttachmentMaxBytes.
+var attachmentMaxBytes int64 = 1 << 20
+
+// SetAttachmentMaxBytes configures the maximum size of a single uploaded attachment.
+func SetAttachmentMaxBytes(size int64) {
+	attachmentMaxBytes = size
+}
+
+// UploadAttachment stores a supporting document for a pending request.
+// UploadAttachment godoc
+// @Summary      Attach a file to a pending request
+// @Description  Uploads a small, size-capped file (e.g. an architecture diagram or an approval email) to the attachment bucket and links it to the request. Only the requestor can attach files.
+// @Tags         Requests
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id               path      string  true   "AccessRequest name"
+// @Param        file             formData  file    true   "File to attach"
+// @Param        Idempotency-Key  header    string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  AttachmentInfo
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      413  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Failure      503  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id}/attachments [post]
+func UploadAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestName := c.Param("id")
+	if !attachmentsEnabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Attachments are disabled, no attachment bucket is configured"})
+		return
+	}
+
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	request, err := k8s.GetAccessRequestAsApp(ctx, requestName)
+	if err != nil {
+		if k8s.IsNotFound(err) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pending request not found"})
+			return
+		}
+		logger.Logger.Error("Failed to get AccessRequest for attachment", "error", err, "request", requestName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve pending request"})
+		return
+	}
+	if !isRequestOwner(userInfo.Email, request.Spec) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the requestor can attach files to this request"})
+		return
+	}
+
+	count, err := redisClient.LLen(ctx, requestAttachmentKeyPrefix+requestName).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to count attachments", "error", err, "request", requestName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not store attachment"})
+		return
+	}
+	if count >= maxAttachmentsPerRequest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("A request cannot have more than %d attachments", maxAttachmentsPerRequest)})
+		return
+	}
+
+	// Leave some room for the multipart envelope around the file itself.
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, attachmentMaxBytes+64*1024)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "A 'file' form field is required (max " + strconv.FormatInt(attachmentMaxBytes, 10) + " bytes)"})
+		return
+	}
+	if fileHeader.Size > attachmentMaxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Attachment exceeds the maximum size of %d bytes", attachmentMaxBytes)})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read uploaded file"})
+		return
+	}
+	defer file.Close() //nolint:all
+	data, err := io.ReadAll(io.LimitReader(file, attachmentMaxBytes+1))
+	if err != nil || int64(len(data)) > attachmentMaxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Attachment exceeds the maximum size of %d bytes", attachmentMaxBytes)})
+		return
+	}
+
+	contentType := fileHeader.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	info := AttachmentInfo{
+		ID:          uuid.New().String(),
+		Name:        fileHeader.Filename,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		UploadedBy:  userInfo.Email,
+		UploadedAt:  time.Now().Unix(),
+	}
+
+	if err := putAttachment(ctx, requestName, info.ID, bytes.NewReader(data), info.Size); err != nil {
+		logger.Logger.Error("Failed to store attachment in the bucket", "error", err, "request", requestName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not store attachment"})
+		return
+	}
+
+	pipe := redisClient.TxPipeline()
+	pipe.HSet(ctx, attachmentKeyPrefix+info.ID, map[string]any{
+		"request":     requestName,
+		"name":        info.Name,
+		"contentType": info.ContentType,
+		"size":        info.Size,
+		"uploadedBy":  info.UploadedBy,
+		"uploadedAt":  info.UploadedAt,
+	})
+	pipe.Expire(ctx, attachmentKeyPrefix+info.ID, attachmentRetention)
+	pipe.RPush(ctx, requestAttachmentKeyPrefix+requestName, info.ID)
+	pipe.Expire(ctx, requestAttachmentKeyPrefix+requestName, attachmentRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Error("Failed to store attachment metadata in Redis", "error", err, "request", requestName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not store attachment"})
+		return
+	}
+
+	info.URL = attachmentURL(requestName, info.ID)
+	logger.Logger.Info("Attachment uploaded", "request", requestName, "user", userInfo.Email, "name", info.Name, "size", info.Size)
+	c.JSON(http.StatusCreated, info)
+}
+
+// DownloadAttachment returns the content of a request attachment.
+// DownloadAttachment godoc
+// @Summary      Download a request attachment
+// @Description  Returns the raw content of a file attached to an access request, as a download. Only the requestor and the users allowed to approve the request can download it.
+// @Tags         Requests
+// @Produce      octet-stream
+// @Param        id            path  string  true  "AccessRequest name"
+// @Param        attachmentID  path  string  true  "Attachment ID"
+// @Success      200
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id}/attachments/{attachmentID} [get]
+func DownloadAttachment(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestName, attachmentID := c.Param("id"), c.Param("attachmentID")
+
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	fields, err := redisClient.HGetAll(ctx, attachmentKeyPrefix+attachmentID).Result()
+	if err != nil || len(fields) == 0 || fields["request"] != requestName || !attachmentsEnabled() {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attachment not found"})
+		return
+	}
+	// The submission of a decided request is kept, so its attachments stay restricted to the same users.
+	_, spec, err := loadRequestDetail(ctx, requestName)
+	if err != nil && !errors.Is(err, errRequestNotFound) {
+		logger.Logger.Error("Failed to get AccessRequest for attachment", "error", err, "request", requestName)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve the request"})
+		return
+	}
+	if err != nil || !canSeeRequest(ctx, userInfo, spec) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the requestor and approvers can download this attachment"})
+		return
+	}
+
+	content, size, err := getAttachment(ctx, requestName, attachmentID)
+	if err != nil {
+		logger.Logger.Error("Failed to read attachment from the bucket", "error", err, "request", requestName, "attachment", attachmentID)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not read attachment"})
+		return
+	}
+	defer content.Close() //nolint:all
+
+	// Never let the browser render an uploaded file: it is served as a download, whatever its declared type.
+	c.DataFromReader(http.StatusOK, size, "application/octet-stream", content, map[string]string{
+		"Content-Disposition":    fmt.Sprintf("attachment; filename=%q", fields["name"]),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// listRequestAttachments returns the metadata of every attachment linked to a request.
+func listRequestAttachments(ctx context.Context, requestName string) []AttachmentInfo {
+	ids, err := redisClient.LRange(ctx, requestAttachmentKeyPrefix+requestName, 0, -1).Result()
+	if err != nil {
+		logger.Logger.Warn("Failed to list request attachments", "error", err, "request", requestName)
+		return nil
+	}
+
+	var attachments []AttachmentInfo
+	for _, id := range ids {
+		fields, err := redisClient.HMGet(ctx, attachmentKeyPrefix+id, "name", "contentType", "size", "uploadedBy", "uploadedAt").Result()
+		if err != nil || fields[0] == nil {
+			// The attachment expired or was never stored completely.
+			continue
+		}
+		size, _ := strconv.ParseInt(fmt.Sprint(fields[2]), 10, 64)
+		uploadedAt, _ := strconv.ParseInt(fmt.Sprint(fields[4]), 10, 64)
+		attachments = append(attachments, AttachmentInfo{
+			ID:          id,
+			Name:        fmt.Sprint(fields[0]),
+			ContentType: fmt.Sprint(fields[1]),
+			Size:        size,
+			UploadedBy:  fmt.Sprint(fields[3]),
+			UploadedAt:  uploadedAt,
+			URL:         attachmentURL(requestName, id),
+		})
+	}
+	return attachments
+}
+
+func attachmentURL(requestName, attachmentID string) string {
+	return fmt.Sprintf("/api/pending-requests/%s/attachments/%s", requestName, attachmentID)
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// AttachmentStorageConfig locates the S3-compatible bucket the content of attachments is stored in. Their
+// metadata stays in Redis.
+type AttachmentStorageConfig struct {
+	// Endpoint is the URL of the object storage, e.g. https://s3.eu-west-1.amazonaws.com.
+	Endpoint string
+	Bucket   string
+	Region   string
+	// AccessKey and SecretKey authenticate to the object storage. Without them, credentials are read from the
+	// AWS_* or MINIO_* environment variables, or from the IAM role of the pod.
+	AccessKey string
+	SecretKey string
+}
+
+var (
+	attachmentStore  *minio.Client
+	attachmentBucket string
+)
+
+// SetAttachmentStorage configures the bucket attachments are stored in. Attachments are disabled without one.
+func SetAttachmentStorage(cfg AttachmentStorageConfig) error {
+	attachmentStore, attachmentBucket = nil, ""
+	if cfg.Bucket == "" {
+		return nil
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil || endpoint.Host == "" || (endpoint.Scheme != "https" && endpoint.Scheme != "http") {
+		return fmt.Errorf("invalid endpoint %q, expected http(s)://host[:port]", cfg.Endpoint)
+	}
+	creds := credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, "")
+	if cfg.AccessKey == "" {
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{}, &credentials.EnvMinio{}, &credentials.IAM{},
+		})
+	}
+	store, err := minio.New(endpoint.Host, &minio.Options{Creds: creds, Secure: endpoint.Scheme == "https", Region: cfg.Region})
+	if err != nil {
+		return err
+	}
+	attachmentStore, attachmentBucket = store, cfg.Bucket
+	return nil
+}
+
+// attachmentsEnabled reports whether a bucket is configured for attachments.
+func attachmentsEnabled() bool {
+	return attachmentStore != nil
+}
+
+// attachmentObjectPrefix is the prefix of the keys of attachment contents in the bucket.
+const attachmentObjectPrefix = "attachments/"
+
+// attachmentObject is the key of the content of an attachment in the bucket.
+func attachmentObject(requestName, attachmentID string) string {
+	return attachmentObjectPrefix + requestName + "/" + attachmentID
+}
+
+// putAttachment stores the content of an attachment. Its original content type is only kept as metadata: it is
+// always served as a download.
+func putAttachment(ctx context.Context, requestName, attachmentID string, content io.Reader, size int64) error {
+	_, err := attachmentStore.PutObject(ctx, attachmentBucket, attachmentObject(requestName, attachmentID), content, size,
+		minio.PutObjectOptions{ContentType: "application/octet-stream"})
+	return err
+}
+
+// getAttachment opens the content of an attachment. Callers close it.
+func getAttachment(ctx context.Context, requestName, attachmentID string) (io.ReadCloser, int64, error) {
+	object, err := attachmentStore.GetObject(ctx, attachmentBucket, attachmentObject(requestName, attachmentID), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, 0, err
+	}
+	stat, err := object.Stat()
+	if err != nil {
+		object.Close() //nolint:all
+		return nil, 0, err
+	}
+	return object, stat.Size, nil
+}
+
+// StartAttachmentSweeper periodically deletes the attachments older than attachmentRetention from the bucket. Their
+// metadata expires from Redis at the same time, so the bucket needs no expiration rule of its own.
+func StartAttachmentSweeper(ctx context.Context, interval time.Duration) {
+	if !attachmentsEnabled() {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting attachment sweeper", "retention", attachmentRetention, "interval", interval)
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := sweepAttachments(ctx, time.Now().Add(-attachmentRetention))
+			if err != nil {
+				logger.Logger.Error("Attachment sweeper failed", "error", err, "deleted", deleted)
+			} else if deleted > 0 {
+				logger.Logger.Info("Expired attachments deleted from the bucket", "deleted", deleted)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sweepAttachments deletes the attachments uploaded before cutoff, and returns how many were deleted. Replicas may
+// sweep at the same time, deleting an object twice is harmless.
+func sweepAttachments(ctx context.Context, cutoff time.Time) (int, error) {
+	deleted := 0
+	var errs []error
+	for object := range attachmentStore.ListObjects(ctx, attachmentBucket, minio.ListObjectsOptions{Prefix: attachmentObjectPrefix, Recursive: true}) {
+		if object.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to list attachments: %w", object.Err))
+			break
+		}
+		if !object.LastModified.Before(cutoff) {
+			continue
+		}
+		if err := attachmentStore.RemoveObject(ctx, attachmentBucket, object.Key, minio.RemoveObjectOptions{}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", object.Key, err))
+			continue
+		}
+		deleted++
+	}
+	return deleted, errors.Join(errs...)
+}
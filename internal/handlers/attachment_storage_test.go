@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeBucket serves the ListObjectsV2 and DeleteObject calls of the S3 API on a single bucket.
+type fakeBucket struct {
+	mu      sync.Mutex
+	objects map[string]time.Time
+}
+
+func (b *fakeBucket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/evidence/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		prefix := r.URL.Query().Get("prefix")
+		var contents strings.Builder
+		for name, modified := range b.objects {
+			if strings.HasPrefix(name, prefix) {
+				fmt.Fprintf(&contents, "<Contents><Key>%s</Key><LastModified>%s</LastModified><Size>1</Size></Contents>",
+					name, modified.UTC().Format(time.RFC3339))
+			}
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?><ListBucketResult><Name>evidence</Name><Prefix>%s</Prefix>`+
+			`<KeyCount>%d</KeyCount><MaxKeys>1000</MaxKeys><IsTruncated>false</IsTruncated>%s</ListBucketResult>`,
+			prefix, len(b.objects), contents.String())
+	case r.Method == http.MethodDelete && key != "":
+		delete(b.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "unexpected call "+r.Method+" "+r.URL.String(), http.StatusNotImplemented)
+	}
+}
+
+func TestSweepAttachmentsDeletesExpiredObjects(t *testing.T) {
+	now := time.Now()
+	bucket := &fakeBucket{objects: map[string]time.Time{
+		"attachments/request-1/old":   now.Add(-attachmentRetention - time.Hour),
+		"attachments/request-2/fresh": now.Add(-time.Hour),
+		"reports/old":                 now.Add(-attachmentRetention - time.Hour),
+	}}
+	server := httptest.NewServer(bucket)
+	defer server.Close()
+	if err := SetAttachmentStorage(AttachmentStorageConfig{
+		Endpoint: server.URL, Bucket: "evidence", Region: "us-east-1", AccessKey: "key", SecretKey: "secret",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetAttachmentStorage(AttachmentStorageConfig{}) }) //nolint:errcheck
+
+	deleted, err := sweepAttachments(context.Background(), now.Add(-attachmentRetention))
+	if err != nil {
+		t.Fatalf("sweepAttachments: %v", err)
+	}
+	var left []string
+	for name := range bucket.objects {
+		left = append(left, name)
+	}
+	slices.Sort(left)
+	if want := []string{"attachments/request-2/fresh", "reports/old"}; deleted != 1 || !slices.Equal(left, want) {
+		t.Errorf("deleted %d, left %v, want 1 deleted and %v left", deleted, left, want)
+	}
+}
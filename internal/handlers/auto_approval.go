@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// autoApprovalApprover is recorded as the approver of the accesses provisioned by an auto-approval rule.
+func autoApprovalApprover(rule string) string {
+	return "auto-approval-rule:" + rule
+}
+
+// findAutoApprovalRule returns an auto-approval rule matching a request of userInfo, if any.
+func findAutoApprovalRule(
+	ctx context.Context,
+	userInfo *k8s.UserInfo,
+	spec netwatchv1alpha1.AccessRequestSpec,
+) *netwatchv1alpha1.AutoApprovalRule {
+	list, err := k8s.ListAutoApprovalRulesAsApp(ctx)
+	if err != nil {
+		logger.Logger.Warn("Failed to list auto-approval rules", "error", err)
+		return nil
+	}
+	for i := range list.Items {
+		rule := &list.Items[i]
+		if !autoApprovalRuleMatches(rule.Spec, userInfo, spec) {
+			continue
+		}
+		// The ports are checked last, they may need the target service.
+		if len(rule.Spec.Ports) > 0 {
+			ports, err := requestPorts(ctx, spec)
+			if err != nil {
+				logger.Logger.Warn("Could not check the ports of an auto-approval rule", "rule", rule.Name, "error", err)
+				continue
+			}
+			if slices.ContainsFunc(ports, func(port int32) bool { return !slices.Contains(rule.Spec.Ports, port) }) {
+				continue
+			}
+		}
+		return rule
+	}
+	return nil
+}
+
+// autoApprovalRuleMatches checks a request against everything but the ports of a rule.
+func autoApprovalRuleMatches(rule netwatchv1alpha1.AutoApprovalRuleSpec, userInfo *k8s.UserInfo, spec netwatchv1alpha1.AccessRequestSpec) bool {
+	if rule.RequestType != "" && rule.RequestType != spec.RequestType {
+		return false
+	}
+	if !slices.ContainsFunc(userInfo.Groups, func(g string) bool { return slices.Contains(rule.Groups, g) }) {
+		return false
+	}
+	// The access must be time-bound and no longer than the rule allows.
+	if spec.Duration <= 0 || time.Duration(spec.Duration)*time.Second > rule.MaxDuration.Duration {
+		return false
+	}
+
+	switch spec.RequestType {
+	case "Service":
+		sourceNs, _, _ := strings.Cut(spec.SourceService, "/")
+		targetNs, _, _ := strings.Cut(spec.TargetService, "/")
+		return namespaceAllowed(rule.SourceNamespaces, sourceNs) && namespaceAllowed(rule.TargetNamespaces, targetNs)
+	case "External":
+		ns, _, _ := strings.Cut(spec.Service, "/")
+		return len(rule.SourceNamespaces) == 0 && namespaceAllowed(rule.TargetNamespaces, ns) && cidrWithin(rule.ExternalCIDRs, spec.Cidr)
+	}
+	return false
+}
+
+// namespaceAllowed reports whether a namespace is listed, an empty list allowing any.
+func namespaceAllowed(namespaces []string, ns string) bool {
+	return len(namespaces) == 0 || slices.Contains(namespaces, ns)
+}
+
+// cidrWithin reports whether a CIDR, or a single IP, lies entirely within one of the blocks.
+func cidrWithin(blocks []string, cidr string) bool {
+	cidr = strings.TrimSpace(cidr)
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return false
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	for _, block := range blocks {
+		allowed, err := netip.ParsePrefix(strings.TrimSpace(block))
+		if err == nil && allowed.Bits() <= prefix.Bits() && allowed.Contains(prefix.Addr()) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestPath describes what a request connects, for the activity log.
+func requestPath(spec netwatchv1alpha1.AccessRequestSpec) string {
+	if spec.RequestType == "Service" {
+		return fmt.Sprintf("%s -> %s", spec.SourceService, spec.TargetService)
+	}
+	return fmt.Sprintf("%s <-> %s", spec.Service, spec.Cidr)
+}
+
+// autoApprove provisions a submission right away, as the app, when an auto-approval rule matches it.
+// It reports whether the submission was handled; otherwise it goes through review as usual.
+func (p *webSocketCommandProcessor) autoApprove(request *netwatchv1alpha1.AccessRequest) bool {
+	// Rules cover low-risk paths, a request needing a quorum or the approval of two teams is not one.
+	if request.Spec.RequiredApprovals > 1 || request.Spec.DualApproval {
+		return false
+	}
+	rule := findAutoApprovalRule(p.ctx, p.userInfo, request.Spec)
+	if rule == nil {
+		return false
+	}
+	approver := autoApprovalApprover(rule.Name)
+	if err := p.approveFullRequest(k8s.GetAppKubeClient(), request, provenanceAnnotations(request.Spec.Requestor, approver)); err != nil {
+		p.sendError("Failed to apply the auto-approved request", err, "Request")
+		return true
+	}
+	logger.Logger.Info("Request approved by auto-approval rule", "user", p.userInfo.Email, "rule", rule.Name,
+		"requestType", request.Spec.RequestType, "requestID", request.Spec.RequestID)
+	p.submitted(request, true)
+	recordAccessDecision(p.ctx, request, "approved", approver, "", nil)
+	notifyLifecycleEvent(newRequestEvent("request.approved", request, approver, ""))
+	p.logAndBroadcast(LogEntry{
+		Payload: fmt.Sprintf("SUCCESS: Request from %s approved automatically by rule %s: %s for %s, %s.",
+			request.Spec.Requestor, rule.Name, requestPath(request.Spec), durationLabel(request.Spec.Duration), portsLabel(request.Spec.Ports)),
+		ClassName: "log-success",
+		LogType:   request.Spec.RequestType,
+		Type:      "applyResult",
+	})
+	p.logAndBroadcast(
+		LogEntry{Payload: "--- Request complete ---", ClassName: "log-success", LogType: request.Spec.RequestType, Type: "applyComplete"},
+	)
+	return true
+}
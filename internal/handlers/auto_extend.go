@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// AutoExtendConfig configures the optional job that extends accesses still in use when they are about to expire.
+type AutoExtendConfig struct {
+	// UsageURL is queried with the namespace, name and request ID of an expiring access.
+	// It must answer with {"active": true} when flow logs show recent traffic through it.
+	UsageURL string
+	// Increment is the extra time granted per extension.
+	Increment time.Duration
+	// MaxExtensions bounds how many times a single access can be extended.
+	MaxExtensions int
+	// Interval is how often expiring accesses are checked.
+	Interval time.Duration
+}
+
+type usageResponse struct {
+	Active bool `json:"active"`
+}
+
+// StartAutoExtender periodically extends accesses that are about to expire while still actively used.
+func StartAutoExtender(ctx context.Context, cfg AutoExtendConfig) {
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting access auto-extender", "interval", cfg.Interval, "increment", cfg.Increment, "maxExtensions", cfg.MaxExtensions)
+
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	for {
+		select {
+		case <-ticker.C:
+			// Anything expiring before the next check would be gone by then.
+			horizon := time.Now().Add(2 * cfg.Interval)
+
+			var accessList vtkiov1alpha1.AccessList
+			if err := k8s.ListNetwatchAccesses(ctx, &accessList); err != nil {
+				logger.Logger.Error("Auto-extender failed to list accesses", "error", err)
+			} else {
+				// Both sides of a service-to-service access share a request ID and are extended together.
+				byRequest := make(map[string][]vtkiov1alpha1.Access)
+				for _, access := range accessList.Items {
+					if reqID, ok := access.Labels["netwatch.vtk.io/request-id"]; ok {
+						byRequest[reqID] = append(byRequest[reqID], access)
+					}
+				}
+				for reqID, accesses := range byRequest {
+					first := accesses[0]
+					if !shouldAutoExtend(first.CreationTimestamp.Time, first.Spec.Duration, first.Status.ExpirationTimestamp, first.Annotations, horizon, cfg) {
+						continue
+					}
+					if !isAccessInUse(ctx, httpClient, cfg.UsageURL, first.Namespace, first.Name, reqID) {
+						continue
+					}
+					for _, access := range accesses {
+						count, err := k8s.ExtendAccessAsApp(ctx, access.Namespace, access.Name, cfg.Increment)
+						if err != nil {
+							logger.Logger.Error("Failed to auto-extend access", "error", err, "namespace", access.Namespace, "name", access.Name)
+							continue
+						}
+						recordAutoExtension("Service", access.Namespace, access.Name, access.Labels["netwatch.vtk.io/user"], count, cfg)
+					}
+				}
+			}
+
+			var extList vtkiov1alpha1.ExternalAccessList
+			if err := k8s.ListNetwatchExternalAccesses(ctx, &extList); err != nil {
+				logger.Logger.Error("Auto-extender failed to list external accesses", "error", err)
+				continue
+			}
+			for _, access := range extList.Items {
+				if !shouldAutoExtend(access.CreationTimestamp.Time, access.Spec.Duration, access.Status.ExpirationTimestamp, access.Annotations, horizon, cfg) {
+					continue
+				}
+				if !isAccessInUse(ctx, httpClient, cfg.UsageURL, access.Namespace, access.Name, access.Labels["netwatch.vtk.io/request-id"]) {
+					continue
+				}
+				count, err := k8s.ExtendExternalAccessAsApp(ctx, access.Namespace, access.Name, cfg.Increment)
+				if err != nil {
+					logger.Logger.Error("Failed to auto-extend external access", "error", err, "namespace", access.Namespace, "name", access.Name)
+					continue
+				}
+				recordAutoExtension("External", access.Namespace, access.Name, access.Labels["netwatch.vtk.io/user"], count, cfg)
+			}
+		case <-ctx.Done():
+			logger.Logger.Info("Stopping access auto-extender.")
+			return
+		}
+	}
+}
+
+func shouldAutoExtend(created time.Time, duration string, expiration *metav1.Time, annotations map[string]string, horizon time.Time, cfg AutoExtendConfig) bool {
+	expiresAt, ok := accessExpiry(created, duration, expiration)
+	if !ok || expiresAt.After(horizon) || expiresAt.Before(time.Now()) {
+		return false
+	}
+	count, _ := strconv.Atoi(annotations[k8s.AutoExtensionsAnnotation])
+	return count < cfg.MaxExtensions
+}
+
+// isAccessInUse asks the flow-log integration whether traffic recently went through an access.
+func isAccessInUse(ctx context.Context, httpClient *http.Client, usageURL, namespace, name, requestID string) bool {
+	u, err := url.Parse(usageURL)
+	if err != nil {
+		logger.Logger.Error("Invalid auto-extend usage URL", "error", err)
+		return false
+	}
+	query := u.Query()
+	query.Set("namespace", namespace)
+	query.Set("name", name)
+	query.Set("requestID", requestID)
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		logger.Logger.Warn("Could not query access usage, not extending", "error", err, "namespace", namespace, "name", name)
+		return false
+	}
+	defer resp.Body.Close() //nolint:all
+	if resp.StatusCode != http.StatusOK {
+		logger.Logger.Warn("Access usage endpoint returned an error, not extending", "status", resp.StatusCode, "namespace", namespace, "name", name)
+		return false
+	}
+	var usage usageResponse
+	if err := json.NewDecoder(resp.Body).Decode(&usage); err != nil {
+		logger.Logger.Warn("Could not decode access usage response, not extending", "error", err)
+		return false
+	}
+	return usage.Active
+}
+
+// recordAutoExtension writes the audit entry that notifies users through the activity log.
+func recordAutoExtension(accessType, namespace, name, owner string, count int, cfg AutoExtendConfig) {
+	msg := fmt.Sprintf("AUTO-EXTENDED: %s access %s/%s (owner %s) was still in use and has been extended by %s (%d/%d).",
+		accessType, namespace, name, owner, cfg.Increment, count, cfg.MaxExtensions)
+	logger.Logger.Info("Access auto-extended", "type", accessType, "namespace", namespace, "name", name, "owner", owner, "extensions", count)
+	persistLogEntry(LogEntry{Payload: msg, ClassName: "log-warning", LogType: accessType, Type: "applyResult"})
+}
@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/sessions"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/middleware"
+)
+
+// Bootstrap is the data a frontend needs to start: who is logged in, until when, and what the server offers.
+// The main page renders it as HTML, /api/bootstrap returns it as JSON.
+type Bootstrap struct {
+	User   string `json:"user" example:"jane@example.com"`
+	UserIP string `json:"userIP" example:"10.0.0.12"`
+	// SessionExpiresAt is when the session ends, as a Unix timestamp.
+	SessionExpiresAt int64    `json:"sessionExpiresAt" example:"1760000000"`
+	Version          string   `json:"version" example:"v1.4.0"`
+	Features         Features `json:"features"`
+	// IDToken is only embedded in the HTML page, never returned by the API.
+	IDToken string `json:"-"`
+	// CSPNonce authorizes the page's inline script under the Content-Security-Policy.
+	CSPNonce string `json:"-"`
+}
+
+// Features describes the optional capabilities enabled on this server.
+type Features struct {
+	Slack                 bool     `json:"slack"`
+	ExposureReports       bool     `json:"exposureReports"`
+	GraphQL               bool     `json:"graphql"`
+	GeneratedRequestNames bool     `json:"generatedRequestNames"`
+	RequestLabelKeys      []string `json:"requestLabelKeys"`
+	Attachments           bool     `json:"attachments"`
+	AttachmentMaxBytes    int64    `json:"attachmentMaxBytes"`
+	HeartbeatGraceSeconds int64    `json:"heartbeatGraceSeconds"`
+	// MaxAccessDurationSeconds is the longest duration accepted, 0 when only maxtac's own limit applies.
+	MaxAccessDurationSeconds int64 `json:"maxAccessDurationSeconds"`
+	// IdleTimeoutSeconds is how long a login session may stay unused before it ends, 0 when only the session TTL applies.
+	IdleTimeoutSeconds int64 `json:"idleTimeoutSeconds"`
+}
+
+// currentFeatures reports the optional capabilities as configured at startup.
+func currentFeatures() Features {
+	labelKeys := make([]string, 0, len(requestLabelKeys))
+	for key := range requestLabelKeys {
+		labelKeys = append(labelKeys, key)
+	}
+	slices.Sort(labelKeys)
+	return Features{
+		Slack:                    slackSigningSecret != "",
+		ExposureReports:          len(reportSigningKey) > 0,
+		GraphQL:                  GraphQLEnabled(),
+		GeneratedRequestNames:    generateRequestNames,
+		RequestLabelKeys:         labelKeys,
+		Attachments:              attachmentsEnabled(),
+		AttachmentMaxBytes:       attachmentMaxBytes,
+		HeartbeatGraceSeconds:    int64(heartbeatGrace.Seconds()),
+		MaxAccessDurationSeconds: int64(maxAccessDuration.Seconds()),
+		IdleTimeoutSeconds:       int64(sessionIdleTimeout.Seconds()),
+	}
+}
+
+// sessionBootstrap builds the bootstrap data from the browser session.
+func sessionBootstrap(c *gin.Context, session *sessions.Session, version string) Bootstrap {
+	idToken, _ := session.Values["id_token"].(string)
+	user, _ := session.Values["user"].(string)
+
+	sessionExpiresAt, ok := session.Values["expires_at"].(int64)
+	if !ok {
+		sessionExpiresAt = time.Now().Add(time.Second * time.Duration(sessionTTL)).Unix()
+	}
+	return Bootstrap{
+		User:             user,
+		UserIP:           c.ClientIP(),
+		SessionExpiresAt: sessionExpiresAt,
+		Version:          version,
+		Features:         currentFeatures(),
+		IDToken:          idToken,
+		CSPNonce:         c.GetString(middleware.CSPNonceKey),
+	}
+}
+
+// GetBootstrap returns a handler serving the main page data as JSON.
+// GetBootstrap godoc
+// @Summary      Get frontend bootstrap data
+// @Description  Returns the data the main page is rendered with: the logged-in user, the session expiry, the server version and the optional features enabled. Callers authenticating with a bearer token get the identity of the token and its expiry.
+// @Tags         System
+// @Produce      json
+// @Success      200  {object}  handlers.Bootstrap
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /bootstrap [get]
+func GetBootstrap(version string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, _ := sessionStore.Get(c.Request, "auth-session")
+		data := sessionBootstrap(c, session, version)
+
+		// Bearer token callers have no session: describe the token instead.
+		if idToken, err := getUserIdToken(c); err == nil && idToken != data.IDToken {
+			userInfo, err := k8s.GetUserInfoFromToken(c.Request.Context(), idToken)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+				return
+			}
+			data.User = userInfo.Email
+			data.SessionExpiresAt = 0
+			if exp, ok := userInfo.Claims["exp"].(float64); ok {
+				data.SessionExpiresAt = int64(exp)
+			}
+		}
+		c.JSON(http.StatusOK, data)
+	}
+}
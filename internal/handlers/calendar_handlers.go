@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	calendarTokenPrefix     = "netwatch:calendar_token:"
+	calendarUserTokenPrefix = "netwatch:calendar_user:"
+	// calendarReminder is how long before an expiry calendar clients should alert the user.
+	calendarReminder = 15 * time.Minute
+)
+
+// CalendarFeedInfo is returned when a user (re)generates their personal calendar feed.
+type CalendarFeedInfo struct {
+	URL string `json:"url"`
+}
+
+// calendarEvent is a single access window rendered in the iCal feed.
+type calendarEvent struct {
+	uid     string
+	summary string
+	start   time.Time
+	end     time.Time
+}
+
+// CreateCalendarToken generates a new personal iCal feed URL for the caller, revoking the previous one.
+// CreateCalendarToken godoc
+// @Summary      Generate a personal calendar feed
+// @Description  Creates a token-authenticated iCal feed URL listing the caller's access windows and upcoming expiries. Any previously issued feed URL is revoked.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  CalendarFeedInfo
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /calendar/token [post]
+func CreateCalendarToken(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	tokenBytes := make([]byte, 24)
+	rand.Read(tokenBytes) //nolint:all
+	token := hex.EncodeToString(tokenBytes)
+
+	previous, err := redisClient.Get(ctx, calendarUserTokenPrefix+userInfo.Email).Result()
+	if err != nil && err != redis.Nil {
+		logger.Logger.Error("Failed to read previous calendar token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate calendar feed"})
+		return
+	}
+	pipe := redisClient.TxPipeline()
+	if previous != "" {
+		pipe.Del(ctx, calendarTokenPrefix+previous)
+	}
+	pipe.Set(ctx, calendarTokenPrefix+token, userInfo.Email, 0)
+	pipe.Set(ctx, calendarUserTokenPrefix+userInfo.Email, token, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Error("Failed to store calendar token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not generate calendar feed"})
+		return
+	}
+
+	c.JSON(http.StatusOK, CalendarFeedInfo{URL: fmt.Sprintf("%s/calendar/%s.ics", GetBaseURL(c.Request), token)})
+}
+
+// HandleCalendarFeed serves the iCal feed of a user. The token in the URL is the only credential,
+// so calendar clients can subscribe to it without an OIDC session.
+func HandleCalendarFeed(c *gin.Context) {
+	ctx := c.Request.Context()
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	email, err := redisClient.Get(ctx, calendarTokenPrefix+token).Result()
+	if err != nil {
+		c.String(http.StatusNotFound, "Unknown calendar feed")
+		return
+	}
+	owner := sanitizeUsername(email)
+
+	var events []calendarEvent
+	var accessList vtkiov1alpha1.AccessList
+	if err := k8s.ListNetwatchAccesses(ctx, &accessList); err != nil {
+		logger.Logger.Error("Failed to list accesses for calendar feed", "error", err)
+		c.String(http.StatusInternalServerError, "Could not build calendar feed")
+		return
+	}
+	seen := make(map[string]bool)
+	for _, access := range accessList.Items {
+		reqID := access.Labels["netwatch.vtk.io/request-id"]
+		if access.Labels["netwatch.vtk.io/user"] != owner || reqID == "" || seen[reqID] {
+			continue
+		}
+		end, ok := accessExpiry(access.CreationTimestamp.Time, access.Spec.Duration, access.Status.ExpirationTimestamp)
+		if !ok {
+			continue
+		}
+		// Both sides of a service-to-service access share the request ID, only list it once.
+		seen[reqID] = true
+		target := "?"
+		if len(access.Spec.Targets) > 0 {
+			target = fmt.Sprintf("%s/%s", access.Spec.Targets[0].Namespace, access.Spec.Targets[0].ServiceName)
+		}
+		events = append(events, calendarEvent{
+			uid:     reqID,
+			summary: fmt.Sprintf("Netwatch access %s/%s <-> %s", access.Namespace, access.Name, target),
+			start:   access.CreationTimestamp.Time,
+			end:     end,
+		})
+	}
+
+	var extList vtkiov1alpha1.ExternalAccessList
+	if err := k8s.ListNetwatchExternalAccesses(ctx, &extList); err != nil {
+		logger.Logger.Error("Failed to list external accesses for calendar feed", "error", err)
+		c.String(http.StatusInternalServerError, "Could not build calendar feed")
+		return
+	}
+	for _, access := range extList.Items {
+		if access.Labels["netwatch.vtk.io/user"] != owner {
+			continue
+		}
+		end, ok := accessExpiry(access.CreationTimestamp.Time, access.Spec.Duration, access.Status.ExpirationTimestamp)
+		if !ok {
+			continue
+		}
+		events = append(events, calendarEvent{
+			uid:     string(access.UID),
+			summary: fmt.Sprintf("Netwatch external access %s -> %s/%s", strings.Join(access.Spec.TargetCIDRs, ", "), access.Namespace, access.Name),
+			start:   access.CreationTimestamp.Time,
+			end:     end,
+		})
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].end.Before(events[j].end) })
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(renderCalendar(events)))
+}
+
+// accessExpiry returns when an access ends. Accesses without a duration never expire and are not listed.
+func accessExpiry(created time.Time, duration string, expiration *metav1.Time) (time.Time, bool) {
+	if expiration != nil {
+		return expiration.Time, true
+	}
+	if duration == "" {
+		return time.Time{}, false
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return created.Add(d), true
+}
+
+func renderCalendar(events []calendarEvent) string {
+	const layout = "20060102T150405Z"
+	var b strings.Builder
+	b.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Netwatch//Access Calendar//EN\r\nCALSCALE:GREGORIAN\r\nX-WR-CALNAME:Netwatch accesses\r\n")
+	now := time.Now().UTC().Format(layout)
+	for _, e := range events {
+		b.WriteString("BEGIN:VEVENT\r\n")
+		fmt.Fprintf(&b, "UID:%s@netwatch\r\n", e.uid)
+		fmt.Fprintf(&b, "DTSTAMP:%s\r\n", now)
+		fmt.Fprintf(&b, "DTSTART:%s\r\n", e.start.UTC().Format(layout))
+		fmt.Fprintf(&b, "DTEND:%s\r\n", e.end.UTC().Format(layout))
+		fmt.Fprintf(&b, "SUMMARY:%s\r\n", escapeICalText(e.summary))
+		b.WriteString("BEGIN:VALARM\r\nACTION:DISPLAY\r\n")
+		fmt.Fprintf(&b, "DESCRIPTION:%s\r\n", escapeICalText("Expiring soon: "+e.summary))
+		fmt.Fprintf(&b, "TRIGGER;RELATED=END:-PT%dM\r\n", int(calendarReminder.Minutes()))
+		b.WriteString("END:VALARM\r\nEND:VEVENT\r\n")
+	}
+	b.WriteString("END:VCALENDAR\r\n")
+	return b.String()
+}
+
+func escapeICalText(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,554 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// CommandResult is the outcome of a WebSocket command run through the REST API.
+type CommandResult struct {
+	// RequestID identifies the accesses created, or the access request submitted.
+	RequestID string `json:"requestID,omitempty" example:"0b7f8a4e-2f4c-4c1e-9f0a-6f1d2b3c4d5e"`
+	// Name is the AccessRequest submitted, or its request ID when it was approved at once by a pre-approval.
+	Name        string `json:"name,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	// Status is the state of a submitted request: "PendingFull", "PendingSource", "PendingTarget", or "Approved".
+	Status string `json:"status,omitempty"`
+	// Accesses are the namespace/name of the Access or ExternalAccess objects created.
+	Accesses []string `json:"accesses,omitempty" example:"frontend/access-nc-1a2b3c4d5e6f-30623766"`
+	// Revoked are the namespace/name of the Access or ExternalAccess objects deleted by a revocation.
+	Revoked []string `json:"revoked,omitempty" example:"frontend/access-nc-1a2b3c4d5e6f-30623766"`
+	// Clones are the namespace/name of the service clones the controller removes with the revoked objects.
+	Clones []string `json:"clones,omitempty" example:"frontend/nc-1a2b3c4d5e6f-30623766"`
+	// Messages are the activity log entries produced while running the command.
+	Messages []LogEntry `json:"messages"`
+}
+
+// commandRunner runs WebSocket commands for a REST caller. What they would broadcast or send privately is collected
+// in the result instead, broadcasts are still written to the activity log.
+type commandRunner struct {
+	processor *webSocketCommandProcessor
+	result    CommandResult
+	failed    bool
+}
+
+// newCommandRunner authenticates a REST caller. It answers 401 itself and returns nil when it cannot.
+// channel records where submissions come from.
+func newCommandRunner(c *gin.Context, channel string) *commandRunner {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return nil
+	}
+
+	r := &commandRunner{result: CommandResult{Messages: []LogEntry{}}}
+	collect := func(entry LogEntry) { r.result.Messages = append(r.result.Messages, entry) }
+	r.processor = &webSocketCommandProcessor{
+		ctx:               ctx,
+		idToken:           idToken,
+		userInfo:          userInfo,
+		sanitizedUsername: rememberUsername(ctx, userInfo),
+		logAndBroadcast: func(entry LogEntry) {
+			entry.User = userInfo.Email
+			collect(persistLogEntry(entry))
+		},
+		sendPrivate: collect,
+		sendError: func(msg string, err error, logType string) {
+			r.failed = true
+			if err != nil {
+				msg = fmt.Sprintf("%s - %s", msg, err.Error())
+			}
+			logger.Logger.Warn("REST command failed", "user", userInfo.Email, "reason", msg)
+			collect(persistLogEntry(LogEntry{
+				Payload: "REQUEST FAILED: " + msg, ClassName: "log-error", LogType: logType, Type: "applyResult", User: userInfo.Email,
+			}))
+		},
+		channel: channel,
+		onSubmitted: func(request *netwatchv1alpha1.AccessRequest, approved bool) {
+			r.result.RequestID, r.result.Name, r.result.DisplayName = request.Spec.RequestID, request.Name, requestDisplayName(request)
+			r.result.Status = request.Spec.Status
+			// A partial request already created the side its requestor may create.
+			if clone := request.Spec.SourceCloneName; clone != "" {
+				r.result.Accesses = []string{requestNamespaces(request.Spec)[0] + "/access-" + clone}
+			}
+			if clone := request.Spec.TargetCloneName; clone != "" {
+				r.result.Accesses = []string{requestNamespaces(request.Spec)[1] + "/access-" + clone}
+			}
+			if approved {
+				// Approved by a pre-approval: the request was never stored.
+				r.result.Name, r.result.Status = request.Spec.RequestID, "Approved"
+			}
+		},
+		onCreated: func(requestID string, accesses []string) {
+			r.result.RequestID, r.result.Accesses = requestID, accesses
+		},
+		onRevoked: func(requestID string, accesses, clones []string) {
+			r.result.RequestID, r.result.Revoked, r.result.Clones = requestID, accesses, clones
+		},
+	}
+	return r
+}
+
+// run dispatches a command like the WebSocket does and answers with its result: successStatus when it went
+// through, 422 otherwise. A command can fail after doing part of its work, e.g. an access created without
+// heartbeats, the result then tells what was done.
+func (r *commandRunner) run(c *gin.Context, successStatus int, payload webSocketPayload) {
+	result, ok := r.dispatch(payload)
+	if !ok {
+		c.JSON(http.StatusUnprocessableEntity, result)
+		return
+	}
+	c.JSON(successStatus, result)
+}
+
+// dispatch runs a command and returns its result and whether it went through. The runner is then ready for the
+// next command.
+func (r *commandRunner) dispatch(payload webSocketPayload) (CommandResult, bool) {
+	r.processor.dispatch(payload)
+	result, ok := r.result, !r.failed && (payload.Command != "submitAccessRequest" || r.result.Status != "")
+	r.result, r.failed = CommandResult{Messages: []LogEntry{}}, false
+	return result, ok
+}
+
+// CreateAccessInput describes a cluster access to create directly, without review.
+type CreateAccessInput struct {
+	SourceService string `json:"sourceService" binding:"required" example:"frontend/web"`
+	TargetService string `json:"targetService" binding:"required" example:"backend/api"`
+	// Direction is ingress, egress or all, all when empty.
+	Direction string `json:"direction,omitempty" example:"all"`
+	// Ports overrides the ports of the services, comma-separated.
+	Ports string `json:"ports,omitempty" example:"8080,9090"`
+	// Duration is in seconds, 0 for an access that does not expire when allowed.
+	Duration int64 `json:"duration" example:"3600"`
+	// Heartbeat binds the access to heartbeats, the token is in the messages. See SendHeartbeat.
+	Heartbeat bool `json:"heartbeat,omitempty"`
+	// Description says why the access is needed, see NETWATCH_MIN_DESCRIPTION_LENGTH.
+	Description string `json:"description,omitempty" example:"Debugging the checkout flow"`
+}
+
+// CreateExternalAccessInput describes an external access to create directly, without review.
+type CreateExternalAccessInput struct {
+	Service string `json:"service" binding:"required" example:"backend/api"`
+	// Cidr is the external IP or CIDR allowed.
+	Cidr      string `json:"cidr" binding:"required" example:"203.0.113.0/24"`
+	Direction string `json:"direction,omitempty" example:"ingress"`
+	Ports     string `json:"ports,omitempty" example:"443"`
+	Duration  int64  `json:"duration" example:"3600"`
+	Heartbeat bool   `json:"heartbeat,omitempty"`
+	// Description says why the access is needed, see NETWATCH_MIN_DESCRIPTION_LENGTH.
+	Description string `json:"description,omitempty" example:"Partner API tests"`
+}
+
+// SubmitAccessRequestInput describes an access request to submit for review. Set sourceService and targetService
+// for a service-to-service request, or service and cidr for an external one.
+type SubmitAccessRequestInput struct {
+	SourceService string            `json:"sourceService,omitempty" example:"frontend/web"`
+	TargetService string            `json:"targetService,omitempty" example:"backend/api"`
+	Service       string            `json:"service,omitempty"`
+	Cidr          string            `json:"cidr,omitempty"`
+	Direction     string            `json:"direction,omitempty" example:"all"`
+	Ports         string            `json:"ports,omitempty"`
+	Duration      int64             `json:"duration" example:"3600"`
+	Description   string            `json:"description,omitempty" example:"Debugging the checkout flow"`
+	Labels        map[string]string `json:"labels,omitempty"`
+	// OnBehalfOf files the request for another user, see the file-on-behalf verb.
+	OnBehalfOf string `json:"onBehalfOf,omitempty"`
+	// TicketRef is the Jira issue or ServiceNow record tracking the request.
+	TicketRef string `json:"ticketRef,omitempty" example:"NET-123"`
+	// Priority is low, normal or urgent, normal when empty.
+	Priority string `json:"priority,omitempty" example:"urgent"`
+	// PreviousRequestID is the denied or expired request this one resubmits, by RequestID or name. It must be for the
+	// same requestor, source and target.
+	PreviousRequestID string `json:"previousRequestID,omitempty"`
+}
+
+// CreateClusterAccess creates a service-to-service access without going through the WebSocket.
+// CreateClusterAccess godoc
+// @Summary      Create a cluster access
+// @Description  Creates the service clones and Access objects between two services directly, like the requestClusterAccess WebSocket command: the caller needs to create services and accesses in both namespaces. Users without these permissions must submit an access request instead.
+// @Tags         Access Policies
+// @Accept       json
+// @Produce      json
+// @Param        access           body    handlers.CreateAccessInput  true   "Access to create"
+// @Param        Idempotency-Key  header  string                      false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /accesses [post]
+func CreateClusterAccess(c *gin.Context) {
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input CreateAccessInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	runner.run(c, http.StatusCreated, webSocketPayload{
+		Command:       "requestClusterAccess",
+		SourceService: input.SourceService,
+		TargetService: input.TargetService,
+		Direction:     input.Direction,
+		Ports:         input.Ports,
+		Duration:      input.Duration,
+		Heartbeat:     input.Heartbeat,
+		Description:   input.Description,
+	})
+}
+
+// CreateExternalAccess creates an access between a service and an external CIDR without going through the WebSocket.
+// CreateExternalAccess godoc
+// @Summary      Create an external access
+// @Description  Creates the service clone and ExternalAccess object directly, like the requestExternalAccess WebSocket command: the caller needs to create services and externalaccesses in the namespace.
+// @Tags         Access Policies
+// @Accept       json
+// @Produce      json
+// @Param        access           body    handlers.CreateExternalAccessInput  true   "External access to create"
+// @Param        Idempotency-Key  header  string                              false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /external-accesses [post]
+func CreateExternalAccess(c *gin.Context) {
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input CreateExternalAccessInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	runner.run(c, http.StatusCreated, webSocketPayload{
+		Command:     "requestExternalAccess",
+		Service:     input.Service,
+		Cidr:        input.Cidr,
+		Direction:   input.Direction,
+		Ports:       input.Ports,
+		Duration:    input.Duration,
+		Heartbeat:   input.Heartbeat,
+		Description: input.Description,
+	})
+}
+
+// RevokeClusterAccess deletes an Access and the other half of its pair.
+// RevokeClusterAccess godoc
+// @Summary      Revoke a cluster access
+// @Description  Deletes the Access and every Access sharing its request ID, like the revokeClusterAccess WebSocket command. The result lists the Access objects deleted and the service clones the controller removes with them. When some deletions fail, the objects that were deleted are still listed.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace  path      string  true  "Namespace of the Access"
+// @Param        name       path      string  true  "Name of the Access"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /accesses/{namespace}/{name} [delete]
+func RevokeClusterAccess(c *gin.Context) {
+	if runner := newCommandRunner(c, "api"); runner != nil {
+		runner.run(c, http.StatusOK, webSocketPayload{Command: "revokeClusterAccess", Namespace: c.Param("namespace"), Name: c.Param("name")})
+	}
+}
+
+// RevokeExternalAccess deletes an ExternalAccess.
+// RevokeExternalAccess godoc
+// @Summary      Revoke an external access
+// @Description  Deletes the ExternalAccess, like the revokeExternalAccess WebSocket command. The result lists it and the service clone the controller removes with it.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace  path      string  true  "Namespace of the ExternalAccess"
+// @Param        name       path      string  true  "Name of the ExternalAccess"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /external-accesses/{namespace}/{name} [delete]
+func RevokeExternalAccess(c *gin.Context) {
+	if runner := newCommandRunner(c, "api"); runner != nil {
+		runner.run(c, http.StatusOK, webSocketPayload{Command: "revokeExternalAccess", Namespace: c.Param("namespace"), Name: c.Param("name")})
+	}
+}
+
+// PauseAccess pauses an Access pair.
+// PauseAccess godoc
+// @Summary      Pause a cluster access
+// @Description  Deletes the Access pair while keeping its service clones and remaining duration, like the pauseAccess WebSocket command. Resume it with its request ID.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace        path    string  true   "Namespace of the Access"
+// @Param        name             path    string  true   "Name of the Access"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /accesses/{namespace}/{name}/pause [post]
+func PauseAccess(c *gin.Context) {
+	if runner := newCommandRunner(c, "api"); runner != nil {
+		runner.run(c, http.StatusOK, webSocketPayload{Command: "pauseAccess", Namespace: c.Param("namespace"), Name: c.Param("name")})
+	}
+}
+
+// PauseExternalAccess pauses an ExternalAccess.
+// PauseExternalAccess godoc
+// @Summary      Pause an external access
+// @Description  Deletes the ExternalAccess while keeping its service clone and remaining duration, like the pauseAccess WebSocket command. Resume it with its request ID.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace        path    string  true   "Namespace of the ExternalAccess"
+// @Param        name             path    string  true   "Name of the ExternalAccess"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /external-accesses/{namespace}/{name}/pause [post]
+func PauseExternalAccess(c *gin.Context) {
+	if runner := newCommandRunner(c, "api"); runner != nil {
+		runner.run(c, http.StatusOK, webSocketPayload{
+			Command: "pauseAccess", Namespace: c.Param("namespace"), Name: c.Param("name"), AccessType: "External",
+		})
+	}
+}
+
+// ResumeAccess recreates a paused access.
+// ResumeAccess godoc
+// @Summary      Resume a paused access
+// @Description  Recreates a paused Access pair or ExternalAccess with the duration it had left, like the resumeAccess WebSocket command.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        id               path    string  true   "Request ID of the paused access"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /paused-accesses/{id}/resume [post]
+func ResumeAccess(c *gin.Context) {
+	if runner := newCommandRunner(c, "api"); runner != nil {
+		runner.run(c, http.StatusOK, webSocketPayload{Command: "resumeAccess", RequestID: c.Param("id")})
+	}
+}
+
+// SubmitAccessRequest submits an access request for review without going through the WebSocket.
+// SubmitAccessRequest godoc
+// @Summary      Submit an access request
+// @Description  Submits an access request for review, like the submitAccessRequest WebSocket command, without a browser session. When the caller may create one side of a service-to-service access, that side is created at once and listed in accesses, and the request (status PendingSource or PendingTarget) waits for the approval of the other side only. Pre-approvals apply.
+// @Tags         Requests
+// @Accept       json
+// @Produce      json
+// @Param        request          body    handlers.SubmitAccessRequestInput  true   "Access request to submit"
+// @Param        Idempotency-Key  header  string                             false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /access-requests [post]
+func SubmitAccessRequest(c *gin.Context) {
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input SubmitAccessRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if (input.TargetService == "") == (input.Service == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set either sourceService and targetService, or service and cidr"})
+		return
+	}
+	runner.run(c, http.StatusCreated, webSocketPayload{
+		Command:           "submitAccessRequest",
+		SourceService:     input.SourceService,
+		TargetService:     input.TargetService,
+		Service:           input.Service,
+		Cidr:              input.Cidr,
+		Direction:         input.Direction,
+		Ports:             input.Ports,
+		Duration:          input.Duration,
+		Description:       input.Description,
+		Labels:            input.Labels,
+		OnBehalfOf:        input.OnBehalfOf,
+		TicketRef:         input.TicketRef,
+		Priority:          input.Priority,
+		PreviousRequestID: input.PreviousRequestID,
+	})
+}
+
+// requireReviewer rejects the static API key on approvals and denials: a decision must be made by an identified user.
+func requireReviewer(c *gin.Context) bool {
+	if c.GetString("user") == "api-key-user" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Reviewing requests requires a user identity, authenticate with a Bearer token"})
+		return false
+	}
+	return true
+}
+
+// ApproveAccessRequestInput is the optional body of an approval.
+type ApproveAccessRequestInput struct {
+	// Comment is kept in the activity log and on the accesses created, for audit.
+	Comment string `json:"comment,omitempty" example:"Approved for the checkout incident"`
+	// Direction, Ports and Duration narrow the request before it is approved: one direction instead of all
+	// traffic, some of its ports, or a shorter duration in seconds. Empty fields keep the request as is.
+	Direction string `json:"direction,omitempty" example:"ingress"`
+	Ports     string `json:"ports,omitempty" example:"443"`
+	Duration  int64  `json:"duration,omitempty" example:"1800"`
+}
+
+// ApproveAccessRequest approves a pending access request.
+// ApproveAccessRequest godoc
+// @Summary      Approve an access request
+// @Description  Approves a pending access request, or the side of a partial request still waiting, like the approveAccessRequest WebSocket command. The approver is the user of the Bearer token, e.g. a ChatOps bot's service account or the person it acts for, and needs the permissions to create the accesses. The static API key is rejected. The body is optional.
+// @Tags         Requests
+// @Accept       json
+// @Produce      json
+// @Param        id               path    string  true   "AccessRequest name"
+// @Param        approval         body    handlers.ApproveAccessRequestInput  false  "Approval comment"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id}/approve [post]
+func ApproveAccessRequest(c *gin.Context) {
+	if !requireReviewer(c) {
+		return
+	}
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input ApproveAccessRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	runner.run(c, http.StatusOK, webSocketPayload{
+		Command:   "approveAccessRequest",
+		RequestID: c.Param("id"),
+		Comment:   input.Comment,
+		Direction: input.Direction,
+		Ports:     input.Ports,
+		Duration:  input.Duration,
+	})
+}
+
+// UpdateAccessRequestInput is the body of an update of a pending request. Empty fields keep the request as is.
+type UpdateAccessRequestInput struct {
+	Ports       string `json:"ports,omitempty" example:"443,8443"`
+	Duration    int64  `json:"duration,omitempty" example:"7200"`
+	Description string `json:"description,omitempty" example:"Debugging the checkout flow, TLS ports too"`
+}
+
+// UpdateAccessRequest changes the user's own pending access request.
+// UpdateAccessRequest godoc
+// @Summary      Update a pending access request
+// @Description  Changes the ports, duration or description of a PendingFull access request of the user, until someone approves it, like the updateAccessRequest WebSocket command. Unlike an approver's changes, the requestor's may widen the request.
+// @Tags         Requests
+// @Accept       json
+// @Produce      json
+// @Param        id               path    string  true   "AccessRequest name"
+// @Param        update           body    handlers.UpdateAccessRequestInput  true  "Changes"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id} [patch]
+func UpdateAccessRequest(c *gin.Context) {
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input UpdateAccessRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if _, err := getOverridePorts(input.Ports); err != nil || input.Duration < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ports or duration"})
+		return
+	}
+	runner.run(c, http.StatusOK, webSocketPayload{
+		Command:     "updateAccessRequest",
+		RequestID:   c.Param("id"),
+		Ports:       input.Ports,
+		Duration:    input.Duration,
+		Description: input.Description,
+	})
+}
+
+// DenyAccessRequestInput is the body of a denial, optional unless NETWATCH_REQUIRE_DENIAL_REASON is set.
+type DenyAccessRequestInput struct {
+	// Reason is told to the requestor in the activity log and kept in the request timing.
+	Reason string `json:"reason,omitempty" example:"Use the staging database instead"`
+}
+
+// DenyAccessRequest denies a pending access request, or aborts it when called by its requestor.
+// DenyAccessRequest godoc
+// @Summary      Deny or abort an access request
+// @Description  Denies a pending access request, or aborts it when called by its requestor or whoever filed it, like the denyAccessRequest WebSocket command. The reviewer is the user of the Bearer token and needs delete on the AccessRequest. The static API key is rejected. The reason is told to the requestor in the activity log, and denials without one are refused when NETWATCH_REQUIRE_DENIAL_REASON is set.
+// @Tags         Requests
+// @Accept       json
+// @Produce      json
+// @Param        id               path    string  true   "AccessRequest name"
+// @Param        denial           body    handlers.DenyAccessRequestInput  false  "Denial reason"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id}/deny [post]
+func DenyAccessRequest(c *gin.Context) {
+	if !requireReviewer(c) {
+		return
+	}
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input DenyAccessRequestInput
+	if err := c.ShouldBindJSON(&input); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	runner.run(c, http.StatusOK, webSocketPayload{Command: "denyAccessRequest", RequestID: c.Param("id"), Reason: input.Reason})
+}
@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// decisionLinkUsedPrefix marks the decision links already used, until they expire.
+const decisionLinkUsedPrefix = "netwatch:decision_link_used:"
+
+// DecisionLinkConfig enables the signed approve and deny links of submitted requests, to embed in notifications.
+type DecisionLinkConfig struct {
+	// Secret is the HMAC-SHA256 key signing the links.
+	Secret string
+	// BaseURL is the public URL of Netwatch the links point to.
+	BaseURL string
+	// TTL is how long a link stays valid.
+	TTL time.Duration
+}
+
+var decisionLinks DecisionLinkConfig
+
+// SetDecisionLinks configures the signed decision links. Links are disabled without a secret.
+func SetDecisionLinks(cfg DecisionLinkConfig) {
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	decisionLinks = cfg
+}
+
+// DecisionLinks are the signed links approving or denying a request in one click, once its approver is logged in.
+type DecisionLinks struct {
+	Approve string `json:"approve"`
+	Deny    string `json:"deny"`
+	// ExpiresAt is when the links stop working, as a Unix timestamp.
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// decisionClaims are signed into a decision link.
+type decisionClaims struct {
+	Request  string `json:"r"`
+	Decision string `json:"d"`
+	Expires  int64  `json:"e"`
+	Nonce    string `json:"n"`
+}
+
+// newDecisionLinks signs the approve and deny links of a request, nil when links are disabled.
+func newDecisionLinks(request *netwatchv1alpha1.AccessRequest) *DecisionLinks {
+	if decisionLinks.Secret == "" {
+		return nil
+	}
+	expires := time.Now().Add(decisionLinks.TTL).Unix()
+	links := &DecisionLinks{ExpiresAt: expires}
+	for decision, link := range map[string]*string{"approve": &links.Approve, "deny": &links.Deny} {
+		nonce := make([]byte, 16)
+		rand.Read(nonce) //nolint:all
+		token, err := signDecision(decisionClaims{Request: request.Name, Decision: decision, Expires: expires, Nonce: hex.EncodeToString(nonce)})
+		if err != nil {
+			logger.Logger.Error("Failed to sign a decision link", "error", err, "request", request.Name)
+			return nil
+		}
+		*link = decisionLinks.BaseURL + "/decisions/" + token
+	}
+	return links
+}
+
+func signDecision(claims decisionClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + decisionSignature(encoded), nil
+}
+
+func decisionSignature(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(decisionLinks.Secret))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// verifyDecision checks the signature and expiry of a decision link token and returns its claims.
+func verifyDecision(token string) (*decisionClaims, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || decisionLinks.Secret == "" || !hmac.Equal([]byte(signature), []byte(decisionSignature(encoded))) {
+		return nil, errors.New("this link is invalid")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.New("this link is invalid")
+	}
+	var claims decisionClaims
+	if err := json.Unmarshal(payload, &claims); err != nil || (claims.Decision != "approve" && claims.Decision != "deny") {
+		return nil, errors.New("this link is invalid")
+	}
+	if time.Now().Unix() >= claims.Expires {
+		return nil, errors.New("this link has expired")
+	}
+	return &claims, nil
+}
+
+// HandleDecisionLink serves a signed decision link. GET shows the request and asks for confirmation, so that mail
+// scanners opening links decide nothing. POST takes the decision as the logged-in user, through the same checks as
+// the UI. A link works once.
+func HandleDecisionLink(c *gin.Context) {
+	ctx := c.Request.Context()
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+
+	claims, err := verifyDecision(c.Param("token"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Sorry, %s.", err)
+		return
+	}
+	if _, err := getUserIdToken(c); err != nil {
+		c.String(http.StatusUnauthorized, "Please log in to Netwatch first, then open this link again.")
+		return
+	}
+	detail, _, err := loadRequestDetail(ctx, claims.Request)
+	if err != nil || detail.Request == nil {
+		c.String(http.StatusNotFound, "This request has already been approved or denied.")
+		return
+	}
+	page := gin.H{"Decision": claims.Decision, "Detail": detail}
+	if c.Request.Method != http.MethodPost {
+		c.HTML(http.StatusOK, "decision.html", page)
+		return
+	}
+
+	runner := newCommandRunner(c, "link")
+	if runner == nil {
+		return
+	}
+	usedKey := decisionLinkUsedPrefix + claims.Nonce
+	first, err := redisClient.SetNX(ctx, usedKey, runner.processor.userInfo.Email, time.Until(time.Unix(claims.Expires, 0))).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to claim a decision link", "error", err)
+		c.String(http.StatusInternalServerError, "Could not take the decision, please retry.")
+		return
+	}
+	if !first {
+		c.String(http.StatusConflict, "This link has already been used.")
+		return
+	}
+
+	comment := strings.TrimSpace(c.PostForm("comment"))
+	payload := webSocketPayload{Command: "approveAccessRequest", RequestID: claims.Request, Comment: comment}
+	if claims.Decision == "deny" {
+		payload = webSocketPayload{Command: "denyAccessRequest", RequestID: claims.Request, Reason: comment}
+	}
+	logger.Logger.Info("Decision link used", "request", claims.Request, "decision", claims.Decision, "user", runner.processor.userInfo.Email)
+	result, ok := runner.dispatch(payload)
+	if !ok {
+		// A failed decision leaves the link usable, e.g. by another approver.
+		redisClient.Del(ctx, usedKey) //nolint:all
+	}
+	page["Done"], page["Messages"] = ok, result.Messages
+	status := http.StatusOK
+	if !ok {
+		status = http.StatusUnprocessableEntity
+	}
+	c.HTML(status, "decision.html", page)
+}
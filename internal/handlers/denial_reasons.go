@@ -0,0 +1,28 @@
+package handlers
+
+import "fmt"
+
+var denialReasonRequired bool
+
+// SetDenialReasonRequired refuses denials that don't say why. Requestors can still abort their own requests
+// without a reason.
+func SetDenialReasonRequired(required bool) {
+	denialReasonRequired = required
+}
+
+// missingDenialReason reports, and rejects, a denial without the reason it requires.
+func (p *webSocketCommandProcessor) missingDenialReason(reason string) bool {
+	if !denialReasonRequired || reason != "" {
+		return false
+	}
+	p.sendError("A reason is required to deny a request", nil, "Request")
+	return true
+}
+
+// reasonSuffix quotes the reason of a decision at the end of an activity log entry.
+func reasonSuffix(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	return fmt.Sprintf(" Reason: %q", reason)
+}
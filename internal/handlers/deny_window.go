@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	denialsKeyPrefix  = "netwatch:denials:"
+	denyHoldKeyPrefix = "netwatch:deny_hold:"
+)
+
+// DenyWindowConfig holds submissions of a user for a target after repeated denials.
+type DenyWindowConfig struct {
+	// MaxDenials denials within Window start a hold of Cooldown. Zero disables holds.
+	MaxDenials int
+	Window     time.Duration
+	Cooldown   time.Duration
+}
+
+var denyWindow = DenyWindowConfig{MaxDenials: 3, Window: 24 * time.Hour, Cooldown: 24 * time.Hour}
+
+// SetDenyWindow configures when repeatedly denied requests are held.
+func SetDenyWindow(cfg DenyWindowConfig) {
+	denyWindow = cfg
+}
+
+// denyWindowTarget is what a request asks access to: the target service, or the exposed service of External requests.
+func denyWindowTarget(spec netwatchv1alpha1.AccessRequestSpec) string {
+	if spec.TargetService != "" {
+		return spec.TargetService
+	}
+	return spec.Service
+}
+
+// denyWindowKey identifies the requests of a user for a target.
+func denyWindowKey(requestor, target string) string {
+	sum := sha256.Sum256([]byte(requestor + "\x00" + target))
+	return hex.EncodeToString(sum[:16])
+}
+
+// recordDenial counts a denial and holds further submissions of the requestor for the target once there are too many.
+func recordDenial(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) {
+	if denyWindow.MaxDenials <= 0 {
+		return
+	}
+	target := denyWindowTarget(spec)
+	key := denyWindowKey(spec.Requestor, target)
+	now := time.Now()
+
+	pipe := redisClient.TxPipeline()
+	pipe.ZAdd(ctx, denialsKeyPrefix+key, redis.Z{Score: float64(now.UnixMilli()), Member: strconv.FormatInt(now.UnixNano(), 10)})
+	pipe.ZRemRangeByScore(ctx, denialsKeyPrefix+key, "-inf", "("+strconv.FormatInt(now.Add(-denyWindow.Window).UnixMilli(), 10))
+	pipe.Expire(ctx, denialsKeyPrefix+key, denyWindow.Window)
+	denials := pipe.ZCard(ctx, denialsKeyPrefix+key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Warn("Failed to record request denial", "requestor", spec.Requestor, "target", target, "error", err)
+		return
+	}
+	if denials.Val() < int64(denyWindow.MaxDenials) {
+		return
+	}
+
+	until := now.Add(denyWindow.Cooldown)
+	pipe = redisClient.TxPipeline()
+	pipe.Set(ctx, denyHoldKeyPrefix+key, until.Unix(), denyWindow.Cooldown)
+	pipe.Del(ctx, denialsKeyPrefix+key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Warn("Failed to hold denied requests", "requestor", spec.Requestor, "target", target, "error", err)
+		return
+	}
+	persistLogEntry(LogEntry{
+		Payload: fmt.Sprintf("DENY WINDOW: %s had %d requests for %s denied within %s. New requests for it are held until %s.",
+			spec.Requestor, denials.Val(), target, denyWindow.Window, until.UTC().Format(time.RFC3339)),
+		ClassName: "log-warning", LogType: "Request", Type: "applyResult",
+	})
+}
+
+// deniedRequestsHold returns until when submissions of the requestor for the target are held, if they are.
+func deniedRequestsHold(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) (time.Time, bool) {
+	until, err := redisClient.Get(ctx, denyHoldKeyPrefix+denyWindowKey(spec.Requestor, denyWindowTarget(spec))).Int64()
+	if err != nil {
+		if err != redis.Nil {
+			logger.Logger.Warn("Failed to check the deny window", "requestor", spec.Requestor, "error", err)
+		}
+		return time.Time{}, false
+	}
+	return time.Unix(until, 0), true
+}
+
+// heldByDenyWindow tells the user their submission is held after repeated denials.
+func (p *webSocketCommandProcessor) heldByDenyWindow(spec netwatchv1alpha1.AccessRequestSpec) bool {
+	until, held := deniedRequestsHold(p.ctx, spec)
+	if !held {
+		return false
+	}
+	p.sendError("Request held",
+		fmt.Errorf("your requests for %s were denied repeatedly, new ones are held until %s", denyWindowTarget(spec), until.UTC().Format(time.RFC3339)),
+		"Request")
+	return true
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import "fmt"
+
+// Directions understood by maxtac on Access and ExternalAccess objects.
+const (
+	directionIngress = "ingress"
+	directionEgress  = "egress"
+	directionAll     = "all"
+)
+
+// normalizeDirection maps a requested direction to the value maxtac enforces. An empty direction and "both"
+// mean all traffic. Anything else is refused rather than silently widened to all traffic.
+func normalizeDirection(direction string) (string, error) {
+	switch direction {
+	case directionIngress, directionEgress:
+		return direction, nil
+	case directionAll, "both", "":
+		return directionAll, nil
+	default:
+		return "", fmt.Errorf("unknown direction %q, expected 'ingress', 'egress' or 'all'", direction)
+	}
+}
+
+// sideDirection returns the direction of the Access created on one side of a service-to-service request, for a
+// normalized request direction. The source side gets the requested direction and the target side its mirror:
+// for "egress", the source may send to the target and the target may receive from the source.
+func sideDirection(direction string, isSource bool) string {
+	switch {
+	case direction == directionAll:
+		return directionAll
+	case isSource:
+		return direction
+	case direction == directionIngress:
+		return directionEgress
+	default:
+		return directionIngress
+	}
+}
+
+// invalidDirection reports a direction maxtac can't enforce to the user, and normalizes a valid one in place.
+func (p *webSocketCommandProcessor) invalidDirection(direction *string, logType string) bool {
+	normalized, err := normalizeDirection(*direction)
+	if err != nil {
+		p.sendError("Invalid direction", err, logType)
+		return true
+	}
+	*direction = normalized
+	return false
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import "testing"
+
+func TestNormalizeDirection(t *testing.T) {
+	tests := []struct {
+		direction string
+		want      string
+		wantErr   bool
+	}{
+		{"ingress", directionIngress, false},
+		{"egress", directionEgress, false},
+		{"all", directionAll, false},
+		{"both", directionAll, false},
+		{"", directionAll, false},
+		{"Ingress", "", true},
+		{"EGRESS", "", true},
+		{" all", "", true},
+		{"inbound", "", true},
+		{"ingress,egress", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.direction, func(t *testing.T) {
+			got, err := normalizeDirection(tt.direction)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeDirection(%q) error = %v, wantErr %v", tt.direction, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("normalizeDirection(%q) = %q, want %q", tt.direction, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSideDirection(t *testing.T) {
+	tests := []struct {
+		direction string
+		isSource  bool
+		want      string
+	}{
+		{directionIngress, true, directionIngress},
+		{directionIngress, false, directionEgress},
+		{directionEgress, true, directionEgress},
+		{directionEgress, false, directionIngress},
+		{directionAll, true, directionAll},
+		{directionAll, false, directionAll},
+	}
+	for _, tt := range tests {
+		side := "target"
+		if tt.isSource {
+			side = "source"
+		}
+		t.Run(tt.direction+"/"+side, func(t *testing.T) {
+			if got := sideDirection(tt.direction, tt.isSource); got != tt.want {
+				t.Fatalf("sideDirection(%q, %v) = %q, want %q", tt.direction, tt.isSource, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSideDirectionsMirror checks that, for every accepted direction, the two sides of a service request agree:
+// whatever the source may send, the target may receive.
+func TestSideDirectionsMirror(t *testing.T) {
+	mirror := map[string]string{directionIngress: directionEgress, directionEgress: directionIngress, directionAll: directionAll}
+	for _, requested := range []string{"ingress", "egress", "all", "both", ""} {
+		direction, err := normalizeDirection(requested)
+		if err != nil {
+			t.Fatalf("normalizeDirection(%q): %v", requested, err)
+		}
+		source, target := sideDirection(direction, true), sideDirection(direction, false)
+		if mirror[source] != target {
+			t.Errorf("direction %q: source %q and target %q don't mirror each other", requested, source, target)
+		}
+	}
+}
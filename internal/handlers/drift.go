@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// driftGracePeriod leaves maxtac time to reconcile a freshly created access before it is reported as drifting.
+const driftGracePeriod = 2 * time.Minute
+
+// EnforcementDrift describes an access whose downstream enforcement objects do not match what maxtac should have created.
+type EnforcementDrift struct {
+	Type      string   `json:"type"`
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace"`
+	RequestID string   `json:"requestID,omitempty"`
+	Problems  []string `json:"problems"`
+	CheckedAt int64    `json:"checkedAt"`
+}
+
+var (
+	driftMu    sync.RWMutex
+	driftState = make(map[string]EnforcementDrift)
+
+	driftGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "netwatch_enforcement_drift",
+		Help: "Set to 1 for every access whose NetworkPolicies or service clones are missing.",
+	}, []string{"type", "namespace", "name"})
+	driftCheckTimestamp = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "netwatch_enforcement_drift_last_check_timestamp_seconds",
+		Help: "Unix time of the last completed enforcement drift check.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(driftGauge, driftCheckTimestamp)
+}
+
+func driftKey(accessType, namespace, name string) string {
+	return accessType + "/" + namespace + "/" + name
+}
+
+// DriftCount returns the number of accesses currently reported as drifting.
+func DriftCount() int {
+	driftMu.RLock()
+	defer driftMu.RUnlock()
+	return len(driftState)
+}
+
+func lookupDrift(accessType, namespace, name string) []string {
+	driftMu.RLock()
+	defer driftMu.RUnlock()
+	return driftState[driftKey(accessType, namespace, name)].Problems
+}
+
+// StartDriftDetector periodically verifies that every netwatch Access and ExternalAccess is backed by the
+// NetworkPolicies listed in its status and by its service clones.
+func StartDriftDetector(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting enforcement drift detector", "interval", interval)
+
+	for {
+		select {
+		case <-ticker.C:
+			checkEnforcementDrift(ctx)
+		case <-ctx.Done():
+			logger.Logger.Info("Stopping enforcement drift detector.")
+			return
+		}
+	}
+}
+
+func checkEnforcementDrift(ctx context.Context) {
+	var accessList vtkiov1alpha1.AccessList
+	if err := k8s.ListNetwatchAccesses(ctx, &accessList); err != nil {
+		logger.Logger.Error("Drift detector failed to list accesses", "error", err)
+		return
+	}
+	var extList vtkiov1alpha1.ExternalAccessList
+	if err := k8s.ListNetwatchExternalAccesses(ctx, &extList); err != nil {
+		logger.Logger.Error("Drift detector failed to list external accesses", "error", err)
+		return
+	}
+
+	now := time.Now()
+	found := make(map[string]EnforcementDrift)
+	record := func(accessType, namespace, name, reqID string, problems []string) {
+		if len(problems) == 0 {
+			return
+		}
+		found[driftKey(accessType, namespace, name)] = EnforcementDrift{
+			Type: accessType, Name: name, Namespace: namespace, RequestID: reqID, Problems: problems, CheckedAt: now.Unix(),
+		}
+	}
+
+	for _, access := range accessList.Items {
+		if access.DeletionTimestamp != nil || now.Sub(access.CreationTimestamp.Time) < driftGracePeriod {
+			continue
+		}
+		record("Service", access.Namespace, access.Name, access.Labels["netwatch.vtk.io/request-id"],
+			enforcementProblems(ctx, access.Status.Netpols, access.Status.Services))
+	}
+	for _, access := range extList.Items {
+		if access.DeletionTimestamp != nil || now.Sub(access.CreationTimestamp.Time) < driftGracePeriod {
+			continue
+		}
+		record("External", access.Namespace, access.Name, access.Labels["netwatch.vtk.io/request-id"],
+			enforcementProblems(ctx, access.Status.Netpols, access.Status.Services))
+	}
+
+	driftMu.Lock()
+	driftState = found
+	driftMu.Unlock()
+
+	driftGauge.Reset()
+	for _, drift := range found {
+		driftGauge.WithLabelValues(drift.Type, drift.Namespace, drift.Name).Set(1)
+		logger.Logger.Warn("Enforcement drift detected", "type", drift.Type, "namespace", drift.Namespace, "name", drift.Name, "problems", drift.Problems)
+	}
+	driftCheckTimestamp.Set(float64(now.Unix()))
+}
+
+// enforcementProblems lists what is missing downstream of an access, based on the objects maxtac reports in its status.
+func enforcementProblems(ctx context.Context, netpols []vtkiov1alpha1.Netpol, services []vtkiov1alpha1.SvcRef) []string {
+	var problems []string
+	if len(netpols) == 0 {
+		problems = append(problems, "no NetworkPolicy reported in status")
+	}
+	for _, np := range netpols {
+		if _, err := k8s.GetNetworkPolicyAsApp(ctx, np.Namespace, np.Name); err != nil {
+			if k8s.IsNotFound(err) {
+				problems = append(problems, fmt.Sprintf("NetworkPolicy %s/%s is missing", np.Namespace, np.Name))
+			} else {
+				logger.Logger.Warn("Drift detector could not get NetworkPolicy", "error", err, "namespace", np.Namespace, "name", np.Name)
+			}
+		}
+	}
+	for _, svc := range services {
+		if _, err := k8s.GetServiceAsApp(ctx, svc.Namespace, svc.Name); err != nil && k8s.IsNotFound(err) {
+			problems = append(problems, fmt.Sprintf("Service %s/%s is missing", svc.Namespace, svc.Name))
+		}
+	}
+	return problems
+}
+
+// GetEnforcementDrift returns the accesses found drifting by the last check.
+// GetEnforcementDrift godoc
+// @Summary      List enforcement drift
+// @Description  Returns every Access/ExternalAccess whose NetworkPolicies or service clones were missing during the last periodic check.
+// @Tags         Access Policies
+// @Produce      json
+// @Success      200  {array}   EnforcementDrift
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /enforcement-drift [get]
+func GetEnforcementDrift(c *gin.Context) {
+	driftMu.RLock()
+	drifts := make([]EnforcementDrift, 0, len(driftState))
+	for _, drift := range driftState {
+		drifts = append(drifts, drift)
+	}
+	driftMu.RUnlock()
+
+	sort.Slice(drifts, func(i, j int) bool {
+		return driftKey(drifts[i].Type, drifts[i].Namespace, drifts[i].Name) < driftKey(drifts[j].Type, drifts[j].Namespace, drifts[j].Name)
+	})
+	c.JSON(http.StatusOK, drifts)
+}
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// requestSides are the sides of a Service request, in the order their approvals are collected.
+var requestSides = []string{"source", "target"}
+
+var dualApproval bool
+
+// SetDualApproval requires an approval from each side of the Service requests spanning the namespaces of two teams.
+func SetDualApproval(enabled bool) {
+	dualApproval = enabled
+}
+
+// needsDualApproval reports whether a submission needs an approval from each side. Its namespaces belong to
+// different teams when their owners, or their approver groups, differ. Namespaces declaring neither are a team
+// of their own.
+func needsDualApproval(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) bool {
+	if !dualApproval || spec.RequestType != "Service" {
+		return false
+	}
+	source, target := sideNamespace(spec, "source"), sideNamespace(spec, "target")
+	return source != target && !slices.Equal(namespaceTeam(ctx, source), namespaceTeam(ctx, target))
+}
+
+// setRequiredApprovals records the approvals a submission needs: the quorum of its namespaces and duration, and
+// whether each side of a Service request spanning two teams must approve it. Its type must already be set.
+func setRequiredApprovals(ctx context.Context, spec *netwatchv1alpha1.AccessRequestSpec) {
+	spec.RequiredApprovals = 0
+	if required := requiredApprovals(*spec); required > 1 {
+		spec.RequiredApprovals = required
+	}
+	spec.DualApproval = needsDualApproval(ctx, *spec)
+}
+
+// namespaceTeam identifies who reviews the requests touching a namespace.
+func namespaceTeam(ctx context.Context, ns string) []string {
+	_, owned := ownersOf(ctx, ns)
+	if _, mapped := approverGroups[ns]; !owned && !mapped {
+		return []string{"namespace:" + ns}
+	}
+	team := slices.Clone(namespaceReviewers(ctx, ns))
+	slices.Sort(team)
+	return team
+}
+
+// sideNamespace returns the namespace of the source or target side of a Service request.
+func sideNamespace(spec netwatchv1alpha1.AccessRequestSpec, side string) string {
+	service := spec.SourceService
+	if side == "target" {
+		service = spec.TargetService
+	}
+	ns, _, _ := strings.Cut(service, "/")
+	return ns
+}
+
+// approvedSides maps the sides of a DualApproval request approved so far to their approver.
+func approvedSides(spec netwatchv1alpha1.AccessRequestSpec) map[string]string {
+	if !spec.DualApproval {
+		return nil
+	}
+	sides := make(map[string]string)
+	for _, approval := range spec.ApprovedBy {
+		if _, ok := sides[approval.Side]; approval.Side != "" && !ok {
+			sides[approval.Side] = approval.Approver
+		}
+	}
+	return sides
+}
+
+// waitingSides lists the sides of a DualApproval request without an approval yet.
+func waitingSides(spec netwatchv1alpha1.AccessRequestSpec) []string {
+	approved := approvedSides(spec)
+	var waiting []string
+	for _, side := range requestSides {
+		if _, ok := approved[side]; !ok {
+			waiting = append(waiting, side)
+		}
+	}
+	return waiting
+}
+
+// canApproveSide reports whether the user reviews the namespace of a side, and may create its service clone and
+// Access.
+func (p *webSocketCommandProcessor) canApproveSide(request *netwatchv1alpha1.AccessRequest, side string) (bool, error) {
+	ns := sideNamespace(request.Spec, side)
+	if !isNamespaceReviewer(p.ctx, p.userInfo, ns) && !isEscalationApprover(p.userInfo, request) {
+		return false, nil
+	}
+	return k8s.CanPerformAllActions(p.ctx, p.userInfo, []k8s.PermissionRequest{
+		{Verb: "create", Resource: "services", Namespace: ns},
+		{Verb: "create", Group: "maxtac.vtk.io", Resource: "accesses", Namespace: ns},
+	})
+}
+
+// collectSideApproval records the approval of a side of a DualApproval request, as long as another side, or the
+// quorum, is still waiting. Approvers approve the first waiting side they may approve, once. It reports whether the
+// approval was handled: recorded, or refused. Otherwise both sides are approved and the accesses can be created.
+func (p *webSocketCommandProcessor) collectSideApproval(request *netwatchv1alpha1.AccessRequest, comment string, changes []string) bool {
+	if !request.Spec.DualApproval {
+		return false
+	}
+	if isRequestOwner(p.userInfo.Email, request.Spec) {
+		p.sendError("Requests needing an approval from each side cannot be approved by their requestor", nil, "Request")
+		return true
+	}
+	if slices.ContainsFunc(request.Spec.ApprovedBy, func(a netwatchv1alpha1.Approval) bool { return a.Approver == p.userInfo.Email }) {
+		p.sendError("You already approved this request, it needs an approval from the other side", nil, "Request")
+		return true
+	}
+
+	// Once both sides are approved, the remaining approvals of a quorum may come from either side.
+	candidates := waitingSides(request.Spec)
+	if len(candidates) == 0 {
+		candidates = requestSides
+	}
+	side := ""
+	for _, candidate := range candidates {
+		allowed, err := p.canApproveSide(request, candidate)
+		if err != nil {
+			p.sendError("Could not verify permissions for approving the request", err, "Request")
+			return true
+		}
+		if allowed {
+			side = candidate
+			break
+		}
+	}
+	if side == "" {
+		var waiting []string
+		for _, candidate := range candidates {
+			ns := sideNamespace(request.Spec, candidate)
+			waiting = append(waiting, fmt.Sprintf("the %s side (%s, reviewed by %s)", candidate, ns, strings.Join(namespaceReviewers(p.ctx, ns), ", ")))
+		}
+		p.sendError("Permission denied. This request waits for an approval from "+strings.Join(waiting, " or "), nil, "Request")
+		return true
+	}
+
+	remaining := slices.DeleteFunc(waitingSides(request.Spec), func(s string) bool { return s == side })
+	if len(remaining) == 0 && len(request.Spec.ApprovedBy)+1 >= max(request.Spec.RequiredApprovals, len(requestSides)) {
+		return false
+	}
+
+	request.Spec.ApprovedBy = append(request.Spec.ApprovedBy, netwatchv1alpha1.Approval{
+		Approver: p.userInfo.Email, ApprovedAt: metav1.Now(), Comment: comment, Side: side,
+	})
+	// As with a quorum, the update fails on a conflict, so two approvals at once can't overwrite each other.
+	if err := k8s.UpdateAccessRequestAsApp(p.ctx, request); err != nil {
+		p.sendError("Could not record the approval, please retry", err, "Request")
+		return true
+	}
+	invalidateAccessRequestCache()
+	logger.Logger.Info("Side approval recorded", "request", request.Name, "approver", p.userInfo.Email, "side", side,
+		"waiting", remaining, "changes", changes)
+	waiting := "more approvals"
+	if len(remaining) > 0 {
+		waiting = "the " + strings.Join(remaining, " and ") + " side"
+	}
+	p.logAndBroadcast(LogEntry{
+		Payload: fmt.Sprintf("Request from %s approved by %s for the %s side (%s), waiting for %s.%s%s", requestorLabel(request.Spec),
+			p.userInfo.Email, side, sideNamespace(request.Spec, side), waiting, changesSuffix(changes), commentSuffix(comment)),
+		ClassName: "log-info",
+		LogType:   "Request",
+		Type:      "applyResult",
+		Groups:    routedGroups(p.ctx, request.Spec),
+		Owners:    routedOwners(p.ctx, request.Spec),
+	})
+	return true
+}
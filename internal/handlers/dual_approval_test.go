@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+// withApprovalTeams enables dual approval with the approver groups of the namespaces, none of them declaring
+// owners.
+func withApprovalTeams(t *testing.T, groups map[string][]string) {
+	t.Helper()
+	previousDual, previousGroups := dualApproval, approverGroups
+	SetDualApproval(true)
+	SetApproverGroups(groups)
+	namespaceOwnersMu.Lock()
+	namespaceOwnersByName, namespaceOwnersFetchedAt = map[string]namespaceOwners{}, time.Now()
+	namespaceOwnersMu.Unlock()
+	t.Cleanup(func() {
+		dualApproval, approverGroups = previousDual, previousGroups
+		namespaceOwnersMu.Lock()
+		namespaceOwnersByName, namespaceOwnersFetchedAt = nil, time.Time{}
+		namespaceOwnersMu.Unlock()
+	})
+}
+
+func TestSetRequiredApprovalsOfSubmissions(t *testing.T) {
+	withApprovalTeams(t, map[string][]string{"frontend": {"web"}, "backend": {"core"}, "api": {"core"}})
+
+	tests := []struct {
+		name    string
+		payload webSocketPayload
+		want    bool
+	}{
+		{
+			name:    "services of two teams",
+			payload: webSocketPayload{SourceService: "frontend/web", TargetService: "backend/db"},
+			want:    true,
+		},
+		{
+			name:    "services of the same team",
+			payload: webSocketPayload{SourceService: "api/gateway", TargetService: "backend/db"},
+		},
+		{
+			name:    "services of the same namespace",
+			payload: webSocketPayload{SourceService: "backend/api", TargetService: "backend/db"},
+		},
+		{
+			name:    "an external access",
+			payload: webSocketPayload{Service: "frontend/web", Cidr: "10.0.0.0/8"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := netwatchv1alpha1.AccessRequestSpec{
+				RequestType:   submissionType(tt.payload),
+				SourceService: tt.payload.SourceService,
+				TargetService: tt.payload.TargetService,
+				Service:       tt.payload.Service,
+				Cidr:          tt.payload.Cidr,
+				Duration:      3600,
+			}
+			setRequiredApprovals(context.Background(), &spec)
+			if spec.DualApproval != tt.want {
+				t.Errorf("got DualApproval=%t, want %t", spec.DualApproval, tt.want)
+			}
+		})
+	}
+}
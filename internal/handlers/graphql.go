@@ -0,0 +1,356 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// graphqlRouter serves the REST endpoints the GraphQL fields resolve to. GraphQL is disabled while it is nil.
+var graphqlRouter http.Handler
+
+// SetGraphQLRouter enables the GraphQL endpoint. Its fields are resolved by the REST endpoints served by router,
+// called with the credentials of the GraphQL request.
+func SetGraphQLRouter(router http.Handler) {
+	graphqlRouter = router
+}
+
+// GraphQLEnabled reports whether the GraphQL endpoint is enabled.
+func GraphQLEnabled() bool {
+	return graphqlRouter != nil
+}
+
+// graphqlField is a top-level field of the GraphQL schema, resolved by a REST endpoint. Its arguments are passed
+// as query parameters of the same name.
+type graphqlField struct {
+	path string
+	args []string
+}
+
+// graphqlFields is the GraphQL schema. The fields of the objects they return are the JSON fields of the REST
+// payloads.
+var graphqlFields = map[string]graphqlField{
+	"services":        {path: "/services", args: []string{"q", "namespace", "labelSelector", "scoped"}},
+	"namespaces":      {path: "/namespaces", args: []string{"labelSelector", "scoped"}},
+	"activeAccesses":  {path: "/active-accesses", args: []string{"user", "namespace", "type", "status"}},
+	"pendingRequests": {path: "/pending-requests", args: []string{"label"}},
+	"myRequests":      {path: "/my-requests"},
+	"logs":            {path: "/logs/search", args: []string{"q", "logType", "className", "user", "since", "limit"}},
+}
+
+// GraphQLRequest is a GraphQL query.
+type GraphQLRequest struct {
+	Query         string         `json:"query" binding:"required" example:"{ services { name namespace } activeAccesses(status: \"Active\") { name source target expiresAt } }"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty" swaggertype:"object"`
+}
+
+// GraphQLError is an error of a GraphQL query, with the path of the field it comes from.
+type GraphQLError struct {
+	Message string `json:"message"`
+	Path    []any  `json:"path,omitempty" swaggertype:"array,string"`
+}
+
+// GraphQLResponse is the result of a GraphQL query. Data is null when the query could not be run.
+type GraphQLResponse struct {
+	Data   any            `json:"data" swaggertype:"object"`
+	Errors []GraphQLError `json:"errors,omitempty"`
+}
+
+// graphqlSelection is a field requested by a query.
+type graphqlSelection struct {
+	alias      string
+	name       string
+	args       map[string]any
+	selections []graphqlSelection
+}
+
+// graphqlObject is an object of a GraphQL response, its fields in the order of the query.
+type graphqlObject struct {
+	keys   []string
+	values map[string]any
+}
+
+func (o graphqlObject) MarshalJSON() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, key := range o.keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(key) //nolint:all
+		value, err := json.Marshal(o.values[key])
+		if err != nil {
+			return nil, err
+		}
+		b.Write(name)
+		b.WriteByte(':')
+		b.Write(value)
+	}
+	b.WriteByte('}')
+	return b.Bytes(), nil
+}
+
+func (o *graphqlObject) set(key string, value any) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+	o.values[key] = value
+}
+
+// GraphQL runs a read-only GraphQL query over services, accesses, requests and logs.
+// GraphQL godoc
+// @Summary      Query with GraphQL
+// @Description  Runs a GraphQL query, so a dashboard can be fetched in one round-trip. Only queries are supported, without fragments or directives, selections can be nested up to 10 levels deep, and a query can have up to 10 top-level fields and 10 aliases. The top-level fields are services, namespaces, activeAccesses, pendingRequests, myRequests and logs; they take the query parameters of the matching REST endpoints as arguments, and their objects have the fields of its JSON payloads. Each field is checked like a call to its endpoint: a field that fails is null, with the reason in errors. Disabled unless NETWATCH_GRAPHQL_ENABLED is true.
+// @Tags         System
+// @Accept       json
+// @Produce      json
+// @Param        query  body      handlers.GraphQLRequest  true  "GraphQL query"
+// @Success      200  {object}  handlers.GraphQLResponse
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /graphql [post]
+func GraphQL(c *gin.Context) {
+	var request GraphQLRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	selections, err := parseGraphQLQuery(request.Query, request.OperationName, request.Variables)
+	if err != nil {
+		c.JSON(http.StatusOK, GraphQLResponse{Errors: []GraphQLError{{Message: err.Error()}}})
+		return
+	}
+
+	response := GraphQLResponse{}
+	data := graphqlObject{values: map[string]any{}}
+	for _, selection := range selections {
+		key := selection.alias
+		if selection.name == "__typename" {
+			data.set(key, "Query")
+			continue
+		}
+		value, err := resolveGraphQLField(c, selection)
+		if err != nil {
+			response.Errors = append(response.Errors, GraphQLError{Message: err.Error(), Path: []any{key}})
+			data.set(key, nil)
+			continue
+		}
+		data.set(key, projectGraphQL(value, selection.selections))
+	}
+	response.Data = data
+	c.JSON(http.StatusOK, response)
+}
+
+// resolveGraphQLField calls the REST endpoint of a top-level field with the credentials of the GraphQL request.
+func resolveGraphQLField(c *gin.Context, selection graphqlSelection) (any, error) {
+	field, ok := graphqlFields[selection.name]
+	if !ok {
+		return nil, fmt.Errorf("cannot query field %q on type Query", selection.name)
+	}
+	query := url.Values{}
+	for name, value := range selection.args {
+		if !slices.Contains(field.args, name) {
+			return nil, fmt.Errorf("unknown argument %q on field %q", name, selection.name)
+		}
+		values, isList := value.([]any)
+		if !isList {
+			values = []any{value}
+		}
+		for _, v := range values {
+			if v != nil {
+				query.Add(name, fmt.Sprint(v))
+			}
+		}
+	}
+
+	target := "/api/" + APIVersion + field.path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Keep the credentials, whether a header, a session cookie or a forwarded client certificate.
+	req.Header = c.Request.Header.Clone()
+	for _, header := range []string{"Content-Type", "Content-Length", IdempotencyKeyHeader} {
+		req.Header.Del(header)
+	}
+	req.RemoteAddr = c.Request.RemoteAddr
+	recorder := httptest.NewRecorder()
+	graphqlRouter.ServeHTTP(recorder, req)
+
+	if recorder.Code < 200 || recorder.Code > 299 {
+		var apiErr HTTPError
+		if json.Unmarshal(recorder.Body.Bytes(), &apiErr) == nil && apiErr.Error != "" {
+			return nil, fmt.Errorf("%s", apiErr.Error)
+		}
+		return nil, fmt.Errorf("%s answered %d", field.path, recorder.Code)
+	}
+	var value any
+	if err := json.Unmarshal(recorder.Body.Bytes(), &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// projectGraphQL keeps the selected fields of a JSON value. Without selections the value is returned whole.
+func projectGraphQL(value any, selections []graphqlSelection) any {
+	if len(selections) == 0 {
+		return value
+	}
+	switch v := value.(type) {
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = projectGraphQL(item, selections)
+		}
+		return items
+	case map[string]any:
+		object := graphqlObject{values: map[string]any{}}
+		for _, selection := range selections {
+			// Fields the payload omits when empty are null.
+			object.set(selection.alias, projectGraphQL(v[selection.name], selection.selections))
+		}
+		return object
+	default:
+		return value
+	}
+}
+
+const (
+	// maxGraphQLTokens bounds the size of the queries parsed.
+	maxGraphQLTokens = 2000
+	// maxGraphQLDepth bounds how deep selections can be nested. The REST payloads are much shallower.
+	maxGraphQLDepth = 10
+	// maxGraphQLRootFields bounds the top-level fields of a query, each resolved by a call to a REST endpoint.
+	maxGraphQLRootFields = 10
+	// maxGraphQLAliases bounds the aliased fields of a query, which would otherwise let a field be selected again
+	// and again.
+	maxGraphQLAliases = 10
+)
+
+// parseGraphQLQuery returns the top-level selections of the operation to run, with the variables substituted in
+// their arguments. Only queries with fields, aliases, arguments and variables are supported.
+func parseGraphQLQuery(query, operationName string, variables map[string]any) ([]graphqlSelection, error) {
+	doc, err := parser.ParseQueryWithTokenLimit(&ast.Source{Input: query}, maxGraphQLTokens)
+	if err != nil {
+		var gqlErr *gqlerror.Error
+		if errors.As(err, &gqlErr) && len(gqlErr.Locations) > 0 {
+			return nil, fmt.Errorf("%s (line %d, column %d)", gqlErr.Message, gqlErr.Locations[0].Line, gqlErr.Locations[0].Column)
+		}
+		return nil, err
+	}
+	if len(doc.Fragments) > 0 {
+		return nil, fmt.Errorf("fragments are not supported")
+	}
+
+	var operation *ast.OperationDefinition
+	switch {
+	case len(doc.Operations) == 0:
+		return nil, fmt.Errorf("the query has no operation")
+	case operationName == "" && len(doc.Operations) > 1:
+		return nil, fmt.Errorf("operationName is required when the query has several operations")
+	case operationName == "":
+		operation = doc.Operations[0]
+	default:
+		if operation = doc.Operations.ForName(operationName); operation == nil {
+			return nil, fmt.Errorf("unknown operation %q", operationName)
+		}
+	}
+	if operation.Operation != ast.Query {
+		return nil, fmt.Errorf("only queries are supported, %s is not", operation.Operation)
+	}
+	if len(operation.Directives) > 0 {
+		return nil, fmt.Errorf("directives are not supported")
+	}
+
+	// Variables the request doesn't set take their default value.
+	values := map[string]any{}
+	for _, definition := range operation.VariableDefinitions {
+		if definition.DefaultValue == nil {
+			continue
+		}
+		value, err := definition.DefaultValue.Value(nil)
+		if err != nil {
+			return nil, err
+		}
+		values[definition.Variable] = value
+	}
+	for name, value := range variables {
+		values[name] = value
+	}
+	aliases := 0
+	selections, err := graphqlSelections(operation.SelectionSet, values, 1, &aliases)
+	if err != nil {
+		return nil, err
+	}
+	if len(selections) > maxGraphQLRootFields {
+		return nil, fmt.Errorf("a query can select at most %d top-level fields", maxGraphQLRootFields)
+	}
+	seen := make(map[string]bool, len(selections))
+	for _, selection := range selections {
+		if seen[selection.alias] {
+			return nil, fmt.Errorf("%q is selected more than once", selection.alias)
+		}
+		seen[selection.alias] = true
+	}
+	return selections, nil
+}
+
+// graphqlSelections converts a parsed selection set, depth levels deep. aliases counts the aliased fields of the
+// whole query.
+func graphqlSelections(set ast.SelectionSet, variables map[string]any, depth int, aliases *int) ([]graphqlSelection, error) {
+	if depth > maxGraphQLDepth {
+		return nil, fmt.Errorf("selections can't be nested more than %d levels deep", maxGraphQLDepth)
+	}
+	selections := make([]graphqlSelection, 0, len(set))
+	for _, s := range set {
+		field, ok := s.(*ast.Field)
+		if !ok {
+			return nil, fmt.Errorf("fragments are not supported")
+		}
+		if len(field.Directives) > 0 {
+			return nil, fmt.Errorf("directives are not supported")
+		}
+		if field.Alias != field.Name {
+			if *aliases++; *aliases > maxGraphQLAliases {
+				return nil, fmt.Errorf("a query can have at most %d aliases", maxGraphQLAliases)
+			}
+		}
+		selection := graphqlSelection{alias: field.Alias, name: field.Name}
+		for _, arg := range field.Arguments {
+			if arg.Value.Kind == ast.ObjectValue {
+				return nil, fmt.Errorf("argument %q of %q can't be an object", arg.Name, field.Name)
+			}
+			value, err := arg.Value.Value(variables)
+			if err != nil {
+				return nil, fmt.Errorf("invalid argument %q of %q: %w", arg.Name, field.Name, err)
+			}
+			if selection.args == nil {
+				selection.args = map[string]any{}
+			}
+			selection.args[arg.Name] = value
+		}
+		if len(field.SelectionSet) > 0 {
+			var err error
+			if selection.selections, err = graphqlSelections(field.SelectionSet, variables, depth+1, aliases); err != nil {
+				return nil, err
+			}
+		}
+		selections = append(selections, selection)
+	}
+	return selections, nil
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseGraphQLQuery(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		variables     map[string]any
+		want          []graphqlSelection
+	}{
+		{
+			name:  "shorthand query",
+			query: `{ services { name namespace } }`,
+			want: []graphqlSelection{{alias: "services", name: "services", selections: []graphqlSelection{
+				{alias: "name", name: "name"}, {alias: "namespace", name: "namespace"},
+			}}},
+		},
+		{
+			name:  "aliases and arguments",
+			query: `query { active: activeAccesses(status: "Active", namespace: ["dev", "prod"]) { name } logs(limit: 5, q: null) }`,
+			want: []graphqlSelection{
+				{alias: "active", name: "activeAccesses", args: map[string]any{"status": "Active", "namespace": []any{"dev", "prod"}},
+					selections: []graphqlSelection{{alias: "name", name: "name"}}},
+				{alias: "logs", name: "logs", args: map[string]any{"limit": int64(5), "q": nil}},
+			},
+		},
+		{
+			name:      "variables and defaults",
+			query:     `query Dashboard($ns: String, $limit: Int = 20, $type: String = "Service") { services(namespace: $ns) { name } logs(limit: $limit) activeAccesses(type: $type, user: $missing) }`,
+			variables: map[string]any{"ns": "dev", "type": "External"},
+			want: []graphqlSelection{
+				{alias: "services", name: "services", args: map[string]any{"namespace": "dev"}, selections: []graphqlSelection{{alias: "name", name: "name"}}},
+				{alias: "logs", name: "logs", args: map[string]any{"limit": int64(20)}},
+				{alias: "activeAccesses", name: "activeAccesses", args: map[string]any{"type": "External", "user": nil}},
+			},
+		},
+		{
+			name:  "nested selections",
+			query: "# comment\n{ pendingRequests { requestID history { decision decidedBy } labels { key value } } }",
+			want: []graphqlSelection{{alias: "pendingRequests", name: "pendingRequests", selections: []graphqlSelection{
+				{alias: "requestID", name: "requestID"},
+				{alias: "history", name: "history", selections: []graphqlSelection{{alias: "decision", name: "decision"}, {alias: "decidedBy", name: "decidedBy"}}},
+				{alias: "labels", name: "labels", selections: []graphqlSelection{{alias: "key", name: "key"}, {alias: "value", name: "value"}}},
+			}}},
+		},
+		{
+			name:          "named operation",
+			query:         `query A { services { name } } query B { myRequests { requestID } }`,
+			operationName: "B",
+			want: []graphqlSelection{{alias: "myRequests", name: "myRequests", selections: []graphqlSelection{
+				{alias: "requestID", name: "requestID"},
+			}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseGraphQLQuery(tt.query, tt.operationName, tt.variables)
+			if err != nil {
+				t.Fatalf("parseGraphQLQuery: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// aliasedFields selects the name field n times, each under its own alias.
+func aliasedFields(n int) string {
+	var fields strings.Builder
+	for i := range n {
+		fmt.Fprintf(&fields, "a%d: name ", i)
+	}
+	return fields.String()
+}
+
+func TestParseGraphQLQueryRejects(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		operationName string
+		wantErr       string
+	}{
+		{"empty", ``, "", "the query has no operation"},
+		{"unterminated selection set", `{ services { name }`, "", "Expected Name, found <EOF>"},
+		{"unterminated string", `{ services(q: "api) { name } }`, "", "Unexpected <Invalid>"},
+		{"unexpected character", `{ services { name; } }`, "", "Expected Name, found <Invalid>"},
+		{"missing argument value", `{ services(q:) { name } }`, "", "Unexpected )"},
+		{"mutation", `mutation { revoke(id: "x") }`, "", "only queries are supported, mutation is not"},
+		{"subscription", `subscription { logs }`, "", "only queries are supported, subscription is not"},
+		{"fragment definition", `{ services { ...svc } } fragment svc on Service { name }`, "", "fragments are not supported"},
+		{"inline fragment", `{ services { ... on Service { name } } }`, "", "fragments are not supported"},
+		{"field directive", `{ services @include(if: true) { name } }`, "", "directives are not supported"},
+		{"operation directive", `query @cached { services { name } }`, "", "directives are not supported"},
+		{"object argument", `{ services(q: {name: "api"}) { name } }`, "", `argument "q" of "services" can't be an object`},
+		{"several operations without a name", `query A { services } query B { logs }`, "", "operationName is required"},
+		{"unknown operation", `query A { services }`, "B", `unknown operation "B"`},
+		{"too deep", `{ a { b { c { d { e { f { g { h { i { j { k } } } } } } } } } } }`, "", "nested more than 10 levels deep"},
+		{"too many tokens", "{ " + strings.Repeat("name ", maxGraphQLTokens) + "}", "", "exceeded token limit"},
+		{"too many top-level fields", "{ " + strings.Repeat("services ", maxGraphQLRootFields+1) + "}", "", "at most 10 top-level fields"},
+		{"too many aliases", "{ services { " + aliasedFields(maxGraphQLAliases+1) + "} }", "", "at most 10 aliases"},
+		{"field selected twice", `{ services { name } services { namespace } }`, "", `"services" is selected more than once`},
+		{"alias selected twice", `{ a: pendingRequests { name } a: myRequests { name } }`, "", `"a" is selected more than once`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseGraphQLQuery(tt.query, tt.operationName, nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseGraphQLQueryDepthLimit(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("{ f ", depth) + strings.Repeat("}", depth)
+	}
+	if _, err := parseGraphQLQuery(nested(maxGraphQLDepth), "", nil); err != nil {
+		t.Fatalf("a query nested %d levels deep was rejected: %v", maxGraphQLDepth, err)
+	}
+	if _, err := parseGraphQLQuery(nested(maxGraphQLDepth+1), "", nil); err == nil {
+		t.Fatalf("a query nested %d levels deep was accepted", maxGraphQLDepth+1)
+	}
+	// Deep nesting is stopped by the token limit before it can exhaust the stack.
+	if _, err := parseGraphQLQuery(nested(100000), "", nil); err == nil {
+		t.Fatal("a very deep query was accepted")
+	}
+}
+
+func TestProjectGraphQL(t *testing.T) {
+	var value any
+	json.Unmarshal([]byte(`[{"name":"api","namespace":"dev","ports":[{"port":80,"protocol":"TCP"}]}]`), &value) //nolint:all
+	selections := []graphqlSelection{
+		{alias: "svc", name: "name"},
+		{alias: "ports", name: "ports", selections: []graphqlSelection{{alias: "port", name: "port"}}},
+		{alias: "labels", name: "labels"},
+	}
+	got, err := json.Marshal(projectGraphQL(value, selections))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `[{"svc":"api","ports":[{"port":80}],"labels":null}]`; string(got) != want {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
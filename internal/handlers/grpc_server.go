@@ -0,0 +1,373 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	netwatchv1 "github.com/Banh-Canh/netwatch/pkg/proto/netwatch/v1"
+)
+
+// grpcServer implements the gRPC API on top of the REST API: every RPC is served by its REST endpoint, called
+// in-process with the credentials of the call, so both APIs run the same checks.
+type grpcServer struct {
+	netwatchv1.UnimplementedNetwatchServer
+	router http.Handler
+}
+
+// NewGRPCServer returns a gRPC server whose RPCs are resolved by the REST endpoints served by router.
+func NewGRPCServer(router http.Handler) *grpc.Server {
+	server := grpc.NewServer()
+	netwatchv1.RegisterNetwatchServer(server, &grpcServer{router: router})
+	return server
+}
+
+// restRequest builds the in-process REST request of an RPC. The "authorization" and "idempotency-key" metadata
+// become the headers of the same name, and the peer address the remote address, for the auth lockout.
+func restRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Request, error) {
+	reader := io.Reader(http.NoBody)
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		reader = bytes.NewReader(data)
+	}
+	target := "/api/" + APIVersion + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 {
+		req.Header.Set("Authorization", values[0])
+	}
+	if values := md.Get("idempotency-key"); len(values) > 0 {
+		req.Header.Set(IdempotencyKeyHeader, values[0])
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		req.RemoteAddr = p.Addr.String()
+	}
+	return req, nil
+}
+
+// callREST serves an RPC with its REST endpoint and decodes the JSON answer into out.
+func (s *grpcServer) callREST(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	req, err := restRequest(ctx, method, path, query, body)
+	if err != nil {
+		return err
+	}
+	recorder := httptest.NewRecorder()
+	s.router.ServeHTTP(recorder, req)
+	if recorder.Code < 200 || recorder.Code > 299 {
+		return restStatus(recorder.Code, recorder.Body.Bytes())
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), out); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+// restStatus converts an error answer of the REST API to a gRPC status. Commands that failed answer with their
+// result, whose last message tells why.
+func restStatus(code int, body []byte) error {
+	message := http.StatusText(code)
+	var apiErr HTTPError
+	var result CommandResult
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Error != "" {
+		message = apiErr.Error
+	} else if json.Unmarshal(body, &result) == nil && len(result.Messages) > 0 {
+		message = result.Messages[len(result.Messages)-1].Payload
+	}
+
+	grpcCode := codes.Unknown
+	switch code {
+	case http.StatusBadRequest:
+		grpcCode = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		grpcCode = codes.Unauthenticated
+	case http.StatusForbidden:
+		grpcCode = codes.PermissionDenied
+	case http.StatusNotFound:
+		grpcCode = codes.NotFound
+	case http.StatusConflict:
+		grpcCode = codes.Aborted
+	case http.StatusUnprocessableEntity:
+		grpcCode = codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		grpcCode = codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		grpcCode = codes.Unavailable
+	default:
+		if code >= http.StatusInternalServerError {
+			grpcCode = codes.Internal
+		}
+	}
+	return status.Error(grpcCode, message)
+}
+
+func (s *grpcServer) runCommand(ctx context.Context, method, path string, body any) (*netwatchv1.CommandResult, error) {
+	var result CommandResult
+	if err := s.callREST(ctx, method, path, nil, body, &result); err != nil {
+		return nil, err
+	}
+	return toProtoCommandResult(result), nil
+}
+
+func (s *grpcServer) SubmitAccessRequest(ctx context.Context, in *netwatchv1.SubmitAccessRequestRequest) (*netwatchv1.CommandResult, error) {
+	return s.runCommand(ctx, http.MethodPost, "/access-requests", SubmitAccessRequestInput{
+		SourceService:     in.GetSourceService(),
+		TargetService:     in.GetTargetService(),
+		Service:           in.GetService(),
+		Cidr:              in.GetCidr(),
+		Direction:         in.GetDirection(),
+		Ports:             in.GetPorts(),
+		Duration:          in.GetDuration(),
+		Description:       in.GetDescription(),
+		Labels:            in.GetLabels(),
+		OnBehalfOf:        in.GetOnBehalfOf(),
+		TicketRef:         in.GetTicketRef(),
+		Priority:          in.GetPriority(),
+		PreviousRequestID: in.GetPreviousRequestId(),
+	})
+}
+
+func (s *grpcServer) CreateAccess(ctx context.Context, in *netwatchv1.CreateAccessRequest) (*netwatchv1.CommandResult, error) {
+	return s.runCommand(ctx, http.MethodPost, "/accesses", CreateAccessInput{
+		SourceService: in.GetSourceService(),
+		TargetService: in.GetTargetService(),
+		Direction:     in.GetDirection(),
+		Ports:         in.GetPorts(),
+		Duration:      in.GetDuration(),
+		Heartbeat:     in.GetHeartbeat(),
+	})
+}
+
+func (s *grpcServer) CreateExternalAccess(ctx context.Context, in *netwatchv1.CreateExternalAccessRequest) (*netwatchv1.CommandResult, error) {
+	return s.runCommand(ctx, http.MethodPost, "/external-accesses", CreateExternalAccessInput{
+		Service:   in.GetService(),
+		Cidr:      in.GetCidr(),
+		Direction: in.GetDirection(),
+		Ports:     in.GetPorts(),
+		Duration:  in.GetDuration(),
+		Heartbeat: in.GetHeartbeat(),
+	})
+}
+
+func (s *grpcServer) ApproveAccessRequest(ctx context.Context, in *netwatchv1.AccessRequestRef) (*netwatchv1.CommandResult, error) {
+	return s.runCommand(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(in.GetId())+"/approve",
+		ApproveAccessRequestInput{Comment: in.GetComment()})
+}
+
+func (s *grpcServer) DenyAccessRequest(ctx context.Context, in *netwatchv1.AccessRequestRef) (*netwatchv1.CommandResult, error) {
+	return s.runCommand(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(in.GetId())+"/deny",
+		DenyAccessRequestInput{Reason: in.GetComment()})
+}
+
+func (s *grpcServer) RevokeAccess(ctx context.Context, in *netwatchv1.AccessRef) (*netwatchv1.CommandResult, error) {
+	collection := "/accesses/"
+	if in.GetExternal() {
+		collection = "/external-accesses/"
+	}
+	return s.runCommand(ctx, http.MethodDelete, collection+url.PathEscape(in.GetNamespace())+"/"+url.PathEscape(in.GetName()), nil)
+}
+
+func (s *grpcServer) ListPendingRequests(ctx context.Context, in *netwatchv1.ListPendingRequestsRequest) (*netwatchv1.ListPendingRequestsResponse, error) {
+	var query url.Values
+	if len(in.GetLabels()) > 0 {
+		query = url.Values{"label": in.GetLabels()}
+	}
+	var requests []AccessRequestPayload
+	if err := s.callREST(ctx, http.MethodGet, "/pending-requests", query, nil, &requests); err != nil {
+		return nil, err
+	}
+	response := &netwatchv1.ListPendingRequestsResponse{}
+	for _, request := range requests {
+		response.Requests = append(response.Requests, &netwatchv1.AccessRequest{
+			RequestId:      request.RequestID,
+			DisplayName:    request.DisplayName,
+			Requestor:      request.Requestor,
+			FiledBy:        request.FiledBy,
+			Timestamp:      request.Timestamp,
+			RequestType:    request.RequestType,
+			SourceService:  request.SourceService,
+			TargetService:  request.TargetService,
+			Cidr:           request.Cidr,
+			Service:        request.Service,
+			Direction:      request.Direction,
+			Ports:          request.Ports,
+			Duration:       request.Duration,
+			Description:    request.Description,
+			CanSelfApprove: request.CanSelfApprove,
+			Status:         request.Status,
+			Labels:         request.Labels,
+		})
+	}
+	return response, nil
+}
+
+func (s *grpcServer) ListActiveAccesses(ctx context.Context, in *netwatchv1.ListActiveAccessesRequest) (*netwatchv1.ListActiveAccessesResponse, error) {
+	query := url.Values{}
+	for name, value := range map[string]string{"user": in.GetUser(), "namespace": in.GetNamespace(), "type": in.GetType(), "status": in.GetStatus()} {
+		if value != "" {
+			query.Set(name, value)
+		}
+	}
+	var accesses []ActiveAccessInfo
+	if err := s.callREST(ctx, http.MethodGet, "/active-accesses", query, nil, &accesses); err != nil {
+		return nil, err
+	}
+	response := &netwatchv1.ListActiveAccessesResponse{}
+	for _, access := range accesses {
+		response.Accesses = append(response.Accesses, &netwatchv1.ActiveAccess{
+			Type:       access.Type,
+			Name:       access.Name,
+			Namespace:  access.Namespace,
+			Source:     access.Source,
+			Target:     access.Target,
+			ExpiresAt:  access.ExpiresAt,
+			Direction:  access.Direction,
+			Ports:      access.Ports,
+			Status:     access.Status,
+			Owner:      access.Owner,
+			Requestor:  access.Requestor,
+			ApprovedBy: access.ApprovedBy,
+			CreatedAt:  access.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+func (s *grpcServer) WatchLogs(in *netwatchv1.WatchLogsRequest, stream grpc.ServerStreamingServer[netwatchv1.LogEntry]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	var query url.Values
+	if in.GetSince() != 0 {
+		query = url.Values{"since": {fmt.Sprint(in.GetSince())}}
+	}
+	req, err := restRequest(ctx, http.MethodGet, "/logs/stream", query, nil)
+	if err != nil {
+		return err
+	}
+	writer := &sseStreamWriter{header: http.Header{}, cancel: cancel, send: func(entry LogEntry) error {
+		return stream.Send(toProtoLogEntry(entry))
+	}}
+	s.router.ServeHTTP(writer, req)
+
+	switch {
+	case writer.status != 0 && writer.status != http.StatusOK:
+		return restStatus(writer.status, writer.body.Bytes())
+	case writer.err != nil:
+		return writer.err
+	case stream.Context().Err() != nil:
+		return status.FromContextError(stream.Context().Err()).Err()
+	default:
+		return status.Error(codes.Unavailable, "the log stream ended")
+	}
+}
+
+// sseStreamWriter is the response writer of an in-process call to the log stream. It sends each "log" event to
+// the gRPC stream as it is written, and cancels the call when the stream fails.
+type sseStreamWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+	send   func(LogEntry) error
+	cancel context.CancelFunc
+	err    error
+}
+
+func (w *sseStreamWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *sseStreamWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *sseStreamWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK)
+	w.body.Write(b)
+	if w.status != http.StatusOK {
+		return len(b), nil
+	}
+	for w.err == nil {
+		event, rest, complete := strings.Cut(w.body.String(), "\n\n")
+		if !complete {
+			break
+		}
+		w.body.Reset()
+		w.body.WriteString(rest)
+		for _, line := range strings.Split(event, "\n") {
+			data, ok := strings.CutPrefix(line, "data:")
+			if !ok {
+				continue
+			}
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				w.err = status.Error(codes.Internal, err.Error())
+			} else if err := w.send(entry); err != nil {
+				w.err = err
+			}
+		}
+	}
+	if w.err != nil {
+		w.cancel()
+		return 0, w.err
+	}
+	return len(b), nil
+}
+
+func (w *sseStreamWriter) Flush() {}
+
+// CloseNotify is required by gin's Context.Stream. The stream ends when the context of the call is cancelled.
+func (w *sseStreamWriter) CloseNotify() <-chan bool {
+	return make(chan bool)
+}
+
+func toProtoCommandResult(result CommandResult) *netwatchv1.CommandResult {
+	out := &netwatchv1.CommandResult{
+		RequestId:   result.RequestID,
+		Name:        result.Name,
+		DisplayName: result.DisplayName,
+		Status:      result.Status,
+		Accesses:    result.Accesses,
+		Revoked:     result.Revoked,
+		Clones:      result.Clones,
+	}
+	for _, entry := range result.Messages {
+		out.Messages = append(out.Messages, toProtoLogEntry(entry))
+	}
+	return out
+}
+
+func toProtoLogEntry(entry LogEntry) *netwatchv1.LogEntry {
+	return &netwatchv1.LogEntry{
+		Timestamp: entry.Timestamp,
+		Payload:   entry.Payload,
+		ClassName: entry.ClassName,
+		LogType:   entry.LogType,
+		Type:      entry.Type,
+		User:      entry.User,
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	netwatchv1 "github.com/Banh-Canh/netwatch/pkg/proto/netwatch/v1"
+)
+
+// dialGRPC serves the gRPC API on top of router over an in-memory listener and returns a client of it.
+func dialGRPC(t *testing.T, router http.Handler) netwatchv1.NetwatchClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := NewGRPCServer(router)
+	go server.Serve(listener) //nolint:errcheck
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() }) //nolint:errcheck
+	return netwatchv1.NewNetwatchClient(conn)
+}
+
+func TestGRPCServerForwardsRPCsToREST(t *testing.T) {
+	router := gin.New()
+	api := router.Group("/api/" + APIVersion)
+	api.POST("/pending-requests/:id/approve", func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "Bearer token" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "missing credentials"})
+			return
+		}
+		var input ApproveAccessRequestInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, CommandResult{
+			RequestID: c.Param("id"),
+			Status:    "Approved",
+			Messages:  []LogEntry{{Payload: input.Comment}},
+		})
+	})
+	api.POST("/pending-requests/:id/deny", func(c *gin.Context) {
+		c.JSON(http.StatusUnprocessableEntity, CommandResult{Messages: []LogEntry{{Payload: "the request is not pending"}}})
+	})
+	api.GET("/pending-requests", func(c *gin.Context) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "admin only"})
+	})
+	client := dialGRPC(t, router)
+	authorized := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+
+	result, err := client.ApproveAccessRequest(authorized, &netwatchv1.AccessRequestRef{Id: "req-1", Comment: "ok"})
+	if err != nil {
+		t.Fatalf("ApproveAccessRequest: %v", err)
+	}
+	if result.GetRequestId() != "req-1" || result.GetStatus() != "Approved" || len(result.GetMessages()) != 1 ||
+		result.GetMessages()[0].GetPayload() != "ok" {
+		t.Errorf("got %v, want the REST result", result)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		code    codes.Code
+		message string
+	}{
+		{
+			name: "without the authorization metadata",
+			call: func() error {
+				_, err := client.ApproveAccessRequest(context.Background(), &netwatchv1.AccessRequestRef{Id: "req-1"})
+				return err
+			},
+			code:    codes.Unauthenticated,
+			message: "missing credentials",
+		},
+		{
+			name: "a failed command",
+			call: func() error {
+				_, err := client.DenyAccessRequest(authorized, &netwatchv1.AccessRequestRef{Id: "req-1"})
+				return err
+			},
+			code:    codes.FailedPrecondition,
+			message: "the request is not pending",
+		},
+		{
+			name: "a forbidden listing",
+			call: func() error {
+				_, err := client.ListPendingRequests(authorized, &netwatchv1.ListPendingRequestsRequest{})
+				return err
+			},
+			code:    codes.PermissionDenied,
+			message: "admin only",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := status.Convert(tt.call())
+			if got.Code() != tt.code || got.Message() != tt.message {
+				t.Errorf("got %v %q, want %v %q", got.Code(), got.Message(), tt.code, tt.message)
+			}
+		})
+	}
+}
+
+func TestGRPCServerWatchLogsStreamsEvents(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/"+APIVersion+"/logs/stream", func(c *gin.Context) {
+		if c.Query("since") != "42" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "unexpected since"})
+			return
+		}
+		for _, payload := range []string{"first", "second"} {
+			c.SSEvent("log", LogEntry{Timestamp: 43, Payload: payload, Type: "log"})
+			c.Writer.Flush()
+		}
+		<-c.Request.Context().Done()
+	})
+	client := dialGRPC(t, router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchLogs(ctx, &netwatchv1.WatchLogsRequest{Since: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"first", "second"} {
+		entry, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("Recv: %v", err)
+		}
+		if entry.GetPayload() != want || entry.GetTimestamp() != 43 {
+			t.Errorf("got %v, want payload %q", entry, want)
+		}
+	}
+	cancel()
+	if _, err := stream.Recv(); err == io.EOF || status.Code(err) != codes.Canceled {
+		t.Errorf("got %v after cancelling, want Canceled", err)
+	}
+}
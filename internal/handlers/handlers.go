@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"net/http"
+	"sync/atomic"
 
 	"github.com/gorilla/sessions"
 	"github.com/gorilla/websocket"
@@ -15,6 +16,9 @@ var (
 	sessionStore sessions.Store
 	redisClient  *redis.Client
 	logKey       = "netwatch:activity_log"
+
+	// openWebSockets tracks the number of currently connected WebSocket clients.
+	openWebSockets atomic.Int64
 )
 
 // SetSessionStore injects the session store dependency.
@@ -26,3 +30,8 @@ func SetSessionStore(store sessions.Store) {
 func SetRedisClient(client *redis.Client) {
 	redisClient = client
 }
+
+// OpenWebSocketCount returns the number of currently open WebSocket connections.
+func OpenWebSocketCount() int64 {
+	return openWebSockets.Load()
+}
@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// heartbeatsKey is a ZSET of request IDs scored by the time of their last heartbeat.
+	heartbeatsKey      = "netwatch:heartbeats"
+	heartbeatKeyPrefix = "netwatch:heartbeat:"
+	// HeartbeatTokenHeader carries the heartbeat token returned when the access was opened.
+	HeartbeatTokenHeader = "X-Netwatch-Heartbeat-Token"
+	// sessionBoundKey is a SET of the request IDs of accesses bound to a login session instead of a token.
+	sessionBoundKey = "netwatch:session_bound"
+	// sessionKeyPrefix is the prefix redistore stores sessions under.
+	sessionKeyPrefix = "session_"
+)
+
+var heartbeatGrace = 2 * time.Minute
+
+// heartbeatRecord is what is kept about a heartbeat-bound or session-bound access. Only a hash of the token is stored.
+type heartbeatRecord struct {
+	RequestID   string `json:"requestID"`
+	TokenSHA256 string `json:"tokenSHA256"`
+	Owner       string `json:"owner"`
+	AccessType  string `json:"accessType"`
+	// SessionID is set for session-bound accesses, which the server keeps alive while the session exists.
+	SessionID string `json:"sessionID,omitempty"`
+}
+
+// HeartbeatStatus is returned when a heartbeat is accepted.
+type HeartbeatStatus struct {
+	RequestID string `json:"requestID"`
+	// RevokeAt is when the access is revoked if no further heartbeat is received, as a Unix timestamp.
+	RevokeAt int64 `json:"revokeAt"`
+}
+
+// SetHeartbeatGrace sets how long a heartbeat-bound access survives without heartbeats.
+func SetHeartbeatGrace(grace time.Duration) {
+	heartbeatGrace = grace
+}
+
+// startHeartbeat binds an access to heartbeats and returns the token the caller must send them with.
+func startHeartbeat(ctx context.Context, requestID, owner, accessType string) (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(buf)
+	sum := sha256.Sum256([]byte(token))
+	record, err := json.Marshal(heartbeatRecord{RequestID: requestID, TokenSHA256: hex.EncodeToString(sum[:]), Owner: owner, AccessType: accessType})
+	if err != nil {
+		return "", err
+	}
+	pipe := redisClient.TxPipeline()
+	pipe.Set(ctx, heartbeatKeyPrefix+requestID, record, 0)
+	pipe.ZAdd(ctx, heartbeatsKey, redis.Z{Score: float64(time.Now().Unix()), Member: requestID})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// bindToSession ties an access to the requestor's login session: it is revoked when they log out or the session expires.
+func bindToSession(ctx context.Context, requestID, owner, accessType, sessionID string) error {
+	record, err := json.Marshal(heartbeatRecord{RequestID: requestID, Owner: owner, AccessType: accessType, SessionID: sessionID})
+	if err != nil {
+		return err
+	}
+	pipe := redisClient.TxPipeline()
+	pipe.Set(ctx, heartbeatKeyPrefix+requestID, record, 0)
+	pipe.ZAdd(ctx, heartbeatsKey, redis.Z{Score: float64(time.Now().Unix()), Member: requestID})
+	pipe.SAdd(ctx, sessionBoundKey, requestID)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// getHeartbeatRecord loads the record of a heartbeat-bound or session-bound access.
+func getHeartbeatRecord(ctx context.Context, requestID string) (*heartbeatRecord, error) {
+	recordJSON, err := redisClient.Get(ctx, heartbeatKeyPrefix+requestID).Result()
+	if err != nil {
+		return nil, err
+	}
+	var record heartbeatRecord
+	if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// checkSessionBound keeps session-bound accesses alive while their session exists and is in use, and revokes the others.
+// With endedSessionID set, only the accesses of that session are revoked, e.g. on logout.
+func checkSessionBound(ctx context.Context, endedSessionID string) {
+	requestIDs, err := redisClient.SMembers(ctx, sessionBoundKey).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to list session-bound accesses", "error", err)
+		return
+	}
+	for _, requestID := range requestIDs {
+		record, err := getHeartbeatRecord(ctx, requestID)
+		if err != nil {
+			if errors.Is(err, redis.Nil) {
+				redisClient.SRem(ctx, sessionBoundKey, requestID) //nolint:all
+			}
+			continue
+		}
+		if endedSessionID != "" {
+			if record.SessionID != endedSessionID {
+				continue
+			}
+			if err := revokeHeartbeatAccess(ctx, record, "its requestor logged out"); err != nil {
+				logger.Logger.Error("Failed to revoke session-bound access", "error", err, "requestID", requestID)
+			}
+			continue
+		}
+		exists, err := redisClient.Exists(ctx, sessionKeyPrefix+record.SessionID).Result()
+		if err != nil {
+			continue
+		}
+		if exists == 1 && sessionIdle(ctx, record.SessionID) {
+			redisClient.Del(ctx, sessionKeyPrefix+record.SessionID) //nolint:all
+			if err := revokeHeartbeatAccess(ctx, record, "its requestor's session was idle for too long"); err != nil {
+				logger.Logger.Error("Failed to revoke session-bound access", "error", err, "requestID", requestID)
+			}
+			continue
+		}
+		if exists == 1 {
+			redisClient.ZAddXX(ctx, heartbeatsKey, redis.Z{Score: float64(time.Now().Unix()), Member: requestID}) //nolint:all
+			continue
+		}
+		if err := revokeHeartbeatAccess(ctx, record, "its requestor's session ended"); err != nil {
+			logger.Logger.Error("Failed to revoke session-bound access", "error", err, "requestID", requestID)
+		}
+	}
+}
+
+// checkHeartbeatToken returns the record of a heartbeat-bound access when token matches it.
+func checkHeartbeatToken(ctx context.Context, requestID, token string) (*heartbeatRecord, bool) {
+	record, err := getHeartbeatRecord(ctx, requestID)
+	if err != nil || record.TokenSHA256 == "" {
+		return nil, false
+	}
+	sum := sha256.Sum256([]byte(token))
+	if subtle.ConstantTimeCompare([]byte(hex.EncodeToString(sum[:])), []byte(record.TokenSHA256)) != 1 {
+		return nil, false
+	}
+	return record, true
+}
+
+// SendHeartbeat keeps a heartbeat-bound access alive.
+// SendHeartbeat godoc
+// @Summary      Send a heartbeat for an access
+// @Description  Keeps an access opened with `heartbeat: true` alive. Without heartbeats, the access is revoked once the grace period elapses.
+// @Tags         Heartbeats
+// @Produce      json
+// @Param        id                          path    string  true   "Request ID of the access"
+// @Param        X-Netwatch-Heartbeat-Token  header  string  true   "Heartbeat token returned when the access was opened"
+// @Param        Idempotency-Key             header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.HeartbeatStatus
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /heartbeats/{id} [post]
+func SendHeartbeat(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID := c.Param("id")
+	if _, ok := checkHeartbeatToken(ctx, requestID, c.GetHeader(HeartbeatTokenHeader)); !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown heartbeat or invalid token"})
+		return
+	}
+	now := time.Now()
+	// XX: a heartbeat racing with a revocation must not resurrect the entry.
+	if err := redisClient.ZAddXX(ctx, heartbeatsKey, redis.Z{Score: float64(now.Unix()), Member: requestID}).Err(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record heartbeat"})
+		return
+	}
+	c.JSON(http.StatusOK, HeartbeatStatus{RequestID: requestID, RevokeAt: now.Add(heartbeatGrace).Unix()})
+}
+
+// StopHeartbeat revokes a heartbeat-bound access right away, e.g. when its CI job completes.
+// StopHeartbeat godoc
+// @Summary      Revoke a heartbeat-bound access
+// @Description  Revokes an access opened with `heartbeat: true` without waiting for the grace period.
+// @Tags         Heartbeats
+// @Param        id                          path    string  true  "Request ID of the access"
+// @Param        X-Netwatch-Heartbeat-Token  header  string  true  "Heartbeat token returned when the access was opened"
+// @Success      204
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /heartbeats/{id} [delete]
+func StopHeartbeat(c *gin.Context) {
+	ctx := c.Request.Context()
+	requestID := c.Param("id")
+	record, ok := checkHeartbeatToken(ctx, requestID, c.GetHeader(HeartbeatTokenHeader))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown heartbeat or invalid token"})
+		return
+	}
+	if err := revokeHeartbeatAccess(ctx, record, "its job completed"); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke access: " + err.Error()})
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// StartHeartbeatMonitor periodically revokes heartbeat-bound accesses whose heartbeats stopped, and
+// session-bound accesses whose session ended.
+func StartHeartbeatMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting heartbeat monitor", "interval", interval, "grace", heartbeatGrace)
+	for {
+		select {
+		case <-ticker.C:
+			checkSessionBound(ctx, "")
+			deadline := time.Now().Add(-heartbeatGrace).Unix()
+			expired, err := redisClient.ZRangeByScore(ctx, heartbeatsKey, &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(deadline, 10)}).Result()
+			if err != nil {
+				logger.Logger.Error("Heartbeat monitor failed to list heartbeats", "error", err)
+				continue
+			}
+			for _, requestID := range expired {
+				record, err := getHeartbeatRecord(ctx, requestID)
+				if err != nil {
+					redisClient.ZRem(ctx, heartbeatsKey, requestID) //nolint:all
+					continue
+				}
+				if err := revokeHeartbeatAccess(ctx, record, "its heartbeat stopped"); err != nil {
+					logger.Logger.Error("Failed to revoke access without heartbeat", "error", err, "requestID", requestID)
+				}
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// revokeHeartbeatAccess deletes a heartbeat-bound access. Only the replica that removes the heartbeat entry
+// revokes it, so concurrent monitors don't report the same revocation twice.
+func revokeHeartbeatAccess(ctx context.Context, record *heartbeatRecord, reason string) error {
+	removed, err := redisClient.ZRem(ctx, heartbeatsKey, record.RequestID).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return nil
+	}
+	deleted, err := k8s.RevokeRequestAsApp(ctx, record.RequestID)
+	if err != nil {
+		// Put the entry back so the next pass retries.
+		redisClient.ZAdd(ctx, heartbeatsKey, redis.Z{Score: 0, Member: record.RequestID}) //nolint:all
+		return err
+	}
+	redisClient.Del(ctx, heartbeatKeyPrefix+record.RequestID) //nolint:all
+	redisClient.SRem(ctx, sessionBoundKey, record.RequestID)  //nolint:all
+	if deleted == 0 {
+		return nil
+	}
+	msg := fmt.Sprintf("HEARTBEAT REVOKED: %s access %s (owner %s) was revoked because %s.", record.AccessType, record.RequestID, record.Owner, reason)
+	logger.Logger.Info("Heartbeat-bound access revoked", "requestID", record.RequestID, "owner", record.Owner, "reason", reason)
+	persistLogEntry(LogEntry{Payload: msg, ClassName: "log-warning", LogType: record.AccessType, Type: "applyResult"})
+	event := newAccessEvent("access.revoked", record.RequestID, record.Owner, "netwatch", nil)
+	event.Comment = reason
+	notifyLifecycleEvent(event)
+	return nil
+}
+
+// HeartbeatCount returns the number of heartbeat-bound accesses.
+func HeartbeatCount() int {
+	count, err := redisClient.ZCard(context.Background(), heartbeatsKey).Result()
+	if err != nil {
+		return 0
+	}
+	return int(count)
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// IdempotencyKeyHeader lets clients retry a POST safely: a request repeating the key of an earlier one gets the
+// earlier response again instead of being run twice.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+const (
+	// idempotencyKeyPrefix keys the response recorded for an idempotency key or WebSocket request token of a user.
+	idempotencyKeyPrefix = "netwatch:idempotency:"
+	// idempotencyTTL is how long a key is remembered, and so how long a client can retry with it.
+	idempotencyTTL = 24 * time.Hour
+	// maxIdempotencyKeyLength bounds the keys clients can make Netwatch store.
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotentResponse is the response recorded for an idempotency key. Status is 0 while the first request runs.
+type idempotentResponse struct {
+	Path        string `json:"path"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// recordingWriter keeps a copy of the response body written.
+type recordingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *recordingWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *recordingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// idempotencyUser returns who made a request, so keys of different callers never collide. Every caller of the
+// static API key is the same user, so they are told apart by their IP.
+func idempotencyUser(c *gin.Context) string {
+	if user := c.GetString("user"); user == "api-key-user" {
+		return user + "@" + c.ClientIP()
+	} else if user != "" {
+		return user
+	}
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		return ""
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(c.Request.Context(), idToken)
+	if err != nil {
+		return ""
+	}
+	return userInfo.Email
+}
+
+// idempotencyPath is the path of a request without its API version, since /api is an alias of the current
+// version: a retry may go through either.
+func idempotencyPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, "/api/"+APIVersion); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+		return "/api" + rest
+	}
+	return path
+}
+
+// Idempotent replays the recorded response of a POST whose Idempotency-Key was already used by the same user,
+// and answers 409 while the first request with that key is still running. Server errors and panics are not
+// recorded, so the request can be retried with the same key.
+func Idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+		user := idempotencyUser(c)
+		if user == "" {
+			// Unauthenticated, the handler rejects it.
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		path := idempotencyPath(c.Request.URL.Path)
+		redisKey := idempotencyKeyPrefix + user + ":" + key
+		pending, _ := json.Marshal(idempotentResponse{Path: path}) //nolint:all
+		claimed, err := redisClient.SetNX(ctx, redisKey, pending, idempotencyTTL).Result()
+		if err != nil {
+			logger.Logger.Error("Failed to check idempotency key, running the request anyway", "error", err, "user", user)
+			c.Next()
+			return
+		}
+		if !claimed {
+			replayIdempotentResponse(c, redisKey)
+			return
+		}
+
+		// The key is released even if the client went away or the handler panicked, so the request can be retried.
+		release := func() {
+			redisClient.Del(context.WithoutCancel(ctx), redisKey) //nolint:all
+		}
+		defer func() {
+			if r := recover(); r != nil {
+				release()
+				panic(r)
+			}
+		}()
+
+		writer := &recordingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		if writer.Status() >= http.StatusInternalServerError {
+			release()
+			return
+		}
+		recorded, _ := json.Marshal(idempotentResponse{ //nolint:all
+			Path:        path,
+			Status:      writer.Status(),
+			ContentType: writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		})
+		if err := redisClient.Set(context.WithoutCancel(ctx), redisKey, recorded, idempotencyTTL).Err(); err != nil {
+			logger.Logger.Error("Failed to record idempotent response", "error", err, "user", user)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a request whose idempotency key was already used.
+func replayIdempotentResponse(c *gin.Context, redisKey string) {
+	recordedJSON, err := redisClient.Get(c.Request.Context(), redisKey).Result()
+	if errors.Is(err, redis.Nil) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "The request with this Idempotency-Key just failed, retry it"})
+		return
+	}
+	var recorded idempotentResponse
+	if err == nil {
+		err = json.Unmarshal([]byte(recordedJSON), &recorded)
+	}
+	if err != nil {
+		logger.Logger.Error("Failed to read idempotent response", "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Could not check the Idempotency-Key"})
+		return
+	}
+	switch {
+	case recorded.Path != idempotencyPath(c.Request.URL.Path):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "This Idempotency-Key was already used for another endpoint"})
+	case recorded.Status == 0:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is still running"})
+	default:
+		c.Header("Idempotent-Replayed", "true")
+		c.Data(recorded.Status, recorded.ContentType, recorded.Body)
+		c.Abort()
+	}
+}
+
+// claimRequestToken records the request token of a WebSocket command. It reports false, and tells the user, when
+// a command with the same token was already received.
+func (p *webSocketCommandProcessor) claimRequestToken(token string) bool {
+	if len(token) > maxIdempotencyKeyLength {
+		p.sendError("Invalid requestToken", errors.New("it is too long"), "Global")
+		return false
+	}
+	claimed, err := redisClient.SetNX(p.ctx, idempotencyKeyPrefix+"ws:"+p.userInfo.Email+":"+token, time.Now().Unix(), idempotencyTTL).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to check request token, running the command anyway", "error", err, "user", p.userInfo.Email)
+		return true
+	}
+	if !claimed {
+		p.sendPrivate(LogEntry{
+			Payload:   "A command with this requestToken was already received, this one was ignored.",
+			ClassName: "log-warning", LogType: "Global", Type: "applyResult",
+		})
+	}
+	return claimed
+}
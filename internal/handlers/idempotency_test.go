@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIdempotencyPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/pending-requests/req-1/share", "/api/pending-requests/req-1/share"},
+		{"/api/pending-requests/req-1/share", "/api/pending-requests/req-1/share"},
+		{"/api/v1", "/api"},
+		{"/api/v10/requests", "/api/v10/requests"},
+		{"/api/v1requests", "/api/v1requests"},
+		{"/api/admin/users/leaver@example.com/offboard", "/api/admin/users/leaver@example.com/offboard"},
+	}
+	for _, tt := range tests {
+		if got := idempotencyPath(tt.path); got != tt.want {
+			t.Errorf("idempotencyPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestIdempotencyUserTellsAPIKeyCallersApart(t *testing.T) {
+	user := func(remoteAddr, authenticated string) string {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/v1/requests", nil)
+		c.Request.RemoteAddr = remoteAddr
+		c.Set("user", authenticated)
+		return idempotencyUser(c)
+	}
+
+	first, second := user("10.0.0.1:4000", "api-key-user"), user("10.0.0.2:4000", "api-key-user")
+	if first == second {
+		t.Fatalf("two API key callers share the idempotency scope %q", first)
+	}
+	if again := user("10.0.0.1:5000", "api-key-user"); again != first {
+		t.Fatalf("got scope %q for a retry from the same IP, want %q", again, first)
+	}
+	if got := user("10.0.0.1:4000", "alice@example.com"); got != "alice@example.com" {
+		t.Fatalf("got scope %q for a user, want their email", got)
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// JustificationConfig requires a description of a minimum length on new accesses and access requests.
+type JustificationConfig struct {
+	// MinLength applies to every namespace, 0 leaves the description optional.
+	MinLength int
+	// Namespaces sets the minimum length of the accesses touching a namespace, when it is stricter.
+	Namespaces map[string]int
+}
+
+var justificationPolicy JustificationConfig
+
+// SetJustificationPolicy sets the minimum length of the descriptions of submissions and direct creations.
+func SetJustificationPolicy(cfg JustificationConfig) {
+	justificationPolicy = cfg
+}
+
+// ParseJustificationPolicy reads NETWATCH_MIN_DESCRIPTION_LENGTH and the "namespace=length,..." rules of
+// NETWATCH_NAMESPACE_MIN_DESCRIPTION_LENGTH.
+func ParseJustificationPolicy(minLength, namespaces string) (JustificationConfig, error) {
+	cfg := JustificationConfig{Namespaces: make(map[string]int)}
+	if minLength != "" {
+		n, err := parseMinLength(minLength)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.MinLength = n
+	}
+	for namespace, length := range quorumRules(namespaces) {
+		if namespace == "" {
+			return cfg, fmt.Errorf("invalid rule without a namespace, expected namespace=length")
+		}
+		n, err := parseMinLength(length)
+		if err != nil {
+			return cfg, fmt.Errorf("namespace %q: %w", namespace, err)
+		}
+		cfg.Namespaces[namespace] = n
+	}
+	return cfg, nil
+}
+
+func parseMinLength(length string) (int, error) {
+	n, err := strconv.Atoi(length)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid minimum description length %q", length)
+	}
+	return n, nil
+}
+
+// requiredJustification returns the minimum description length of an access touching the namespaces, and the
+// namespace requiring it, empty when the global minimum applies.
+func requiredJustification(namespaces []string) (int, string) {
+	required, from := justificationPolicy.MinLength, ""
+	for _, ns := range namespaces {
+		if n := justificationPolicy.Namespaces[ns]; n > required {
+			required, from = n, ns
+		}
+	}
+	return required, from
+}
+
+// missingJustification reports, and rejects, an access or a request whose description is shorter than the policy
+// requires for its namespaces. Lengths are counted in characters, ignoring surrounding spaces.
+func (p *webSocketCommandProcessor) missingJustification(description string, namespaces []string, logType string) bool {
+	required, from := requiredJustification(slices.Compact(slices.Sorted(slices.Values(namespaces))))
+	length := len([]rune(strings.TrimSpace(description)))
+	if required == 0 || length >= required {
+		return false
+	}
+	scope := ""
+	if from != "" {
+		scope = " for accesses to namespace " + from
+	}
+	if length == 0 {
+		p.sendError(fmt.Sprintf("A description of at least %d characters is required%s, explain why you need this access", required, scope), nil, logType)
+		return true
+	}
+	p.sendError(fmt.Sprintf("The description is too short: %d characters, at least %d are required%s", length, required, scope), nil, logType)
+	return true
+}
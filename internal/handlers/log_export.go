@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// logExportBatchSize is how many activity log entries are read from Redis at once while exporting.
+const logExportBatchSize = 500
+
+// forEachLogEntry calls fn with every activity log entry written at or after since, in milliseconds, oldest first.
+// It reads the log in batches, resuming from the score of the last entry so entries trimmed meanwhile do not shift
+// the next batch.
+func forEachLogEntry(c *gin.Context, since int64, fn func(LogEntry) error) error {
+	ctx := c.Request.Context()
+	minScore := max(since, time.Now().Add(-logRetention).UnixMilli())
+	// seen counts the entries already read with the score of the last entry, they come first in the next batch.
+	seen := int64(0)
+	for {
+		batch, err := redisClient.ZRangeByScoreWithScores(ctx, logKey, &redis.ZRangeBy{
+			Min: strconv.FormatInt(minScore, 10), Max: "+inf", Offset: seen, Count: logExportBatchSize,
+		}).Result()
+		if err != nil {
+			return err
+		}
+		for _, z := range batch {
+			if score := int64(z.Score); score != minScore {
+				minScore, seen = score, 0
+			}
+			seen++
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(z.Member.(string)), &entry); err != nil {
+				logger.Logger.Warn("Failed to unmarshal a log entry from Redis", "error", err)
+				continue
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		if len(batch) < logExportBatchSize {
+			return nil
+		}
+	}
+}
+
+// ExportLogs streams the activity log as a download.
+// ExportLogs godoc
+// @Summary      Export the activity log
+// @Description  Streams every persisted activity log entry, oldest first, as NDJSON (one JSON entry per line) or CSV, to archive the log before retention trims it. Pass the timestamp of the last archived entry as since to only get what is newer.
+// @Tags         System
+// @Produce      application/x-ndjson
+// @Produce      text/csv
+// @Param        format  query     string  false  "Format of the export, ndjson by default"  Enums(ndjson, csv)
+// @Param        since   query     int     false  "Only entries written at or after this Unix timestamp, in milliseconds"
+// @Success      200  {array}   LogEntry
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /logs/export [get]
+func ExportLogs(c *gin.Context) {
+	format := c.DefaultQuery("format", "ndjson")
+	if format != "ndjson" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be ndjson or csv"})
+		return
+	}
+	var since int64
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		var err error
+		if since, err = strconv.ParseInt(sinceStr, 10, 64); err != nil || since < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'since' parameter"})
+			return
+		}
+	}
+
+	filename := fmt.Sprintf("netwatch-activity-%s.%s", time.Now().UTC().Format("20060102T150405Z"), format)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	var err error
+	if format == "ndjson" {
+		c.Header("Content-Type", "application/x-ndjson")
+		c.Status(http.StatusOK)
+		encoder := json.NewEncoder(c.Writer)
+		err = forEachLogEntry(c, since, func(entry LogEntry) error { return encoder.Encode(entry) })
+	} else {
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		w := csv.NewWriter(c.Writer)
+		w.Write([]string{"timestamp", "logType", "className", "type", "user", "payload"}) //nolint:all
+		err = forEachLogEntry(c, since, func(entry LogEntry) error {
+			return w.Write([]string{
+				time.UnixMilli(entry.Timestamp).UTC().Format(time.RFC3339Nano), entry.LogType, entry.ClassName, entry.Type, entry.User, entry.Payload,
+			})
+		})
+		w.Flush()
+	}
+	// The status is already sent, a failure can only cut the download short.
+	if err != nil {
+		logger.Logger.Error("Failed to export the activity log", "error", err)
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// logRetention is how long activity log entries are kept. It can be overridden with SetLogRetention.
+var logRetention = time.Hour
+
+// RetentionPolicy describes how long Netwatch keeps the data it stores in Redis and in the attachment bucket.
+type RetentionPolicy struct {
+	// ActivityLogSeconds applies to every activity log entry, counted from when it was written.
+	ActivityLogSeconds int64 `json:"activityLogSeconds" example:"3600"`
+	// RequestSubmissionSeconds applies to the payload stored with each access request.
+	RequestSubmissionSeconds int64 `json:"requestSubmissionSeconds" example:"7776000"`
+	// AttachmentSeconds applies to the documents attached to access requests, counted from their upload. Their
+	// metadata expires from Redis, and an hourly sweep deletes their content from the bucket.
+	AttachmentSeconds int64 `json:"attachmentSeconds" example:"2592000"`
+}
+
+// SetLogRetention configures how long activity log entries are kept.
+func SetLogRetention(retention time.Duration) {
+	logRetention = retention
+}
+
+// logRetentionCutoff returns the lowest score an activity log entry can have without being expired.
+func logRetentionCutoff() string {
+	return strconv.FormatInt(time.Now().Add(-logRetention).UnixMilli(), 10)
+}
+
+// appendLogEntry writes an entry to the activity log and enforces retention in the same round trip.
+// Entries older than the retention are trimmed on every write, and the key itself expires one retention
+// after the last write, so nothing outlives the policy even if the server stops writing altogether.
+func appendLogEntry(ctx context.Context, score int64, entryJSON []byte) error {
+	pipe := redisClient.TxPipeline()
+	pipe.ZAdd(ctx, logKey, redis.Z{Score: float64(score), Member: entryJSON})
+	pipe.ZRemRangeByScore(ctx, logKey, "-inf", "("+logRetentionCutoff())
+	pipe.Expire(ctx, logKey, logRetention)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetRetentionPolicy returns how long Netwatch keeps its data.
+// GetRetentionPolicy godoc
+// @Summary      Get the data retention policy
+// @Description  Returns how long activity log entries, request submissions and attachments are kept. Redis data is expired by Redis and trimmed on every write. The content of attachments is deleted from the bucket by an hourly sweep.
+// @Tags         System
+// @Produce      json
+// @Success      200  {object}  handlers.RetentionPolicy
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /retention [get]
+func GetRetentionPolicy(c *gin.Context) {
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := k8s.GetUserInfoFromToken(c.Request.Context(), idToken); err != nil {
+		logger.Logger.Warn("Rejected retention policy request", "error", err)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, RetentionPolicy{
+		ActivityLogSeconds:       int64(logRetention.Seconds()),
+		RequestSubmissionSeconds: int64(requestSubmissionRetention.Seconds()),
+		AttachmentSeconds:        int64(attachmentRetention.Seconds()),
+	})
+}
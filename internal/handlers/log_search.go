@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// Bounds of the number of entries a log search returns.
+const (
+	defaultLogSearchLimit = 100
+	maxLogSearchLimit     = 1000
+)
+
+// logSearch filters activity log entries. Empty fields match everything.
+type logSearch struct {
+	// Query is matched against the payload, case-insensitive.
+	Query     string
+	LogType   string
+	ClassName string
+	// User matches the user of the entry, or a payload mentioning them, as older entries and those of background
+	// jobs only name users in their payload.
+	User string
+}
+
+func (s logSearch) matches(entry LogEntry) bool {
+	payload := strings.ToLower(entry.Payload)
+	return (s.Query == "" || strings.Contains(payload, s.Query)) &&
+		(s.LogType == "" || strings.EqualFold(entry.LogType, s.LogType)) &&
+		(s.ClassName == "" || entry.ClassName == s.ClassName) &&
+		(s.User == "" || strings.EqualFold(entry.User, s.User) || strings.Contains(payload, s.User))
+}
+
+// SearchLogs searches the activity log.
+// SearchLogs godoc
+// @Summary      Search the activity log
+// @Description  Returns the most recent persisted activity log entries matching every filter given, oldest first. The search runs on the server over the whole log kept in Redis.
+// @Tags         System
+// @Produce      json
+// @Param        q          query     string  false  "Only entries whose payload contains this text, case-insensitive"
+// @Param        logType    query     string  false  "Only entries of this log type, e.g. Service, External, Request or Global"
+// @Param        className  query     string  false  "Only entries of this class, e.g. log-error"
+// @Param        user       query     string  false  "Only entries produced by, or mentioning, this user"
+// @Param        since      query     int     false  "Only entries written at or after this Unix timestamp, in milliseconds"
+// @Param        limit      query     int     false  "Maximum number of entries, 100 by default and at most 1000"
+// @Success      200  {array}   LogEntry
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /logs/search [get]
+func SearchLogs(c *gin.Context) {
+	search := logSearch{
+		Query:     strings.ToLower(strings.TrimSpace(c.Query("q"))),
+		LogType:   c.Query("logType"),
+		ClassName: c.Query("className"),
+		User:      strings.ToLower(strings.TrimSpace(c.Query("user"))),
+	}
+	var since int64
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		var err error
+		if since, err = strconv.ParseInt(sinceStr, 10, 64); err != nil || since < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'since' parameter"})
+			return
+		}
+	}
+	limit := defaultLogSearchLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		var err error
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 || limit > maxLogSearchLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' parameter"})
+			return
+		}
+	}
+
+	results := make([]LogEntry, 0)
+	err := forEachLogEntry(c, since, func(entry LogEntry) error {
+		if search.matches(entry) {
+			results = append(results, entry)
+			// Keep the most recent ones, as the log is read oldest first.
+			if len(results) > limit {
+				results = results[1:]
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Logger.Error("Failed to search logs in Redis", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search logs"})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
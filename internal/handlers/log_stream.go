@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// logStreamPollInterval is how often a log stream checks Redis for new activity log entries.
+const logStreamPollInterval = 2 * time.Second
+
+// StreamLogs streams new activity log entries as Server-Sent Events.
+// StreamLogs godoc
+// @Summary      Stream the activity log
+// @Description  Sends every activity log entry written after the call, or after since, as a Server-Sent Event named "log" whose data is the JSON entry. Every access created, approved, paused or revoked writes entries, so clients can refresh their lists on each event instead of polling them.
+// @Tags         System
+// @Produce      text/event-stream
+// @Param        since  query     int  false  "Also send the entries written at or after this Unix timestamp, in milliseconds"
+// @Success      200  {array}   LogEntry
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /logs/stream [get]
+func StreamLogs(c *gin.Context) {
+	since := time.Now().UnixMilli()
+	if sinceStr := c.Query("since"); sinceStr != "" {
+		var err error
+		if since, err = strconv.ParseInt(sinceStr, 10, 64); err != nil || since < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'since' parameter"})
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	// sent counts the entries already sent with the timestamp since, they come first in the next poll.
+	sent := 0
+	poll := func() bool {
+		skip := sent
+		err := forEachLogEntry(c, since, func(entry LogEntry) error {
+			if entry.Timestamp == since && skip > 0 {
+				skip--
+				return nil
+			}
+			if entry.Timestamp != since {
+				since, sent = entry.Timestamp, 0
+			}
+			sent++
+			c.SSEvent("log", entry)
+			return nil
+		})
+		if err != nil {
+			logger.Logger.Error("Failed to read the activity log for a stream", "error", err)
+			return false
+		}
+		return true
+	}
+
+	if !poll() {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not read logs"})
+		return
+	}
+	c.Writer.Flush()
+	ticker := time.NewTicker(logStreamPollInterval)
+	defer ticker.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-ticker.C:
+			return poll()
+		}
+	})
+}
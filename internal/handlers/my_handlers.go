@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// GetMyRequests lists the pending access requests of the current user.
+// GetMyRequests godoc
+// @Summary      List my access requests
+// @Description  Retrieves the pending AccessRequests whose requestor is the current user, or that the user filed on someone else's behalf, newest first. Each comes with its status and when it was submitted and first looked at by an approver. Approved and denied requests are no longer listed, see the request detail for their history.
+// @Tags         Requests
+// @Produce      json
+// @Success      200  {array}   AccessRequestPayload
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /my-requests [get]
+func GetMyRequests(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	requests, err := cachedAccessRequests(ctx, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to list AccessRequests from cluster", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve your requests"})
+		return
+	}
+
+	myRequests := make([]AccessRequestPayload, 0)
+	for i := range requests {
+		request := &requests[i]
+		if !isRequestOwner(userInfo.Email, request.Spec) {
+			continue
+		}
+		timing, _, _ := getRequestTiming(ctx, request.Name)
+		age, overdue := requestAging(request)
+		myRequests = append(myRequests, AccessRequestPayload{
+			RequestID:         request.Name,
+			DisplayName:       requestDisplayName(request),
+			Requestor:         request.Spec.Requestor,
+			FiledBy:           request.Spec.FiledBy,
+			Timestamp:         request.CreationTimestamp.Unix(),
+			RequestType:       request.Spec.RequestType,
+			SourceService:     request.Spec.SourceService,
+			TargetService:     request.Spec.TargetService,
+			Cidr:              request.Spec.Cidr,
+			Service:           request.Spec.Service,
+			Direction:         request.Spec.Direction,
+			Ports:             request.Spec.Ports,
+			Duration:          request.Spec.Duration,
+			Description:       request.Spec.Description,
+			TicketRef:         request.Spec.TicketRef,
+			Priority:          requestPriority(request.Spec),
+			PreviousRequestID: request.Spec.PreviousRequestID,
+			Status:            request.Spec.Status,
+			Attachments:       listRequestAttachments(ctx, request.Name),
+			Labels:            fromRequestObjectLabels(request.Labels),
+			RequiredApprovals: request.Spec.RequiredApprovals,
+			ApprovedBy:        approverNames(request.Spec),
+			DualApproval:      request.Spec.DualApproval,
+			ApprovedSides:     approvedSides(request.Spec),
+			Age:               age,
+			SLABreached:       overdue,
+			Timing:            timing,
+		})
+	}
+	sort.Slice(myRequests, func(i, j int) bool { return myRequests[i].Timestamp > myRequests[j].Timestamp })
+
+	c.JSON(http.StatusOK, myRequests)
+}
+
+// GetMyAccesses lists the access policies requested by the current user.
+// GetMyAccesses godoc
+// @Summary      List my access policies
+// @Description  Retrieves the active, paused and partially-created access policies requested by the current user, whoever approved them. Accesses approved by the caller for someone else are not listed.
+// @Tags         Access Policies
+// @Produce      json
+// @Success      200  {array}   ActiveAccessInfo
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /my-accesses [get]
+func GetMyAccesses(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+	filter := activeAccessFilter{Requestor: userInfo.Email, RequestorUsername: rememberUsername(ctx, userInfo)}
+	c.JSON(http.StatusOK, listActiveAccesses(ctx, filter))
+}
+
+// RevokeAllResult is the outcome of revoking every access of a user.
+type RevokeAllResult struct {
+	// RequestIDs are the requests whose accesses were revoked.
+	RequestIDs []string `json:"requestIDs"`
+	// Deleted is the number of Access and ExternalAccess objects deleted.
+	Deleted int `json:"deleted" example:"3"`
+}
+
+// RevokeMyAccesses revokes every access policy requested by the current user.
+// RevokeMyAccesses godoc
+// @Summary      Revoke all my access policies
+// @Description  Deletes every Access and ExternalAccess requested by the current user, including partially-created ones, but not those the caller approved for someone else. Giving up one's own accesses does not require delete permissions on them. Paused accesses are left alone.
+// @Tags         Access Policies
+// @Produce      json
+// @Success      200  {object}  handlers.RevokeAllResult
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /my-accesses [delete]
+func RevokeMyAccesses(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	requestIDs, err := userRequestIDs(ctx, userInfo.Email, rememberUsername(ctx, userInfo))
+	if err != nil {
+		logger.Logger.Error("Failed to list the accesses of a user", "error", err, "user", userInfo.Email)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not list your accesses"})
+		return
+	}
+	result := RevokeAllResult{RequestIDs: []string{}}
+	for _, requestID := range requestIDs {
+		deleted, err := k8s.RevokeRequestAsApp(ctx, requestID)
+		result.Deleted += deleted
+		if err != nil {
+			logger.Logger.Error("Failed to revoke an access of a user", "error", err, "user", userInfo.Email, "requestID", requestID)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not revoke all your accesses: " + err.Error()})
+			return
+		}
+		result.RequestIDs = append(result.RequestIDs, requestID)
+		notifyLifecycleEvent(newAccessEvent("access.revoked", requestID, userInfo.Email, userInfo.Email, nil))
+	}
+
+	if len(result.RequestIDs) > 0 {
+		persistLogEntry(LogEntry{
+			Payload:   fmt.Sprintf("SUCCESS: %s revoked all their accesses (%d policies).", userInfo.Email, result.Deleted),
+			ClassName: "log-success", LogType: "Global", Type: "applyResult",
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
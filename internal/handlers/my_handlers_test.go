@@ -0,0 +1,30 @@
+package handlers
+
+import "testing"
+
+func TestMyAccessesFilterOnTheRequestor(t *testing.T) {
+	// Accesses approved by bob for alice are labelled with bob's username.
+	approvedForAlice := ActiveAccessInfo{Name: "access-approved", Owner: "bob", Requestor: "alice@example.com", ApprovedBy: "bob@example.com"}
+	// Accesses created before their requestor was recorded only carry the label.
+	legacy := ActiveAccessInfo{Name: "access-legacy", Owner: "alice"}
+
+	tests := []struct {
+		name   string
+		filter activeAccessFilter
+		info   ActiveAccessInfo
+		want   bool
+	}{
+		{"requestor of an access approved by someone else", activeAccessFilter{Requestor: "alice@example.com", RequestorUsername: "alice"}, approvedForAlice, true},
+		{"requestor with a differently cased email", activeAccessFilter{Requestor: "Alice@Example.com", RequestorUsername: "alice"}, approvedForAlice, true},
+		{"approver of an access requested by someone else", activeAccessFilter{Requestor: "bob@example.com", RequestorUsername: "bob"}, approvedForAlice, false},
+		{"owner of an access without a recorded requestor", activeAccessFilter{Requestor: "alice@example.com", RequestorUsername: "alice"}, legacy, true},
+		{"someone else without a recorded requestor", activeAccessFilter{Requestor: "bob@example.com", RequestorUsername: "bob"}, legacy, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matches(tt.info); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
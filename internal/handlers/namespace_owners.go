@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// ownersAnnotation declares the owners of a namespace, who review the requests touching it instead of anyone its
+	// RBAC lets approve: a comma-separated list of user emails and, for the entries without "@", group names.
+	ownersAnnotation = "netwatch.vtk.io/owners"
+	// namespaceOwnersTTL bounds how long the owners of the namespaces are reused before they are listed again.
+	namespaceOwnersTTL = 30 * time.Second
+)
+
+// namespaceOwners are the users and groups declared as the owners of a namespace.
+type namespaceOwners struct {
+	users  []string
+	groups []string
+}
+
+var (
+	namespaceOwnersMu        sync.Mutex
+	namespaceOwnersByName    map[string]namespaceOwners
+	namespaceOwnersFetchedAt time.Time
+)
+
+// parseNamespaceOwners reads the ownersAnnotation of a namespace.
+func parseNamespaceOwners(annotation string) namespaceOwners {
+	var owners namespaceOwners
+	for owner := range strings.SplitSeq(annotation, ",") {
+		owner = strings.TrimSpace(owner)
+		switch {
+		case owner == "":
+		case strings.Contains(owner, "@"):
+			owners.users = append(owners.users, strings.ToLower(owner))
+		default:
+			owners.groups = append(owners.groups, owner)
+		}
+	}
+	return owners
+}
+
+// ownersOf returns the owners of a namespace, and whether it declares any. The namespaces are listed at most once
+// per namespaceOwnersTTL; when listing fails, the owners listed last are used.
+func ownersOf(ctx context.Context, namespace string) (namespaceOwners, bool) {
+	namespaceOwnersMu.Lock()
+	defer namespaceOwnersMu.Unlock()
+	if namespaceOwnersByName == nil || time.Since(namespaceOwnersFetchedAt) >= namespaceOwnersTTL {
+		list, err := k8s.ListNamespaces(ctx)
+		if err != nil {
+			logger.Logger.Warn("Failed to list namespace owners", "error", err)
+		} else {
+			namespaceOwnersByName = make(map[string]namespaceOwners)
+			for _, ns := range list.Items {
+				if owners := parseNamespaceOwners(ns.Annotations[ownersAnnotation]); len(owners.users)+len(owners.groups) > 0 {
+					namespaceOwnersByName[ns.Name] = owners
+				}
+			}
+			namespaceOwnersFetchedAt = time.Now()
+		}
+	}
+	owners, ok := namespaceOwnersByName[namespace]
+	return owners, ok
+}
+
+// includes reports whether a user is one of the owners, or in one of their groups.
+func (o namespaceOwners) includes(userInfo *k8s.UserInfo) bool {
+	return slices.Contains(o.users, strings.ToLower(userInfo.Email)) ||
+		slices.ContainsFunc(userInfo.Groups, func(group string) bool { return slices.Contains(o.groups, group) })
+}
@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// notificationAttempts bounds the deliveries of a notification, spaced by notificationBackoff, doubled each time.
+const (
+	notificationAttempts = 4
+	notificationBackoff  = 2 * time.Second
+)
+
+// LifecycleEvent is a step in the life of an access request or of an access, sent to the notification targets.
+type LifecycleEvent struct {
+	// ID identifies the event, the same across the retries of its delivery.
+	ID string `json:"id"`
+	// Type is request.submitted, request.updated, request.approved, request.denied, request.expired, access.created,
+	// access.revoked or access.expired.
+	Type        string `json:"type" example:"request.submitted"`
+	Request     string `json:"request,omitempty"`
+	RequestID   string `json:"requestID"`
+	DisplayName string `json:"displayName,omitempty"`
+	Requestor   string `json:"requestor,omitempty"`
+	// Actor is who filed the request on behalf of the requestor, who approved, denied or revoked it, or the rule that
+	// approved it.
+	Actor string `json:"actor,omitempty"`
+	// Path describes what the request connects.
+	Path       string   `json:"path,omitempty" example:"frontend/web -> backend/api"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Priority is the priority of a request: low, normal or urgent.
+	Priority string `json:"priority,omitempty"`
+	// TicketRef is the ticket tracking the request, see SetTicketing.
+	TicketRef string `json:"ticketRef,omitempty"`
+	// Groups and Owners are the groups and users reviewing the request, set on submitted events when it is routed.
+	Groups []string `json:"groups,omitempty"`
+	Owners []string `json:"owners,omitempty"`
+	// Accesses are the namespace/name of the Access and ExternalAccess objects of access events.
+	Accesses []string `json:"accesses,omitempty"`
+	// Comment is the description of a submission, the changes of an update, the approval comment, the denial reason
+	// or why an access was revoked.
+	Comment string `json:"comment,omitempty"`
+	// DecisionLinks are set on submitted events when decision links are enabled.
+	DecisionLinks *DecisionLinks `json:"decisionLinks,omitempty"`
+	Timestamp     int64          `json:"timestamp"`
+}
+
+// notificationTarget delivers lifecycle events to an external system.
+type notificationTarget interface {
+	name() string
+	// urls lists where an event is delivered, none when the target doesn't want it.
+	urls(event LifecycleEvent) []string
+	body(event LifecycleEvent) ([]byte, error)
+	// headers are added to a delivery, e.g. to sign it.
+	headers(event LifecycleEvent, body []byte) map[string]string
+}
+
+var (
+	notificationTargets []notificationTarget
+	notificationClient  = &http.Client{Timeout: 10 * time.Second}
+)
+
+// newRequestEvent describes a request for its notifications.
+func newRequestEvent(eventType string, request *netwatchv1alpha1.AccessRequest, actor, comment string) LifecycleEvent {
+	return LifecycleEvent{
+		Type:        eventType,
+		Request:     request.Name,
+		RequestID:   request.Spec.RequestID,
+		DisplayName: requestDisplayName(request),
+		Requestor:   request.Spec.Requestor,
+		Actor:       actor,
+		Path:        requestPath(request.Spec),
+		Namespaces:  requestNamespaces(request.Spec),
+		Comment:     comment,
+		TicketRef:   request.Spec.TicketRef,
+		Priority:    requestPriority(request.Spec),
+	}
+}
+
+// newAccessEvent describes the accesses of a request, given as namespace/name, for their notifications.
+func newAccessEvent(eventType, requestID, requestor, actor string, accesses []string) LifecycleEvent {
+	event := LifecycleEvent{Type: eventType, RequestID: requestID, Requestor: requestor, Actor: actor, Accesses: accesses}
+	for _, access := range accesses {
+		if ns, _, ok := strings.Cut(access, "/"); ok && !slices.Contains(event.Namespaces, ns) {
+			event.Namespaces = append(event.Namespaces, ns)
+		}
+	}
+	return event
+}
+
+// notifyLifecycleEvent sends an event to every notification target and to the ticketing system, in the background.
+// Failed deliveries are retried, then logged.
+func notifyLifecycleEvent(event LifecycleEvent) {
+	if ticketing != nil {
+		go syncTicket(event)
+	}
+	if len(notificationTargets) == 0 {
+		return
+	}
+	id := make([]byte, 16)
+	rand.Read(id) //nolint:all
+	event.ID, event.Timestamp = hex.EncodeToString(id), time.Now().Unix()
+	for _, target := range notificationTargets {
+		urls := target.urls(event)
+		if len(urls) == 0 {
+			continue
+		}
+		body, err := target.body(event)
+		if err != nil {
+			logger.Logger.Error("Failed to build a notification", "target", target.name(), "error", err)
+			continue
+		}
+		headers := target.headers(event, body)
+		for _, u := range urls {
+			go deliverNotification(target.name(), u, body, headers, event)
+		}
+	}
+}
+
+func deliverNotification(target, u string, body []byte, headers map[string]string, event LifecycleEvent) {
+	log := logger.Logger.With("target", target, "event", event.Type, "id", event.ID, "requestID", event.RequestID)
+	backoff := notificationBackoff
+	for attempt := 1; ; attempt++ {
+		retry, err := postNotification(u, body, headers)
+		if err == nil {
+			return
+		}
+		if !retry || attempt == notificationAttempts {
+			log.Warn("Failed to deliver a notification", "attempts", attempt, "error", err)
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// postNotification makes one delivery. It reports whether a failed delivery is worth retrying: network errors,
+// rate limiting and server errors are, other refusals aren't.
+func postNotification(u string, body []byte, headers map[string]string) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationClient.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := notificationClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// ParseNamespaceURLs reads "namespace=url,..." notification routes. A namespace repeated in several routes is sent
+// to all their URLs.
+func ParseNamespaceURLs(routes string) (map[string][]string, error) {
+	parsed := make(map[string][]string)
+	for route := range strings.SplitSeq(routes, ",") {
+		if route = strings.TrimSpace(route); route == "" {
+			continue
+		}
+		namespace, u, ok := strings.Cut(route, "=")
+		namespace, u = strings.TrimSpace(namespace), strings.TrimSpace(u)
+		if !ok || namespace == "" {
+			return nil, fmt.Errorf("invalid route %q, expected namespace=url", route)
+		}
+		if err := ValidateNotificationURL(u); err != nil {
+			return nil, fmt.Errorf("namespace %q: %w", namespace, err)
+		}
+		if !slices.Contains(parsed[namespace], u) {
+			parsed[namespace] = append(parsed[namespace], u)
+		}
+	}
+	return parsed, nil
+}
+
+// ValidateNotificationURL checks that a notification target URL is an absolute HTTP(S) URL.
+func ValidateNotificationURL(u string) error {
+	parsed, err := url.Parse(u)
+	if err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+		return fmt.Errorf("invalid URL %q", u)
+	}
+	return nil
+}
+
+// routedURLs lists the global URLs and those of the namespaces of an event, without duplicates.
+func routedURLs(global []string, namespaces map[string][]string, event LifecycleEvent) []string {
+	urls := slices.Clone(global)
+	for _, ns := range event.Namespaces {
+		for _, u := range namespaces[ns] {
+			if !slices.Contains(urls, u) {
+				urls = append(urls, u)
+			}
+		}
+	}
+	return urls
+}
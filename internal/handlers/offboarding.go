@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// userSessionsPrefix keys the set of login session IDs of a user, so they can all be ended when the user is offboarded.
+	userSessionsPrefix = "netwatch:user_sessions:"
+	// offboardedUsersKey is a SET of the users of the offboarded users file the watcher already offboarded.
+	offboardedUsersKey = "netwatch:offboarded_users"
+	// offboardingLockPrefix keys the lock a replica takes while it offboards a user for the watcher.
+	offboardingLockPrefix = "netwatch:offboarding:"
+)
+
+// OffboardResult tells what was cleaned up for a user who no longer exists in the directory.
+type OffboardResult struct {
+	User string `json:"user" example:"leaver@example.com"`
+	// Sessions is the number of login sessions ended.
+	Sessions int `json:"sessions" example:"2"`
+	// Tokens is the number of per-user tokens revoked: the calendar feed token, the linked Slack identities, the
+	// heartbeat tokens of their accesses and the share links of their requests.
+	Tokens int `json:"tokens" example:"1"`
+	// Requests is the number of their pending AccessRequests deleted.
+	Requests int `json:"requests" example:"1"`
+	// Accesses is the number of Access and ExternalAccess objects deleted.
+	Accesses int `json:"accesses" example:"3"`
+}
+
+// indexUserSession remembers a login session of a user, for as long as the session lives.
+func indexUserSession(ctx context.Context, email, sessionID string) {
+	key := userSessionsPrefix + email
+	pipe := redisClient.TxPipeline()
+	pipe.SAdd(ctx, key, sessionID)
+	pipe.Expire(ctx, key, time.Duration(sessionTTL)*time.Second)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Error("Failed to index user session", "error", err, "user", email)
+	}
+}
+
+// offboardUser ends the login sessions of a user, revokes their per-user tokens and deletes their pending requests
+// and accesses.
+// It keeps going after a failure, so as much as possible is cleaned up, and returns the errors met.
+func offboardUser(ctx context.Context, email string) (OffboardResult, error) {
+	result := OffboardResult{User: email}
+	var errs []error
+
+	sessionIDs, err := redisClient.SMembers(ctx, userSessionsPrefix+email).Result()
+	if err != nil {
+		errs = append(errs, fmt.Errorf("failed to list sessions: %w", err))
+	}
+	for _, sessionID := range sessionIDs {
+		deleted, err := redisClient.Del(ctx, sessionKeyPrefix+sessionID, sessionActivityPrefix+sessionID).Result()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to end session: %w", err))
+			continue
+		}
+		if deleted > 0 {
+			result.Sessions++
+		}
+	}
+	if err == nil {
+		redisClient.Del(ctx, userSessionsPrefix+email) //nolint:all
+	}
+
+	calendarToken, err := redisClient.GetDel(ctx, calendarUserTokenPrefix+email).Result()
+	switch {
+	case errors.Is(err, redis.Nil):
+	case err != nil:
+		errs = append(errs, fmt.Errorf("failed to revoke calendar token: %w", err))
+	default:
+		redisClient.Del(ctx, calendarTokenPrefix+calendarToken) //nolint:all
+		result.Tokens++
+	}
+
+	iter := redisClient.Scan(ctx, 0, slackIdentityPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		linked, err := redisClient.Get(ctx, iter.Val()).Result()
+		if err != nil || linked != email {
+			continue
+		}
+		if err := redisClient.Del(ctx, iter.Val()).Err(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unlink Slack identity: %w", err))
+			continue
+		}
+		result.Tokens++
+	}
+	if err := iter.Err(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to list Slack identities: %w", err))
+	}
+
+	pendingRequests, err := deleteUserPendingRequests(ctx, email)
+	result.Requests = len(pendingRequests)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	heartbeats, err := revokeUserHeartbeats(ctx, email)
+	result.Tokens += heartbeats
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	requestIDs, err := userRequestIDs(ctx, email, sanitizeUsername(email))
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	shareLinks, err := revokeShareLinks(ctx, append(slices.Clone(pendingRequests), requestIDs...))
+	result.Tokens += shareLinks
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, requestID := range requestIDs {
+		deleted, err := k8s.RevokeRequestAsApp(ctx, requestID)
+		result.Accesses += deleted
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to revoke request %s: %w", requestID, err))
+			continue
+		}
+		event := newAccessEvent("access.revoked", requestID, email, "netwatch", nil)
+		event.Comment = "its owner was offboarded"
+		notifyLifecycleEvent(event)
+	}
+
+	persistLogEntry(LogEntry{
+		Payload: fmt.Sprintf("OFFBOARDED: %s no longer exists. Ended %d session(s), revoked %d token(s), %d pending request(s) and %d access(es).",
+			email, result.Sessions, result.Tokens, result.Requests, result.Accesses),
+		ClassName: "log-warning", LogType: "Global", Type: "applyResult",
+	})
+	return result, errors.Join(errs...)
+}
+
+// deleteUserPendingRequests deletes the pending AccessRequests of a user, cleaning up the half of a partial request
+// they already created, and returns their names. Requests filed for someone else stay: only the requestor's go.
+func deleteUserPendingRequests(ctx context.Context, email string) ([]string, error) {
+	list, err := k8s.ListAccessRequestsAsApp(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list access requests: %w", err)
+	}
+	var deleted []string
+	var errs []error
+	for i := range list.Items {
+		request := &list.Items[i]
+		if request.Spec.Requestor != email || !request.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if request.Spec.Status == "PendingTarget" || request.Spec.Status == "PendingSource" {
+			if _, err := k8s.RevokeRequestAsApp(ctx, request.Spec.RequestID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to clean up partial request %s: %w", request.Name, err))
+				continue
+			}
+		}
+		if err := k8s.DeleteAccessRequestAsApp(ctx, request.Name); err != nil && !k8s.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to delete request %s: %w", request.Name, err))
+			continue
+		}
+		deleted = append(deleted, request.Name)
+		recordAccessDecision(ctx, request, "aborted", "netwatch", "its requestor was offboarded", nil)
+		recordRequestDecision(ctx, request, "aborted", "netwatch", "its requestor was offboarded")
+	}
+	if len(deleted) > 0 {
+		invalidateAccessRequestCache()
+	}
+	return deleted, errors.Join(errs...)
+}
+
+// revokeUserHeartbeats revokes the heartbeat tokens and session bindings of the accesses of a user. The accesses
+// themselves are deleted with the user's other accesses.
+func revokeUserHeartbeats(ctx context.Context, email string) (int, error) {
+	requestIDs, err := redisClient.ZRange(ctx, heartbeatsKey, 0, -1).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list heartbeats: %w", err)
+	}
+	revoked := 0
+	var errs []error
+	for _, requestID := range requestIDs {
+		record, err := getHeartbeatRecord(ctx, requestID)
+		if err != nil || record.Owner != email {
+			continue
+		}
+		pipe := redisClient.TxPipeline()
+		pipe.ZRem(ctx, heartbeatsKey, requestID)
+		pipe.Del(ctx, heartbeatKeyPrefix+requestID)
+		pipe.SRem(ctx, sessionBoundKey, requestID)
+		if _, err := pipe.Exec(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to revoke heartbeat token of %s: %w", requestID, err))
+			continue
+		}
+		revoked++
+	}
+	return revoked, errors.Join(errs...)
+}
+
+// revokeShareLinks deletes the share links to any of the requests.
+func revokeShareLinks(ctx context.Context, requestNames []string) (int, error) {
+	if len(requestNames) == 0 {
+		return 0, nil
+	}
+	revoked := 0
+	iter := redisClient.Scan(ctx, 0, requestShareTokenPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		name, err := redisClient.Get(ctx, iter.Val()).Result()
+		if err != nil || !slices.Contains(requestNames, name) {
+			continue
+		}
+		if err := redisClient.Del(ctx, iter.Val()).Err(); err == nil {
+			revoked++
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return revoked, fmt.Errorf("failed to list share links: %w", err)
+	}
+	return revoked, nil
+}
+
+// userRequestIDs returns the IDs of the requests whose accesses a user asked for, see requestedBy.
+func userRequestIDs(ctx context.Context, email, username string) ([]string, error) {
+	var accesses vtkiov1alpha1.AccessList
+	if err := k8s.ListNetwatchAccesses(ctx, &accesses); err != nil {
+		return nil, fmt.Errorf("failed to list accesses: %w", err)
+	}
+	var extAccesses vtkiov1alpha1.ExternalAccessList
+	if err := k8s.ListNetwatchExternalAccesses(ctx, &extAccesses); err != nil {
+		return nil, fmt.Errorf("failed to list external accesses: %w", err)
+	}
+
+	seen := make(map[string]bool)
+	var requestIDs []string
+	add := func(labels, annotations map[string]string) {
+		requestID := labels["netwatch.vtk.io/request-id"]
+		if !requestedBy(annotations[requestorAnnotation], labels["netwatch.vtk.io/user"], email, username) || requestID == "" || seen[requestID] {
+			return
+		}
+		seen[requestID] = true
+		requestIDs = append(requestIDs, requestID)
+	}
+	for _, access := range accesses.Items {
+		add(access.Labels, access.Annotations)
+	}
+	for _, access := range extAccesses.Items {
+		add(access.Labels, access.Annotations)
+	}
+	return requestIDs, nil
+}
+
+// OffboardUser cleans up after a user who no longer exists in the directory.
+// OffboardUser godoc
+// @Summary      Offboard a deleted user
+// @Description  Ends every login session of the user, revokes their calendar feed token, linked Slack identities, heartbeat tokens and share links, and deletes their pending AccessRequests, Accesses and ExternalAccesses. The offboarding watcher does the same for the users listed in NETWATCH_OFFBOARDED_USERS_FILE; this endpoint offboards a user on demand. Restricted to administrators.
+// @Tags         Admin
+// @Produce      json
+// @Param        email            path    string  true   "Email of the deleted user"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.OffboardResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /admin/users/{email}/offboard [post]
+func OffboardUser(c *gin.Context) {
+	email := c.Param("email")
+	if _, err := mail.ParseAddress(email); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid email address"})
+		return
+	}
+
+	result, err := offboardUser(c.Request.Context(), email)
+	if err != nil {
+		logger.Logger.Error("Failed to fully offboard user", "error", err, "user", email, "result", result)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Offboarding was incomplete, retry it: " + err.Error()})
+		return
+	}
+	logger.Logger.Info("Offboarded user", "user", email, "sessions", result.Sessions, "tokens", result.Tokens,
+		"requests", result.Requests, "accesses", result.Accesses)
+	c.JSON(http.StatusOK, result)
+}
+
+// StartOffboardingWatcher periodically offboards the users listed in a file maintained by the directory sync, e.g. a
+// mounted ConfigMap, once each. A user is offboarded again if they are removed from the file and listed later.
+func StartOffboardingWatcher(ctx context.Context, path string, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting offboarding watcher", "path", path, "interval", interval)
+	for {
+		select {
+		case <-ticker.C:
+			offboardListedUsers(ctx, path)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// offboardListedUsers offboards the users of the file not offboarded yet. Failed offboardings are retried on the
+// next pass.
+func offboardListedUsers(ctx context.Context, path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		logger.Logger.Error("Offboarding watcher failed to read the offboarded users", "error", err, "path", path)
+		return
+	}
+	users := parseOffboardedUsers(data)
+	done, err := redisClient.SMembers(ctx, offboardedUsersKey).Result()
+	if err != nil {
+		logger.Logger.Error("Offboarding watcher failed to list offboarded users", "error", err)
+		return
+	}
+
+	for _, email := range users {
+		if slices.Contains(done, email) {
+			continue
+		}
+		locked, err := redisClient.SetNX(ctx, offboardingLockPrefix+email, "1", 5*time.Minute).Result()
+		if err != nil || !locked {
+			continue
+		}
+		result, err := offboardUser(ctx, email)
+		if err != nil {
+			logger.Logger.Error("Failed to fully offboard user, retrying on the next pass", "error", err, "user", email, "result", result)
+			redisClient.Del(ctx, offboardingLockPrefix+email) //nolint:all
+			continue
+		}
+		redisClient.SAdd(ctx, offboardedUsersKey, email) //nolint:all
+		logger.Logger.Info("Offboarded user", "user", email, "sessions", result.Sessions, "tokens", result.Tokens,
+			"requests", result.Requests, "accesses", result.Accesses)
+	}
+	for _, email := range done {
+		if !slices.Contains(users, email) {
+			redisClient.SRem(ctx, offboardedUsersKey, email) //nolint:all
+		}
+	}
+}
+
+// parseOffboardedUsers reads one email address per line. Blank lines, comments starting with # and invalid
+// addresses are skipped.
+func parseOffboardedUsers(data []byte) []string {
+	var users []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := mail.ParseAddress(line); err != nil || strings.ContainsAny(line, "<> ") {
+			logger.Logger.Warn("Skipping an invalid address in the offboarded users", "line", line)
+			continue
+		}
+		if !slices.Contains(users, line) {
+			users = append(users, line)
+		}
+	}
+	return users
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseOffboardedUsers(t *testing.T) {
+	data := []byte(`# Maintained by the directory sync
+leaver@example.com
+
+  other.leaver@example.com  
+not-an-address
+Leaver Name <named@example.com>
+leaver@example.com
+# gone@example.com
+`)
+	got := parseOffboardedUsers(data)
+	want := []string{"leaver@example.com", "other.leaver@example.com"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -1,48 +1,43 @@
 package handlers
 
 import (
-	"context"
 	"crypto/rand"
 	"encoding/base64"
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/gin-gonic/gin"
 	"golang.org/x/oauth2"
 
+	"github.com/Banh-Canh/netwatch/internal/auth"
 	"github.com/Banh-Canh/netwatch/internal/utils/logger"
 )
 
 // This file contains all handlers and configuration related to the OIDC authentication flow.
 
 var (
-	oidcVerifier *oidc.IDTokenVerifier
-	oidcConfig   *oauth2.Config // This is a base config without a RedirectURL
-	sessionTTL   int
+	oidcConfig *oauth2.Config // This is a base config without a RedirectURL
+	sessionTTL int
 )
 
 type OIDCConfig struct {
-	IssuerURL    string
 	ClientID     string
 	ClientSecret string
 	SessionTTL   int
 }
 
-// InitOIDC initializes the OIDC provider and configuration for the handlers package.
+// InitOIDC initializes the login flow configuration for the handlers package. Tokens are verified by the
+// auth package, which must be initialized first.
 func InitOIDC(cfg OIDCConfig) error {
-	provider, err := oidc.NewProvider(context.Background(), cfg.IssuerURL)
-	if err != nil {
-		return fmt.Errorf("failed to get OIDC provider: %w", err)
+	if auth.Endpoint().AuthURL == "" {
+		return fmt.Errorf("failed to get OIDC provider: auth is not initialized")
 	}
-	oidcVerifier = provider.Verifier(&oidc.Config{ClientID: cfg.ClientID})
-
 	// Initialize the base config. The RedirectURL will be generated.
 	oidcConfig = &oauth2.Config{
 		ClientID:     cfg.ClientID,
 		ClientSecret: cfg.ClientSecret,
-		Endpoint:     provider.Endpoint(),
+		Endpoint:     auth.Endpoint(),
 		Scopes:       []string{oidc.ScopeOpenID, "profile", "email", "groups"},
 	}
 	sessionTTL = cfg.SessionTTL
@@ -53,24 +48,11 @@ func InitOIDC(cfg OIDCConfig) error {
 func HandleMainPage(version string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		session, _ := sessionStore.Get(c.Request, "auth-session")
-		idToken, _ := session.Values["id_token"].(string)
-		user, _ := session.Values["user"].(string)
-
-		var sessionExpiresAt int64
-		if exp, ok := session.Values["expires_at"].(int64); ok {
-			sessionExpiresAt = exp
-		} else {
-			sessionExpiresAt = time.Now().Add(time.Second * time.Duration(sessionTTL)).Unix()
+		// An idle session is cleared, which renders the login page.
+		if idToken, _ := session.Values["id_token"].(string); idToken != "" {
+			checkSessionActivity(c, session) //nolint:all
 		}
-
-		// Pass the version to the template here.
-		c.HTML(http.StatusOK, "layout.html", gin.H{
-			"IDToken":          idToken,
-			"User":             user,
-			"UserIP":           c.ClientIP(),
-			"SessionExpiresAt": sessionExpiresAt,
-			"Version":          version, // Add the version to the data map
-		})
+		c.HTML(http.StatusOK, "layout.html", sessionBootstrap(c, session, version))
 	}
 }
 
@@ -126,33 +108,33 @@ func HandleCallback(c *gin.Context) {
 		return
 	}
 
-	idToken, err := oidcVerifier.Verify(c.Request.Context(), rawIDToken)
+	identity, err := auth.Verify(c.Request.Context(), rawIDToken)
 	if err != nil {
 		http.Error(c.Writer, "Failed to verify ID Token: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	var claims struct {
-		Email string `json:"email"`
-	}
-	if err := idToken.Claims(&claims); err != nil {
-		http.Error(c.Writer, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
 	session.Values["id_token"] = rawIDToken
-	session.Values["user"] = claims.Email
+	session.Values["user"] = identity.Email
 	session.Options.MaxAge = sessionTTL
 	session.Save(c.Request, c.Writer) //nolint:all
+	touchSession(c.Request.Context(), session.ID)
+	indexUserSession(c.Request.Context(), identity.Email, session.ID)
 
-	logger.Logger.Info("User successfully authenticated", "user", claims.Email)
+	logger.Logger.Info("User successfully authenticated", "user", identity.Email)
 	http.Redirect(c.Writer, c.Request, "/", http.StatusFound)
 }
 
-// HandleLogout clears the user's session.
+// HandleLogout clears the user's session and revokes the accesses bound to it.
 func HandleLogout(c *gin.Context) {
 	session, err := sessionStore.Get(c.Request, "auth-session")
 	if err == nil {
+		if !session.IsNew {
+			checkSessionBound(c.Request.Context(), session.ID)
+			if user, ok := session.Values["user"].(string); ok && user != "" {
+				redisClient.SRem(c.Request.Context(), userSessionsPrefix+user, session.ID) //nolint:all
+			}
+		}
 		session.Values["id_token"] = ""
 		session.Values["user"] = ""
 		session.Options.MaxAge = -1       // Expire the session cookie immediately
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"fmt"
+	"slices"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// onBehalfVerb is the custom RBAC verb on accessrequests that lets a user file requests for someone else,
+// e.g. a manager for a contractor who has no Netwatch access yet. Admins may always do it.
+const onBehalfVerb = "file-on-behalf"
+
+// isAdmin reports whether a user belongs to one of the admin groups.
+func isAdmin(userInfo *k8s.UserInfo) bool {
+	return slices.ContainsFunc(userInfo.Groups, func(group string) bool { return slices.Contains(adminGroups, group) })
+}
+
+// mayFileOnBehalf reports, and rejects, a request filed for another user by someone not allowed to.
+func (p *webSocketCommandProcessor) mayFileOnBehalf(requestor string) bool {
+	if isAdmin(p.userInfo) {
+		return true
+	}
+	allowed, err := k8s.CanPerformAction(p.ctx, p.userInfo, onBehalfVerb, "netwatch.vtk.io", "accessrequests", "", "")
+	if err != nil {
+		p.sendError("Could not verify permissions for filing a request on behalf of another user", err, "Request")
+		return false
+	}
+	if !allowed {
+		p.sendError(fmt.Sprintf("Permission denied. Filing a request on behalf of %s requires the %q verb on accessrequests", requestor, onBehalfVerb),
+			nil, "Request")
+		return false
+	}
+	return true
+}
+
+// requestorLabel names the requestor of a request for the activity log, with who filed it on their behalf.
+func requestorLabel(spec netwatchv1alpha1.AccessRequestSpec) string {
+	if spec.FiledBy != "" {
+		return fmt.Sprintf("%s (filed by %s)", spec.Requestor, spec.FiledBy)
+	}
+	return spec.Requestor
+}
+
+// isRequestOwner reports whether a user is the requestor of a request or filed it for them.
+func isRequestOwner(email string, spec netwatchv1alpha1.AccessRequestSpec) bool {
+	return email == spec.Requestor || (spec.FiledBy != "" && email == spec.FiledBy)
+}
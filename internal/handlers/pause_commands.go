@@ -0,0 +1,288 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/redis/go-redis/v9"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// pausedAccessesKey is a Redis hash of paused accesses, keyed by request ID.
+const pausedAccessesKey = "netwatch:paused_accesses"
+
+// pausedAccess is the record kept while an access is paused. Its service clones stay in the cluster,
+// only the Access/ExternalAccess objects are deleted and recreated on resume.
+type pausedAccess struct {
+	RequestID string `json:"requestID"`
+	Type      string `json:"type"`
+	PausedBy  string `json:"pausedBy"`
+	PausedAt  int64  `json:"pausedAt"`
+	// Remaining is the duration left in seconds when the access was paused, -1 when it never expires.
+	Remaining        int64                          `json:"remaining"`
+	Source           string                         `json:"source"`
+	Target           string                         `json:"target"`
+	Ports            string                         `json:"ports"`
+	Accesses         []vtkiov1alpha1.Access         `json:"accesses,omitempty"`
+	ExternalAccesses []vtkiov1alpha1.ExternalAccess `json:"externalAccesses,omitempty"`
+}
+
+func (p *webSocketCommandProcessor) handlePauseAccess(payload webSocketPayload) {
+	logger.Logger.Info("WebSocket command received", "command", "pauseAccess", "name", payload.Name, "namespace", payload.Namespace, "user", p.userInfo.Email)
+	logType := "Service"
+	if payload.AccessType == "External" {
+		logType = "External"
+	}
+
+	userKubeClient, err := k8s.GetImpersonatingKubeClient(p.idToken)
+	if err != nil {
+		p.sendError("Could not create user-impersonating client for pause", err, logType)
+		return
+	}
+
+	record := pausedAccess{Type: logType, PausedBy: p.userInfo.Email, PausedAt: time.Now().Unix(), Remaining: -1}
+	var created time.Time
+	var duration string
+	var expiration *metav1.Time
+
+	if logType == "Service" {
+		access, err := k8s.GetAccessAsUser(p.ctx, userKubeClient, payload.Namespace, payload.Name)
+		if err != nil {
+			p.sendError("Could not find the specified access policy", err, logType)
+			return
+		}
+		record.RequestID = access.Labels["netwatch.vtk.io/request-id"]
+		if record.RequestID == "" {
+			p.sendError("Could not pause access: request-id label is missing.", nil, logType)
+			return
+		}
+		accesses, err := k8s.ListAllAccessesWithLabelAsApp(p.ctx, record.RequestID)
+		if err != nil {
+			p.sendError("Failed to find the full access policy pair", err, logType)
+			return
+		}
+		if len(accesses.Items) != 2 {
+			p.sendError("Only fully approved service accesses can be paused", nil, logType)
+			return
+		}
+		record.Accesses = accesses.Items
+		created, duration, expiration = access.CreationTimestamp.Time, access.Spec.Duration, access.Status.ExpirationTimestamp
+	} else {
+		access, err := k8s.GetExternalAccessAsApp(p.ctx, payload.Namespace, payload.Name)
+		if err != nil {
+			p.sendError("Could not find the specified external access policy", err, logType)
+			return
+		}
+		record.RequestID = access.Labels["netwatch.vtk.io/request-id"]
+		if record.RequestID == "" {
+			p.sendError("Could not pause access: request-id label is missing.", nil, logType)
+			return
+		}
+		record.ExternalAccesses = []vtkiov1alpha1.ExternalAccess{*access}
+		record.Source = strings.Join(access.Spec.TargetCIDRs, ", ")
+		created, duration, expiration = access.CreationTimestamp.Time, access.Spec.Duration, access.Status.ExpirationTimestamp
+	}
+
+	if expiresAt, ok := accessExpiry(created, duration, expiration); ok {
+		record.Remaining = int64(time.Until(expiresAt).Seconds())
+		if record.Remaining <= 0 {
+			p.sendError("This access has already expired", nil, logType)
+			return
+		}
+	}
+	p.describePausedAccess(&record)
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		p.sendError("Could not serialize the paused access", err, logType)
+		return
+	}
+	if err := redisClient.HSet(p.ctx, pausedAccessesKey, record.RequestID, recordJSON).Err(); err != nil {
+		p.sendError("Could not save the paused access", err, logType)
+		return
+	}
+
+	// Mark the objects first so the cleanup controller keeps the clones, then delete them with the user's permissions.
+	var deletionErrors []string
+	for i := range record.Accesses {
+		access := &record.Accesses[i]
+		if err := k8s.MarkPausedAsApp(p.ctx, access); err != nil {
+			deletionErrors = append(deletionErrors, fmt.Sprintf("failed to mark %s/%s as paused: %v", access.Namespace, access.Name, err))
+			continue
+		}
+		if err := k8s.DeleteAccess(p.ctx, userKubeClient, access.Namespace, access.Name); err != nil && !k8s.IsNotFound(err) {
+			deletionErrors = append(deletionErrors, fmt.Sprintf("failed to delete %s/%s: %v", access.Namespace, access.Name, err))
+		}
+	}
+	for i := range record.ExternalAccesses {
+		access := &record.ExternalAccesses[i]
+		if err := k8s.MarkPausedAsApp(p.ctx, access); err != nil {
+			deletionErrors = append(deletionErrors, fmt.Sprintf("failed to mark %s/%s as paused: %v", access.Namespace, access.Name, err))
+			continue
+		}
+		if err := k8s.DeleteExternalAccess(p.ctx, userKubeClient, access.Namespace, access.Name); err != nil && !k8s.IsNotFound(err) {
+			deletionErrors = append(deletionErrors, fmt.Sprintf("failed to delete %s/%s: %v", access.Namespace, access.Name, err))
+		}
+	}
+	if len(deletionErrors) > 0 {
+		redisClient.HDel(p.ctx, pausedAccessesKey, record.RequestID) //nolint:all
+		p.sendError("Encountered errors while pausing the access", fmt.Errorf("%s", strings.Join(deletionErrors, "; ")), logType)
+		return
+	}
+
+	msg := fmt.Sprintf("SUCCESS: Access with request-id '%s' has been paused by %s.", record.RequestID, p.userInfo.Email)
+	p.logAndBroadcast(LogEntry{Payload: msg, ClassName: "log-success", LogType: logType, Type: "applyResult"})
+}
+
+func (p *webSocketCommandProcessor) handleResumeAccess(payload webSocketPayload) {
+	logger.Logger.Info("WebSocket command received", "command", "resumeAccess", "requestID", payload.RequestID, "user", p.userInfo.Email)
+
+	record, err := getPausedAccess(p.ctx, payload.RequestID)
+	if err != nil {
+		p.sendError("Could not find the paused access", err, "Service")
+		return
+	}
+
+	userKubeClient, err := k8s.GetImpersonatingKubeClient(p.idToken)
+	if err != nil {
+		p.sendError("Could not create user-impersonating client for resume", err, record.Type)
+		return
+	}
+
+	var durationStr string
+	if record.Remaining > 0 {
+		durationStr = fmt.Sprintf("%ds", record.Remaining)
+	}
+
+	for _, paused := range record.Accesses {
+		access := &vtkiov1alpha1.Access{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        paused.Name,
+				Namespace:   paused.Namespace,
+				Labels:      paused.Labels,
+				Annotations: copyProvenance(paused.Annotations),
+			},
+			Spec: paused.Spec,
+		}
+		access.Spec.Duration = durationStr
+		// A previous, partially failed resume may already have recreated this side.
+		if err := k8s.CreateAccess(p.ctx, userKubeClient, access); err != nil && !k8s.IsAlreadyExists(err) {
+			p.sendError(fmt.Sprintf("Failed to recreate access %s/%s", paused.Namespace, paused.Name), err, record.Type)
+			return
+		}
+	}
+	for _, paused := range record.ExternalAccesses {
+		access := &vtkiov1alpha1.ExternalAccess{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        paused.Name,
+				Namespace:   paused.Namespace,
+				Labels:      paused.Labels,
+				Annotations: copyProvenance(paused.Annotations),
+			},
+			Spec: paused.Spec,
+		}
+		access.Spec.Duration = durationStr
+		if err := k8s.CreateExternalAccess(p.ctx, userKubeClient, access); err != nil && !k8s.IsAlreadyExists(err) {
+			p.sendError(fmt.Sprintf("Failed to recreate external access %s/%s", paused.Namespace, paused.Name), err, record.Type)
+			return
+		}
+	}
+
+	if err := redisClient.HDel(p.ctx, pausedAccessesKey, record.RequestID).Err(); err != nil {
+		logger.Logger.Error("Failed to delete paused access record", "error", err, "requestID", record.RequestID)
+	}
+	msg := fmt.Sprintf("SUCCESS: Access with request-id '%s' has been resumed by %s.", record.RequestID, p.userInfo.Email)
+	p.logAndBroadcast(LogEntry{Payload: msg, ClassName: "log-success", LogType: record.Type, Type: "applyResult"})
+}
+
+// describePausedAccess fills the display fields of a paused access from its service clones, which outlive the pause.
+func (p *webSocketCommandProcessor) describePausedAccess(record *pausedAccess) {
+	services, err := k8s.ListAllServices(p.ctx)
+	if err != nil {
+		logger.Logger.Warn("Could not list services to describe paused access", "error", err)
+		return
+	}
+	var targetClone string
+	if len(record.Accesses) > 0 && len(record.Accesses[0].Spec.Targets) > 0 {
+		targetClone = record.Accesses[0].Spec.Targets[0].ServiceName
+	}
+	for _, svc := range services.Items {
+		if svc.Labels["netwatch.vtk.io/request-id"] != record.RequestID {
+			continue
+		}
+		clonedFrom := svc.Annotations["netwatch.vtk.io/cloned-from"]
+		switch {
+		case record.Type == "External":
+			record.Target = clonedFrom
+			record.Ports = svc.Annotations["netwatch.vtk.io/ports"]
+		case svc.Name == targetClone:
+			record.Target = clonedFrom
+		default:
+			record.Source = clonedFrom
+			record.Ports = svc.Annotations["netwatch.vtk.io/ports"]
+		}
+	}
+}
+
+func getPausedAccess(ctx context.Context, requestID string) (*pausedAccess, error) {
+	recordJSON, err := redisClient.HGet(ctx, pausedAccessesKey, requestID).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, fmt.Errorf("no paused access with request-id '%s'", requestID)
+		}
+		return nil, err
+	}
+	var record pausedAccess
+	if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+// listPausedAccesses returns the paused accesses in the format of the active access list.
+func listPausedAccesses(ctx context.Context) []ActiveAccessInfo {
+	records, err := redisClient.HGetAll(ctx, pausedAccessesKey).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to list paused accesses", "error", err)
+		return nil
+	}
+	var infos []ActiveAccessInfo
+	for _, recordJSON := range records {
+		var record pausedAccess
+		if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+			logger.Logger.Warn("Failed to unmarshal a paused access record", "error", err)
+			continue
+		}
+		info := ActiveAccessInfo{
+			Type:      record.Type,
+			RequestID: record.RequestID,
+			Source:    record.Source,
+			Target:    record.Target,
+			Ports:     record.Ports,
+			ExpiresAt: -1,
+			Remaining: record.Remaining,
+			Status:    "Paused",
+		}
+		if len(record.Accesses) > 0 {
+			info.Name, info.Namespace, info.Direction = record.Accesses[0].Name, record.Accesses[0].Namespace, record.Accesses[0].Spec.Direction
+			info.Owner = record.Accesses[0].Labels["netwatch.vtk.io/user"]
+			info.Requestor, info.ApprovedBy = record.Accesses[0].Annotations[requestorAnnotation], record.Accesses[0].Annotations[approvedByAnnotation]
+			info.CreatedAt = record.Accesses[0].CreationTimestamp.Unix()
+		} else if len(record.ExternalAccesses) > 0 {
+			ea := record.ExternalAccesses[0]
+			info.Name, info.Namespace, info.Direction = ea.Name, ea.Namespace, ea.Spec.Direction
+			info.Owner = ea.Labels["netwatch.vtk.io/user"]
+			info.Requestor, info.ApprovedBy = ea.Annotations[requestorAnnotation], ea.Annotations[approvedByAnnotation]
+			info.CreatedAt = ea.CreationTimestamp.Unix()
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
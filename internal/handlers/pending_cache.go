@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// selfApprovalMaxAge bounds how long a permission check result is reused, so RBAC changes are eventually picked up.
+const selfApprovalMaxAge = 5 * time.Minute
+
+var (
+	pendingRequestsTTL = 5 * time.Second
+
+	requestListMu        sync.Mutex
+	requestListItems     []netwatchv1alpha1.AccessRequest
+	requestListFetchedAt time.Time
+
+	selfApprovalMu    sync.Mutex
+	selfApprovalCache = make(map[string]selfApprovalEntry)
+)
+
+type selfApprovalEntry struct {
+	allowed   bool
+	checkedAt time.Time
+}
+
+// SetPendingRequestsCacheTTL sets how long the AccessRequest list is reused across polls. Zero disables the cache.
+func SetPendingRequestsCacheTTL(ttl time.Duration) {
+	pendingRequestsTTL = ttl
+}
+
+// SelfApprovalCacheSize returns the number of cached permission check results.
+func SelfApprovalCacheSize() int {
+	selfApprovalMu.Lock()
+	defer selfApprovalMu.Unlock()
+	return len(selfApprovalCache)
+}
+
+// cachedAccessRequests returns the AccessRequests matching the label filter, listing them from the cluster
+// at most once per TTL for all connected users.
+func cachedAccessRequests(ctx context.Context, filter client.MatchingLabels) ([]netwatchv1alpha1.AccessRequest, error) {
+	requestListMu.Lock()
+	defer requestListMu.Unlock()
+
+	if requestListItems == nil || time.Since(requestListFetchedAt) >= pendingRequestsTTL {
+		requestList, err := k8s.ListAccessRequestsAsApp(ctx)
+		if err != nil {
+			return nil, err
+		}
+		requestListItems = requestList.Items
+		requestListFetchedAt = time.Now()
+		pruneSelfApprovalCache(requestListItems)
+	}
+
+	selector := labels.SelectorFromSet(labels.Set(filter))
+	items := make([]netwatchv1alpha1.AccessRequest, 0, len(requestListItems))
+	for _, request := range requestListItems {
+		if selector.Matches(labels.Set(request.Labels)) {
+			items = append(items, request)
+		}
+	}
+	return items, nil
+}
+
+// invalidateAccessRequestCache forces the next poll to list the AccessRequests again. It is called whenever
+// netwatch itself creates or deletes a request, so users see their own changes immediately.
+func invalidateAccessRequestCache() {
+	requestListMu.Lock()
+	defer requestListMu.Unlock()
+	requestListItems = nil
+}
+
+// cachedCanSelfApprove runs the self-approval permission checks of a request for a user, reusing the previous
+// result as long as neither the request nor the user's groups changed. A result checked before the user's token
+// was issued is not reused either, so a token refresh picks up group membership changes right away.
+func cachedCanSelfApprove(ctx context.Context, userInfo *k8s.UserInfo, request *netwatchv1alpha1.AccessRequest, perms []k8s.PermissionRequest) (bool, error) {
+	key := selfApprovalKey(userInfo, request)
+
+	selfApprovalMu.Lock()
+	entry, ok := selfApprovalCache[key]
+	selfApprovalMu.Unlock()
+	if ok && time.Since(entry.checkedAt) < selfApprovalMaxAge && !entry.checkedAt.Before(tokenIssuedAt(userInfo)) {
+		return entry.allowed, nil
+	}
+
+	allowed, err := k8s.CanPerformAllActions(ctx, userInfo, perms)
+	if err != nil {
+		return false, err
+	}
+	selfApprovalMu.Lock()
+	selfApprovalCache[key] = selfApprovalEntry{allowed: allowed, checkedAt: time.Now()}
+	selfApprovalMu.Unlock()
+	return allowed, nil
+}
+
+// selfApprovalKey identifies a result by the request version and the user's identity: the email and the hash of
+// the sorted group set, since RBAC bindings to groups matter as much as the ones to the user.
+func selfApprovalKey(userInfo *k8s.UserInfo, request *netwatchv1alpha1.AccessRequest) string {
+	groups := slices.Clone(userInfo.Groups)
+	slices.Sort(groups)
+	groups = slices.Compact(groups)
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{userInfo.Email}, groups...), "\x00")))
+	return request.Name + "/" + request.ResourceVersion + "/" + hex.EncodeToString(sum[:])
+}
+
+// tokenIssuedAt returns when the user's token was issued, or the zero time when it has no iat claim.
+func tokenIssuedAt(userInfo *k8s.UserInfo) time.Time {
+	if iat, ok := userInfo.Claims["iat"].(float64); ok {
+		return time.Unix(int64(iat), 0)
+	}
+	return time.Time{}
+}
+
+// pruneSelfApprovalCache drops the results of requests that no longer exist or changed since they were checked.
+func pruneSelfApprovalCache(current []netwatchv1alpha1.AccessRequest) {
+	valid := make(map[string]bool, len(current))
+	for _, request := range current {
+		valid[request.Name+"/"+request.ResourceVersion+"/"] = true
+	}
+	selfApprovalMu.Lock()
+	defer selfApprovalMu.Unlock()
+	for key, entry := range selfApprovalCache {
+		prefix := key[:strings.LastIndex(key, "/")+1]
+		if !valid[prefix] || time.Since(entry.checkedAt) >= selfApprovalMaxAge {
+			delete(selfApprovalCache, key)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// primeAccessRequestCache fills the AccessRequest list cache with n pending requests, as if just listed.
+func primeAccessRequestCache(b *testing.B, n int) {
+	b.Helper()
+	items := make([]netwatchv1alpha1.AccessRequest, n)
+	for i := range items {
+		team := "platform"
+		if i%4 == 0 {
+			team = "payments"
+		}
+		items[i] = netwatchv1alpha1.AccessRequest{ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("request-%d", i),
+			ResourceVersion: "1",
+			Labels:          map[string]string{"team": team},
+		}}
+	}
+	previousTTL := pendingRequestsTTL
+	pendingRequestsTTL = time.Hour
+	requestListMu.Lock()
+	requestListItems, requestListFetchedAt = items, time.Now()
+	requestListMu.Unlock()
+	b.Cleanup(func() {
+		pendingRequestsTTL = previousTTL
+		invalidateAccessRequestCache()
+	})
+}
+
+func BenchmarkCachedAccessRequests(b *testing.B) {
+	for _, n := range []int{100, 1000} {
+		b.Run(fmt.Sprintf("requests=%d", n), func(b *testing.B) {
+			primeAccessRequestCache(b, n)
+			filter := client.MatchingLabels{"team": "payments"}
+			b.ResetTimer()
+			for b.Loop() {
+				if _, err := cachedAccessRequests(context.Background(), filter); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkCachedCanSelfApprove(b *testing.B) {
+	userInfo := &k8s.UserInfo{Email: "jane@example.com", Groups: []string{"platform", "payments", "oncall"}}
+	request := &netwatchv1alpha1.AccessRequest{ObjectMeta: metav1.ObjectMeta{Name: "request-1", ResourceVersion: "1"}}
+	selfApprovalMu.Lock()
+	selfApprovalCache[selfApprovalKey(userInfo, request)] = selfApprovalEntry{allowed: true, checkedAt: time.Now()}
+	selfApprovalMu.Unlock()
+	b.Cleanup(func() { pruneSelfApprovalCache(nil) })
+
+	for b.Loop() {
+		if _, err := cachedCanSelfApprove(context.Background(), userInfo, request, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
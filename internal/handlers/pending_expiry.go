@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// pendingExpiredReason is the reason of the Events the cleanup controller records when it deletes a request
+	// nobody reviewed within NETWATCH_PENDING_EXPIRY_SECONDS. Events of cluster-scoped objects live in "default".
+	pendingExpiredReason = "PendingExpired"
+	// expiryNotifiedPrefix marks the Events already relayed, so each expiry is announced once across replicas.
+	// Kubernetes keeps Events for an hour by default.
+	expiryNotifiedPrefix = "netwatch:pending_expiry_notified:"
+	expiryNotifiedTTL    = 3 * time.Hour
+	// accessExpiredReason is the reason of the Events the cleanup controller records when an access is deleted once
+	// expired, in the namespace of the access.
+	accessExpiredReason = "AccessExpired"
+)
+
+// StartExpiryNotifier relays the expiries recorded by the cleanup controller: pending requests nobody reviewed in
+// time, to the activity log and their requestors, and accesses, to the notification targets.
+func StartExpiryNotifier(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			notifyExpiredRequests(ctx)
+			notifyExpiredAccesses(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func notifyExpiredRequests(ctx context.Context) {
+	events, err := k8s.ListEventsAsApp(ctx, metav1.NamespaceDefault, pendingExpiredReason)
+	if err != nil {
+		logger.Logger.Error("Failed to list expired request events", "error", err)
+		return
+	}
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind != "AccessRequest" {
+			continue
+		}
+		first, err := redisClient.SetNX(ctx, expiryNotifiedPrefix+string(event.UID), 1, expiryNotifiedTTL).Result()
+		if err != nil || !first {
+			continue
+		}
+		requestor := event.Annotations["netwatch.vtk.io/requestor"]
+		requestID := event.Annotations["netwatch.vtk.io/request-id"]
+		var namespaces []string
+		if joined := event.Annotations["netwatch.vtk.io/namespaces"]; joined != "" {
+			namespaces = strings.Split(joined, ",")
+		}
+		recordRequestExpiry(ctx, event.InvolvedObject.Name)
+		invalidateAccessRequestCache()
+		logger.Logger.Info("Pending access request expired", "request", event.InvolvedObject.Name, "requestor", requestor)
+		persistLogEntry(LogEntry{
+			Payload: fmt.Sprintf("EXPIRED: Access request %s from %s was deleted, nobody reviewed it in time. Submit it again if it is still needed.",
+				requestID, requestor),
+			ClassName: "log-warning",
+			LogType:   "Request",
+			Type:      "applyResult",
+			Requestor: requestor,
+		})
+		notifyLifecycleEvent(LifecycleEvent{
+			Type:       "request.expired",
+			Request:    event.InvolvedObject.Name,
+			RequestID:  requestID,
+			Requestor:  requestor,
+			Namespaces: namespaces,
+			TicketRef:  event.Annotations["netwatch.vtk.io/ticket-ref"],
+		})
+	}
+}
+
+func notifyExpiredAccesses(ctx context.Context) {
+	events, err := k8s.ListEventsAsApp(ctx, metav1.NamespaceAll, accessExpiredReason)
+	if err != nil {
+		logger.Logger.Error("Failed to list expired access events", "error", err)
+		return
+	}
+	for _, event := range events.Items {
+		if event.InvolvedObject.Kind != "Access" && event.InvolvedObject.Kind != "ExternalAccess" {
+			continue
+		}
+		first, err := redisClient.SetNX(ctx, expiryNotifiedPrefix+string(event.UID), 1, expiryNotifiedTTL).Result()
+		if err != nil || !first {
+			continue
+		}
+		notifyLifecycleEvent(newAccessEvent("access.expired", event.Annotations["netwatch.vtk.io/request-id"],
+			event.Annotations["netwatch.vtk.io/user"], "", []string{event.InvolvedObject.Namespace + "/" + event.InvolvedObject.Name}))
+	}
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// PermissionCheck is a permission needed to create an access directly, and whether the user has it.
+type PermissionCheck struct {
+	Verb      string `json:"verb" example:"create"`
+	Group     string `json:"group" example:"maxtac.vtk.io"`
+	Resource  string `json:"resource" example:"accesses"`
+	Namespace string `json:"namespace" example:"backend"`
+	Allowed   bool   `json:"allowed" example:"false"`
+}
+
+// PermissionExplanation tells which permissions creating an access directly needs, and which ones the user lacks.
+type PermissionExplanation struct {
+	// Allowed is true when the user has every permission, and can create the access without review.
+	Allowed     bool              `json:"allowed" example:"false"`
+	Permissions []PermissionCheck `json:"permissions"`
+}
+
+// ExplainPermissions checks each permission needed to create an access directly.
+// ExplainPermissions godoc
+// @Summary      Explain the permissions needed for an access
+// @Description  Lists every RBAC permission creating the access directly requires, the same ones the UI checks, with whether the current user has each of them. Set source and target for a service-to-service access, or service for an external one. Missing permissions are what an approver has to provide, or what to ask the cluster administrators for.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        source   query     string  false  "Source service, as namespace/name"
+// @Param        target   query     string  false  "Target service, as namespace/name"
+// @Param        service  query     string  false  "Service of an external access, as namespace/name"
+// @Success      200  {object}  handlers.PermissionExplanation
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /permissions/explain [get]
+func ExplainPermissions(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	var spec netwatchv1alpha1.AccessRequestSpec
+	switch source, target, service := c.Query("source"), c.Query("target"), c.Query("service"); {
+	case source != "" && target != "" && service == "":
+		spec = netwatchv1alpha1.AccessRequestSpec{RequestType: "Service", SourceService: source, TargetService: target}
+	case service != "" && source == "" && target == "":
+		spec = netwatchv1alpha1.AccessRequestSpec{RequestType: "External", Service: service}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set 'source' and 'target', or 'service'"})
+		return
+	}
+	for _, name := range []string{spec.SourceService, spec.TargetService, spec.Service} {
+		if name != "" && len(strings.Split(name, "/")) != 2 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service format, expected 'namespace/name'"})
+			return
+		}
+	}
+
+	results, err := k8s.CheckAllActions(ctx, userInfo, approvalPermissions(spec))
+	if err != nil {
+		logger.Logger.Error("Failed to check permissions", "error", err, "user", userInfo.Email)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check your permissions"})
+		return
+	}
+
+	explanation := PermissionExplanation{Allowed: true, Permissions: make([]PermissionCheck, 0, len(results))}
+	for _, result := range results {
+		explanation.Allowed = explanation.Allowed && result.Allowed
+		explanation.Permissions = append(explanation.Permissions, PermissionCheck{
+			Verb:      result.Verb,
+			Group:     result.Group,
+			Resource:  result.Resource,
+			Namespace: result.Namespace,
+			Allowed:   result.Allowed,
+		})
+	}
+	c.JSON(http.StatusOK, explanation)
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// PreApprovalInput is the body of a pre-approval creation.
+type PreApprovalInput struct {
+	Description   string    `json:"description"`
+	Users         []string  `json:"users"`
+	Groups        []string  `json:"groups"`
+	RequestType   string    `json:"requestType" example:"Service"`
+	SourceService string    `json:"sourceService" example:"team-x/*"`
+	TargetService string    `json:"targetService" example:"prod/db"`
+	Service       string    `json:"service"`
+	Start         time.Time `json:"start" example:"2026-10-17T02:00:00Z"`
+	End           time.Time `json:"end" example:"2026-10-17T06:00:00Z"`
+}
+
+// PreApprovalInfo is a pre-approval as returned by the API.
+type PreApprovalInfo struct {
+	Name string                           `json:"name"`
+	Spec netwatchv1alpha1.PreApprovalSpec `json:"spec"`
+}
+
+// validServicePattern reports whether a pre-approval service is "namespace/name" or "namespace/*".
+func validServicePattern(pattern string) bool {
+	ns, name, ok := strings.Cut(pattern, "/")
+	return ok && ns != "" && name != "" && !strings.Contains(name, "/")
+}
+
+// servicePatternMatches matches a "namespace/name" service against a "namespace/name" or "namespace/*" pattern.
+func servicePatternMatches(pattern, service string) bool {
+	patternNs, patternName, _ := strings.Cut(pattern, "/")
+	ns, name, ok := strings.Cut(service, "/")
+	return ok && ns == patternNs && (patternName == "*" || patternName == name)
+}
+
+// preApprovalSpecAsRequest describes a pre-approval as a request, to reuse the approval permission checks.
+func preApprovalSpecAsRequest(spec netwatchv1alpha1.PreApprovalSpec) netwatchv1alpha1.AccessRequestSpec {
+	return netwatchv1alpha1.AccessRequestSpec{
+		RequestType:   spec.RequestType,
+		SourceService: spec.SourceService,
+		TargetService: spec.TargetService,
+		Service:       spec.Service,
+	}
+}
+
+// findPreApproval returns an active pre-approval covering a request of userInfo, if any.
+func findPreApproval(ctx context.Context, userInfo *k8s.UserInfo, spec netwatchv1alpha1.AccessRequestSpec) *netwatchv1alpha1.PreApproval {
+	list, err := k8s.ListPreApprovalsAsApp(ctx)
+	if err != nil {
+		logger.Logger.Warn("Failed to list pre-approvals", "error", err)
+		return nil
+	}
+	now := time.Now()
+	for i := range list.Items {
+		pa := &list.Items[i]
+		if now.Before(pa.Spec.Start.Time) || !now.Before(pa.Spec.End.Time) || pa.Spec.RequestType != spec.RequestType {
+			continue
+		}
+		// The access must be time-bound and end within the window.
+		if spec.Duration <= 0 || now.Add(time.Duration(spec.Duration)*time.Second).After(pa.Spec.End.Time) {
+			continue
+		}
+		member := slices.Contains(pa.Spec.Users, userInfo.Email) || slices.ContainsFunc(userInfo.Groups, func(g string) bool {
+			return slices.Contains(pa.Spec.Groups, g)
+		})
+		if !member {
+			continue
+		}
+		switch spec.RequestType {
+		case "Service":
+			if servicePatternMatches(pa.Spec.SourceService, spec.SourceService) && servicePatternMatches(pa.Spec.TargetService, spec.TargetService) {
+				return pa
+			}
+		case "External":
+			if servicePatternMatches(pa.Spec.Service, spec.Service) {
+				return pa
+			}
+		}
+	}
+	return nil
+}
+
+// CreatePreApproval pre-approves matching access requests for a maintenance window.
+// CreatePreApproval godoc
+// @Summary      Pre-approve access for a maintenance window
+// @Description  Creates a PreApproval: requests submitted by the listed users or groups during the window that match the services are approved automatically, on behalf of the caller. The caller must be able to approve such requests.
+// @Tags         Access Requests
+// @Accept       json
+// @Produce      json
+// @Param        preApproval      body    PreApprovalInput  true   "Pre-approval window"
+// @Param        Idempotency-Key  header  string            false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  handlers.PreApprovalInfo
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pre-approvals [post]
+func CreatePreApproval(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	var input PreApprovalInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pre-approval: " + err.Error()})
+		return
+	}
+	if err := validatePreApproval(input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	spec := netwatchv1alpha1.PreApprovalSpec{
+		Description:    input.Description,
+		Approver:       userInfo.Email,
+		ApproverGroups: userInfo.Groups,
+		Users:          input.Users,
+		Groups:         input.Groups,
+		RequestType:    input.RequestType,
+		SourceService:  input.SourceService,
+		TargetService:  input.TargetService,
+		Service:        input.Service,
+		Start:          metav1.NewTime(input.Start),
+		End:            metav1.NewTime(input.End),
+	}
+	allowed, err := k8s.CanPerformAllActions(ctx, userInfo, approvalPermissions(preApprovalSpecAsRequest(spec)))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not verify permissions: " + err.Error()})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only pre-approve accesses you could approve yourself"})
+		return
+	}
+
+	preApproval := &netwatchv1alpha1.PreApproval{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "pa-",
+			Labels:       map[string]string{"app.kubernetes.io/managed-by": "netwatch"},
+		},
+		Spec: spec,
+	}
+	if err := k8s.CreatePreApprovalAsApp(ctx, preApproval); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pre-approval: " + err.Error()})
+		return
+	}
+	persistLogEntry(LogEntry{
+		Payload: fmt.Sprintf("PRE-APPROVAL: %s pre-approved %s access (%s) from %s to %s.",
+			userInfo.Email, spec.RequestType, preApproval.Name, spec.Start.UTC().Format(time.RFC3339), spec.End.UTC().Format(time.RFC3339)),
+		ClassName: "log-info", LogType: "Request", Type: "applyResult",
+	})
+	c.JSON(http.StatusCreated, PreApprovalInfo{Name: preApproval.Name, Spec: preApproval.Spec})
+}
+
+func validatePreApproval(input PreApprovalInput) error {
+	if len(input.Users) == 0 && len(input.Groups) == 0 {
+		return fmt.Errorf("at least one user or group is required")
+	}
+	if !input.End.After(input.Start) || !input.End.After(time.Now()) {
+		return fmt.Errorf("the window must end after it starts, in the future")
+	}
+	switch input.RequestType {
+	case "Service":
+		if !validServicePattern(input.SourceService) || !validServicePattern(input.TargetService) {
+			return fmt.Errorf("sourceService and targetService must be 'namespace/name' or 'namespace/*'")
+		}
+	case "External":
+		if !validServicePattern(input.Service) {
+			return fmt.Errorf("service must be 'namespace/name' or 'namespace/*'")
+		}
+	default:
+		return fmt.Errorf("requestType must be 'Service' or 'External'")
+	}
+	return nil
+}
+
+// ListPreApprovals lists the pre-approvals that have not ended yet.
+// ListPreApprovals godoc
+// @Summary      List pre-approvals
+// @Description  Lists the pre-approval windows that have not ended yet.
+// @Tags         Access Requests
+// @Produce      json
+// @Success      200  {array}   handlers.PreApprovalInfo
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pre-approvals [get]
+func ListPreApprovals(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := k8s.GetUserInfoFromToken(ctx, idToken); err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+	list, err := k8s.ListPreApprovalsAsApp(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pre-approvals: " + err.Error()})
+		return
+	}
+	result := []PreApprovalInfo{}
+	for _, pa := range list.Items {
+		if pa.Spec.End.After(time.Now()) {
+			result = append(result, PreApprovalInfo{Name: pa.Name, Spec: pa.Spec})
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// DeletePreApproval withdraws a pre-approval.
+// DeletePreApproval godoc
+// @Summary      Withdraw a pre-approval
+// @Description  Deletes a pre-approval. Allowed for its approver and anyone who could approve the requests it covers. Accesses already approved are kept.
+// @Tags         Access Requests
+// @Param        name  path  string  true  "Pre-approval name"
+// @Success      204
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pre-approvals/{name} [delete]
+func DeletePreApproval(c *gin.Context) {
+	ctx := c.Request.Context()
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+	list, err := k8s.ListPreApprovalsAsApp(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list pre-approvals: " + err.Error()})
+		return
+	}
+	idx := slices.IndexFunc(list.Items, func(pa netwatchv1alpha1.PreApproval) bool { return pa.Name == c.Param("name") })
+	if idx < 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pre-approval not found"})
+		return
+	}
+	pa := list.Items[idx]
+	if pa.Spec.Approver != userInfo.Email {
+		allowed, err := k8s.CanPerformAllActions(ctx, userInfo, approvalPermissions(preApprovalSpecAsRequest(pa.Spec)))
+		if err != nil || !allowed {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Only approvers can withdraw this pre-approval"})
+			return
+		}
+	}
+	if err := k8s.DeletePreApprovalAsApp(ctx, pa.Name); err != nil && !k8s.IsNotFound(err) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pre-approval: " + err.Error()})
+		return
+	}
+	persistLogEntry(LogEntry{
+		Payload:   fmt.Sprintf("PRE-APPROVAL: %s withdrew pre-approval %s.", userInfo.Email, pa.Name),
+		ClassName: "log-info", LogType: "Request", Type: "applyResult",
+	})
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReachabilityPeer is an active access between a service and one of its peers.
+type ReachabilityPeer struct {
+	// Peer is the other service, as namespace/name, or the CIDRs of an external access.
+	Peer string `json:"peer" example:"frontend/web"`
+	Type string `json:"type" example:"Service"`
+	// Role is the side of the access the queried service is on: "source" or "target".
+	Role      string `json:"role" example:"target"`
+	Direction string `json:"direction" example:"all"`
+	Ports     string `json:"ports" example:"8080"`
+	// ExpiresAt is -1 when the access never expires.
+	ExpiresAt int64  `json:"expiresAt" example:"1718003600"`
+	Owner     string `json:"owner,omitempty" example:"jane.doe-example.com"`
+	// Name and Namespace identify the Access or ExternalAccess object.
+	Name      string `json:"name" example:"access-nc-1a2b3c4d5e6f-30623766"`
+	Namespace string `json:"namespace" example:"backend"`
+}
+
+// ServiceReachability lists what can reach a service, and what it can reach, through Netwatch.
+type ServiceReachability struct {
+	Service string             `json:"service" example:"backend/api"`
+	Peers   []ReachabilityPeer `json:"peers"`
+}
+
+// GetServiceReachability lists the active accesses touching a service.
+// GetServiceReachability godoc
+// @Summary      List who can reach a service
+// @Description  Aggregates the active Access and ExternalAccess objects managed by Netwatch that touch the service, as source or target, with their peers, directions, ports and expiry. Pending and paused accesses are left out, as they do not open anything.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace  path      string  true  "Namespace of the service"
+// @Param        name       path      string  true  "Name of the service"
+// @Success      200  {object}  handlers.ServiceReachability
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /services/{namespace}/{name}/reachability [get]
+func GetServiceReachability(c *gin.Context) {
+	service := c.Param("namespace") + "/" + c.Param("name")
+	reachability := ServiceReachability{Service: service, Peers: []ReachabilityPeer{}}
+
+	for _, info := range listActiveAccesses(c.Request.Context(), activeAccessFilter{Status: "Active"}) {
+		peer := ReachabilityPeer{
+			Type:      info.Type,
+			Direction: info.Direction,
+			Ports:     info.Ports,
+			ExpiresAt: info.ExpiresAt,
+			Owner:     info.Owner,
+			Name:      info.Name,
+			Namespace: info.Namespace,
+		}
+		switch service {
+		case info.Source:
+			peer.Peer, peer.Role = info.Target, "source"
+		case info.Target:
+			peer.Peer, peer.Role = info.Source, "target"
+		default:
+			continue
+		}
+		reachability.Peers = append(reachability.Peers, peer)
+	}
+
+	c.JSON(http.StatusOK, reachability)
+}
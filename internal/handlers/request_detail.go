@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// sensitivePorts are ports whose exposure approvers should look at twice.
+var sensitivePorts = []int{22, 23, 3389, 2379, 3306, 5432, 6379, 9200, 27017}
+
+// AccessRequestDetail is everything the approval drawer shows for a single request.
+// Request, Sides, Effect and Risk are empty once the request has been approved or denied.
+type AccessRequestDetail struct {
+	Request    *AccessRequestPayload `json:"request,omitempty"`
+	Sides      []RequestSideState    `json:"sides,omitempty"`
+	Effect     []string              `json:"effect,omitempty"`
+	Risk       *RequestRisk          `json:"risk,omitempty"`
+	Submission *RequestSubmission    `json:"submission,omitempty"`
+	Timing     *RequestTiming        `json:"timing,omitempty"`
+}
+
+// RequestSideState tells which resources of one side of a request already exist in the cluster.
+type RequestSideState struct {
+	Side         string `json:"side"`
+	Namespace    string `json:"namespace"`
+	Service      string `json:"service"`
+	CloneName    string `json:"cloneName,omitempty"`
+	CloneExists  bool   `json:"cloneExists"`
+	AccessName   string `json:"accessName,omitempty"`
+	AccessExists bool   `json:"accessExists"`
+	// Direction is what the side's Access or ExternalAccess enforces, as derived from the request's direction.
+	// It is empty when the request's direction is invalid and approval would be refused.
+	Direction string `json:"direction,omitempty"`
+}
+
+// RequestRisk is a coarse score from 0 to 100 helping approvers prioritise their review.
+type RequestRisk struct {
+	Score   int      `json:"score"`
+	Level   string   `json:"level"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// GetRequestDetail returns a single access request with its partial state, effect and risk.
+// GetRequestDetail godoc
+// @Summary      Get access request details
+// @Description  Returns an access request, which of its resources already exist and the direction each side enforces, what approving it would create, a risk score, the payload it was submitted with and how long it waited for review and decision. The submission and timing are kept after the request is approved or denied. Only the requestor and users allowed to approve the request can see it.
+// @Tags         Requests
+// @Produce      json
+// @Param        id   path      string  true  "AccessRequest name"
+// @Success      200  {object}  AccessRequestDetail
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id} [get]
+func GetRequestDetail(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("id")
+
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	detail, spec, err := loadRequestDetail(ctx, name)
+	if err != nil {
+		if errors.Is(err, errRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Request not found"})
+			return
+		}
+		logger.Logger.Error("Failed to get AccessRequest", "error", err, "request", name)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve the request"})
+		return
+	}
+
+	if !canSeeRequest(ctx, userInfo, spec) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the requestor and approvers can see this request"})
+		return
+	}
+	// An approver opening a pending request counts as its first review.
+	if detail.Request != nil && !isRequestOwner(userInfo.Email, spec) {
+		recordRequestReview(ctx, name, userInfo.Email)
+		detail.Timing, _, _ = getRequestTiming(ctx, name)
+	}
+	c.JSON(http.StatusOK, detail)
+}
+
+// errRequestNotFound is returned when neither the AccessRequest nor its submission exist.
+var errRequestNotFound = errors.New("request not found")
+
+// loadRequestDetail gathers everything known about a request, pending or not, and the spec it was submitted with.
+func loadRequestDetail(ctx context.Context, name string) (AccessRequestDetail, netwatchv1alpha1.AccessRequestSpec, error) {
+	var detail AccessRequestDetail
+	var spec netwatchv1alpha1.AccessRequestSpec
+	if request, err := k8s.GetAccessRequestAsApp(ctx, name); err == nil {
+		spec = request.Spec
+		detail.Request = &AccessRequestPayload{
+			RequestID:         request.Name,
+			DisplayName:       requestDisplayName(request),
+			Requestor:         request.Spec.Requestor,
+			FiledBy:           request.Spec.FiledBy,
+			Timestamp:         request.CreationTimestamp.Unix(),
+			RequestType:       request.Spec.RequestType,
+			SourceService:     request.Spec.SourceService,
+			TargetService:     request.Spec.TargetService,
+			Cidr:              request.Spec.Cidr,
+			Service:           request.Spec.Service,
+			Direction:         request.Spec.Direction,
+			Ports:             request.Spec.Ports,
+			Duration:          request.Spec.Duration,
+			Description:       request.Spec.Description,
+			TicketRef:         request.Spec.TicketRef,
+			Priority:          requestPriority(request.Spec),
+			PreviousRequestID: request.Spec.PreviousRequestID,
+			History:           priorDecisions(ctx, request.Spec),
+			Status:            request.Spec.Status,
+			Attachments:       listRequestAttachments(ctx, request.Name),
+			Labels:            fromRequestObjectLabels(request.Labels),
+			RequiredApprovals: request.Spec.RequiredApprovals,
+			ApprovedBy:        approverNames(request.Spec),
+			DualApproval:      request.Spec.DualApproval,
+			ApprovedSides:     approvedSides(request.Spec),
+		}
+		detail.Request.Age, detail.Request.SLABreached = requestAging(request)
+	} else if !k8s.IsNotFound(err) {
+		return detail, spec, err
+	}
+	detail.Submission = getRequestSubmission(ctx, name)
+	detail.Timing, _, _ = getRequestTiming(ctx, name)
+	if detail.Request == nil && detail.Submission == nil {
+		return detail, spec, errRequestNotFound
+	}
+	if detail.Request == nil {
+		spec = detail.Submission.Spec
+	}
+
+	if detail.Request != nil {
+		detail.Sides = requestSideStates(ctx, spec)
+		detail.Effect = requestEffect(spec, detail.Sides)
+		risk := computeRequestRisk(spec)
+		detail.Risk = &risk
+	}
+	return detail, spec, nil
+}
+
+// canSeeRequest reports whether a user is the requestor of a request, filed it for them, or could approve it.
+func canSeeRequest(ctx context.Context, userInfo *k8s.UserInfo, spec netwatchv1alpha1.AccessRequestSpec) bool {
+	if isRequestOwner(userInfo.Email, spec) {
+		return true
+	}
+	perms := approvalPermissions(spec)
+	allowed, err := k8s.CanPerformAllActions(ctx, userInfo, perms)
+	return len(perms) > 0 && err == nil && allowed
+}
+
+// requestSideStates looks up the clone and Access of each side of a service request. Partial requests
+// already have one side created by the requestor.
+func requestSideStates(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) []RequestSideState {
+	direction, err := normalizeDirection(spec.Direction)
+	if spec.RequestType != "Service" {
+		return []RequestSideState{{Side: "target", Namespace: strings.Split(spec.Service, "/")[0], Service: spec.Service, Direction: direction}}
+	}
+	sides := []RequestSideState{
+		{Side: "source", Namespace: strings.Split(spec.SourceService, "/")[0], Service: spec.SourceService, CloneName: spec.SourceCloneName},
+		{Side: "target", Namespace: strings.Split(spec.TargetService, "/")[0], Service: spec.TargetService, CloneName: spec.TargetCloneName},
+	}
+	for i := range sides {
+		side := &sides[i]
+		if err == nil {
+			side.Direction = sideDirection(direction, side.Side == "source")
+		}
+		if side.CloneName == "" {
+			continue
+		}
+		side.AccessName = fmt.Sprintf("access-%s", side.CloneName)
+		if _, err := k8s.GetServiceAsApp(ctx, side.Namespace, side.CloneName); err == nil {
+			side.CloneExists = true
+		}
+		if _, err := k8s.GetAccessAsApp(ctx, side.Namespace, side.AccessName); err == nil {
+			side.AccessExists = true
+		}
+	}
+	return sides
+}
+
+// requestEffect describes what approving the request would create.
+func requestEffect(spec netwatchv1alpha1.AccessRequestSpec, sides []RequestSideState) []string {
+	duration := "never expires"
+	if spec.Duration > 0 {
+		duration = "expires " + (time.Duration(spec.Duration) * time.Second).String() + " after approval"
+	}
+	ports := spec.Ports
+	if ports == "" {
+		ports = "the service's own ports"
+	}
+
+	var effect []string
+	direction, err := normalizeDirection(spec.Direction)
+	if err != nil {
+		return []string{"refuse the approval: " + err.Error()}
+	}
+	if spec.RequestType != "Service" {
+		effect = append(effect,
+			fmt.Sprintf("create a clone of Service %s exposing %s", spec.Service, ports),
+			fmt.Sprintf("create an ExternalAccess allowing %s (%s) to %s, %s", spec.Cidr, direction, spec.Service, duration),
+		)
+		return effect
+	}
+	for _, side := range sides {
+		if !side.CloneExists {
+			effect = append(effect, fmt.Sprintf("create a clone of Service %s", side.Service))
+		}
+		if !side.AccessExists {
+			effect = append(effect, fmt.Sprintf("create an Access in namespace %s (%s, ports: %s), %s", side.Namespace, side.Direction, ports, duration))
+		}
+	}
+	return effect
+}
+
+// computeRequestRisk scores a request on its duration, scope and exposed ports.
+func computeRequestRisk(spec netwatchv1alpha1.AccessRequestSpec) RequestRisk {
+	var risk RequestRisk
+	add := func(points int, reason string) {
+		risk.Score += points
+		risk.Reasons = append(risk.Reasons, reason)
+	}
+
+	switch {
+	case spec.Duration <= 0:
+		add(35, "the access never expires")
+	case spec.Duration > int64((24 * time.Hour).Seconds()):
+		add(15, "the access lasts more than a day")
+	}
+	if direction, err := normalizeDirection(spec.Direction); err == nil && direction == directionAll {
+		add(10, "traffic is allowed in both directions")
+	}
+	for _, p := range strings.Split(spec.Ports, ",") {
+		port, err := strconv.Atoi(strings.TrimSpace(p))
+		if err == nil && slices.Contains(sensitivePorts, port) {
+			add(20, fmt.Sprintf("port %d is sensitive", port))
+		}
+	}
+	if spec.RequestType == "External" {
+		if _, network, err := net.ParseCIDR(spec.Cidr); err == nil {
+			ones, _ := network.Mask.Size()
+			switch {
+			case ones == 0:
+				add(50, "the whole internet is allowed")
+			case ones <= 16:
+				add(25, fmt.Sprintf("%s is a wide range", spec.Cidr))
+			}
+		}
+	}
+
+	risk.Score = min(risk.Score, 100)
+	switch {
+	case risk.Score >= 60:
+		risk.Level = "high"
+	case risk.Score >= 30:
+		risk.Level = "medium"
+	default:
+		risk.Level = "low"
+	}
+	return risk
+}
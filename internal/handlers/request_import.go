@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"sigs.k8s.io/yaml"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+// maxManifestBytes bounds the size of an imported AccessRequest manifest.
+const maxManifestBytes = 64 << 10
+
+// manifestToPayload validates an AccessRequest manifest and turns it into a submission.
+// Fields the server manages must be left empty, so a manifest can be kept in a repository and submitted again.
+// A requestor other than the submitter, from the manifest or onBehalfOf, files the request on their behalf.
+func manifestToPayload(data []byte, submitter, onBehalfOf string) (webSocketPayload, error) {
+	var manifest netwatchv1alpha1.AccessRequest
+	if err := yaml.UnmarshalStrict(data, &manifest); err != nil {
+		return webSocketPayload{}, fmt.Errorf("invalid manifest: %w", err)
+	}
+	if manifest.APIVersion != netwatchv1alpha1.GroupVersion.String() || manifest.Kind != "AccessRequest" {
+		return webSocketPayload{}, fmt.Errorf("expected apiVersion %s and kind AccessRequest", netwatchv1alpha1.GroupVersion)
+	}
+	spec := manifest.Spec
+	if onBehalfOf != "" && spec.Requestor != "" && spec.Requestor != onBehalfOf {
+		return webSocketPayload{}, fmt.Errorf("spec.requestor %s does not match onBehalfOf %s", spec.Requestor, onBehalfOf)
+	}
+	if onBehalfOf == "" && spec.Requestor != submitter {
+		onBehalfOf = spec.Requestor
+	}
+	for field, value := range map[string]string{
+		"spec.status": spec.Status, "spec.requestID": spec.RequestID, "spec.filedBy": spec.FiledBy,
+		"spec.sourceCloneName": spec.SourceCloneName, "spec.targetCloneName": spec.TargetCloneName,
+	} {
+		if value != "" {
+			return webSocketPayload{}, fmt.Errorf("%s is set by the server and must be left empty", field)
+		}
+	}
+	switch spec.RequestType {
+	case "Service":
+		if spec.SourceService == "" || spec.TargetService == "" || spec.Service != "" || spec.Cidr != "" {
+			return webSocketPayload{}, fmt.Errorf("requests of type Service need sourceService and targetService, and no service or cidr")
+		}
+	case "External":
+		if spec.Service == "" || spec.Cidr == "" || spec.SourceService != "" || spec.TargetService != "" {
+			return webSocketPayload{}, fmt.Errorf("requests of type External need service and cidr, and no sourceService or targetService")
+		}
+	default:
+		return webSocketPayload{}, fmt.Errorf("spec.requestType must be 'Service' or 'External'")
+	}
+
+	// Request labels may be written with or without their prefix.
+	var labels map[string]string
+	for key, value := range manifest.Labels {
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		labels[strings.TrimPrefix(key, requestLabelPrefix)] = value
+	}
+	return webSocketPayload{
+		Command:           "submitAccessRequest",
+		SourceService:     spec.SourceService,
+		TargetService:     spec.TargetService,
+		Direction:         spec.Direction,
+		Ports:             spec.Ports,
+		Cidr:              spec.Cidr,
+		Service:           spec.Service,
+		Duration:          spec.Duration,
+		Description:       spec.Description,
+		Labels:            labels,
+		OnBehalfOf:        onBehalfOf,
+		TicketRef:         spec.TicketRef,
+		Priority:          spec.Priority,
+		PreviousRequestID: spec.PreviousRequestID,
+	}, nil
+}
+
+// ImportAccessRequest submits an AccessRequest from a manifest.
+// ImportAccessRequest godoc
+// @Summary      Submit an access request from a manifest
+// @Description  Submits an AccessRequest described by a YAML or JSON manifest matching the CRD schema, with the same checks as a submission from the UI. metadata.name is ignored: names are generated. Request labels go in metadata.labels. spec.requestor may be omitted. Another user as spec.requestor, or the onBehalfOf parameter, files the request on their behalf: this requires the file-on-behalf verb on accessrequests, or being an admin. Server-managed fields (status, requestID, filedBy, clone names) must be empty.
+// @Tags         Requests
+// @Accept       plain
+// @Produce      json
+// @Param        manifest         body    string  true   "AccessRequest manifest, YAML or JSON"
+// @Param        onBehalfOf       query   string  false  "Email of the user to file the request for"
+// @Param        Idempotency-Key  header  string  false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  handlers.CommandResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      422  {object}  handlers.CommandResult
+// @Security     ApiKeyAuth
+// @Router       /access-requests/import [post]
+func ImportAccessRequest(c *gin.Context) {
+	runner := newCommandRunner(c, "import")
+	if runner == nil {
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxManifestBytes))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Could not read manifest: " + err.Error()})
+		return
+	}
+	payload, err := manifestToPayload(data, runner.processor.userInfo.Email, c.Query("onBehalfOf"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	runner.run(c, http.StatusCreated, payload)
+}
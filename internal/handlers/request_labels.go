@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// requestLabelPrefix namespaces user-provided labels on AccessRequest objects so they never clash with system labels.
+const requestLabelPrefix = "labels.netwatch.vtk.io/"
+
+// requestLabelKeys is the allowlist of label keys users can set on their requests.
+var requestLabelKeys = map[string]bool{"team": true, "project": true, "environment": true}
+
+// SetRequestLabelKeys replaces the allowlist of label keys accepted on access request submissions.
+func SetRequestLabelKeys(keys []string) {
+	allowed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key = strings.TrimSpace(key); key != "" {
+			allowed[key] = true
+		}
+	}
+	requestLabelKeys = allowed
+}
+
+// toRequestObjectLabels validates user labels against the allowlist and converts them to Kubernetes labels.
+func toRequestObjectLabels(labels map[string]string) (map[string]string, error) {
+	objectLabels := make(map[string]string, len(labels))
+	for key, value := range labels {
+		if !requestLabelKeys[key] {
+			return nil, fmt.Errorf("label key %q is not allowed", key)
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return nil, fmt.Errorf("invalid value for label %q: %s", key, strings.Join(errs, "; "))
+		}
+		objectLabels[requestLabelPrefix+key] = value
+	}
+	return objectLabels, nil
+}
+
+// fromRequestObjectLabels extracts the user labels from the labels of an AccessRequest object.
+func fromRequestObjectLabels(objectLabels map[string]string) map[string]string {
+	var labels map[string]string
+	for key, value := range objectLabels {
+		if name, ok := strings.CutPrefix(key, requestLabelPrefix); ok {
+			if labels == nil {
+				labels = make(map[string]string)
+			}
+			labels[name] = value
+		}
+	}
+	return labels
+}
+
+// requestLabelFilter builds a label selector from the repeatable 'label=key=value' query parameter.
+func requestLabelFilter(c *gin.Context) (client.MatchingLabels, error) {
+	filter := client.MatchingLabels{}
+	for _, raw := range c.QueryArray("label") {
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid label filter %q, expected key=value", raw)
+		}
+		selector, err := toRequestObjectLabels(map[string]string{key: value})
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range selector {
+			filter[k] = v
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+// displayNameAnnotation holds the human readable `ar-<user>-<id>` name of a request whose object name was generated.
+const displayNameAnnotation = "netwatch.vtk.io/display-name"
+
+var generateRequestNames bool
+
+// SetGenerateRequestNames makes new AccessRequests use a server-generated name instead of `ar-<user>-<id>`.
+func SetGenerateRequestNames(enabled bool) {
+	generateRequestNames = enabled
+}
+
+// accessRequestMeta builds the metadata of a new AccessRequest. With generated names, the object name only
+// carries a short hash of the username so it stays within limits, and the apiserver guarantees uniqueness.
+func accessRequestMeta(username, requestID string, labels map[string]string) metav1.ObjectMeta {
+	displayName := fmt.Sprintf("ar-%s-%s", username, requestID[:8])
+	if !generateRequestNames {
+		return metav1.ObjectMeta{Name: displayName, Labels: labels}
+	}
+	sum := sha256.Sum256([]byte(username))
+	return metav1.ObjectMeta{
+		GenerateName: fmt.Sprintf("ar-%s-", hex.EncodeToString(sum[:])[:8]),
+		Labels:       labels,
+		Annotations:  map[string]string{displayNameAnnotation: displayName},
+	}
+}
+
+// requestDisplayName returns the name shown to users for a request.
+func requestDisplayName(request *netwatchv1alpha1.AccessRequest) string {
+	if name := request.Annotations[displayNameAnnotation]; name != "" {
+		return name
+	}
+	return request.Name
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+// Priorities of access requests. Requests submitted without one are normal.
+const (
+	priorityLow    = "low"
+	priorityNormal = "normal"
+	priorityUrgent = "urgent"
+)
+
+// normalizePriority validates a requested priority. An empty priority is normal.
+func normalizePriority(priority string) (string, error) {
+	switch priority = strings.ToLower(strings.TrimSpace(priority)); priority {
+	case priorityLow, priorityNormal, priorityUrgent:
+		return priority, nil
+	case "":
+		return priorityNormal, nil
+	default:
+		return "", fmt.Errorf("unknown priority %q, expected 'low', 'normal' or 'urgent'", priority)
+	}
+}
+
+// invalidPriority reports an unknown priority to the user, and normalizes a valid one in place.
+func (p *webSocketCommandProcessor) invalidPriority(priority *string) bool {
+	normalized, err := normalizePriority(*priority)
+	if err != nil {
+		p.sendError("Invalid priority", err, "Request")
+		return true
+	}
+	*priority = normalized
+	return false
+}
+
+// requestPriority returns the priority of a request, normal for requests submitted before priorities existed.
+func requestPriority(spec netwatchv1alpha1.AccessRequestSpec) string {
+	if spec.Priority == "" {
+		return priorityNormal
+	}
+	return spec.Priority
+}
+
+// priorityRank orders requests for review, urgent ones first.
+func priorityRank(priority string) int {
+	switch priority {
+	case priorityUrgent:
+		return 0
+	case priorityLow:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// prioritySuffix flags the requests that aren't normal at the end of an activity log entry.
+func prioritySuffix(spec netwatchv1alpha1.AccessRequestSpec) string {
+	if priority := requestPriority(spec); priority != priorityNormal {
+		return fmt.Sprintf(" Priority: %s.", priority)
+	}
+	return ""
+}
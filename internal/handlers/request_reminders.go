@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// requestReminderPrefix and requestEscalationPrefix mark the requests already reminded or escalated, so each
+// notice is sent once across replicas.
+const (
+	requestReminderPrefix   = "netwatch:request_reminded:"
+	requestEscalationPrefix = "netwatch:request_escalated:"
+)
+
+// ReminderConfig sets when requests left pending are brought back to the attention of approvers.
+type ReminderConfig struct {
+	// RemindAfter is how long a request waits before its approvers are reminded of it. Zero disables reminders.
+	RemindAfter time.Duration
+	// EscalateAfter is how long a request waits before it is escalated to EscalationGroups. Zero disables escalation.
+	EscalateAfter time.Duration
+	// EscalationGroups may review escalated requests, on top of their approvers.
+	EscalationGroups []string
+	// UrgentRemindAfter and UrgentEscalateAfter replace RemindAfter and EscalateAfter for urgent requests. They
+	// default to a quarter of them.
+	UrgentRemindAfter   time.Duration
+	UrgentEscalateAfter time.Duration
+}
+
+var reminderConfig ReminderConfig
+
+// SetRequestReminders configures the reminders and escalation of requests left pending.
+func SetRequestReminders(cfg ReminderConfig) {
+	if cfg.UrgentRemindAfter == 0 {
+		cfg.UrgentRemindAfter = cfg.RemindAfter / 4
+	}
+	if cfg.UrgentEscalateAfter == 0 {
+		cfg.UrgentEscalateAfter = cfg.EscalateAfter / 4
+	}
+	reminderConfig = cfg
+}
+
+// remindAfter returns how long a request waits before its approvers are reminded of it, zero for never.
+func remindAfter(request *netwatchv1alpha1.AccessRequest) time.Duration {
+	if requestPriority(request.Spec) == priorityUrgent {
+		return reminderConfig.UrgentRemindAfter
+	}
+	return reminderConfig.RemindAfter
+}
+
+// escalateAfter returns how long a request waits before it is escalated, zero for never.
+func escalateAfter(request *netwatchv1alpha1.AccessRequest) time.Duration {
+	if requestPriority(request.Spec) == priorityUrgent {
+		return reminderConfig.UrgentEscalateAfter
+	}
+	return reminderConfig.EscalateAfter
+}
+
+// escalated reports whether a request has waited long enough to be escalated.
+func escalated(request *netwatchv1alpha1.AccessRequest) bool {
+	after := escalateAfter(request)
+	return after > 0 && len(reminderConfig.EscalationGroups) > 0 && time.Since(request.CreationTimestamp.Time) >= after
+}
+
+// isEscalationApprover reports whether a user may review a request as a member of the escalation groups.
+func isEscalationApprover(userInfo *k8s.UserInfo, request *netwatchv1alpha1.AccessRequest) bool {
+	return escalated(request) && slices.ContainsFunc(userInfo.Groups, func(group string) bool {
+		return slices.Contains(reminderConfig.EscalationGroups, group)
+	})
+}
+
+// StartRequestReminders periodically reminds approvers of the requests waiting for them, and escalates the
+// oldest ones. Notices go to the activity log, with the groups they are meant for.
+func StartRequestReminders(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	logger.Logger.Info("Starting request reminders", "remindAfter", reminderConfig.RemindAfter,
+		"escalateAfter", reminderConfig.EscalateAfter, "escalationGroups", reminderConfig.EscalationGroups,
+		"urgentRemindAfter", reminderConfig.UrgentRemindAfter, "urgentEscalateAfter", reminderConfig.UrgentEscalateAfter)
+	for {
+		select {
+		case <-ticker.C:
+			remindStaleRequests(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func remindStaleRequests(ctx context.Context) {
+	list, err := k8s.ListAccessRequestsAsApp(ctx)
+	if err != nil {
+		logger.Logger.Error("Request reminders failed to list access requests", "error", err)
+		return
+	}
+	for i := range list.Items {
+		request := &list.Items[i]
+		if !strings.HasPrefix(request.Spec.Status, "Pending") || !request.DeletionTimestamp.IsZero() {
+			continue
+		}
+		age := time.Since(request.CreationTimestamp.Time)
+		if after := remindAfter(request); after > 0 && age >= after && firstNotice(ctx, requestReminderPrefix+request.Name) {
+			logger.Logger.Info("Reminding approvers of a pending request", "request", request.Name, "age", age)
+			persistLogEntry(LogEntry{
+				Payload: fmt.Sprintf("REMINDER: Access request from %s (%s) has been waiting for review for %s.%s",
+					requestorLabel(request.Spec), requestPath(request.Spec), age.Round(time.Minute), prioritySuffix(request.Spec)),
+				ClassName: "log-info",
+				LogType:   "Request",
+				Type:      "applyResult",
+				Groups:    routedGroups(ctx, request.Spec),
+				Owners:    routedOwners(ctx, request.Spec),
+			})
+		}
+		if escalated(request) && firstNotice(ctx, requestEscalationPrefix+request.Name) {
+			logger.Logger.Info("Escalating a pending request", "request", request.Name, "age", age, "groups", reminderConfig.EscalationGroups)
+			persistLogEntry(LogEntry{
+				Payload: fmt.Sprintf("ESCALATED: Access request from %s (%s) got no decision for %s and can now be reviewed by %s.%s",
+					requestorLabel(request.Spec), requestPath(request.Spec), age.Round(time.Minute),
+					strings.Join(reminderConfig.EscalationGroups, ", "), prioritySuffix(request.Spec)),
+				ClassName: "log-warning",
+				LogType:   "Request",
+				Type:      "applyResult",
+				Groups:    reminderConfig.EscalationGroups,
+			})
+		}
+	}
+}
+
+// firstNotice reports whether a notice is sent for the first time, and marks it as sent.
+func firstNotice(ctx context.Context, key string) bool {
+	first, err := redisClient.SetNX(ctx, key, 1, requestSubmissionRetention).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to record a request notice", "error", err, "key", key)
+		return false
+	}
+	return first
+}
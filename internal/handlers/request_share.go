@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	requestShareTokenPrefix = "netwatch:request_share:"
+	// defaultRequestShareTTL and maxRequestShareTTL bound how long a shared link stays readable.
+	defaultRequestShareTTL = 24 * time.Hour
+	maxRequestShareTTL     = 7 * 24 * time.Hour
+)
+
+// RequestShareInput optionally sets how long a shared link is valid.
+type RequestShareInput struct {
+	// TTL is a duration such as "2h". It defaults to 24h and is capped to 7 days.
+	TTL string `json:"ttl" example:"2h"`
+}
+
+// RequestShareLink is a read-only link to a single request.
+type RequestShareLink struct {
+	URL string `json:"url"`
+	// ExpiresAt is when the link stops working, as a Unix timestamp.
+	ExpiresAt int64 `json:"expiresAt"`
+}
+
+// ShareRequest creates a time-limited read-only link to one request.
+// ShareRequest godoc
+// @Summary      Share an access request
+// @Description  Creates a time-limited link to the details of one request, to paste to an approver. Anyone with the link can read that request, and only that request, whatever their permissions. Only the requestor and users allowed to approve the request can share it.
+// @Tags         Requests
+// @Accept       json
+// @Produce      json
+// @Param        id               path    string             true   "AccessRequest name"
+// @Param        share            body    RequestShareInput  false  "Link validity"
+// @Param        Idempotency-Key  header  string             false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      201  {object}  handlers.RequestShareLink
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      404  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /pending-requests/{id}/share [post]
+func ShareRequest(c *gin.Context) {
+	ctx := c.Request.Context()
+	name := c.Param("id")
+
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token: " + err.Error()})
+		return
+	}
+
+	ttl := defaultRequestShareTTL
+	var input RequestShareInput
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid share settings: " + err.Error()})
+			return
+		}
+	}
+	if input.TTL != "" {
+		ttl, err = time.ParseDuration(input.TTL)
+		if err != nil || ttl <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "ttl must be a positive duration such as '2h'"})
+			return
+		}
+		ttl = min(ttl, maxRequestShareTTL)
+	}
+
+	_, spec, err := loadRequestDetail(ctx, name)
+	if err != nil {
+		if errors.Is(err, errRequestNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Request not found"})
+			return
+		}
+		logger.Logger.Error("Failed to get AccessRequest", "error", err, "request", name)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not retrieve the request"})
+		return
+	}
+	if !canSeeRequest(ctx, userInfo, spec) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the requestor and approvers can share this request"})
+		return
+	}
+
+	tokenBytes := make([]byte, 24)
+	rand.Read(tokenBytes) //nolint:all
+	token := hex.EncodeToString(tokenBytes)
+	if err := redisClient.Set(ctx, requestShareTokenPrefix+token, name, ttl).Err(); err != nil {
+		logger.Logger.Error("Failed to store request share token", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not share the request"})
+		return
+	}
+	logger.Logger.Info("Request shared", "user", userInfo.Email, "request", name, "ttl", ttl)
+	c.JSON(http.StatusCreated, RequestShareLink{
+		URL:       fmt.Sprintf("%s/shared/requests/%s", GetBaseURL(c.Request), token),
+		ExpiresAt: time.Now().Add(ttl).Unix(),
+	})
+}
+
+// HandleSharedRequest serves the details of a shared request. The token in the URL is the only credential and
+// only grants reading that request. Browsers get a page, other clients JSON.
+func HandleSharedRequest(c *gin.Context) {
+	ctx := c.Request.Context()
+	name, err := redisClient.Get(ctx, requestShareTokenPrefix+c.Param("token")).Result()
+	if err != nil {
+		c.String(http.StatusNotFound, "This link is invalid or has expired")
+		return
+	}
+	detail, _, err := loadRequestDetail(ctx, name)
+	if err != nil {
+		c.String(http.StatusNotFound, "This request no longer exists")
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Header("Referrer-Policy", "no-referrer")
+	if strings.Contains(c.GetHeader("Accept"), "text/html") {
+		c.HTML(http.StatusOK, "shared_request.html", gin.H{"Name": name, "Detail": detail})
+		return
+	}
+	c.JSON(http.StatusOK, detail)
+}
@@ -0,0 +1,264 @@
+package handlers
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// requestTimingPrefix keys a Redis hash tracking when a request was submitted, first reviewed and decided.
+// It is kept as long as the submission, so SLAs can be measured after the AccessRequest is gone.
+const requestTimingPrefix = "netwatch:request_timing:"
+
+var (
+	// The buckets go from a minute to about a day and a half.
+	requestTimeToFirstReview = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "netwatch_request_time_to_first_review_seconds",
+		Help:    "Time between the submission of an access request and the first time an approver looked at or acted on it.",
+		Buckets: prometheus.ExponentialBuckets(60, 2, 12),
+	}, []string{"namespace", "team"})
+	requestTimeToDecision = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "netwatch_request_time_to_decision_seconds",
+		Help:    "Time between the submission of an access request and its approval or denial.",
+		Buckets: prometheus.ExponentialBuckets(60, 2, 12),
+	}, []string{"namespace", "team", "decision"})
+	requestSLABreaches = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "netwatch_request_sla_breaches_total",
+		Help: "Access requests decided after their review SLA, or expired without a review.",
+	}, []string{"namespace", "team", "priority"})
+	pendingRequestsOverdue = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "netwatch_pending_requests_overdue",
+		Help: "Access requests still pending past their review SLA.",
+	})
+	pendingRequestOldestAge = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "netwatch_pending_request_oldest_age_seconds",
+		Help: "How long the oldest pending access request has been waiting for a decision.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(requestTimeToFirstReview, requestTimeToDecision, requestSLABreaches, pendingRequestsOverdue,
+		pendingRequestOldestAge)
+}
+
+// ReviewSLAConfig sets how long access requests may wait for a decision before they are overdue.
+type ReviewSLAConfig struct {
+	// Target is how long a request may wait. Zero disables the SLA: nothing is ever overdue.
+	Target time.Duration
+	// UrgentTarget replaces Target for urgent requests. It defaults to a quarter of it.
+	UrgentTarget time.Duration
+}
+
+var reviewSLA ReviewSLAConfig
+
+// SetReviewSLA configures how long access requests may wait for a decision.
+func SetReviewSLA(cfg ReviewSLAConfig) {
+	if cfg.UrgentTarget == 0 {
+		cfg.UrgentTarget = cfg.Target / 4
+	}
+	reviewSLA = cfg
+}
+
+// reviewSLAFor returns how long a request of a priority may wait for a decision, zero for as long as it takes.
+func reviewSLAFor(priority string) time.Duration {
+	if priority == priorityUrgent {
+		return reviewSLA.UrgentTarget
+	}
+	return reviewSLA.Target
+}
+
+// slaBreached reports whether a request of a priority waited longer than its SLA.
+func slaBreached(priority string, waited time.Duration) bool {
+	sla := reviewSLAFor(priority)
+	return sla > 0 && waited > sla
+}
+
+// requestAging returns how long a pending request has been waiting, in seconds, and whether it is overdue.
+func requestAging(request *netwatchv1alpha1.AccessRequest) (int64, bool) {
+	waited := time.Since(request.CreationTimestamp.Time)
+	return int64(waited.Seconds()), slaBreached(requestPriority(request.Spec), waited)
+}
+
+// StartReviewSLAMonitor periodically exports how many pending requests are overdue and the age of the oldest one.
+func StartReviewSLAMonitor(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		updatePendingRequestAging(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func updatePendingRequestAging(ctx context.Context) {
+	requests, err := cachedAccessRequests(ctx, nil)
+	if err != nil {
+		logger.Logger.Error("Failed to list pending requests for the review SLA", "error", err)
+		return
+	}
+	var overdue, oldest int64
+	for i := range requests {
+		age, breached := requestAging(&requests[i])
+		if breached {
+			overdue++
+		}
+		oldest = max(oldest, age)
+	}
+	pendingRequestsOverdue.Set(float64(overdue))
+	pendingRequestOldestAge.Set(float64(oldest))
+}
+
+// RequestTiming tells how long an access request waited for review, to measure approval SLAs.
+type RequestTiming struct {
+	SubmittedAt   int64  `json:"submittedAt" example:"1760000000"`
+	FirstReviewAt int64  `json:"firstReviewAt,omitempty" example:"1760000300"`
+	FirstReviewer string `json:"firstReviewer,omitempty" example:"approver@example.com"`
+	DecidedAt     int64  `json:"decidedAt,omitempty" example:"1760000600"`
+	// Decision is approved, denied, aborted or expired. Aborted requests are withdrawn by their requestor, expired ones
+	// deleted because nobody reviewed them in time. Neither is counted in the metrics.
+	Decision  string `json:"decision,omitempty" example:"approved"`
+	DecidedBy string `json:"decidedBy,omitempty" example:"approver@example.com"`
+	// Reason is why the request was denied or aborted, when it was said.
+	Reason                   string `json:"reason,omitempty" example:"Use the staging database instead"`
+	TimeToFirstReviewSeconds int64  `json:"timeToFirstReviewSeconds,omitempty" example:"300"`
+	TimeToDecisionSeconds    int64  `json:"timeToDecisionSeconds,omitempty" example:"600"`
+}
+
+// requestTargetNamespace is the namespace whose owners review a request.
+func requestTargetNamespace(spec netwatchv1alpha1.AccessRequestSpec) string {
+	service := spec.Service
+	if spec.RequestType == "Service" {
+		service = spec.TargetService
+	}
+	ns, _, _ := strings.Cut(service, "/")
+	return ns
+}
+
+// startRequestTiming starts measuring the review of a freshly submitted request.
+func startRequestTiming(ctx context.Context, request *netwatchv1alpha1.AccessRequest) {
+	key := requestTimingPrefix + request.Name
+	pipe := redisClient.TxPipeline()
+	pipe.HSet(ctx, key,
+		"submittedAt", time.Now().Unix(),
+		"namespace", requestTargetNamespace(request.Spec),
+		"team", fromRequestObjectLabels(request.Labels)["team"],
+		"priority", requestPriority(request.Spec),
+	)
+	pipe.Expire(ctx, key, requestSubmissionRetention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Error("Failed to start request timing", "error", err, "request", request.Name)
+	}
+}
+
+// recordRequestReview records the first time an approver looked at or acted on a request. Later reviews are ignored.
+func recordRequestReview(ctx context.Context, name, reviewer string) {
+	key := requestTimingPrefix + name
+	if !requestTimed(ctx, key) {
+		return
+	}
+	now := time.Now().Unix()
+	// HSetNX only lets the first reviewer through, also across replicas.
+	first, err := redisClient.HSetNX(ctx, key, "firstReviewAt", now).Result()
+	if err != nil || !first {
+		return
+	}
+	redisClient.HSet(ctx, key, "firstReviewer", reviewer) //nolint:all
+	timing, labels, ok := getRequestTiming(ctx, name)
+	if !ok {
+		return
+	}
+	requestTimeToFirstReview.WithLabelValues(labels["namespace"], labels["team"]).Observe(float64(now - timing.SubmittedAt))
+}
+
+// recordRequestDecision records how a request was decided, and why when a reason was given. A decision by someone
+// other than the requestor also counts as its review.
+func recordRequestDecision(ctx context.Context, request *netwatchv1alpha1.AccessRequest, decision, decidedBy, reason string) {
+	if !isRequestOwner(decidedBy, request.Spec) {
+		recordRequestReview(ctx, request.Name, decidedBy)
+	}
+	key := requestTimingPrefix + request.Name
+	if !requestTimed(ctx, key) {
+		return
+	}
+	now := time.Now().Unix()
+	first, err := redisClient.HSetNX(ctx, key, "decidedAt", now).Result()
+	if err != nil || !first {
+		return
+	}
+	redisClient.HSet(ctx, key, "decision", decision, "decidedBy", decidedBy, "reason", reason) //nolint:all
+	timing, labels, ok := getRequestTiming(ctx, request.Name)
+	if !ok || decision == "aborted" {
+		return
+	}
+	requestTimeToDecision.WithLabelValues(labels["namespace"], labels["team"], decision).Observe(float64(now - timing.SubmittedAt))
+	countSLABreach(timing, labels)
+}
+
+// countSLABreach counts a request decided or expired after its review SLA.
+func countSLABreach(timing *RequestTiming, labels map[string]string) {
+	if slaBreached(labels["priority"], time.Duration(timing.DecidedAt-timing.SubmittedAt)*time.Second) {
+		requestSLABreaches.WithLabelValues(labels["namespace"], labels["team"], labels["priority"]).Inc()
+	}
+}
+
+// recordRequestExpiry closes the timing of a request the cleanup controller deleted because nobody reviewed it in
+// time.
+func recordRequestExpiry(ctx context.Context, name string) {
+	key := requestTimingPrefix + name
+	if !requestTimed(ctx, key) {
+		return
+	}
+	first, err := redisClient.HSetNX(ctx, key, "decidedAt", time.Now().Unix()).Result()
+	if err != nil || !first {
+		return
+	}
+	redisClient.HSet(ctx, key, "decision", "expired") //nolint:all
+	if timing, labels, ok := getRequestTiming(ctx, name); ok {
+		countSLABreach(timing, labels)
+	}
+}
+
+// requestTimed reports whether a timing exists, so none is created for requests submitted before it was tracked.
+func requestTimed(ctx context.Context, key string) bool {
+	exists, err := redisClient.Exists(ctx, key).Result()
+	return err == nil && exists == 1
+}
+
+// getRequestTiming returns the timing of a request and the labels its metrics are recorded with.
+func getRequestTiming(ctx context.Context, name string) (*RequestTiming, map[string]string, bool) {
+	fields, err := redisClient.HGetAll(ctx, requestTimingPrefix+name).Result()
+	if err != nil || fields["submittedAt"] == "" {
+		return nil, nil, false
+	}
+	parse := func(field string) int64 {
+		value, _ := strconv.ParseInt(fields[field], 10, 64)
+		return value
+	}
+	timing := &RequestTiming{
+		SubmittedAt:   parse("submittedAt"),
+		FirstReviewAt: parse("firstReviewAt"),
+		FirstReviewer: fields["firstReviewer"],
+		DecidedAt:     parse("decidedAt"),
+		Decision:      fields["decision"],
+		DecidedBy:     fields["decidedBy"],
+		Reason:        fields["reason"],
+	}
+	if timing.FirstReviewAt > 0 {
+		timing.TimeToFirstReviewSeconds = timing.FirstReviewAt - timing.SubmittedAt
+	}
+	if timing.DecidedAt > 0 {
+		timing.TimeToDecisionSeconds = timing.DecidedAt - timing.SubmittedAt
+	}
+	// Requests submitted before priorities were tracked are normal.
+	priority := requestPriority(netwatchv1alpha1.AccessRequestSpec{Priority: fields["priority"]})
+	return timing, map[string]string{"namespace": fields["namespace"], "team": fields["team"], "priority": priority}, true
+}
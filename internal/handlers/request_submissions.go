@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	requestSubmissionPrefix = "netwatch:request_submission:"
+	// requestSubmissionRetention keeps the submitted payload long after the AccessRequest itself is gone,
+	// so disputes about what was asked for can be settled.
+	requestSubmissionRetention = 90 * 24 * time.Hour
+)
+
+// RequestSubmission is the exact payload a user submitted for an access request, as accepted after validation.
+type RequestSubmission struct {
+	RequestName string                             `json:"requestName"`
+	RequestID   string                             `json:"requestID"`
+	SubmittedBy string                             `json:"submittedBy"`
+	SubmittedAt int64                              `json:"submittedAt"`
+	Channel     string                             `json:"channel"`
+	Payload     json.RawMessage                    `json:"payload" swaggertype:"object"`
+	Spec        netwatchv1alpha1.AccessRequestSpec `json:"spec"`
+}
+
+// storeRequestSubmission records the submitted payload of a freshly created AccessRequest.
+func storeRequestSubmission(ctx context.Context, request *netwatchv1alpha1.AccessRequest, channel string, payload any) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		logger.Logger.Error("Failed to serialize submitted payload", "error", err, "request", request.Name)
+		return
+	}
+	submission := RequestSubmission{
+		RequestName: request.Name,
+		RequestID:   request.Spec.RequestID,
+		SubmittedBy: cmp.Or(request.Spec.FiledBy, request.Spec.Requestor),
+		SubmittedAt: time.Now().Unix(),
+		Channel:     channel,
+		Payload:     payloadJSON,
+		Spec:        request.Spec,
+	}
+	submissionJSON, err := json.Marshal(submission)
+	if err != nil {
+		logger.Logger.Error("Failed to serialize request submission", "error", err, "request", request.Name)
+		return
+	}
+	if err := redisClient.Set(ctx, requestSubmissionPrefix+request.Name, submissionJSON, requestSubmissionRetention).Err(); err != nil {
+		logger.Logger.Error("Failed to store request submission", "error", err, "request", request.Name)
+	}
+}
+
+func getRequestSubmission(ctx context.Context, name string) *RequestSubmission {
+	submissionJSON, err := redisClient.Get(ctx, requestSubmissionPrefix+name).Result()
+	if err != nil {
+		return nil
+	}
+	var submission RequestSubmission
+	if err := json.Unmarshal([]byte(submissionJSON), &submission); err != nil {
+		logger.Logger.Warn("Failed to unmarshal a request submission", "error", err, "request", name)
+		return nil
+	}
+	return &submission
+}
+
+// approvalPermissions lists the permissions needed to approve a request on its own.
+func approvalPermissions(spec netwatchv1alpha1.AccessRequestSpec) []k8s.PermissionRequest {
+	var requiredPerms []k8s.PermissionRequest
+	if spec.RequestType == "Service" {
+		sourceParts := strings.Split(spec.SourceService, "/")
+		targetParts := strings.Split(spec.TargetService, "/")
+		if len(sourceParts) == 2 && len(targetParts) == 2 {
+			requiredPerms = append(requiredPerms,
+				k8s.PermissionRequest{Verb: "create", Resource: "services", Namespace: sourceParts[0]},
+				k8s.PermissionRequest{Verb: "create", Resource: "services", Namespace: targetParts[0]},
+				k8s.PermissionRequest{Verb: "create", Group: "maxtac.vtk.io", Resource: "accesses", Namespace: sourceParts[0]},
+				k8s.PermissionRequest{Verb: "create", Group: "maxtac.vtk.io", Resource: "accesses", Namespace: targetParts[0]},
+			)
+		}
+	} else {
+		serviceParts := strings.Split(spec.Service, "/")
+		if len(serviceParts) == 2 {
+			requiredPerms = append(requiredPerms,
+				k8s.PermissionRequest{Verb: "create", Resource: "services", Namespace: serviceParts[0]},
+				k8s.PermissionRequest{Verb: "create", Group: "maxtac.vtk.io", Resource: "externalaccesses", Namespace: serviceParts[0]},
+			)
+		}
+	}
+	return requiredPerms
+}
+
+// requestNamespaces lists the namespaces an access request touches.
+func requestNamespaces(spec netwatchv1alpha1.AccessRequestSpec) []string {
+	var namespaces []string
+	for _, service := range []string{spec.SourceService, spec.TargetService, spec.Service} {
+		if ns, _, ok := strings.Cut(service, "/"); ok {
+			namespaces = append(namespaces, ns)
+		}
+	}
+	return namespaces
+}
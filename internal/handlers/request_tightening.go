@@ -0,0 +1,150 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// tightenRequest applies the changes an approver makes to a pending request while approving it. Changes can only
+// narrow the access: fewer ports, a shorter duration, or one direction instead of all traffic. Fields left empty
+// are kept. It returns the changes made, for the activity log. The direction of spec must already be normalized.
+func tightenRequest(ctx context.Context, spec *netwatchv1alpha1.AccessRequestSpec, payload webSocketPayload) ([]string, error) {
+	var changes []string
+
+	if payload.Direction != "" {
+		direction, err := normalizeDirection(payload.Direction)
+		if err != nil {
+			return nil, err
+		}
+		if direction != spec.Direction {
+			if spec.Direction != directionAll {
+				return nil, fmt.Errorf("the direction can only be narrowed, from %s", spec.Direction)
+			}
+			changes = append(changes, fmt.Sprintf("direction %s -> %s", spec.Direction, direction))
+			spec.Direction = direction
+		}
+	}
+
+	if payload.Duration != 0 {
+		if payload.Duration < 0 || (spec.Duration > 0 && payload.Duration > spec.Duration) {
+			return nil, fmt.Errorf("the duration can only be shortened, from %s", durationLabel(spec.Duration))
+		}
+		if payload.Duration != spec.Duration {
+			changes = append(changes, fmt.Sprintf("duration %s -> %s", durationLabel(spec.Duration), durationLabel(payload.Duration)))
+			spec.Duration = payload.Duration
+		}
+	}
+
+	if payload.Ports != "" {
+		ports, err := tightenedPorts(ctx, *spec, payload.Ports)
+		if err != nil {
+			return nil, err
+		}
+		if ports != spec.Ports {
+			changes = append(changes, fmt.Sprintf("ports %s -> %s", portsLabel(spec.Ports), ports))
+			spec.Ports = ports
+		}
+	}
+	return changes, nil
+}
+
+// tightenedPorts checks that the ports an approver sets are a subset of the ports of a request, the ports of its
+// target service when it has none, and returns them sorted.
+func tightenedPorts(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec, ports string) (string, error) {
+	if spec.Status != "PendingFull" {
+		return "", fmt.Errorf("the ports of a partial request can't be changed, its requestor's side already exists")
+	}
+	requested, err := getOverridePorts(ports)
+	if err != nil {
+		return "", err
+	}
+	if len(requested) == 0 {
+		return "", fmt.Errorf("at least one port must be kept")
+	}
+
+	allowed, err := requestPorts(ctx, spec)
+	if err != nil {
+		return "", err
+	}
+
+	var kept []int
+	for _, port := range requested {
+		if !slices.Contains(allowed, port.Port) {
+			return "", fmt.Errorf("port %d is not part of the request, ports can only be removed", port.Port)
+		}
+		if !slices.Contains(kept, int(port.Port)) {
+			kept = append(kept, int(port.Port))
+		}
+	}
+	slices.Sort(kept)
+	slices.Sort(allowed)
+	if spec.Ports != "" && len(kept) == len(slices.Compact(allowed)) {
+		return spec.Ports, nil
+	}
+	keptStrings := make([]string, len(kept))
+	for i, port := range kept {
+		keptStrings[i] = strconv.Itoa(port)
+	}
+	return strings.Join(keptStrings, ","), nil
+}
+
+// requestPorts lists the ports a request opens: its own, or those of its target service when it sets none.
+func requestPorts(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) ([]int32, error) {
+	var ports []int32
+	if spec.Ports != "" {
+		current, err := getOverridePorts(spec.Ports)
+		if err != nil {
+			return nil, err
+		}
+		for _, port := range current {
+			ports = append(ports, port.Port)
+		}
+		return ports, nil
+	}
+	namespace, name, _ := strings.Cut(requestTargetService(spec), "/")
+	service, err := k8s.GetServiceAsApp(ctx, namespace, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not look up the ports of %s/%s: %w", namespace, name, err)
+	}
+	for _, port := range service.Spec.Ports {
+		ports = append(ports, port.Port)
+	}
+	return ports, nil
+}
+
+// requestTargetService is the service whose ports a request gets when it doesn't set any.
+func requestTargetService(spec netwatchv1alpha1.AccessRequestSpec) string {
+	if spec.RequestType == "Service" {
+		return spec.TargetService
+	}
+	return spec.Service
+}
+
+func durationLabel(seconds int64) string {
+	if seconds == 0 {
+		return "infinite"
+	}
+	return (time.Duration(seconds) * time.Second).String()
+}
+
+func portsLabel(ports string) string {
+	if ports == "" {
+		return "the service ports"
+	}
+	return ports
+}
+
+// changesSuffix lists the changes an approver made at the end of an activity log entry.
+func changesSuffix(changes []string) string {
+	if len(changes) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" Changed before approval: %s.", strings.Join(changes, ", "))
+}
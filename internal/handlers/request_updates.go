@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// handleUpdateAccessRequest lets the requestor of a PendingFull request change its ports, duration or description
+// until someone approves it, instead of aborting and resubmitting it. Fields left empty are kept.
+func (p *webSocketCommandProcessor) handleUpdateAccessRequest(payload webSocketPayload) {
+	logger.Logger.Info("WebSocket command received", "command", "updateAccessRequest", "user", p.userInfo.Email,
+		"requestID", payload.RequestID)
+	request, err := k8s.GetAccessRequestAsApp(p.ctx, payload.RequestID)
+	if err != nil {
+		p.sendError("Could not find pending request to update", err, "Request")
+		return
+	}
+	switch {
+	case !isRequestOwner(p.userInfo.Email, request.Spec):
+		p.sendError("Only the requestor can update a request", nil, "Request")
+		return
+	case request.Spec.Status != "PendingFull":
+		p.sendError("Partial requests can't be updated, their requestor's side already exists. Abort and resubmit it instead.", nil, "Request")
+		return
+	case len(request.Spec.ApprovedBy) > 0:
+		p.sendError("The request was already approved by "+strings.Join(approverNames(request.Spec), ", ")+" and can no longer be updated", nil, "Request")
+		return
+	}
+
+	changes := updateRequest(&request.Spec, payload)
+	if len(changes) == 0 {
+		p.sendError("Nothing to update, the request is unchanged", nil, "Request")
+		return
+	}
+	if p.invalidDuration(request.Spec.Duration, "Request") ||
+		p.outOfScope(request.Spec.RequestType, requestNamespaces(request.Spec), request.Spec.Duration, "Request") ||
+		p.missingTicket(request.Spec) || p.missingJustification(request.Spec.Description, requestNamespaces(request.Spec), "Request") {
+		return
+	}
+	// A longer duration can raise the quorum. The request has no approval yet, so none is lost by recomputing it.
+	required := max(request.Spec.RequiredApprovals, 1)
+	setRequiredApprovals(p.ctx, &request.Spec)
+	if updated := max(request.Spec.RequiredApprovals, 1); updated != required {
+		changes = append(changes, fmt.Sprintf("required approvals %d -> %d", required, updated))
+	}
+	// The update is based on the version read above, so it fails rather than overwrite a concurrent approval.
+	if err := k8s.UpdateAccessRequestAsApp(p.ctx, request); err != nil {
+		if k8s.IsConflict(err) {
+			p.sendError("The request changed in the meantime, reload it and try again", err, "Request")
+			return
+		}
+		p.sendError("Failed to update the request", err, "Request")
+		return
+	}
+	invalidateAccessRequestCache()
+	logger.Logger.Info("Pending request updated by its requestor", "request", request.Name, "user", p.userInfo.Email, "changes", changes)
+
+	notifyLifecycleEvent(newRequestEvent("request.updated", request, p.userInfo.Email, strings.Join(changes, ", ")))
+	p.logAndBroadcast(LogEntry{
+		Payload: fmt.Sprintf("UPDATED: Request from %s changed by %s: %s.",
+			requestorLabel(request.Spec), p.userInfo.Email, strings.Join(changes, ", ")),
+		ClassName: "log-info",
+		LogType:   "Request",
+		Type:      "applyResult",
+		Groups:    routedGroups(p.ctx, request.Spec),
+		Owners:    routedOwners(p.ctx, request.Spec),
+	})
+}
+
+// updateRequest applies the changes of its requestor to a pending request, which, unlike an approver's, may widen
+// it. It returns the changes made, for the activity log.
+func updateRequest(spec *netwatchv1alpha1.AccessRequestSpec, payload webSocketPayload) []string {
+	var changes []string
+	if ports := strings.TrimSpace(payload.Ports); ports != "" && ports != spec.Ports {
+		changes = append(changes, fmt.Sprintf("ports %s -> %s", portsLabel(spec.Ports), ports))
+		spec.Ports = ports
+	}
+	if payload.Duration != 0 && payload.Duration != spec.Duration {
+		changes = append(changes, fmt.Sprintf("duration %s -> %s", durationLabel(spec.Duration), durationLabel(payload.Duration)))
+		spec.Duration = payload.Duration
+	}
+	if description := strings.TrimSpace(payload.Description); description != "" && description != spec.Description {
+		changes = append(changes, "description")
+		spec.Description = description
+	}
+	return changes
+}
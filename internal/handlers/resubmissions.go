@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// maxRequestHistory bounds how many earlier submissions of a resubmitted request are followed.
+const maxRequestHistory = 10
+
+// PriorDecision is the decision on an earlier submission of a resubmitted request.
+type PriorDecision struct {
+	RequestID string `json:"requestID"`
+	// Request is the name the AccessRequest had.
+	Request   string `json:"request"`
+	Decision  string `json:"decision" example:"denied"`
+	DecidedBy string `json:"decidedBy,omitempty" example:"approver@example.com"`
+	// Reason is why the request was denied, when it was said.
+	Reason      string `json:"reason,omitempty" example:"Use the staging database instead"`
+	SubmittedAt int64  `json:"submittedAt" example:"1760000000"`
+	DecidedAt   int64  `json:"decidedAt" example:"1760000600"`
+}
+
+// decisionCache keeps the AccessDecisions already read, by name. They never change.
+var decisionCache sync.Map
+
+func getAccessDecision(ctx context.Context, name string) (*netwatchv1alpha1.AccessDecision, error) {
+	if cached, ok := decisionCache.Load(name); ok {
+		return cached.(*netwatchv1alpha1.AccessDecision), nil
+	}
+	decision, err := k8s.GetAccessDecisionAsApp(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	decisionCache.Store(name, decision)
+	return decision, nil
+}
+
+// findAccessDecision returns the decision on a request, given its RequestID or the name of its AccessRequest, or
+// nil when it was never decided.
+func findAccessDecision(ctx context.Context, ref string) (*netwatchv1alpha1.AccessDecision, error) {
+	decision, err := getAccessDecision(ctx, ref)
+	if err == nil || !k8s.IsNotFound(err) {
+		return decision, err
+	}
+	list, err := k8s.ListAccessDecisionsAsApp(ctx, client.MatchingLabels{netwatchv1alpha1.RequestLabel: ref})
+	if err != nil || len(list.Items) == 0 {
+		return nil, err
+	}
+	return &list.Items[0], nil
+}
+
+// invalidResubmission reports, and rejects, a submission resubmitting a request it can't follow: one never decided,
+// approved or aborted, of another requestor, or connecting another source or target. A valid resubmission is
+// linked to the request it follows.
+func (p *webSocketCommandProcessor) invalidResubmission(spec *netwatchv1alpha1.AccessRequestSpec, previous string) bool {
+	if previous = strings.TrimSpace(previous); previous == "" {
+		return false
+	}
+	decision, err := findAccessDecision(p.ctx, previous)
+	if err != nil {
+		p.sendError("Could not look up the resubmitted request", err, "Request")
+		return true
+	}
+	if decision == nil {
+		p.sendError(fmt.Sprintf("Request %s was not found among the decided requests", previous), nil, "Request")
+		return true
+	}
+	prior := decision.Spec.AccessRequest
+	switch {
+	case decision.Spec.Decision != "denied" && decision.Spec.Decision != "expired":
+		p.sendError(fmt.Sprintf("Request %s was %s, only denied and expired requests can be resubmitted", previous, decision.Spec.Decision), nil, "Request")
+	case prior.Requestor != spec.Requestor:
+		p.sendError(fmt.Sprintf("Request %s was submitted for another requestor", previous), nil, "Request")
+	case prior.SourceService != spec.SourceService || prior.TargetService != spec.TargetService || prior.Service != spec.Service:
+		p.sendError(fmt.Sprintf("A resubmission must connect the same source and target as request %s", previous), nil, "Request")
+	default:
+		spec.PreviousRequestID = decision.Name
+		return false
+	}
+	return true
+}
+
+// priorDecisions lists the decisions on the earlier submissions of a resubmitted request, the latest first.
+func priorDecisions(ctx context.Context, spec netwatchv1alpha1.AccessRequestSpec) []PriorDecision {
+	var history []PriorDecision
+	for previous := spec.PreviousRequestID; previous != "" && len(history) < maxRequestHistory; {
+		decision, err := getAccessDecision(ctx, previous)
+		if err != nil {
+			if !k8s.IsNotFound(err) {
+				logger.Logger.Warn("Failed to get the decision on an earlier submission", "error", err, "requestID", previous)
+			}
+			break
+		}
+		history = append(history, PriorDecision{
+			RequestID:   decision.Name,
+			Request:     decision.Spec.Request,
+			Decision:    decision.Spec.Decision,
+			DecidedBy:   decision.Spec.DecidedBy,
+			Reason:      decision.Spec.Comment,
+			SubmittedAt: decision.Spec.SubmittedAt.Unix(),
+			DecidedAt:   decision.Spec.DecidedAt.Unix(),
+		})
+		previous = decision.Spec.AccessRequest.PreviousRequestID
+	}
+	return history
+}
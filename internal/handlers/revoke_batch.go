@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	"github.com/gin-gonic/gin"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// RevokeBatchInput selects the accesses to revoke, by request ID, by label selector, or both.
+type RevokeBatchInput struct {
+	RequestIDs []string `json:"requestIDs,omitempty" example:"0b7f8a4e-2f4c-4c1e-9f0a-6f1d2b3c4d5e"`
+	// LabelSelector is matched against the labels of the Access and ExternalAccess objects, e.g.
+	// netwatch.vtk.io/user=jane.doe-example.com for all the accesses of a user.
+	LabelSelector string `json:"labelSelector,omitempty" example:"netwatch.vtk.io/user=jane.doe-example.com"`
+}
+
+// RevokeBatchItem is the outcome of revoking the accesses of one request.
+type RevokeBatchItem struct {
+	RequestID string `json:"requestID"`
+	// Status is "revoked", "failed", or "notFound" when the request has no access left.
+	Status  string   `json:"status" example:"revoked"`
+	Revoked []string `json:"revoked,omitempty" example:"frontend/access-nc-1a2b3c4d5e6f-30623766"`
+	Clones  []string `json:"clones,omitempty" example:"frontend/nc-1a2b3c4d5e6f-30623766"`
+	// Error is why the revocation failed. Part of the accesses may still have been revoked.
+	Error string `json:"error,omitempty"`
+}
+
+// RevokeBatchResult is the outcome of a bulk revocation, one item per request.
+type RevokeBatchResult struct {
+	Items  []RevokeBatchItem `json:"items"`
+	Failed int               `json:"failed" example:"0"`
+}
+
+// RevokeBatch revokes the accesses of several requests at once.
+// RevokeBatch godoc
+// @Summary      Revoke accesses in bulk
+// @Description  Revokes the accesses of every request listed, and of every request with an Access or ExternalAccess matching the label selector, like the revokeClusterAccess and revokeExternalAccess WebSocket commands: both sides of a Service access are deleted, with the caller's own permissions. Each request gets its own result, a failure does not stop the others.
+// @Tags         Access Policies
+// @Accept       json
+// @Produce      json
+// @Param        revocation       body    handlers.RevokeBatchInput  true   "Accesses to revoke"
+// @Param        Idempotency-Key  header  string                     false  "Replays the first response to a retry with the same key, for 24 hours"
+// @Success      200  {object}  handlers.RevokeBatchResult
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      409  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /accesses/revoke-batch [post]
+func RevokeBatch(c *gin.Context) {
+	ctx := c.Request.Context()
+	runner := newCommandRunner(c, "api")
+	if runner == nil {
+		return
+	}
+	var input RevokeBatchInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if len(input.RequestIDs) == 0 && input.LabelSelector == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Set 'requestIDs', 'labelSelector', or both"})
+		return
+	}
+	var selector labels.Selector
+	if input.LabelSelector != "" {
+		var err error
+		if selector, err = labels.Parse(input.LabelSelector); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'labelSelector': " + err.Error()})
+			return
+		}
+	}
+
+	var accesses vtkiov1alpha1.AccessList
+	var extAccesses vtkiov1alpha1.ExternalAccessList
+	if err := k8s.ListNetwatchAccesses(ctx, &accesses); err != nil {
+		logger.Logger.Error("Failed to list accesses for bulk revocation", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list accesses"})
+		return
+	}
+	if err := k8s.ListNetwatchExternalAccesses(ctx, &extAccesses); err != nil {
+		logger.Logger.Error("Failed to list external accesses for bulk revocation", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list external accesses"})
+		return
+	}
+
+	// Revoking one side of a Service access revokes its pair, so a single command per request is enough.
+	commands := make(map[string]webSocketPayload)
+	requestIDs := slices.Clone(input.RequestIDs)
+	add := func(objLabels map[string]string, payload webSocketPayload) {
+		requestID := objLabels["netwatch.vtk.io/request-id"]
+		if requestID == "" {
+			return
+		}
+		if _, ok := commands[requestID]; !ok {
+			commands[requestID] = payload
+		}
+		if selector != nil && selector.Matches(labels.Set(objLabels)) && !slices.Contains(requestIDs, requestID) {
+			requestIDs = append(requestIDs, requestID)
+		}
+	}
+	for _, access := range accesses.Items {
+		add(access.Labels, webSocketPayload{Command: "revokeClusterAccess", Namespace: access.Namespace, Name: access.Name})
+	}
+	for _, access := range extAccesses.Items {
+		add(access.Labels, webSocketPayload{Command: "revokeExternalAccess", Namespace: access.Namespace, Name: access.Name})
+	}
+
+	result := RevokeBatchResult{Items: make([]RevokeBatchItem, 0, len(requestIDs))}
+	seen := make(map[string]bool)
+	for _, requestID := range requestIDs {
+		if seen[requestID] {
+			continue
+		}
+		seen[requestID] = true
+		item := RevokeBatchItem{RequestID: requestID}
+		payload, found := commands[requestID]
+		if !found {
+			item.Status = "notFound"
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		commandResult, ok := runner.dispatch(payload)
+		item.Revoked, item.Clones = commandResult.Revoked, commandResult.Clones
+		if ok {
+			item.Status = "revoked"
+		} else {
+			item.Status = "failed"
+			result.Failed++
+			for _, message := range commandResult.Messages {
+				if message.ClassName == "log-error" {
+					item.Error = message.Payload
+				}
+			}
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	c.JSON(http.StatusOK, result)
+}
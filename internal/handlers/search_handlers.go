@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// defaultSearchLimit caps the number of hits returned per category when no limit is given.
+const defaultSearchLimit = 100
+
+// SearchResults groups the matches of a search query by source.
+type SearchResults struct {
+	Query    string                 `json:"query"`
+	Requests []AccessRequestPayload `json:"requests"`
+	// Decisions are the requests approved, denied, aborted or expired, which are no longer pending.
+	Decisions []AccessDecisionInfo `json:"decisions"`
+	Logs      []LogEntry           `json:"logs"`
+}
+
+// Search looks up a term in pending access requests, in the decisions on past ones and in the activity log.
+// Search godoc
+// @Summary      Search requests and activity history
+// @Description  Case-insensitive search over request descriptions, requestors, service names, ticket references, labels and activity log payloads.
+// @Description  Pending requests are matched by submission time, decisions by decision time, with their approvers and comments too. Activity log matches are bounded by the log retention. Restricted to administrators.
+// @Tags         Admin
+// @Produce      json
+// @Param        q      query     string  true   "Search term"
+// @Param        since  query     string  false  "Only return results created after this time (RFC3339)"
+// @Param        until  query     string  false  "Only return results created before this time (RFC3339)"
+// @Param        limit  query     int     false  "Maximum number of results per category (default 100)"
+// @Success      200  {object}  SearchResults
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      403  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /admin/search [get]
+func Search(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	query := strings.ToLower(strings.TrimSpace(c.Query("q")))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The 'q' query parameter is required"})
+		return
+	}
+	since, err := parseSearchTime(c.Query("since"), time.Time{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'since' parameter, expected RFC3339"})
+		return
+	}
+	until, err := parseSearchTime(c.Query("until"), time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'until' parameter, expected RFC3339"})
+		return
+	}
+	limit := defaultSearchLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'limit' parameter"})
+			return
+		}
+	}
+
+	results := SearchResults{Query: query, Requests: []AccessRequestPayload{}, Decisions: []AccessDecisionInfo{}, Logs: []LogEntry{}}
+
+	requestList, err := k8s.ListAccessRequestsAsApp(ctx)
+	if err != nil {
+		logger.Logger.Error("Failed to list AccessRequests for search", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search requests"})
+		return
+	}
+	for _, request := range requestList.Items {
+		created := request.CreationTimestamp.Time
+		if created.Before(since) || created.After(until) {
+			continue
+		}
+		labels := fromRequestObjectLabels(request.Labels)
+		if !containsTerm(requestHaystack(request.Name, requestDisplayName(&request), request.Spec, labels), query) {
+			continue
+		}
+		results.Requests = append(results.Requests, AccessRequestPayload{
+			RequestID:     request.Name,
+			DisplayName:   requestDisplayName(&request),
+			Requestor:     request.Spec.Requestor,
+			FiledBy:       request.Spec.FiledBy,
+			Timestamp:     request.CreationTimestamp.Unix(),
+			RequestType:   request.Spec.RequestType,
+			SourceService: request.Spec.SourceService,
+			TargetService: request.Spec.TargetService,
+			Cidr:          request.Spec.Cidr,
+			Service:       request.Spec.Service,
+			Direction:     request.Spec.Direction,
+			Ports:         request.Spec.Ports,
+			Duration:      request.Spec.Duration,
+			Description:   request.Spec.Description,
+			TicketRef:     request.Spec.TicketRef,
+			Priority:      requestPriority(request.Spec),
+			Status:        request.Spec.Status,
+			Labels:        labels,
+		})
+	}
+	sort.Slice(results.Requests, func(i, j int) bool { return results.Requests[i].Timestamp > results.Requests[j].Timestamp })
+	if len(results.Requests) > limit {
+		results.Requests = results.Requests[:limit]
+	}
+
+	// Approved requests are deleted, their AccessDecision is the only record left once the log is trimmed.
+	decisions, err := k8s.ListAccessDecisionsAsApp(ctx)
+	if err != nil {
+		logger.Logger.Error("Failed to list AccessDecisions for search", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search decisions"})
+		return
+	}
+	for _, record := range decisions.Items {
+		decided := record.Spec.DecidedAt.Time
+		if decided.Before(since) || decided.After(until) {
+			continue
+		}
+		spec := record.Spec.AccessRequest
+		haystack := append(requestHaystack(record.Spec.Request, record.Spec.Request, spec, nil), record.Spec.DecidedBy, record.Spec.Comment)
+		if containsTerm(haystack, query) {
+			results.Decisions = append(results.Decisions, AccessDecisionInfo{Name: record.Name, Spec: record.Spec})
+		}
+	}
+	sort.Slice(results.Decisions, func(i, j int) bool {
+		return results.Decisions[i].Spec.DecidedAt.After(results.Decisions[j].Spec.DecidedAt.Time)
+	})
+	if len(results.Decisions) > limit {
+		results.Decisions = results.Decisions[:limit]
+	}
+
+	// Scan the activity log newest first, within the requested time range.
+	minScore := logRetentionCutoff()
+	if since.After(time.Now().Add(-logRetention)) {
+		minScore = strconv.FormatInt(since.UnixMilli(), 10)
+	}
+	logData, err := redisClient.ZRevRangeByScore(ctx, logKey, &redis.ZRangeBy{
+		Min: minScore,
+		Max: strconv.FormatInt(until.UnixMilli(), 10),
+	}).Result()
+	if err != nil {
+		logger.Logger.Error("Failed to fetch logs from Redis for search", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not search activity log"})
+		return
+	}
+	for _, entryJSON := range logData {
+		if len(results.Logs) >= limit {
+			break
+		}
+		if !strings.Contains(strings.ToLower(entryJSON), query) {
+			continue
+		}
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(entryJSON), &entry); err != nil {
+			continue
+		}
+		if strings.Contains(strings.ToLower(entry.Payload), query) {
+			results.Logs = append(results.Logs, entry)
+		}
+	}
+
+	c.JSON(http.StatusOK, results)
+}
+
+func parseSearchTime(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// requestHaystack lists the fields of a request a search term is looked up in.
+func requestHaystack(name, displayName string, spec netwatchv1alpha1.AccessRequestSpec, labels map[string]string) []string {
+	haystack := []string{
+		name, displayName, spec.Requestor, spec.FiledBy, spec.Description, spec.SourceService,
+		spec.TargetService, spec.Service, spec.Cidr, spec.TicketRef,
+	}
+	for key, value := range labels {
+		haystack = append(haystack, key+"="+value)
+	}
+	return haystack
+}
+
+func containsTerm(fields []string, term string) bool {
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), term) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+)
+
+// RecordAuthLockout adds a client banned for repeated authentication failures to the activity log.
+func RecordAuthLockout(ip string, failures int64, ban time.Duration) {
+	persistLogEntry(LogEntry{
+		Payload:   fmt.Sprintf("SECURITY: %s banned for %s after %d failed authentication attempts.", ip, ban, failures),
+		ClassName: "log-warning", LogType: "Global", Type: "applyResult",
+	})
+}
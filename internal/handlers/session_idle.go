@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/sessions"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// sessionActivityPrefix keys the last activity of a login session. The key expires after the idle timeout,
+// so a session without one has been idle for too long.
+const sessionActivityPrefix = "netwatch:session_activity:"
+
+// sessionIdleTimeout ends login sessions left unused for that long, independently of the session TTL. 0 disables it.
+var sessionIdleTimeout time.Duration
+
+var errSessionIdle = errors.New("session ended after inactivity")
+
+// SetSessionIdleTimeout sets how long a login session may stay unused before it is ended.
+func SetSessionIdleTimeout(timeout time.Duration) {
+	sessionIdleTimeout = timeout
+}
+
+// idleWarningLead is how long before the idle timeout the WebSocket warns the user.
+func idleWarningLead() time.Duration {
+	return min(time.Minute, sessionIdleTimeout/5)
+}
+
+// touchSession records activity on a login session, restarting its idle timeout.
+func touchSession(ctx context.Context, sessionID string) {
+	if sessionIdleTimeout <= 0 || sessionID == "" {
+		return
+	}
+	if err := redisClient.Set(ctx, sessionActivityPrefix+sessionID, time.Now().Unix(), sessionIdleTimeout).Err(); err != nil {
+		logger.Logger.Error("Failed to record session activity", "error", err)
+	}
+}
+
+// sessionIdleRemaining returns how long a login session may still stay unused, 0 once it has been idle for too long.
+func sessionIdleRemaining(ctx context.Context, sessionID string) (time.Duration, error) {
+	ttl, err := redisClient.PTTL(ctx, sessionActivityPrefix+sessionID).Result()
+	if err != nil {
+		return 0, err
+	}
+	// PTTL answers a negative value for a missing key.
+	return max(ttl, 0), nil
+}
+
+// sessionIdle reports whether a login session has been unused for longer than the idle timeout.
+// Redis errors leave the session alone, the next check decides.
+func sessionIdle(ctx context.Context, sessionID string) bool {
+	if sessionIdleTimeout <= 0 || sessionID == "" {
+		return false
+	}
+	remaining, err := sessionIdleRemaining(ctx, sessionID)
+	if err != nil {
+		logger.Logger.Error("Failed to read session activity", "error", err)
+		return false
+	}
+	return remaining == 0
+}
+
+// checkSessionActivity ends the browser session when it has been idle for too long, and records the activity otherwise.
+func checkSessionActivity(c *gin.Context, session *sessions.Session) error {
+	if session.IsNew {
+		return nil
+	}
+	if sessionIdle(c.Request.Context(), session.ID) {
+		logger.Logger.Info("Ending idle session", "user", session.Values["user"])
+		session.Values["id_token"] = ""
+		session.Values["user"] = ""
+		session.Options.MaxAge = -1 // Deletes the session from Redis and expires the cookie.
+		if err := session.Save(c.Request, c.Writer); err != nil {
+			return fmt.Errorf("%w: %w", errSessionIdle, err)
+		}
+		return errSessionIdle
+	}
+	touchSession(c.Request.Context(), session.ID)
+	return nil
+}
+
+// watchSessionIdle warns a WebSocket client shortly before its session times out for inactivity, then ends the
+// session and closes the connection once it has.
+func watchSessionIdle(ctx context.Context, sessionID string, sendPrivate func(LogEntry), closeConn func()) {
+	ticker := time.NewTicker(max(min(sessionIdleTimeout/10, 15*time.Second), time.Second))
+	defer ticker.Stop()
+	warned := false
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		remaining, err := sessionIdleRemaining(ctx, sessionID)
+		if err != nil {
+			continue
+		}
+		if remaining == 0 {
+			// The session bound accesses are revoked by the heartbeat monitor once the session is gone.
+			redisClient.Del(ctx, sessionKeyPrefix+sessionID) //nolint:all
+			sendPrivate(LogEntry{
+				Payload:   "Your session ended after inactivity. Please log in again.",
+				ClassName: "log-error", LogType: "Global", Type: "sessionExpired",
+			})
+			closeConn()
+			return
+		}
+		if remaining > idleWarningLead() {
+			warned = false
+			continue
+		}
+		if !warned {
+			sendPrivate(LogEntry{
+				Payload:   fmt.Sprintf("Your session will end in %s due to inactivity.", remaining.Round(time.Second)),
+				ClassName: "log-warning", LogType: "Global", Type: "idleWarning",
+			})
+			warned = true
+		}
+	}
+}
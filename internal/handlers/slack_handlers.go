@@ -0,0 +1,260 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	slackLinkTokenPrefix = "netwatch:slack_link:"
+	slackIdentityPrefix  = "netwatch:slack_identity:"
+	slackLinkTokenTTL    = 10 * time.Minute
+	// slackMaxClockSkew rejects replayed Slack requests, as recommended by the Slack signing documentation.
+	slackMaxClockSkew = 5 * time.Minute
+)
+
+const slackUsage = "Usage:\n" +
+	"• `/netwatch link` - link your Slack account to your Netwatch identity\n" +
+	"• `/netwatch request <target ns/name> from <source ns/name> <duration> [ticket=<ref>] [priority=low|normal|urgent] [description]` - submit an access request, e.g. `/netwatch request prod/db from dev/api 2h ticket=OPS-42`"
+
+var slackSigningSecret string
+
+// SetSlackSigningSecret enables the Slack slash command endpoint, verifying requests with the app signing secret.
+func SetSlackSigningSecret(secret string) {
+	slackSigningSecret = secret
+}
+
+type slackResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// HandleSlackCommand serves the Netwatch Slack slash command.
+func HandleSlackCommand(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, 64*1024))
+	if err != nil {
+		c.Status(http.StatusBadRequest)
+		return
+	}
+	if !verifySlackSignature(c.GetHeader("X-Slack-Request-Timestamp"), c.GetHeader("X-Slack-Signature"), body) {
+		logger.Logger.Warn("Rejected Slack command with an invalid signature", "ip", c.ClientIP())
+		c.Status(http.StatusUnauthorized)
+		return
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	slackUserID := c.PostForm("user_id")
+	fields := strings.Fields(c.PostForm("text"))
+	if slackUserID == "" || len(fields) == 0 {
+		c.JSON(http.StatusOK, slackResponse{ResponseType: "ephemeral", Text: slackUsage})
+		return
+	}
+
+	ctx := c.Request.Context()
+	switch fields[0] {
+	case "link":
+		c.JSON(http.StatusOK, slackResponse{ResponseType: "ephemeral", Text: startSlackLink(ctx, slackUserID, GetBaseURL(c.Request))})
+	case "request":
+		c.JSON(http.StatusOK, slackResponse{ResponseType: "ephemeral", Text: submitSlackRequest(ctx, slackUserID, fields[1:])})
+	default:
+		c.JSON(http.StatusOK, slackResponse{ResponseType: "ephemeral", Text: slackUsage})
+	}
+}
+
+// HandleSlackLink completes the linking flow: the browser session proves the OIDC identity of the Slack user.
+func HandleSlackLink(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	idToken, err := getUserIdToken(c)
+	if err != nil {
+		c.String(http.StatusUnauthorized, "Please log in to Netwatch first, then open this link again.")
+		return
+	}
+	userInfo, err := k8s.GetUserInfoFromToken(ctx, idToken)
+	if err != nil {
+		c.String(http.StatusUnauthorized, "Your session is invalid, please log in to Netwatch again.")
+		return
+	}
+
+	// GetDel makes the link token single-use.
+	slackUserID, err := redisClient.GetDel(ctx, slackLinkTokenPrefix+c.Query("token")).Result()
+	if err != nil {
+		if err != redis.Nil {
+			logger.Logger.Error("Failed to read Slack link token", "error", err)
+		}
+		c.String(http.StatusBadRequest, "This link is invalid or has expired. Run `/netwatch link` again in Slack.")
+		return
+	}
+	if err := redisClient.Set(ctx, slackIdentityPrefix+slackUserID, userInfo.Email, 0).Err(); err != nil {
+		logger.Logger.Error("Failed to store Slack identity", "error", err)
+		c.String(http.StatusInternalServerError, "Could not link your Slack account.")
+		return
+	}
+
+	logger.Logger.Info("Slack account linked", "user", userInfo.Email, "slackUser", slackUserID)
+	c.String(http.StatusOK, fmt.Sprintf("Your Slack account is now linked to %s. You can close this page.", userInfo.Email))
+}
+
+func startSlackLink(ctx context.Context, slackUserID, baseURL string) string {
+	tokenBytes := make([]byte, 24)
+	rand.Read(tokenBytes) //nolint:all
+	token := hex.EncodeToString(tokenBytes)
+	if err := redisClient.Set(ctx, slackLinkTokenPrefix+token, slackUserID, slackLinkTokenTTL).Err(); err != nil {
+		logger.Logger.Error("Failed to store Slack link token", "error", err)
+		return "Could not start the linking flow, please try again later."
+	}
+	return fmt.Sprintf("Open %s/slack/link?token=%s while logged in to Netwatch to link your account. The link expires in %s.",
+		baseURL, url.QueryEscape(token), slackLinkTokenTTL)
+}
+
+func submitSlackRequest(ctx context.Context, slackUserID string, args []string) string {
+	email, err := redisClient.Get(ctx, slackIdentityPrefix+slackUserID).Result()
+	if err != nil {
+		return "Your Slack account is not linked yet. Run `/netwatch link` first."
+	}
+
+	if len(args) < 4 || args[1] != "from" {
+		return slackUsage
+	}
+	target, source := args[0], args[2]
+	duration, err := time.ParseDuration(args[3])
+	if err != nil || duration <= 0 {
+		return fmt.Sprintf("Invalid duration %q, use a value such as `30m` or `2h`.", args[3])
+	}
+	for _, svc := range []string{target, source} {
+		parts := strings.Split(svc, "/")
+		if len(parts) != 2 {
+			return fmt.Sprintf("Invalid service %q, expected namespace/name.", svc)
+		}
+		if _, err := k8s.GetServiceAsApp(ctx, parts[0], parts[1]); err != nil {
+			return fmt.Sprintf("Service %q could not be found.", svc)
+		}
+	}
+
+	description, options := slackRequestOptions(args[4:])
+	payload := webSocketPayload{
+		Command:       "submitAccessRequest",
+		SourceService: source,
+		TargetService: target,
+		Direction:     "all",
+		Duration:      int64(math.Ceil(duration.Seconds())),
+		Description:   description,
+		TicketRef:     options["ticket"],
+		Priority:      options["priority"],
+	}
+
+	// The request is created by the app on behalf of the user. Without an ID token there is nothing to impersonate,
+	// so the user's own permissions are never used to pre-provision part of the access: an approver handles all of it.
+	requestID := uuid.New().String()
+	requestCR := &netwatchv1alpha1.AccessRequest{
+		ObjectMeta: accessRequestMeta(sanitizeUsername(email), requestID, nil),
+		Spec: netwatchv1alpha1.AccessRequestSpec{
+			Requestor:     email,
+			RequestID:     requestID,
+			RequestType:   submissionType(payload),
+			SourceService: payload.SourceService,
+			TargetService: payload.TargetService,
+			Direction:     payload.Direction,
+			Duration:      payload.Duration,
+			Description:   payload.Description,
+			TicketRef:     payload.TicketRef,
+			Priority:      payload.Priority,
+			Status:        "PendingFull",
+		},
+	}
+	processor, failure := slackProcessor(ctx, email)
+	if processor.refusedByPolicy(&requestCR.Spec) {
+		return fmt.Sprintf("Your access request was refused: %s.", *failure)
+	}
+	processor.fileRequest(requestCR, payload)
+	if *failure != "" {
+		return fmt.Sprintf("Failed to submit your access request: %s.", *failure)
+	}
+	logger.Logger.Info("Access request submitted from Slack", "user", email, "request", requestCR.Name)
+	return fmt.Sprintf("Access request `%s` submitted: %s -> %s for %s.%s An approver will review it in the Access Request Hub.",
+		requestDisplayName(requestCR), source, target, duration, prioritySuffix(requestCR.Spec))
+}
+
+// slackRequestOptionKeys are the keys of the key=value options of `/netwatch request`, given anywhere after the
+// duration.
+var slackRequestOptionKeys = []string{"ticket", "priority"}
+
+// slackRequestOptions splits the words after the duration of `/netwatch request` into the description and the
+// options. Other words with '=' belong to the description.
+func slackRequestOptions(words []string) (string, map[string]string) {
+	options := make(map[string]string)
+	var description []string
+	for _, word := range words {
+		key, value, ok := strings.Cut(word, "=")
+		if ok && slices.Contains(slackRequestOptionKeys, strings.ToLower(key)) {
+			options[strings.ToLower(key)] = value
+			continue
+		}
+		description = append(description, word)
+	}
+	return strings.Join(description, " "), options
+}
+
+// slackProcessor runs the submissions of a linked Slack user through the same checks as the WebSocket. What it would
+// broadcast goes to the activity log, the reason a command failed is returned for the Slack reply.
+func slackProcessor(ctx context.Context, email string) (*webSocketCommandProcessor, *string) {
+	var failure string
+	userInfo := &k8s.UserInfo{Email: email, Provider: "slack"}
+	return &webSocketCommandProcessor{
+		ctx:               ctx,
+		userInfo:          userInfo,
+		sanitizedUsername: sanitizeUsername(email),
+		logAndBroadcast: func(entry LogEntry) {
+			entry.User = email
+			persistLogEntry(entry)
+		},
+		sendPrivate: func(LogEntry) {},
+		sendError: func(msg string, err error, logType string) {
+			if err != nil {
+				msg = fmt.Sprintf("%s - %s", msg, err.Error())
+			}
+			failure = msg
+			logger.Logger.Warn("Slack command failed", "user", email, "reason", msg)
+			persistLogEntry(LogEntry{
+				Payload: "REQUEST FAILED: " + msg, ClassName: "log-error", LogType: logType, Type: "applyResult", User: email,
+			})
+		},
+		channel: "slack",
+	}, &failure
+}
+
+// verifySlackSignature checks the v0 HMAC-SHA256 signature Slack attaches to every request.
+func verifySlackSignature(timestamp, signature string, body []byte) bool {
+	if slackSigningSecret == "" || timestamp == "" || signature == "" {
+		return false
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(ts, 0)).Abs() > slackMaxClockSkew {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(slackSigningSecret))
+	fmt.Fprintf(mac, "v0:%s:%s", timestamp, body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
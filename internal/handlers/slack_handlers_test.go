@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"maps"
+	"testing"
+)
+
+func TestSlackRequestOptions(t *testing.T) {
+	tests := []struct {
+		name            string
+		words           []string
+		wantDescription string
+		wantOptions     map[string]string
+	}{
+		{
+			name:        "no words",
+			wantOptions: map[string]string{},
+		},
+		{
+			name:            "a description",
+			words:           []string{"debug", "the", "payment", "flow"},
+			wantDescription: "debug the payment flow",
+			wantOptions:     map[string]string{},
+		},
+		{
+			name:            "a ticket among the description",
+			words:           []string{"debug", "Ticket=OPS-42", "the", "payment", "flow"},
+			wantDescription: "debug the payment flow",
+			wantOptions:     map[string]string{"ticket": "OPS-42"},
+		},
+		{
+			name:            "a ticket and a priority",
+			words:           []string{"ticket=OPS-42", "priority=urgent", "outage"},
+			wantDescription: "outage",
+			wantOptions:     map[string]string{"ticket": "OPS-42", "priority": "urgent"},
+		},
+		{
+			name:            "unknown options stay in the description",
+			words:           []string{"set", "retries=3"},
+			wantDescription: "set retries=3",
+			wantOptions:     map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description, options := slackRequestOptions(tt.words)
+			if description != tt.wantDescription || !maps.Equal(options, tt.wantOptions) {
+				t.Errorf("got %q %v, want %q %v", description, options, tt.wantDescription, tt.wantOptions)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// topRequestorsCount is how many requestors the usage statistics rank.
+const topRequestorsCount = 10
+
+// DailyRequestStats counts the access requests of a day, in UTC.
+type DailyRequestStats struct {
+	Date      string `json:"date" example:"2025-06-10"`
+	Submitted int    `json:"submitted" example:"12"`
+	Approved  int    `json:"approved" example:"9"`
+	Denied    int    `json:"denied" example:"2"`
+}
+
+// RequestorStats counts the access requests of a user.
+type RequestorStats struct {
+	Requestor string `json:"requestor" example:"jane.doe@example.com"`
+	Requests  int    `json:"requests" example:"7"`
+}
+
+// UsageStats aggregates how Netwatch is used.
+type UsageStats struct {
+	// Days are the days of the period, oldest first. Requests are counted on the day they were submitted, approvals
+	// and denials on the day they were decided.
+	Days []DailyRequestStats `json:"days"`
+	// ActiveAccessesByNamespace counts the active accesses by the namespace of their Access or ExternalAccess object.
+	ActiveAccessesByNamespace map[string]int `json:"activeAccessesByNamespace"`
+	// TopRequestors ranks who submitted the most requests during the period.
+	TopRequestors []RequestorStats `json:"topRequestors"`
+	// AverageApprovalSeconds is the average time between submission and approval of the requests approved during
+	// the period, 0 when there were none.
+	AverageApprovalSeconds int64 `json:"averageApprovalSeconds" example:"840"`
+	// ReviewSLA measures the time to decision of the requests decided during the period.
+	ReviewSLA ReviewSLAStats `json:"reviewSLA"`
+}
+
+// ReviewSLAStats measures how long access requests wait for a decision, against NETWATCH_REVIEW_SLA.
+type ReviewSLAStats struct {
+	// TargetSeconds and UrgentTargetSeconds are the SLAs of normal and urgent requests, 0 when no SLA is set.
+	TargetSeconds       int64 `json:"targetSeconds" example:"14400"`
+	UrgentTargetSeconds int64 `json:"urgentTargetSeconds" example:"3600"`
+	// Decisions counts the requests approved or denied during the period, and the percentiles their time to
+	// decision.
+	Decisions             int   `json:"decisions" example:"11"`
+	MedianDecisionSeconds int64 `json:"medianDecisionSeconds" example:"720"`
+	P90DecisionSeconds    int64 `json:"p90DecisionSeconds" example:"9000"`
+	// Breached counts the requests decided after their SLA, or expired without a review, during the period.
+	Breached int `json:"breached" example:"2"`
+	// PendingOverdue counts the requests still pending past their SLA.
+	PendingOverdue int `json:"pendingOverdue" example:"1"`
+}
+
+// requestHistory is what is known of a request submitted in the last requestSubmissionRetention.
+type requestHistory struct {
+	timing    RequestTiming
+	requestor string
+	priority  string
+}
+
+// listRequestHistory returns the timing of every request still tracked, with its requestor when its submission
+// is still stored.
+func listRequestHistory(ctx context.Context) ([]requestHistory, error) {
+	var names []string
+	iter := redisClient.Scan(ctx, 0, requestTimingPrefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		names = append(names, iter.Val()[len(requestTimingPrefix):])
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	pipe := redisClient.Pipeline()
+	timings := make([]*redis.MapStringStringCmd, len(names))
+	submissions := make([]*redis.StringCmd, len(names))
+	for i, name := range names {
+		timings[i] = pipe.HGetAll(ctx, requestTimingPrefix+name)
+		submissions[i] = pipe.Get(ctx, requestSubmissionPrefix+name)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	history := make([]requestHistory, 0, len(names))
+	for i := range names {
+		fields := timings[i].Val()
+		parse := func(field string) int64 {
+			value, _ := strconv.ParseInt(fields[field], 10, 64)
+			return value
+		}
+		entry := requestHistory{timing: RequestTiming{
+			SubmittedAt: parse("submittedAt"),
+			DecidedAt:   parse("decidedAt"),
+			Decision:    fields["decision"],
+		}, priority: requestPriority(netwatchv1alpha1.AccessRequestSpec{Priority: fields["priority"]})}
+		if entry.timing.SubmittedAt == 0 {
+			continue
+		}
+		var submission RequestSubmission
+		if err := json.Unmarshal([]byte(submissions[i].Val()), &submission); err == nil {
+			entry.requestor = submission.Spec.Requestor
+		}
+		history = append(history, entry)
+	}
+	return history, nil
+}
+
+// GetStats returns usage statistics.
+// GetStats godoc
+// @Summary      Get usage statistics
+// @Description  Aggregates the access requests submitted, approved and denied per day, the active accesses per namespace, the top requestors, the average approval latency and the time to decision against the review SLA. Requests are tracked for 90 days, so longer periods are cut to that.
+// @Tags         System
+// @Produce      json
+// @Param        days  query     int  false  "Number of days covered, 30 by default and at most 90"
+// @Success      200  {object}  handlers.UsageStats
+// @Failure      400  {object}  handlers.HTTPError
+// @Failure      401  {object}  handlers.HTTPError
+// @Failure      500  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /stats [get]
+func GetStats(c *gin.Context) {
+	ctx := c.Request.Context()
+	maxDays := int(requestSubmissionRetention / (24 * time.Hour))
+	days := 30
+	if daysStr := c.Query("days"); daysStr != "" {
+		var err error
+		if days, err = strconv.Atoi(daysStr); err != nil || days <= 0 || days > maxDays {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid 'days' parameter"})
+			return
+		}
+	}
+
+	history, err := listRequestHistory(ctx)
+	if err != nil {
+		logger.Logger.Error("Failed to read request history", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Could not compute statistics"})
+		return
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 0, -(days - 1))
+	stats := UsageStats{
+		Days:                      make([]DailyRequestStats, days),
+		ActiveAccessesByNamespace: make(map[string]int),
+		TopRequestors:             []RequestorStats{},
+	}
+	for i := range stats.Days {
+		stats.Days[i].Date = start.AddDate(0, 0, i).Format(time.DateOnly)
+	}
+	// dayIndex returns the index of the day of a Unix timestamp in the period, -1 when outside of it.
+	dayIndex := func(unix int64) int {
+		if unix == 0 {
+			return -1
+		}
+		i := int(time.Unix(unix, 0).UTC().Sub(start) / (24 * time.Hour))
+		if unix < start.Unix() || i >= days {
+			return -1
+		}
+		return i
+	}
+
+	requestors := make(map[string]int)
+	var approvals, approvalSeconds int64
+	var decisionSeconds []int64
+	for _, entry := range history {
+		if i := dayIndex(entry.timing.SubmittedAt); i >= 0 {
+			stats.Days[i].Submitted++
+			if entry.requestor != "" {
+				requestors[entry.requestor]++
+			}
+		}
+		if i := dayIndex(entry.timing.DecidedAt); i >= 0 {
+			waited := entry.timing.DecidedAt - entry.timing.SubmittedAt
+			if entry.timing.Decision == "approved" || entry.timing.Decision == "denied" {
+				decisionSeconds = append(decisionSeconds, waited)
+			}
+			if entry.timing.Decision != "aborted" && slaBreached(entry.priority, time.Duration(waited)*time.Second) {
+				stats.ReviewSLA.Breached++
+			}
+			switch entry.timing.Decision {
+			case "approved":
+				stats.Days[i].Approved++
+				approvals++
+				approvalSeconds += entry.timing.DecidedAt - entry.timing.SubmittedAt
+			case "denied":
+				stats.Days[i].Denied++
+			}
+		}
+	}
+	if approvals > 0 {
+		stats.AverageApprovalSeconds = approvalSeconds / approvals
+	}
+	stats.ReviewSLA.TargetSeconds = int64(reviewSLA.Target.Seconds())
+	stats.ReviewSLA.UrgentTargetSeconds = int64(reviewSLA.UrgentTarget.Seconds())
+	stats.ReviewSLA.Decisions = len(decisionSeconds)
+	if len(decisionSeconds) > 0 {
+		slices.Sort(decisionSeconds)
+		// Nearest-rank percentiles.
+		percentile := func(p int) int64 { return decisionSeconds[(len(decisionSeconds)*p+99)/100-1] }
+		stats.ReviewSLA.MedianDecisionSeconds = percentile(50)
+		stats.ReviewSLA.P90DecisionSeconds = percentile(90)
+	}
+	if pending, err := cachedAccessRequests(ctx, nil); err == nil {
+		for i := range pending {
+			if _, overdue := requestAging(&pending[i]); overdue {
+				stats.ReviewSLA.PendingOverdue++
+			}
+		}
+	} else {
+		logger.Logger.Error("Failed to list pending requests for the statistics", "error", err)
+	}
+
+	for requestor, count := range requestors {
+		stats.TopRequestors = append(stats.TopRequestors, RequestorStats{Requestor: requestor, Requests: count})
+	}
+	sort.Slice(stats.TopRequestors, func(i, j int) bool {
+		if stats.TopRequestors[i].Requests != stats.TopRequestors[j].Requests {
+			return stats.TopRequestors[i].Requests > stats.TopRequestors[j].Requests
+		}
+		return stats.TopRequestors[i].Requestor < stats.TopRequestors[j].Requestor
+	})
+	if len(stats.TopRequestors) > topRequestorsCount {
+		stats.TopRequestors = stats.TopRequestors[:topRequestorsCount]
+	}
+
+	for _, info := range listActiveAccesses(ctx, activeAccessFilter{Status: "Active"}) {
+		stats.ActiveAccessesByNamespace[info.Namespace]++
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
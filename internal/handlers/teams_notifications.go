@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// TeamsConfig sends request events as adaptive cards to Microsoft Teams incoming webhooks.
+type TeamsConfig struct {
+	// WebhookURL receives the events of every request.
+	WebhookURL string
+	// NamespaceWebhookURLs receive the events of the requests involving their namespace.
+	NamespaceWebhookURLs map[string][]string
+}
+
+// SetTeamsNotifications enables Teams notifications, unless no webhook is configured.
+func SetTeamsNotifications(cfg TeamsConfig) {
+	if cfg.WebhookURL == "" && len(cfg.NamespaceWebhookURLs) == 0 {
+		return
+	}
+	target := teamsTarget{namespaces: cfg.NamespaceWebhookURLs}
+	if cfg.WebhookURL != "" {
+		target.global = []string{cfg.WebhookURL}
+	}
+	notificationTargets = append(notificationTargets, target)
+}
+
+type teamsTarget struct {
+	global     []string
+	namespaces map[string][]string
+}
+
+func (teamsTarget) name() string { return "teams" }
+
+// urls routes the request events, Teams channels don't follow accesses.
+func (t teamsTarget) urls(event LifecycleEvent) []string {
+	if _, ok := teamsEventStyles[event.Type]; !ok {
+		return nil
+	}
+	return routedURLs(t.global, t.namespaces, event)
+}
+
+func (teamsTarget) headers(LifecycleEvent, []byte) map[string]string { return nil }
+
+// teamsEventStyles are the title and container style of each event type.
+var teamsEventStyles = map[string][2]string{
+	"request.submitted": {"Access request submitted", "accent"},
+	"request.approved":  {"Access request approved", "good"},
+	"request.denied":    {"Access request denied", "attention"},
+	"request.expired":   {"Access request expired", "warning"},
+}
+
+// body builds the adaptive card of an event. Submitted events link to their decision links, so approvers can
+// decide from Teams.
+func (teamsTarget) body(event LifecycleEvent) ([]byte, error) {
+	style, ok := teamsEventStyles[event.Type]
+	if !ok {
+		return nil, fmt.Errorf("unknown event type %q", event.Type)
+	}
+	request := event.DisplayName
+	if request == "" {
+		request = event.RequestID
+	}
+	facts := []map[string]string{
+		{"title": "Request", "value": request},
+		{"title": "Requestor", "value": event.Requestor},
+	}
+	if event.Priority != "" && event.Priority != priorityNormal {
+		facts = append(facts, map[string]string{"title": "Priority", "value": event.Priority})
+	}
+	if event.Path != "" {
+		facts = append(facts, map[string]string{"title": "Path", "value": event.Path})
+	}
+	if event.Actor != "" {
+		actor := map[string]string{"request.submitted": "Filed by", "request.approved": "Approved by", "request.denied": "Denied by"}[event.Type]
+		facts = append(facts, map[string]string{"title": actor, "value": event.Actor})
+	}
+	if reviewers := append(slices.Clone(event.Owners), event.Groups...); len(reviewers) > 0 {
+		facts = append(facts, map[string]string{"title": "Reviewers", "value": strings.Join(reviewers, ", ")})
+	}
+	if event.Comment != "" {
+		facts = append(facts, map[string]string{"title": "Comment", "value": event.Comment})
+	}
+	body := []any{
+		map[string]any{
+			"type":  "Container",
+			"style": style[1],
+			"items": []any{map[string]any{"type": "TextBlock", "text": style[0], "weight": "Bolder", "size": "Medium", "wrap": true}},
+		},
+		map[string]any{"type": "FactSet", "facts": facts},
+	}
+	if event.Type == "request.expired" {
+		body = append(body, map[string]any{
+			"type": "TextBlock", "text": "Nobody reviewed this request in time. The requestor must submit it again.", "wrap": true, "isSubtle": true,
+		})
+	}
+
+	card := map[string]any{
+		"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+		"type":    "AdaptiveCard",
+		"version": "1.4",
+		"body":    body,
+	}
+	if links := event.DecisionLinks; links != nil {
+		card["actions"] = []any{
+			map[string]any{"type": "Action.OpenUrl", "title": "Approve", "url": links.Approve, "style": "positive"},
+			map[string]any{"type": "Action.OpenUrl", "title": "Deny", "url": links.Deny, "style": "destructive"},
+		}
+		body = append(body, map[string]any{
+			"type":     "TextBlock",
+			"text":     fmt.Sprintf("Links valid until %s.", time.Unix(links.ExpiresAt, 0).UTC().Format(time.RFC1123)),
+			"wrap":     true,
+			"isSubtle": true,
+			"size":     "Small",
+		})
+		card["body"] = body
+	}
+	return json.Marshal(map[string]any{
+		"type": "message",
+		"attachments": []any{map[string]any{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content":     card,
+		}},
+	})
+}
@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// TicketingConfig links access requests to a Jira or ServiceNow instance.
+type TicketingConfig struct {
+	// System is "jira" or "servicenow". Empty disables the integration.
+	System string
+	// URL is the base URL of the instance, e.g. https://example.atlassian.net.
+	URL string
+	// User and Token authenticate to the instance. Without a user, Jira gets the token as a Bearer token.
+	User  string
+	Token string
+	// JiraProject and JiraIssueType describe the issues opened in Jira.
+	JiraProject   string
+	JiraIssueType string
+	// ServiceNowTable is the table the records are opened in, e.g. change_request.
+	ServiceNowTable string
+	// Required rejects submissions without a ticket, unless the integration opens one.
+	Required bool
+}
+
+// ticketSystem opens and updates the tickets of access requests.
+type ticketSystem interface {
+	open(ctx context.Context, summary, description string) (string, error)
+	comment(ctx context.Context, ref, text string) error
+}
+
+var (
+	ticketing      ticketSystem
+	ticketRequired bool
+)
+
+// SetTicketing configures the ticketing integration and policy.
+func SetTicketing(cfg TicketingConfig) error {
+	ticketRequired = cfg.Required
+	base := strings.TrimSuffix(cfg.URL, "/")
+	switch cfg.System {
+	case "":
+		ticketing = nil
+	case "jira":
+		if cfg.JiraProject == "" {
+			return fmt.Errorf("a Jira project is required")
+		}
+		if cfg.JiraIssueType == "" {
+			cfg.JiraIssueType = "Task"
+		}
+		ticketing = &jiraTickets{base: base, user: cfg.User, token: cfg.Token, project: cfg.JiraProject, issueType: cfg.JiraIssueType}
+	case "servicenow":
+		if cfg.ServiceNowTable == "" {
+			cfg.ServiceNowTable = "change_request"
+		}
+		ticketing = &serviceNowTickets{base: base, user: cfg.User, token: cfg.Token, table: cfg.ServiceNowTable}
+	default:
+		return fmt.Errorf("unknown ticketing system %q, expected jira or servicenow", cfg.System)
+	}
+	if ticketing != nil {
+		if err := ValidateNotificationURL(base); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missingTicket reports, and rejects, a submission without the ticket the policy requires. The integration opens
+// the tickets of submissions without one, which then need none.
+func (p *webSocketCommandProcessor) missingTicket(spec netwatchv1alpha1.AccessRequestSpec) bool {
+	if !ticketRequired || spec.TicketRef != "" || ticketing != nil {
+		return false
+	}
+	p.sendError("A ticket reference is required to submit a request", nil, "Request")
+	return true
+}
+
+// syncTicket reflects a request event on its ticket. Requests submitted or approved without a ticket get one; the
+// ticket of a request still pending is then recorded on it.
+func syncTicket(event LifecycleEvent) {
+	if !strings.HasPrefix(event.Type, "request.") {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*notificationClient.Timeout)
+	defer cancel()
+	log := logger.Logger.With("event", event.Type, "request", event.Request, "ticket", event.TicketRef)
+
+	if event.TicketRef == "" {
+		if event.Type != "request.submitted" && event.Type != "request.approved" {
+			return
+		}
+		ref, err := ticketing.open(ctx, ticketSummary(event), ticketDescription(event))
+		if err != nil {
+			log.Error("Failed to open a ticket", "error", err)
+			return
+		}
+		log.Info("Ticket opened", "ticket", ref)
+		if event.Type == "request.submitted" {
+			recordTicketRef(ctx, event.Request, ref)
+		}
+		persistLogEntry(LogEntry{
+			Payload:   fmt.Sprintf("TICKET: Access request %s is tracked in %s.", event.RequestID, ref),
+			ClassName: "log-info",
+			LogType:   "Request",
+			Type:      "applyResult",
+			Requestor: event.Requestor,
+		})
+		return
+	}
+	if err := ticketing.comment(ctx, event.TicketRef, ticketComment(event)); err != nil {
+		log.Error("Failed to update a ticket", "error", err)
+	}
+}
+
+// recordTicketRef stores the ticket opened for a pending request on it.
+func recordTicketRef(ctx context.Context, name, ref string) {
+	request, err := k8s.GetAccessRequestAsApp(ctx, name)
+	if err != nil {
+		return
+	}
+	request.Spec.TicketRef = ref
+	if err := k8s.UpdateAccessRequestAsApp(ctx, request); err != nil {
+		logger.Logger.Error("Failed to record a ticket on its request", "error", err, "request", name, "ticket", ref)
+		return
+	}
+	invalidateAccessRequestCache()
+}
+
+func ticketSummary(event LifecycleEvent) string {
+	return fmt.Sprintf("Network access for %s: %s", event.Requestor, event.Path)
+}
+
+func ticketDescription(event LifecycleEvent) string {
+	lines := []string{
+		"Access request " + event.RequestID + " in Netwatch.",
+		"Requestor: " + event.Requestor,
+		"Path: " + event.Path,
+	}
+	if event.Type == "request.submitted" && event.Comment != "" {
+		lines = append(lines, "Description: "+event.Comment)
+	}
+	if event.Type == "request.approved" {
+		lines = append(lines, "Approved by "+event.Actor+".")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func ticketComment(event LifecycleEvent) string {
+	switch event.Type {
+	case "request.submitted":
+		return fmt.Sprintf("Access request %s submitted in Netwatch by %s: %s.", event.RequestID, event.Requestor, event.Path)
+	case "request.updated":
+		return fmt.Sprintf("Access request %s changed by its requestor: %s.", event.RequestID, event.Comment)
+	case "request.approved":
+		return fmt.Sprintf("Access request %s approved by %s.%s", event.RequestID, event.Actor, commentSuffix(event.Comment))
+	case "request.denied":
+		return fmt.Sprintf("Access request %s denied by %s.%s", event.RequestID, event.Actor, reasonSuffix(event.Comment))
+	default:
+		return fmt.Sprintf("Access request %s expired, nobody reviewed it in time.", event.RequestID)
+	}
+}
+
+// ticketCall sends a JSON request to a ticketing system and decodes its answer into out, when set.
+func ticketCall(ctx context.Context, method, u, user, token string, in, out any) error {
+	var body bytes.Buffer
+	if in != nil {
+		if err := json.NewEncoder(&body).Encode(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if user != "" {
+		req.SetBasicAuth(user, token)
+	} else if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := notificationClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close() //nolint:errcheck
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: status %d", method, u, resp.StatusCode)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+type jiraTickets struct {
+	base, user, token, project, issueType string
+}
+
+func (j *jiraTickets) open(ctx context.Context, summary, description string) (string, error) {
+	in := map[string]any{"fields": map[string]any{
+		"project":     map[string]string{"key": j.project},
+		"issuetype":   map[string]string{"name": j.issueType},
+		"summary":     summary,
+		"description": description,
+	}}
+	var out struct {
+		Key string `json:"key"`
+	}
+	if err := ticketCall(ctx, http.MethodPost, j.base+"/rest/api/2/issue", j.user, j.token, in, &out); err != nil {
+		return "", err
+	}
+	return out.Key, nil
+}
+
+func (j *jiraTickets) comment(ctx context.Context, ref, text string) error {
+	u := j.base + "/rest/api/2/issue/" + url.PathEscape(ref) + "/comment"
+	return ticketCall(ctx, http.MethodPost, u, j.user, j.token, map[string]string{"body": text}, nil)
+}
+
+type serviceNowTickets struct {
+	base, user, token, table string
+}
+
+func (s *serviceNowTickets) open(ctx context.Context, summary, description string) (string, error) {
+	var out struct {
+		Result struct {
+			Number string `json:"number"`
+		} `json:"result"`
+	}
+	in := map[string]string{"short_description": summary, "description": description}
+	if err := ticketCall(ctx, http.MethodPost, s.base+"/api/now/table/"+s.table, s.user, s.token, in, &out); err != nil {
+		return "", err
+	}
+	return out.Result.Number, nil
+}
+
+// comment adds a work note to a record, found by its number.
+func (s *serviceNowTickets) comment(ctx context.Context, ref, text string) error {
+	var found struct {
+		Result []struct {
+			SysID string `json:"sys_id"`
+		} `json:"result"`
+	}
+	query := url.Values{"sysparm_query": {"number=" + ref}, "sysparm_fields": {"sys_id"}, "sysparm_limit": {"1"}}
+	if err := ticketCall(ctx, http.MethodGet, s.base+"/api/now/table/"+s.table+"?"+query.Encode(), s.user, s.token, nil, &found); err != nil {
+		return err
+	}
+	if len(found.Result) == 0 {
+		return fmt.Errorf("record %s not found in %s", ref, s.table)
+	}
+	u := s.base + "/api/now/table/" + s.table + "/" + found.Result[0].SysID
+	return ticketCall(ctx, http.MethodPatch, u, s.user, s.token, map[string]string{"work_notes": text}, nil)
+}
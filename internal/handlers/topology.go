@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TopologyNode is a service, or the external CIDRs of an external access.
+type TopologyNode struct {
+	// ID is namespace/name for a service, the CIDRs for external peers.
+	ID string `json:"id" example:"backend/api"`
+	// Kind is "Service" or "CIDR".
+	Kind      string `json:"kind" example:"Service"`
+	Namespace string `json:"namespace,omitempty" example:"backend"`
+}
+
+// TopologyEdge is an active access between two nodes.
+type TopologyEdge struct {
+	Source    string `json:"source" example:"frontend/web"`
+	Target    string `json:"target" example:"backend/api"`
+	Type      string `json:"type" example:"Service"`
+	Direction string `json:"direction" example:"all"`
+	Ports     string `json:"ports" example:"8080"`
+	// ExpiresAt is -1 when the access never expires.
+	ExpiresAt int64  `json:"expiresAt" example:"1718003600"`
+	Owner     string `json:"owner,omitempty" example:"jane.doe-example.com"`
+	// Name and Namespace identify the Access or ExternalAccess object.
+	Name      string `json:"name" example:"access-nc-1a2b3c4d5e6f-30623766"`
+	Namespace string `json:"namespace" example:"backend"`
+}
+
+// Topology is the graph of the services connected by active accesses.
+type Topology struct {
+	Nodes []TopologyNode `json:"nodes"`
+	Edges []TopologyEdge `json:"edges"`
+}
+
+// GetTopology returns the services connected by active accesses as a graph.
+// GetTopology godoc
+// @Summary      Get the connectivity graph
+// @Description  Returns the services and external CIDRs connected by active accesses managed by Netwatch as nodes, and the accesses as edges with their direction, ports and expiry, to render a live connectivity map. Pending and paused accesses are left out.
+// @Tags         Access Policies
+// @Produce      json
+// @Param        namespace  query     string  false  "Only accesses whose Access or ExternalAccess object is in this namespace"
+// @Success      200  {object}  handlers.Topology
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /topology [get]
+func GetTopology(c *gin.Context) {
+	topology := Topology{Nodes: []TopologyNode{}, Edges: []TopologyEdge{}}
+	seen := make(map[string]bool)
+	addNode := func(id, kind string) {
+		if seen[id] {
+			return
+		}
+		seen[id] = true
+		node := TopologyNode{ID: id, Kind: kind}
+		if kind == "Service" {
+			node.Namespace, _, _ = strings.Cut(id, "/")
+		}
+		topology.Nodes = append(topology.Nodes, node)
+	}
+
+	filter := activeAccessFilter{Namespace: c.Query("namespace"), Status: "Active"}
+	for _, info := range listActiveAccesses(c.Request.Context(), filter) {
+		sourceKind := "Service"
+		if info.Type == "External" {
+			sourceKind = "CIDR"
+		}
+		addNode(info.Source, sourceKind)
+		addNode(info.Target, "Service")
+		topology.Edges = append(topology.Edges, TopologyEdge{
+			Source:    info.Source,
+			Target:    info.Target,
+			Type:      info.Type,
+			Direction: info.Direction,
+			Ports:     info.Ports,
+			ExpiresAt: info.ExpiresAt,
+			Owner:     info.Owner,
+			Name:      info.Name,
+			Namespace: info.Namespace,
+		})
+	}
+
+	sort.Slice(topology.Nodes, func(i, j int) bool { return topology.Nodes[i].ID < topology.Nodes[j].ID })
+	c.JSON(http.StatusOK, topology)
+}
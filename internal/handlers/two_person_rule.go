@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+var twoPersonRule bool
+
+// SetTwoPersonRule forbids requestors from approving their own requests, whatever their permissions.
+func SetTwoPersonRule(enabled bool) {
+	twoPersonRule = enabled
+}
+
+// canApproveOwnRequest reports whether the requestor of a request may approve it themselves.
+func canApproveOwnRequest(spec netwatchv1alpha1.AccessRequestSpec) bool {
+	// Requestors never count towards a quorum or approve a side either.
+	return !twoPersonRule && spec.RequiredApprovals <= 1 && !spec.DualApproval
+}
+
+// selfApprovalRefused refuses the approval of a request by its own requestor under the two-person rule. The attempt
+// is recorded in the activity log.
+func (p *webSocketCommandProcessor) selfApprovalRefused(request *netwatchv1alpha1.AccessRequest) bool {
+	if !twoPersonRule || !isRequestOwner(p.userInfo.Email, request.Spec) {
+		return false
+	}
+	logger.Logger.Warn("Self-approval refused by the two-person rule", "request", request.Name, "user", p.userInfo.Email)
+	p.sendError("Two-person rule: requests must be approved by someone other than their requestor", nil, "Request")
+	return true
+}
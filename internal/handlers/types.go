@@ -7,25 +7,77 @@ type LogEntry struct {
 	ClassName string `json:"className"`
 	LogType   string `json:"logType"`
 	Type      string `json:"type"`
+	// User is the email of the user whose command produced the entry, empty for background jobs.
+	User string `json:"user,omitempty"`
+	// Groups are the approver groups a submission or reminder is routed to, see SetApproverGroups, or the owner
+	// groups of its namespaces.
+	Groups []string `json:"groups,omitempty"`
+	// Owners are the users a submission or reminder is routed to as owners of its namespaces.
+	Owners []string `json:"owners,omitempty"`
+	// Requestor and Reason are set on denials, so that the requestor can be told why. Requestor is also set on expiries.
+	Requestor string `json:"requestor,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	// RequestID is set on denials, so the requestor can resubmit the request.
+	RequestID string `json:"requestID,omitempty"`
+	// APIVersion and SchemaVersion are only set on the hello entry sent when a WebSocket connects.
+	APIVersion    string `json:"apiVersion,omitempty"`
+	SchemaVersion int    `json:"schemaVersion,omitempty"`
 }
 
 // AccessRequestPayload defines the structure for a pending request to be sent to the frontend.
 // It is derived from the AccessRequest CRD with more spec for diverse evaluation.
 type AccessRequestPayload struct {
-	RequestID      string `json:"requestID"`
-	Requestor      string `json:"requestor"`
-	Timestamp      int64  `json:"timestamp"`
-	RequestType    string `json:"requestType"`
-	SourceService  string `json:"sourceService,omitempty"`
-	TargetService  string `json:"targetService,omitempty"`
-	Cidr           string `json:"cidr,omitempty"`
-	Service        string `json:"service,omitempty"`
-	Direction      string `json:"direction"`
-	Ports          string `json:"ports"`
-	Duration       int64  `json:"duration"`
-	Description    string `json:"description,omitempty"`
-	CanSelfApprove bool   `json:"canSelfApprove"`
-	Status         string `json:"status,omitempty"`
+	RequestID   string `json:"requestID"`
+	DisplayName string `json:"displayName"`
+	Requestor   string `json:"requestor"`
+	// FiledBy is set when someone else filed the request on behalf of the requestor.
+	FiledBy       string `json:"filedBy,omitempty"`
+	Timestamp     int64  `json:"timestamp"`
+	RequestType   string `json:"requestType"`
+	SourceService string `json:"sourceService,omitempty"`
+	TargetService string `json:"targetService,omitempty"`
+	Cidr          string `json:"cidr,omitempty"`
+	Service       string `json:"service,omitempty"`
+	Direction     string `json:"direction"`
+	Ports         string `json:"ports"`
+	Duration      int64  `json:"duration"`
+	Description   string `json:"description,omitempty"`
+	TicketRef     string `json:"ticketRef,omitempty"`
+	Priority      string `json:"priority" example:"normal"`
+	// PreviousRequestID is the RequestID of the request this one resubmits, and History the decisions on its earlier
+	// submissions, the latest first.
+	PreviousRequestID string          `json:"previousRequestID,omitempty"`
+	History           []PriorDecision `json:"history,omitempty"`
+	CanSelfApprove    bool            `json:"canSelfApprove"`
+	// PermissionCheckFailed is set when CanSelfApprove could not be determined.
+	PermissionCheckFailed bool              `json:"permissionCheckFailed,omitempty"`
+	Status                string            `json:"status,omitempty"`
+	Attachments           []AttachmentInfo  `json:"attachments,omitempty"`
+	Labels                map[string]string `json:"labels,omitempty"`
+	// RequiredApprovals and ApprovedBy are only set on requests that need several approvals.
+	RequiredApprovals int      `json:"requiredApprovals,omitempty"`
+	ApprovedBy        []string `json:"approvedBy,omitempty"`
+	// DualApproval is set on requests needing an approval from each side, and ApprovedSides maps the sides approved
+	// so far, source or target, to their approver.
+	DualApproval  bool              `json:"dualApproval,omitempty"`
+	ApprovedSides map[string]string `json:"approvedSides,omitempty"`
+	// Age is how long the request has been waiting for a decision, in seconds, and SLABreached whether that is
+	// longer than its review SLA, see NETWATCH_REVIEW_SLA.
+	Age         int64 `json:"age" example:"5400"`
+	SLABreached bool  `json:"slaBreached"`
+	// Timing is only set in the requestor's own list, see GetMyRequests.
+	Timing *RequestTiming `json:"timing,omitempty"`
+}
+
+// AttachmentInfo describes a file uploaded alongside an access request. The content itself is served from URL.
+type AttachmentInfo struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+	UploadedBy  string `json:"uploadedBy"`
+	UploadedAt  int64  `json:"uploadedAt"`
+	URL         string `json:"url"`
 }
 
 type ServiceInfo struct {
@@ -35,6 +87,12 @@ type ServiceInfo struct {
 	Labels    map[string]string `json:"labels"`
 }
 
+// NamespaceInfo defines the structure for a namespace sent to the frontend.
+type NamespaceInfo struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
 // ActiveAccessInfo defines the structure for an active access policy sent to the frontend.
 type ActiveAccessInfo struct {
 	Type      string `json:"type"`
@@ -46,22 +104,54 @@ type ActiveAccessInfo struct {
 	Direction string `json:"direction"`
 	Ports     string `json:"ports"`
 	Status    string `json:"status,omitempty"`
+	Owner     string `json:"owner,omitempty"`
+	// Requestor and ApprovedBy are empty for accesses created before they were recorded, ApprovedBy also for
+	// accesses created without review.
+	Requestor  string `json:"requestor,omitempty"`
+	ApprovedBy string `json:"approvedBy,omitempty"`
+	CreatedAt  int64  `json:"createdAt,omitempty"`
+	// Drift lists the enforcement problems found by the last drift check, if any.
+	Drift []string `json:"drift,omitempty"`
+	// RequestID and Remaining are only set for paused accesses.
+	RequestID string `json:"requestID,omitempty"`
+	Remaining int64  `json:"remaining,omitempty"`
 }
 
-// webSocketPayload defines the structure for incoming messages from the WebSocket client.
+// webSocketPayload is the command the handlers run. WebSocket messages are decoded into their typed message first,
+// see webSocketMessages, the REST API and imports build it directly.
 type webSocketPayload struct {
-	Command       string `json:"command"`
-	RequestID     string `json:"requestID"`
-	SourceService string `json:"sourceService"`
-	TargetService string `json:"targetService"`
-	Direction     string `json:"direction"`
-	Ports         string `json:"ports"`
-	Cidr          string `json:"cidr"`
-	Service       string `json:"service"`
-	Duration      int64  `json:"duration"`
-	Name          string `json:"name"`
-	Namespace     string `json:"namespace"`
-	Description   string `json:"description"`
+	Command       string            `json:"command"`
+	RequestID     string            `json:"requestID"`
+	SourceService string            `json:"sourceService"`
+	TargetService string            `json:"targetService"`
+	Direction     string            `json:"direction"`
+	Ports         string            `json:"ports"`
+	Cidr          string            `json:"cidr"`
+	Service       string            `json:"service"`
+	Duration      int64             `json:"duration"`
+	Name          string            `json:"name"`
+	Namespace     string            `json:"namespace"`
+	Description   string            `json:"description"`
+	Labels        map[string]string `json:"labels"`
+	AccessType    string            `json:"accessType"`
+	// Heartbeat binds a directly created access to heartbeats, see SendHeartbeat.
+	Heartbeat bool `json:"heartbeat"`
+	// SessionBound revokes a directly created access when the requestor's login session ends.
+	SessionBound bool `json:"sessionBound"`
+	// OnBehalfOf files an access request for another user, see onBehalfVerb.
+	OnBehalfOf string `json:"onBehalfOf"`
+	// Comment is the approver's note on an approval, see approvalCommentAnnotation.
+	Comment string `json:"comment"`
+	// Reason says why a request is denied or aborted, see SetDenialReasonRequired.
+	Reason string `json:"reason"`
+	// Priority is the priority of a submitted request: low, normal or urgent.
+	Priority string `json:"priority"`
+	// TicketRef is the ticket tracking a submitted request, see SetTicketing.
+	TicketRef string `json:"ticketRef"`
+	// PreviousRequestID is the denied or expired request a submission resubmits, by RequestID or name.
+	PreviousRequestID string `json:"previousRequestID"`
+	// RequestToken makes a command idempotent: a command repeating the token of an earlier one is ignored.
+	RequestToken string `json:"requestToken"`
 }
 
 type HTTPError struct {
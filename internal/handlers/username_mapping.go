@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// Username mapping strategies, selected with NETWATCH_USERNAME_STRATEGY.
+const (
+	// UsernameStrategyLegacy replaces '@' and '.' with dashes. It is the default so existing labels keep matching.
+	UsernameStrategyLegacy = "legacy"
+	// UsernameStrategyHash appends a short hash of the full email so similar emails never collide.
+	UsernameStrategyHash = "hash"
+	// UsernameStrategyTemplate renders a Go template with .Email, .Local, .Domain, .Hash and .Claims.
+	UsernameStrategyTemplate = "template"
+	// UsernameStrategyClaim uses an ID token claim provided by the directory, such as an employee ID.
+	UsernameStrategyClaim = "claim"
+)
+
+// usernameCachePrefix maps an email to the username resolved from its ID token, so code paths that only
+// know the email (Slack, calendar feeds) resolve the same value with the claim and template strategies.
+const usernameCachePrefix = "netwatch:username:"
+
+// maxUsernameLength keeps usernames valid as label values.
+const maxUsernameLength = 63
+
+// UsernameMappingConfig configures how user identities are turned into label values and object names.
+type UsernameMappingConfig struct {
+	Strategy string
+	Template string
+	Claim    string
+}
+
+var (
+	usernameStrategy = UsernameStrategyLegacy
+	usernameTemplate *template.Template
+	usernameClaim    string
+
+	invalidUsernameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// usernameTemplateData is the data available to a NETWATCH_USERNAME_TEMPLATE.
+type usernameTemplateData struct {
+	Email  string
+	Local  string
+	Domain string
+	Hash   string
+	Claims map[string]any
+}
+
+// SetUsernameMapping selects the username mapping strategy.
+func SetUsernameMapping(cfg UsernameMappingConfig) error {
+	switch cfg.Strategy {
+	case "", UsernameStrategyLegacy, UsernameStrategyHash:
+	case UsernameStrategyTemplate:
+		tmpl, err := template.New("username").Option("missingkey=zero").Parse(cfg.Template)
+		if err != nil {
+			return fmt.Errorf("invalid username template: %w", err)
+		}
+		usernameTemplate = tmpl
+	case UsernameStrategyClaim:
+		if cfg.Claim == "" {
+			return fmt.Errorf("the claim strategy requires a claim name")
+		}
+		usernameClaim = cfg.Claim
+	default:
+		return fmt.Errorf("unknown username strategy %q", cfg.Strategy)
+	}
+	if cfg.Strategy != "" {
+		usernameStrategy = cfg.Strategy
+	}
+	return nil
+}
+
+// usernameFor maps a user to the value used for the netwatch.vtk.io/user label and in AccessRequest names.
+func usernameFor(userInfo *k8s.UserInfo) string {
+	switch usernameStrategy {
+	case UsernameStrategyHash:
+		return hashedUsername(userInfo.Email)
+	case UsernameStrategyTemplate:
+		local, domain, _ := strings.Cut(userInfo.Email, "@")
+		var buf bytes.Buffer
+		data := usernameTemplateData{Email: userInfo.Email, Local: local, Domain: domain, Hash: emailHash(userInfo.Email), Claims: userInfo.Claims}
+		if err := usernameTemplate.Execute(&buf, data); err != nil {
+			logger.Logger.Error("Failed to render username template, falling back to hash strategy", "error", err)
+			return hashedUsername(userInfo.Email)
+		}
+		if username := normalizeUsername(buf.String()); username != "" {
+			return username
+		}
+		return hashedUsername(userInfo.Email)
+	case UsernameStrategyClaim:
+		if value, ok := userInfo.Claims[usernameClaim]; ok {
+			if username := normalizeUsername(fmt.Sprint(value)); username != "" {
+				return username
+			}
+		}
+		logger.Logger.Warn("Username claim missing from token, falling back to hash strategy", "claim", usernameClaim, "email", userInfo.Email)
+		return hashedUsername(userInfo.Email)
+	default:
+		// Service account and automation names ("system:serviceaccount:ns:name") are not emails and would not make valid labels.
+		if strings.Contains(userInfo.Email, ":") {
+			return hashedUsername(userInfo.Email)
+		}
+		sanitized := strings.ReplaceAll(userInfo.Email, "@", "-")
+		return strings.ReplaceAll(sanitized, ".", "-")
+	}
+}
+
+// rememberUsername resolves the username of an authenticated user and caches it by email.
+func rememberUsername(ctx context.Context, userInfo *k8s.UserInfo) string {
+	username := usernameFor(userInfo)
+	if usernameStrategy == UsernameStrategyClaim || usernameStrategy == UsernameStrategyTemplate {
+		if err := redisClient.Set(ctx, usernameCachePrefix+userInfo.Email, username, 0).Err(); err != nil {
+			logger.Logger.Warn("Failed to cache resolved username", "error", err, "email", userInfo.Email)
+		}
+	}
+	return username
+}
+
+// sanitizeUsername maps an email to its username when no ID token is at hand. Strategies depending on
+// token claims use the value cached at the user's last login.
+func sanitizeUsername(email string) string {
+	if usernameStrategy == UsernameStrategyClaim || usernameStrategy == UsernameStrategyTemplate {
+		if username, err := redisClient.Get(context.Background(), usernameCachePrefix+email).Result(); err == nil {
+			return username
+		}
+	}
+	return usernameFor(&k8s.UserInfo{Email: email})
+}
+
+func emailHash(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return hex.EncodeToString(sum[:])[:8]
+}
+
+func hashedUsername(email string) string {
+	hash := emailHash(email)
+	base := normalizeUsername(email)
+	if len(base) > maxUsernameLength-len(hash)-1 {
+		base = strings.TrimRight(base[:maxUsernameLength-len(hash)-1], "-")
+	}
+	if base == "" {
+		return hash
+	}
+	return base + "-" + hash
+}
+
+// normalizeUsername lowercases a value and replaces anything not allowed in object names with dashes.
+func normalizeUsername(value string) string {
+	normalized := invalidUsernameChars.ReplaceAllString(strings.ToLower(value), "-")
+	normalized = strings.Trim(normalized, "-")
+	if len(normalized) > maxUsernameLength {
+		normalized = strings.TrimRight(normalized[:maxUsernameLength], "-")
+	}
+	return normalized
+}
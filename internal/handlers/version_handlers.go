@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// APIVersion is the version of the REST API and of the WebSocket payloads. Breaking changes to their shapes get a
+// new version, served under its own /api prefix next to the previous one.
+const APIVersion = "v1"
+
+// versionDependencies lists the modules whose versions matter when triaging version-skew issues.
+var versionDependencies = []string{
+	"github.com/Banh-Canh/maxtac",
+	"sigs.k8s.io/controller-runtime",
+	"k8s.io/client-go",
+	"github.com/coreos/go-oidc/v3",
+	"github.com/redis/go-redis/v9",
+}
+
+// versionAPIGroups lists the API groups whose served versions Netwatch depends on.
+var versionAPIGroups = []string{"netwatch.vtk.io", "maxtac.vtk.io"}
+
+// VersionInfo describes the running build and the API versions the cluster serves.
+type VersionInfo struct {
+	Version   string `json:"version" example:"v1.4.0"`
+	BuildDate string `json:"buildDate,omitempty" example:"2026-10-01T12:00:00Z"`
+	GoVersion string `json:"goVersion" example:"go1.24.5"`
+	Platform  string `json:"platform" example:"linux/amd64"`
+	// Revision and Modified describe the VCS state the binary was built from, when known.
+	Revision string `json:"revision,omitempty"`
+	Modified bool   `json:"modified,omitempty"`
+	// Dependencies maps critical module paths to the version compiled in.
+	Dependencies map[string]string `json:"dependencies"`
+	// APIVersions maps API groups to the versions the cluster serves, preferred first. Empty if the CRDs are missing.
+	APIVersions map[string][]string `json:"apiVersions"`
+	// APIVersionsError is set when the cluster could not be queried.
+	APIVersionsError string `json:"apiVersionsError,omitempty"`
+}
+
+// GetVersion returns a handler reporting build and dependency versions.
+// GetVersion godoc
+// @Summary      Get version information
+// @Description  Returns the Netwatch version and build date, the versions of critical dependencies compiled in, and the versions of the netwatch and maxtac APIs served by the cluster.
+// @Tags         System
+// @Produce      json
+// @Success      200  {object}  handlers.VersionInfo
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /version [get]
+func GetVersion(version, buildDate string) gin.HandlerFunc {
+	info := VersionInfo{
+		Version:      version,
+		BuildDate:    buildDate,
+		GoVersion:    runtime.Version(),
+		Platform:     runtime.GOOS + "/" + runtime.GOARCH,
+		Dependencies: map[string]string{},
+	}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			if !slices.Contains(versionDependencies, dep.Path) {
+				continue
+			}
+			info.Dependencies[dep.Path] = dep.Version
+			if dep.Replace != nil {
+				info.Dependencies[dep.Path] = dep.Replace.Path + " " + dep.Replace.Version
+			}
+		}
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				info.Revision = setting.Value
+			case "vcs.modified":
+				info.Modified = setting.Value == "true"
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	return func(c *gin.Context) {
+		result := info
+		apiVersions, err := k8s.ServedVersions(versionAPIGroups...)
+		if err != nil {
+			logger.Logger.Warn("Failed to discover served API versions", "error", err)
+			result.APIVersionsError = err.Error()
+		}
+		result.APIVersions = apiVersions
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+)
+
+// WebhookConfig sends every lifecycle event as signed JSON to generic webhooks.
+type WebhookConfig struct {
+	// URLs receive every event.
+	URLs []string
+	// Secret is the HMAC-SHA256 key signing the deliveries.
+	Secret string
+}
+
+// SetWebhookNotifications enables the generic webhooks, unless no URL is configured.
+func SetWebhookNotifications(cfg WebhookConfig) {
+	if len(cfg.URLs) == 0 {
+		return
+	}
+	notificationTargets = append(notificationTargets, webhookTarget(cfg))
+}
+
+type webhookTarget WebhookConfig
+
+func (webhookTarget) name() string { return "webhook" }
+
+func (t webhookTarget) urls(LifecycleEvent) []string { return t.URLs }
+
+func (webhookTarget) body(event LifecycleEvent) ([]byte, error) { return json.Marshal(event) }
+
+// headers sign a delivery. X-Netwatch-Signature is "sha256=" and the hex HMAC-SHA256 of the X-Netwatch-Timestamp
+// header, a dot and the body, so that receivers can reject forged and replayed deliveries.
+func (t webhookTarget) headers(event LifecycleEvent, body []byte) map[string]string {
+	timestamp := strconv.FormatInt(event.Timestamp, 10)
+	mac := hmac.New(sha256.New, []byte(t.Secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return map[string]string{
+		"X-Netwatch-Event":     event.Type,
+		"X-Netwatch-Delivery":  event.ID,
+		"X-Netwatch-Timestamp": timestamp,
+		"X-Netwatch-Signature": "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+	}
+}
@@ -6,13 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-	"strings"
-	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/gorilla/websocket"
-	"github.com/redis/go-redis/v9"
 
 	"github.com/Banh-Canh/netwatch/internal/k8s"
 	"github.com/Banh-Canh/netwatch/internal/utils/logger"
@@ -29,7 +25,7 @@ const (
 	pingPeriod = (pongWait * 9) / 10
 )
 
-// HandleWebSocket manages the WebSocket lifecycle, including a robust ping/pong mechanism.
+// HandleWebSocket authenticates the client, upgrades the connection and serves it until it is closed.
 func HandleWebSocket(c *gin.Context) {
 	idToken, err := getUserIdToken(c)
 	if err != nil {
@@ -43,36 +39,27 @@ func HandleWebSocket(c *gin.Context) {
 		return
 	}
 
-	sanitizedUsername := strings.ReplaceAll(userInfo.Email, "@", "-")
-	sanitizedUsername = strings.ReplaceAll(sanitizedUsername, ".", "-")
+	sanitizedUsername := rememberUsername(c.Request.Context(), userInfo)
 
-	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	// Accesses can only be bound to the login session the token comes from.
+	var sessionID string
+	if session, err := sessionStore.Get(c.Request, "auth-session"); err == nil && !session.IsNew && session.Values["id_token"] == idToken {
+		sessionID = session.ID
+	}
+
+	rawConn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		logger.Logger.Error("Failed to upgrade connection", "error", err)
 		return
 	}
-	defer conn.Close()
-
-	var connMu sync.Mutex
+	conn := newWSConnection(c.Request.Context(), rawConn)
+	defer conn.Close() //nolint:all
+	openWebSockets.Add(1)
+	defer openWebSockets.Add(-1)
 
 	logAndBroadcast := func(entry LogEntry) {
-		entry.Timestamp = time.Now().UnixMilli()
-		entryJSON, err := json.Marshal(entry)
-		if err != nil {
-			logger.Logger.Error("Failed to marshal log entry for Redis", "error", err)
-			return
-		}
-		ctx := context.Background()
-		if err := redisClient.ZAdd(ctx, logKey, redis.Z{
-			Score:  float64(entry.Timestamp),
-			Member: entryJSON,
-		}).Err(); err != nil {
-			logger.Logger.Error("Failed to save log entry to Redis", "error", err)
-		}
-
-		connMu.Lock()
-		defer connMu.Unlock()
-		conn.SetWriteDeadline(time.Now().Add(writeWait))
+		entry.User = userInfo.Email
+		entry = persistLogEntry(entry)
 		if err := conn.WriteJSON(entry); err != nil {
 			logger.Logger.Warn("Could not write JSON to WebSocket", "error", err)
 		}
@@ -97,83 +84,94 @@ func HandleWebSocket(c *gin.Context) {
 		})
 	}
 
+	// sendPrivate writes an entry to this user only, without adding it to the activity log.
+	sendPrivate := func(entry LogEntry) {
+		entry.Timestamp = time.Now().UnixMilli()
+		if err := conn.WriteJSON(entry); err != nil {
+			logger.Logger.Warn("Could not write JSON to WebSocket", "error", err)
+		}
+	}
+
+	sendPrivate(LogEntry{
+		Payload:   fmt.Sprintf("Connected to Netwatch, API %s.", APIVersion),
+		ClassName: "log-info", LogType: "Global", Type: "hello", APIVersion: APIVersion, SchemaVersion: webSocketSchemaVersion,
+	})
+
+	ctx := k8s.WithThrottleNotifier(c.Request.Context(), func(delay time.Duration) {
+		sendPrivate(LogEntry{
+			Payload:   fmt.Sprintf("The cluster is throttling requests, retrying in %s...", delay),
+			ClassName: "log-warning", LogType: "Global", Type: "applyResult",
+		})
+	})
+
 	processor := &webSocketCommandProcessor{
-		ctx:               c.Request.Context(),
+		ctx:               ctx,
 		idToken:           idToken,
 		userInfo:          userInfo,
 		sanitizedUsername: sanitizedUsername,
+		sessionID:         sessionID,
 		logAndBroadcast:   logAndBroadcast,
+		sendPrivate:       sendPrivate,
 		sendError:         sendError,
+		channel:           "web",
 	}
 
-	// Set up a channel to signal when the read loop is done.
-	done := make(chan struct{})
+	if sessionID != "" && sessionIdleTimeout > 0 {
+		go watchSessionIdle(conn.Context(), sessionID, sendPrivate, func() { conn.Close() }) //nolint:all
+	}
 
-	// This goroutine is responsible for reading messages from the client.
-	go func() {
-		defer close(done)
-		defer conn.Close()
+	if err := conn.Run(processor.dispatch); err != nil {
+		logger.Logger.Info("WebSocket connection terminated", "user", userInfo.Email, "error", err)
+	}
+}
 
-		conn.SetReadDeadline(time.Now().Add(pongWait))
-		conn.SetPongHandler(func(string) error {
-			conn.SetReadDeadline(time.Now().Add(pongWait))
-			logger.Logger.Debug("Received pong from client")
-			return nil
-		})
+// dispatch routes a single incoming payload to the matching command handler.
+// Every command counts as activity on the login session, keepAlive does nothing else.
+func (p *webSocketCommandProcessor) dispatch(payload webSocketPayload) {
+	touchSession(p.ctx, p.sessionID)
+	if payload.RequestToken != "" && !p.claimRequestToken(payload.RequestToken) {
+		return
+	}
+	switch payload.Command {
+	case "keepAlive":
+	case "requestClusterAccess":
+		p.handleRequestClusterAccess(payload)
+	case "requestExternalAccess":
+		p.handleRequestExternalAccess(payload)
+	case "submitAccessRequest":
+		p.handleSubmitAccessRequest(payload)
+	case "approveAccessRequest":
+		p.handleApproveAccessRequest(payload)
+	case "updateAccessRequest":
+		p.handleUpdateAccessRequest(payload)
+	case "denyAccessRequest":
+		p.handleDenyAccessRequest(payload)
+	case "revokeClusterAccess":
+		p.handleRevokeClusterAccess(payload)
+	case "revokeExternalAccess":
+		p.handleRevokeExternalAccess(payload)
+	case "pauseAccess":
+		p.handlePauseAccess(payload)
+	case "resumeAccess":
+		p.handleResumeAccess(payload)
+	default:
+		// Unreachable from the WebSocket, decodeWebSocketMessage refuses unknown commands.
+		logger.Logger.Warn("Received unknown WebSocket command", "command", payload.Command)
+	}
+}
 
-		for {
-			var payload webSocketPayload
-			if err := conn.ReadJSON(&payload); err != nil {
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-					logger.Logger.Error("Unexpected WebSocket close error", "error", err)
-				} else {
-					logger.Logger.Info("Client disconnected gracefully or due to timeout", "error", err)
-				}
-				break
-			}
-
-			switch payload.Command {
-			case "requestClusterAccess":
-				processor.handleRequestClusterAccess(payload)
-			case "requestExternalAccess":
-				processor.handleRequestExternalAccess(payload)
-			case "submitAccessRequest":
-				processor.handleSubmitAccessRequest(payload)
-			case "approveAccessRequest":
-				processor.handleApproveAccessRequest(payload)
-			case "denyAccessRequest":
-				processor.handleDenyAccessRequest(payload)
-			case "revokeClusterAccess":
-				processor.handleRevokeClusterAccess(payload)
-			case "revokeExternalAccess":
-				processor.handleRevokeExternalAccess(payload)
-			default:
-				logger.Logger.Warn("Received unknown WebSocket command", "command", payload.Command)
-			}
-		}
-	}()
-
-	// This main goroutine is now responsible for sending periodic pings.
-	ticker := time.NewTicker(pingPeriod)
-	defer ticker.Stop()
-
-	for {
-		select {
-		case <-ticker.C:
-			connMu.Lock()
-			conn.SetWriteDeadline(time.Now().Add(writeWait))
-			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
-				logger.Logger.Error("Failed to send ping to client, closing connection", "error", err)
-				connMu.Unlock()
-				return
-			}
-			connMu.Unlock()
-		case <-done:
-			// The read pump has closed. Exit this handler.
-			logger.Logger.Info("Read pump finished, stopping pinger.")
-			return
-		}
+// persistLogEntry timestamps an entry and appends it to the global activity log in Redis.
+func persistLogEntry(entry LogEntry) LogEntry {
+	entry.Timestamp = time.Now().UnixMilli()
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		logger.Logger.Error("Failed to marshal log entry for Redis", "error", err)
+		return entry
+	}
+	if err := appendLogEntry(context.Background(), entry.Timestamp, entryJSON); err != nil {
+		logger.Logger.Error("Failed to save log entry to Redis", "error", err)
 	}
+	return entry
 }
 
 // getUserIdToken retrieves the OIDC ID token from the Gin context or session.
@@ -188,6 +186,9 @@ func getUserIdToken(c *gin.Context) (string, error) {
 		return "", errors.New("could not retrieve session")
 	}
 	if idToken, ok := session.Values["id_token"].(string); ok && idToken != "" {
+		if err := checkSessionActivity(c, session); err != nil {
+			return "", err
+		}
 		return idToken, nil
 	}
 	return "", errors.New("user not authenticated")
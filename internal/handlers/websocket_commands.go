@@ -5,9 +5,10 @@ import (
 	"crypto/sha1"
 	"encoding/hex"
 	"fmt"
-	"net"
+	"maps"
 	"strconv"
 	"strings"
+	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
 	"github.com/google/uuid"
@@ -26,8 +27,96 @@ type webSocketCommandProcessor struct {
 	idToken           string
 	userInfo          *k8s.UserInfo
 	sanitizedUsername string
+	sessionID         string
 	logAndBroadcast   func(entry LogEntry)
+	sendPrivate       func(entry LogEntry)
 	sendError         func(msg string, err error, logType string)
+	// channel records where submissions come from, e.g. "web" or "import".
+	channel string
+	// onSubmitted, when set, is called with each AccessRequest submitted, or approved at once by a pre-approval.
+	onSubmitted func(request *netwatchv1alpha1.AccessRequest, approved bool)
+	// onCreated, when set, is called with the request ID and the namespace/name of each Access or ExternalAccess
+	// created directly.
+	onCreated func(requestID string, accesses []string)
+	// onRevoked, when set, is called with the namespace/name of each Access or ExternalAccess deleted by a revocation,
+	// and of the service clones the controller removes with them.
+	onRevoked func(requestID string, accesses, clones []string)
+}
+
+// created reports accesses created directly to onCreated, if set, and to the notification targets.
+func (p *webSocketCommandProcessor) created(requestID string, accesses []string) {
+	if p.onCreated != nil {
+		p.onCreated(requestID, accesses)
+	}
+	notifyLifecycleEvent(newAccessEvent("access.created", requestID, p.userInfo.Email, "", accesses))
+}
+
+// revoked reports a revocation to onRevoked, if set, and to the notification targets.
+func (p *webSocketCommandProcessor) revoked(requestID string, accesses, clones []string) {
+	if p.onRevoked != nil {
+		p.onRevoked(requestID, accesses, clones)
+	}
+	notifyLifecycleEvent(newAccessEvent("access.revoked", requestID, "", p.userInfo.Email, accesses))
+}
+
+// submitted reports a request accepted at submission to onSubmitted, if set.
+func (p *webSocketCommandProcessor) submitted(request *netwatchv1alpha1.AccessRequest, approved bool) {
+	if p.onSubmitted != nil {
+		p.onSubmitted(request, approved)
+	}
+}
+
+// impersonatingClient acts as the user of the command. Decisions taken through annotations come without an ID token,
+// their approver is impersonated by name.
+func (p *webSocketCommandProcessor) impersonatingClient() (client.Client, error) {
+	if p.idToken == "" {
+		return k8s.GetImpersonatingKubeClientFor(p.userInfo)
+	}
+	return k8s.GetImpersonatingKubeClient(p.idToken)
+}
+
+// outOfScope reports, and rejects, an access that automation identities are not allowed to create or approve.
+func (p *webSocketCommandProcessor) outOfScope(requestType string, namespaces []string, durationSeconds int64, logType string) bool {
+	if err := p.userInfo.Scope.Allows(requestType, namespaces, time.Duration(durationSeconds)*time.Second); err != nil {
+		p.sendError("Request is outside the scope of this automation identity", err, logType)
+		return true
+	}
+	return false
+}
+
+// announceHeartbeat binds a freshly created access to heartbeats and hands the token to this user only.
+func (p *webSocketCommandProcessor) announceHeartbeat(requestID, logType string) {
+	token, err := startHeartbeat(p.ctx, requestID, p.userInfo.Email, logType)
+	if err != nil {
+		p.sendError("Access created, but heartbeats could not be enabled. It will only expire with its duration", err, logType)
+		return
+	}
+	p.sendPrivate(LogEntry{
+		Payload: fmt.Sprintf("HEARTBEAT: keep this access alive with POST /api/heartbeats/%s and header %s: %s at least every %s.",
+			requestID, HeartbeatTokenHeader, token, heartbeatGrace),
+		ClassName: "log-info", LogType: logType, Type: "heartbeatToken",
+	})
+}
+
+// checkSessionBinding rejects a session-bound access when the connection has no login session to bind it to.
+func (p *webSocketCommandProcessor) checkSessionBinding(payload webSocketPayload, logType string) bool {
+	if payload.SessionBound && p.sessionID == "" {
+		p.sendError("Session-bound accesses require being logged in to Netwatch in a browser", nil, logType)
+		return false
+	}
+	return true
+}
+
+// bindAccessToSession ties a freshly created access to the requestor's login session.
+func (p *webSocketCommandProcessor) bindAccessToSession(requestID, logType string) {
+	if err := bindToSession(p.ctx, requestID, p.userInfo.Email, logType, p.sessionID); err != nil {
+		p.sendError("Access created, but it could not be bound to your session. It will only expire with its duration", err, logType)
+		return
+	}
+	p.logAndBroadcast(LogEntry{
+		Payload:   "This access will be revoked when you log out or your session expires.",
+		ClassName: "log-info", LogType: logType, Type: "applyResult",
+	})
 }
 
 // Helper function to generate a short, unique hash from a string.
@@ -38,48 +127,20 @@ func shortHash(s string) string {
 }
 
 func (p *webSocketCommandProcessor) createClusterAccess(userKubeClient client.Client, payload webSocketPayload) {
-	var overridePorts []corev1.ServicePort
-	if payload.Ports != "" {
-		portStrings := strings.SplitSeq(payload.Ports, ",")
-		for pStr := range portStrings {
-			pStr = strings.TrimSpace(pStr)
-			if pStr == "" {
-				continue
-			}
-			port, err := strconv.Atoi(pStr)
-			if err != nil {
-				p.sendError("Invalid port override", fmt.Errorf("'%s' is not a valid port number", pStr), "Service")
-				return
-			}
-			overridePorts = append(overridePorts, corev1.ServicePort{
-				Name: fmt.Sprintf("port-%d", port), Protocol: corev1.ProtocolTCP, Port: int32(port),
-			})
-		}
-	}
-
-	sourceParts := strings.Split(payload.SourceService, "/")
-	targetParts := strings.Split(payload.TargetService, "/")
-	if len(sourceParts) != 2 || len(targetParts) != 2 {
-		p.sendError("Invalid service format", fmt.Errorf("expected 'namespace/name'"), "Service")
+	plan, err := p.planClusterAccess(payload)
+	if err != nil {
+		p.sendError("Could not prepare the access", err, "Service")
 		return
 	}
-	sourceNs, sourceName := sourceParts[0], sourceParts[1]
-	targetNs, targetName := targetParts[0], targetParts[1]
+	sourceClone, targetClone := plan.clones[0], plan.clones[1]
+	sourceAccess, targetAccess := plan.accesses[0], plan.accesses[1]
+	cloneID, durationStr := plan.requestID, sourceAccess.Spec.Duration
 
-	cloneID := uuid.New().String()
-	randSuffix := hex.EncodeToString([]byte(cloneID))[:8]
-	sourceCloneName := fmt.Sprintf("nc-%s-%s", shortHash(sourceName), randSuffix)
-	targetCloneName := fmt.Sprintf("nc-%s-%s", shortHash(targetName), randSuffix)
-	commonRequestLabel := map[string]string{"netwatch.vtk.io/request-id": cloneID}
-
-	sourceClone, err := k8s.CloneService(p.ctx, userKubeClient, sourceNs, sourceName, sourceCloneName, commonRequestLabel, overridePorts)
-	if err != nil {
+	if err := k8s.CreateService(p.ctx, userKubeClient, sourceClone); err != nil {
 		p.sendError("Could not clone source service (check your permissions)", err, "Service")
 		return
 	}
-
-	targetClone, err := k8s.CloneService(p.ctx, userKubeClient, targetNs, targetName, targetCloneName, commonRequestLabel, overridePorts)
-	if err != nil {
+	if err := k8s.CreateService(p.ctx, userKubeClient, targetClone); err != nil {
 		p.sendError("Could not clone target service (check your permissions)", err, "Service")
 		if cleanupErr := k8s.DeleteService(p.ctx, userKubeClient, sourceClone.Namespace, sourceClone.Name); cleanupErr != nil &&
 			!k8s.IsNotFound(cleanupErr) {
@@ -88,44 +149,6 @@ func (p *webSocketCommandProcessor) createClusterAccess(userKubeClient client.Cl
 		return
 	}
 
-	var durationStr string
-	if payload.Duration > 0 {
-		durationStr = fmt.Sprintf("%ds", payload.Duration)
-	}
-	commonAccessLabels := map[string]string{
-		"app.kubernetes.io/managed-by": "netwatch",
-		"netwatch.vtk.io/user":         p.sanitizedUsername,
-		"netwatch.vtk.io/request-id":   cloneID,
-	}
-	sourceAccess := &vtkiov1alpha1.Access{
-		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("access-%s", sourceCloneName), Namespace: sourceNs, Labels: commonAccessLabels},
-		Spec: vtkiov1alpha1.AccessSpec{
-			Duration:        durationStr,
-			ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
-			Targets:         []vtkiov1alpha1.AccessPoint{{ServiceName: targetClone.Name, Namespace: targetClone.Namespace}},
-		},
-	}
-	targetAccess := &vtkiov1alpha1.Access{
-		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("access-%s", targetCloneName), Namespace: targetNs, Labels: commonAccessLabels},
-		Spec: vtkiov1alpha1.AccessSpec{
-			Duration:        durationStr,
-			ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
-			Targets:         []vtkiov1alpha1.AccessPoint{{ServiceName: sourceClone.Name, Namespace: sourceClone.Namespace}},
-		},
-	}
-
-	switch payload.Direction {
-	case "ingress":
-		sourceAccess.Spec.Direction = "ingress"
-		targetAccess.Spec.Direction = "egress"
-	case "egress":
-		sourceAccess.Spec.Direction = "egress"
-		targetAccess.Spec.Direction = "ingress"
-	default:
-		sourceAccess.Spec.Direction = "all"
-		targetAccess.Spec.Direction = "all"
-	}
-
 	if err := k8s.CreateAccess(p.ctx, userKubeClient, sourceAccess); err != nil {
 		p.sendError("Could not create source Access policy (check your permissions)", err, "Service")
 		if cleanupErr := k8s.DeleteService(p.ctx, userKubeClient, sourceClone.Namespace, sourceClone.Name); cleanupErr != nil &&
@@ -162,6 +185,13 @@ func (p *webSocketCommandProcessor) createClusterAccess(userKubeClient client.Cl
 		msg = "SUCCESS: Infinite access policies created."
 	}
 	logger.Logger.Info("Successfully created temporary access package", "user", p.userInfo.Email, "duration", durationStr)
+	p.created(cloneID, []string{sourceAccess.Namespace + "/" + sourceAccess.Name, targetAccess.Namespace + "/" + targetAccess.Name})
+	if payload.Heartbeat {
+		p.announceHeartbeat(cloneID, "Service")
+	}
+	if payload.SessionBound {
+		p.bindAccessToSession(cloneID, "Service")
+	}
 	p.logAndBroadcast(LogEntry{Payload: msg, ClassName: "log-success", LogType: "Service", Type: "applyResult"})
 	p.logAndBroadcast(LogEntry{Payload: "--- Request complete ---", ClassName: "log-success", LogType: "Service", Type: "applyComplete"})
 }
@@ -177,6 +207,21 @@ func (p *webSocketCommandProcessor) handleRequestClusterAccess(payload webSocket
 	}
 	sourceNs := sourceParts[0]
 	targetNs := targetParts[0]
+	if p.invalidDirection(&payload.Direction, "Service") {
+		return
+	}
+	if p.invalidDuration(payload.Duration, "Service") {
+		return
+	}
+	if p.outOfScope("Service", []string{sourceNs, targetNs}, payload.Duration, "Service") {
+		return
+	}
+	if p.missingJustification(payload.Description, []string{sourceNs, targetNs}, "Service") {
+		return
+	}
+	if !p.checkSessionBinding(payload, "Service") {
+		return
+	}
 
 	requiredPerms := []k8s.PermissionRequest{
 		{Verb: "create", Resource: "services", Namespace: sourceNs},
@@ -217,6 +262,21 @@ func (p *webSocketCommandProcessor) handleRequestExternalAccess(payload webSocke
 		return
 	}
 	serviceNs := serviceParts[0]
+	if p.invalidDirection(&payload.Direction, "External") {
+		return
+	}
+	if p.invalidDuration(payload.Duration, "External") {
+		return
+	}
+	if p.outOfScope("External", []string{serviceNs}, payload.Duration, "External") {
+		return
+	}
+	if p.missingJustification(payload.Description, []string{serviceNs}, "External") {
+		return
+	}
+	if !p.checkSessionBinding(payload, "External") {
+		return
+	}
 
 	requiredPerms := []k8s.PermissionRequest{
 		{Verb: "create", Resource: "services", Namespace: serviceNs},
@@ -243,69 +303,18 @@ func (p *webSocketCommandProcessor) handleRequestExternalAccess(payload webSocke
 		return
 	}
 
-	var overridePorts []corev1.ServicePort
-	if payload.Ports != "" {
-		portStrings := strings.SplitSeq(payload.Ports, ",")
-		for pStr := range portStrings {
-			pStr = strings.TrimSpace(pStr)
-			if pStr == "" {
-				continue
-			}
-			port, err := strconv.Atoi(pStr)
-			if err != nil {
-				p.sendError("Invalid port override", fmt.Errorf("'%s' is not a valid port number", pStr), "External")
-				return
-			}
-			overridePorts = append(overridePorts, corev1.ServicePort{
-				Name: fmt.Sprintf("port-%d", port), Protocol: corev1.ProtocolTCP, Port: int32(port),
-			})
-		}
-	}
-
-	trimmedCIDR := strings.TrimSpace(payload.Cidr)
-	if _, _, err := net.ParseCIDR(trimmedCIDR); err != nil {
-		if net.ParseIP(trimmedCIDR) == nil {
-			p.sendError("Invalid Source IP / CIDR", fmt.Errorf("'%s' is not a valid IP address or CIDR block", payload.Cidr), "External")
-			return
-		}
-	}
-
-	var durationStr string
-	if payload.Duration > 0 {
-		durationStr = fmt.Sprintf("%ds", payload.Duration)
-	}
-
-	serviceName := serviceParts[1]
-
-	cloneID := uuid.New().String()
-	randSuffix := hex.EncodeToString([]byte(cloneID))[:8]
-	cloneName := fmt.Sprintf("nc-%s-%s", shortHash(serviceName), randSuffix)
-	cloneLabel := map[string]string{"netwatch.vtk.io/request-id": cloneID}
-
-	_, err = k8s.CloneService(p.ctx, userKubeClient, serviceNs, serviceName, cloneName, cloneLabel, overridePorts)
+	plan, err := p.planExternalAccess(payload)
 	if err != nil {
-		p.sendError("Could not clone service for external access", err, "External")
+		p.sendError("Could not prepare the external access", err, "External")
 		return
 	}
+	clone, ea := plan.clones[0], plan.externalAccess
+	cloneName, cloneID := clone.Name, plan.requestID
 
-	ea := &vtkiov1alpha1.ExternalAccess{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("ea-%s", cloneName),
-			Namespace: serviceNs,
-			Labels: map[string]string{
-				"app.kubernetes.io/managed-by": "netwatch",
-				"netwatch.vtk.io/user":         p.sanitizedUsername,
-				"netwatch.vtk.io/request-id":   cloneID,
-			},
-		},
-		Spec: vtkiov1alpha1.ExternalAccessSpec{
-			TargetCIDRs:     []string{payload.Cidr},
-			Direction:       payload.Direction,
-			Duration:        durationStr,
-			ServiceSelector: &metav1.LabelSelector{MatchLabels: cloneLabel},
-		},
+	if err := k8s.CreateService(p.ctx, userKubeClient, clone); err != nil {
+		p.sendError("Could not clone service for external access", err, "External")
+		return
 	}
-
 	if err := k8s.CreateExternalAccess(p.ctx, userKubeClient, ea); err != nil {
 		p.sendError("Could not create ExternalAccess policy", err, "External")
 		if cleanupErr := k8s.DeleteService(p.ctx, userKubeClient, serviceNs, cloneName); cleanupErr != nil && !k8s.IsNotFound(cleanupErr) {
@@ -314,11 +323,27 @@ func (p *webSocketCommandProcessor) handleRequestExternalAccess(payload webSocke
 		return
 	}
 
+	p.created(cloneID, []string{ea.Namespace + "/" + ea.Name})
+	if payload.Heartbeat {
+		p.announceHeartbeat(cloneID, "External")
+	}
+	if payload.SessionBound {
+		p.bindAccessToSession(cloneID, "External")
+	}
 	msg := "SUCCESS: ExternalAccess policy request sent."
 	p.logAndBroadcast(LogEntry{Payload: msg, ClassName: "log-success", LogType: "External", Type: "applyResult"})
 	p.logAndBroadcast(LogEntry{Payload: "--- Request complete ---", ClassName: "log-success", LogType: "External", Type: "applyComplete"})
 }
 
+// submissionType returns the type of a submitted request: Service when it names a target service, External when it
+// opens a service to a CIDR.
+func submissionType(payload webSocketPayload) string {
+	if payload.TargetService != "" {
+		return "Service"
+	}
+	return "External"
+}
+
 func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketPayload) {
 	logger.Logger.Info("WebSocket command received", "command", "submitAccessRequest", "user", p.userInfo.Email)
 
@@ -328,12 +353,32 @@ func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketP
 		return
 	}
 
+	requestLabels, err := toRequestObjectLabels(payload.Labels)
+	if err != nil {
+		p.sendError("Invalid request labels", err, "Request")
+		return
+	}
+	if p.invalidDirection(&payload.Direction, "Request") {
+		return
+	}
+
+	requestor, username, filedBy := p.userInfo.Email, p.sanitizedUsername, ""
+	onBehalf := payload.OnBehalfOf != "" && payload.OnBehalfOf != p.userInfo.Email
+	if onBehalf {
+		if !p.mayFileOnBehalf(payload.OnBehalfOf) {
+			return
+		}
+		requestor, username, filedBy = payload.OnBehalfOf, sanitizeUsername(payload.OnBehalfOf), p.userInfo.Email
+	}
+
 	requestID := uuid.New().String()
 	requestCR := &netwatchv1alpha1.AccessRequest{
-		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("ar-%s-%s", p.sanitizedUsername, requestID[:8])},
+		ObjectMeta: accessRequestMeta(username, requestID, requestLabels),
 		Spec: netwatchv1alpha1.AccessRequestSpec{
-			Requestor:     p.userInfo.Email,
+			Requestor:     requestor,
+			FiledBy:       filedBy,
 			RequestID:     requestID,
+			RequestType:   submissionType(payload),
 			SourceService: payload.SourceService,
 			TargetService: payload.TargetService,
 			Cidr:          payload.Cidr,
@@ -342,11 +387,15 @@ func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketP
 			Ports:         payload.Ports,
 			Duration:      payload.Duration,
 			Description:   payload.Description,
+			TicketRef:     strings.TrimSpace(payload.TicketRef),
+			Priority:      payload.Priority,
 		},
 	}
+	if p.refusedByPolicy(&requestCR.Spec) || p.invalidResubmission(&requestCR.Spec, payload.PreviousRequestID) {
+		return
+	}
 
-	if payload.TargetService != "" { // Service-to-Service request
-		requestCR.Spec.RequestType = "Service"
+	if requestCR.Spec.RequestType == "Service" { // Service-to-Service request
 		sourceParts := strings.Split(payload.SourceService, "/")
 		targetParts := strings.Split(payload.TargetService, "/")
 		if len(sourceParts) != 2 || len(targetParts) != 2 {
@@ -355,6 +404,16 @@ func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketP
 		}
 		sourceNs, sourceName := sourceParts[0], sourceParts[1]
 		targetNs, targetName := targetParts[0], targetParts[1]
+		// Pre-approvals, auto-approval rules and partial requests rely on the requestor's own groups and permissions,
+		// which are unknown when someone else files the request: an approver handles all of it.
+		if onBehalf {
+			requestCR.Spec.Status = "PendingFull"
+			p.fileRequest(requestCR, payload)
+			return
+		}
+		if p.approveWithPreApproval(requestCR) || p.autoApprove(requestCR) {
+			return
+		}
 
 		permsSource := []k8s.PermissionRequest{
 			{Verb: "create", Resource: "services", Namespace: sourceNs},
@@ -377,6 +436,8 @@ func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketP
 			)
 			requestCR.Spec.Status = "PendingFull"
 		} else if canSource {
+			// The requestor's side stands in for its approval, the other side is reviewed as a partial request.
+			requestCR.Spec.DualApproval = false
 			logger.Logger.Info("User has source permissions. Creating partial request.", "user", p.userInfo.Email, "sourceNs", sourceNs)
 			cloneName, err := p.createPartialAccess(userKubeClient, sourceNs, sourceName, targetNs, targetName, requestID, payload, true)
 			if err != nil {
@@ -386,6 +447,7 @@ func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketP
 			requestCR.Spec.Status = "PendingTarget"
 			requestCR.Spec.SourceCloneName = cloneName
 		} else if canTarget {
+			requestCR.Spec.DualApproval = false
 			logger.Logger.Info("User has target permissions. Creating partial request.", "user", p.userInfo.Email, "targetNs", targetNs)
 			cloneName, err := p.createPartialAccess(userKubeClient, targetNs, targetName, sourceNs, sourceName, requestID, payload, false)
 			if err != nil {
@@ -399,22 +461,61 @@ func (p *webSocketCommandProcessor) handleSubmitAccessRequest(payload webSocketP
 			requestCR.Spec.Status = "PendingFull"
 		}
 	} else { // External Access request
-		requestCR.Spec.RequestType = "External"
 		requestCR.Spec.Status = "PendingFull"
+		if !onBehalf && (p.approveWithPreApproval(requestCR) || p.autoApprove(requestCR)) {
+			return
+		}
 	}
 
+	p.fileRequest(requestCR, payload)
+}
+
+// refusedByPolicy reports, and rejects, a submission the policy refuses: an unknown priority, a target held after
+// repeated denials, a missing ticket or justification, an invalid duration, or an access outside the scope of an
+// automation identity. Otherwise it records the approvals the request needs. Every channel runs it on the requests
+// it submits, once their type is set.
+func (p *webSocketCommandProcessor) refusedByPolicy(spec *netwatchv1alpha1.AccessRequestSpec) bool {
+	namespaces := requestNamespaces(*spec)
+	if p.invalidPriority(&spec.Priority) || p.heldByDenyWindow(*spec) || p.missingTicket(*spec) ||
+		p.missingJustification(spec.Description, namespaces, "Request") || p.invalidDuration(spec.Duration, "Request") ||
+		p.outOfScope(spec.RequestType, namespaces, spec.Duration, "Request") {
+		return true
+	}
+	setRequiredApprovals(p.ctx, spec)
+	return false
+}
+
+// fileRequest stores a validated AccessRequest for review.
+func (p *webSocketCommandProcessor) fileRequest(requestCR *netwatchv1alpha1.AccessRequest, payload webSocketPayload) {
 	if err := k8s.CreateAccessRequestAsApp(p.ctx, requestCR); err != nil {
 		p.sendError("Failed to submit AccessRequest", err, "Request")
 		return
 	}
+	invalidateAccessRequestCache()
+	storeRequestSubmission(p.ctx, requestCR, p.channel, payload)
+	startRequestTiming(p.ctx, requestCR)
+	p.submitted(requestCR, false)
+
+	msg := "SUCCESS: Your access request has been submitted for review."
+	if requestCR.Spec.FiledBy != "" {
+		logger.Logger.Info("Access request filed on behalf of another user", "requestor", requestCR.Spec.Requestor, "filedBy", requestCR.Spec.FiledBy)
+		msg = fmt.Sprintf("SUCCESS: Access request for %s filed by %s has been submitted for review.", requestCR.Spec.Requestor, requestCR.Spec.FiledBy)
+	}
+	groups, owners := routedGroups(p.ctx, requestCR.Spec), routedOwners(p.ctx, requestCR.Spec)
 	p.logAndBroadcast(
 		LogEntry{
-			Payload:   "SUCCESS: Your access request has been submitted for review.",
+			Payload:   msg + prioritySuffix(requestCR.Spec),
 			ClassName: "log-success",
 			LogType:   "Request",
 			Type:      "applyResult",
+			Groups:    groups,
+			Owners:    owners,
 		},
 	)
+	// Decision links only go to the notification targets: the activity log is readable by every user.
+	event := newRequestEvent("request.submitted", requestCR, requestCR.Spec.FiledBy, requestCR.Spec.Description)
+	event.DecisionLinks, event.Groups, event.Owners = newDecisionLinks(requestCR), groups, owners
+	notifyLifecycleEvent(event)
 }
 
 func (p *webSocketCommandProcessor) handleApproveAccessRequest(payload webSocketPayload) {
@@ -432,29 +533,55 @@ func (p *webSocketCommandProcessor) handleApproveAccessRequest(payload webSocket
 		p.sendError("Could not find pending request to approve", err, "Request")
 		return
 	}
+	if p.invalidDirection(&request.Spec.Direction, "Request") {
+		return
+	}
+	// Changes are checked first, so that shortening a request can bring it within the maximum duration.
+	changes, err := tightenRequest(p.ctx, &request.Spec, payload)
+	if err != nil {
+		p.sendError("Invalid changes to the request", err, "Request")
+		return
+	}
+	if p.invalidDuration(request.Spec.Duration, "Request") {
+		return
+	}
+	if p.outOfScope(request.Spec.RequestType, requestNamespaces(request.Spec), request.Spec.Duration, "Request") {
+		return
+	}
+	if p.notRoutedApprover(request) || p.selfApprovalRefused(request) || p.collectSideApproval(request, payload.Comment, changes) ||
+		p.collectApproval(request, payload.Comment, changes) {
+		return
+	}
+	if len(changes) > 0 {
+		logger.Logger.Info("Request changed before approval", "request", request.Name, "approver", p.userInfo.Email, "changes", changes)
+	}
 
-	approverKubeClient, err := k8s.GetImpersonatingKubeClient(p.idToken)
+	approverKubeClient, err := p.impersonatingClient()
 	if err != nil {
 		p.sendError("Could not create approver's impersonating client", err, "Request")
 		return
 	}
+	if request.Spec.DualApproval {
+		// Each approver was only checked for their side, so the app creates both.
+		approverKubeClient = k8s.GetAppKubeClient()
+	}
 
 	switch request.Spec.Status {
 	case "PendingFull":
 		logger.Logger.Info("Approving a full request", "request", request.Name)
-		if err := p.approveFullRequest(approverKubeClient, request); err != nil {
+		if err := p.approveFullRequest(approverKubeClient, request, p.approvalAnnotations(request, payload.Comment)); err != nil {
 			p.sendError("Failed to approve full request", err, "Request")
 			return
 		}
 	case "PendingTarget":
 		logger.Logger.Info("Approving target half of a partial request", "request", request.Name)
-		if err := p.approvePartialRequest(approverKubeClient, request, false); err != nil {
+		if err := p.approvePartialRequest(approverKubeClient, request, false, payload.Comment); err != nil {
 			p.sendError("Failed to approve target-side of the request", err, "Request")
 			return
 		}
 	case "PendingSource":
 		logger.Logger.Info("Approving source half of a partial request", "request", request.Name)
-		if err := p.approvePartialRequest(approverKubeClient, request, true); err != nil {
+		if err := p.approvePartialRequest(approverKubeClient, request, true, payload.Comment); err != nil {
 			p.sendError("Failed to approve source-side of the request", err, "Request")
 			return
 		}
@@ -463,12 +590,18 @@ func (p *webSocketCommandProcessor) handleApproveAccessRequest(payload webSocket
 		return
 	}
 
+	// The decision is recorded first, so that the request is never gone without a trace.
+	recordAccessDecision(p.ctx, request, "approved", p.approvers(request), payload.Comment, changes)
 	if err := k8s.DeleteAccessRequestAsApp(p.ctx, payload.RequestID); err != nil {
 		logger.Logger.Error("Failed to delete approved AccessRequest CR", "error", err, "requestID", payload.RequestID)
 	}
+	invalidateAccessRequestCache()
+	recordRequestDecision(p.ctx, request, "approved", p.userInfo.Email, "")
+	notifyLifecycleEvent(newRequestEvent("request.approved", request, p.approvers(request), payload.Comment))
 
 	p.logAndBroadcast(LogEntry{
-		Payload:   fmt.Sprintf("SUCCESS: Request from %s approved by %s.", request.Spec.Requestor, p.userInfo.Email),
+		Payload: fmt.Sprintf("SUCCESS: Request from %s approved by %s.%s%s",
+			requestorLabel(request.Spec), p.approvers(request), changesSuffix(changes), commentSuffix(payload.Comment)),
 		ClassName: "log-success",
 		LogType:   request.Spec.RequestType,
 		Type:      "applyResult",
@@ -495,14 +628,17 @@ func (p *webSocketCommandProcessor) handleDenyAccessRequest(payload webSocketPay
 		return
 	}
 
-	isOwner := p.userInfo.Email == request.Spec.Requestor
+	isOwner := isRequestOwner(p.userInfo.Email, request.Spec)
 	var canProceed bool
 	var logMessage string
 
 	if isOwner {
 		canProceed = true
-		logMessage = fmt.Sprintf("Request from %s was aborted by the owner.", request.Spec.Requestor)
+		logMessage = fmt.Sprintf("Request from %s was aborted by %s.", requestorLabel(request.Spec), p.userInfo.Email)
 	} else {
+		if p.notRoutedApprover(request) || p.missingDenialReason(payload.Reason) {
+			return
+		}
 		canDeny, err := k8s.CanPerformAction(p.ctx, p.userInfo, "delete", "netwatch.vtk.io", "accessrequests", "", request.Name)
 		if err != nil {
 			p.sendError("Could not verify permissions for denying the request", err, "Request")
@@ -513,7 +649,7 @@ func (p *webSocketCommandProcessor) handleDenyAccessRequest(payload webSocketPay
 			return
 		}
 		canProceed = true
-		logMessage = fmt.Sprintf("Request from %s denied by %s.", request.Spec.Requestor, p.userInfo.Email)
+		logMessage = fmt.Sprintf("Request %s from %s denied by %s.", request.Spec.RequestID, requestorLabel(request.Spec), p.userInfo.Email)
 	}
 
 	if !canProceed {
@@ -521,7 +657,7 @@ func (p *webSocketCommandProcessor) handleDenyAccessRequest(payload webSocketPay
 		return
 	}
 
-	userKubeClient, err := k8s.GetImpersonatingKubeClient(p.idToken)
+	userKubeClient, err := p.impersonatingClient()
 	if err != nil {
 		p.sendError("Could not create impersonating client for cleanup", err, "Request")
 		return
@@ -550,12 +686,26 @@ func (p *webSocketCommandProcessor) handleDenyAccessRequest(payload webSocketPay
 		p.sendError("Failed to delete the AccessRequest resource", err, "Request")
 		return
 	}
+	invalidateAccessRequestCache()
+	if isOwner {
+		recordAccessDecision(p.ctx, request, "aborted", p.userInfo.Email, payload.Reason, nil)
+		recordRequestDecision(p.ctx, request, "aborted", p.userInfo.Email, payload.Reason)
+	} else {
+		recordDenial(p.ctx, request.Spec)
+		recordAccessDecision(p.ctx, request, "denied", p.userInfo.Email, payload.Reason, nil)
+		recordRequestDecision(p.ctx, request, "denied", p.userInfo.Email, payload.Reason)
+		notifyLifecycleEvent(newRequestEvent("request.denied", request, p.userInfo.Email, payload.Reason))
+	}
 
+	// The requestor is named so clients of the log stream can tell them why.
 	p.logAndBroadcast(LogEntry{
-		Payload:   logMessage,
+		Payload:   logMessage + reasonSuffix(payload.Reason),
 		ClassName: "log-warning",
 		LogType:   "Request",
 		Type:      "applyResult",
+		Requestor: request.Spec.Requestor,
+		Reason:    payload.Reason,
+		RequestID: request.Spec.RequestID,
 	})
 }
 
@@ -589,7 +739,7 @@ func (p *webSocketCommandProcessor) handleRevokeClusterAccess(payload webSocketP
 		logger.Logger.Warn("Found request-id but no Access objects to delete, maybe already cleaned up?", "reqID", reqID)
 	}
 
-	var deletionErrors []string
+	var deletionErrors, deleted, clones []string
 	for _, accessToDelete := range accessesToDelete.Items {
 		err := k8s.DeleteAccess(p.ctx, userKubeClient, accessToDelete.Namespace, accessToDelete.Name)
 		if err != nil && !k8s.IsNotFound(err) {
@@ -597,8 +747,12 @@ func (p *webSocketCommandProcessor) handleRevokeClusterAccess(payload webSocketP
 				deletionErrors,
 				fmt.Sprintf("failed to delete %s/%s: %v", accessToDelete.Namespace, accessToDelete.Name, err),
 			)
+			continue
 		}
+		deleted = append(deleted, accessToDelete.Namespace+"/"+accessToDelete.Name)
+		clones = append(clones, accessToDelete.Namespace+"/"+strings.TrimPrefix(accessToDelete.Name, "access-"))
 	}
+	p.revoked(reqID, deleted, clones)
 
 	if len(deletionErrors) > 0 {
 		p.sendError("Encountered errors while deleting the access pair", fmt.Errorf("%s", strings.Join(deletionErrors, "; ")), "Service")
@@ -624,10 +778,17 @@ func (p *webSocketCommandProcessor) handleRevokeExternalAccess(payload webSocket
 		p.sendError("Could not create user-impersonating client for revocation", err, "External")
 		return
 	}
+	access, err := k8s.GetExternalAccessAsApp(p.ctx, payload.Namespace, payload.Name)
+	if err != nil {
+		p.sendError("Could not find the specified external access policy. It may have already been revoked.", err, "External")
+		return
+	}
 	if err := k8s.DeleteExternalAccess(p.ctx, userKubeClient, payload.Namespace, payload.Name); err != nil {
 		p.sendError("Could not delete the specified external access policy.", err, "External")
 		return
 	}
+	p.revoked(access.Labels["netwatch.vtk.io/request-id"], []string{payload.Namespace + "/" + payload.Name},
+		[]string{payload.Namespace + "/" + strings.TrimPrefix(payload.Name, "ea-")})
 	msg := fmt.Sprintf(
 		"SUCCESS: ExternalAccess policy '%s' has been marked for deletion. The controller will clean up its resources.",
 		payload.Name,
@@ -684,7 +845,12 @@ func (p *webSocketCommandProcessor) createPartialAccess(
 	}
 
 	access := &vtkiov1alpha1.Access{
-		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("access-%s", localCloneName), Namespace: localNs, Labels: commonAccessLabels},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("access-%s", localCloneName),
+			Namespace:   localNs,
+			Labels:      commonAccessLabels,
+			Annotations: provenanceAnnotations(p.userInfo.Email, ""),
+		},
 		Spec: vtkiov1alpha1.AccessSpec{
 			Duration:        durationStr,
 			ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
@@ -694,13 +860,7 @@ func (p *webSocketCommandProcessor) createPartialAccess(
 		},
 	}
 
-	if (payload.Direction == "egress" && isSource) || (payload.Direction == "ingress" && !isSource) {
-		access.Spec.Direction = "egress"
-	} else if (payload.Direction == "ingress" && isSource) || (payload.Direction == "egress" && !isSource) {
-		access.Spec.Direction = "ingress"
-	} else {
-		access.Spec.Direction = "all"
-	}
+	access.Spec.Direction = sideDirection(payload.Direction, isSource)
 
 	if err := k8s.CreateAccess(p.ctx, userClient, access); err != nil {
 		if cleanupErr := k8s.DeleteService(p.ctx, userClient, localNs, localCloneName); cleanupErr != nil && !k8s.IsNotFound(cleanupErr) {
@@ -712,8 +872,57 @@ func (p *webSocketCommandProcessor) createPartialAccess(
 	return localCloneName, nil
 }
 
+// approveWithPreApproval approves a submission right away, on behalf of the approver of a matching pre-approval.
+// It reports whether the submission was handled; otherwise it goes through review as usual.
+func (p *webSocketCommandProcessor) approveWithPreApproval(request *netwatchv1alpha1.AccessRequest) bool {
+	// A pre-approval is a single approval, it can't stand in for a quorum or for both sides.
+	if request.Spec.RequiredApprovals > 1 || request.Spec.DualApproval {
+		return false
+	}
+	preApproval := findPreApproval(p.ctx, p.userInfo, request.Spec)
+	if preApproval == nil {
+		return false
+	}
+	approver := &k8s.UserInfo{Email: preApproval.Spec.Approver, Groups: preApproval.Spec.ApproverGroups}
+	allowed, err := k8s.CanPerformAllActions(p.ctx, approver, approvalPermissions(request.Spec))
+	if err != nil || !allowed {
+		logger.Logger.Warn("Pre-approval is no longer backed by its approver's permissions",
+			"preApproval", preApproval.Name, "approver", approver.Email, "error", err)
+		return false
+	}
+	approverClient, err := k8s.GetImpersonatingKubeClientFor(approver)
+	if err != nil {
+		p.sendError("Could not create the pre-approval approver's client", err, "Request")
+		return true
+	}
+	if err := p.approveFullRequest(approverClient, request, p.approvalAnnotations(request, "")); err != nil {
+		p.sendError("Failed to apply the pre-approved request", err, "Request")
+		return true
+	}
+	logger.Logger.Info("Request approved by pre-approval", "user", p.userInfo.Email, "preApproval", preApproval.Name, "approver", approver.Email)
+	p.submitted(request, true)
+	recordAccessDecision(p.ctx, request, "approved", approver.Email, "pre-approval "+preApproval.Name, nil)
+	notifyLifecycleEvent(newRequestEvent("request.approved", request, approver.Email, "pre-approval "+preApproval.Name))
+	p.logAndBroadcast(LogEntry{
+		Payload: fmt.Sprintf("SUCCESS: Request from %s approved by pre-approval %s (granted by %s).",
+			request.Spec.Requestor, preApproval.Name, approver.Email),
+		ClassName: "log-success",
+		LogType:   request.Spec.RequestType,
+		Type:      "applyResult",
+	})
+	p.logAndBroadcast(
+		LogEntry{Payload: "--- Request complete ---", ClassName: "log-success", LogType: request.Spec.RequestType, Type: "applyComplete"},
+	)
+	return true
+}
+
 // approveFullRequest contains the logic for approving a full request (which applies to both Service and External).
-func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Client, request *netwatchv1alpha1.AccessRequest) error {
+// The accesses created carry the provenance annotations given.
+func (p *webSocketCommandProcessor) approveFullRequest(
+	approverClient client.Client,
+	request *netwatchv1alpha1.AccessRequest,
+	annotations map[string]string,
+) error {
 	switch request.Spec.RequestType {
 	case "Service":
 		// The following variables must be defined inside this function if used here,
@@ -774,7 +983,12 @@ func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Cli
 			"netwatch.vtk.io/request-id":   cloneID,
 		}
 		sourceAccess := &vtkiov1alpha1.Access{
-			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("access-%s", sourceCloneName), Namespace: sourceNs, Labels: commonAccessLabels},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("access-%s", sourceCloneName),
+				Namespace:   sourceNs,
+				Labels:      commonAccessLabels,
+				Annotations: maps.Clone(annotations),
+			},
 			Spec: vtkiov1alpha1.AccessSpec{
 				Duration:        durationStr,
 				ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
@@ -782,7 +996,12 @@ func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Cli
 			},
 		}
 		targetAccess := &vtkiov1alpha1.Access{
-			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("access-%s", targetCloneName), Namespace: targetNs, Labels: commonAccessLabels},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        fmt.Sprintf("access-%s", targetCloneName),
+				Namespace:   targetNs,
+				Labels:      commonAccessLabels,
+				Annotations: maps.Clone(annotations),
+			},
 			Spec: vtkiov1alpha1.AccessSpec{
 				Duration:        durationStr,
 				ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
@@ -790,17 +1009,8 @@ func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Cli
 			},
 		}
 
-		switch request.Spec.Direction {
-		case "ingress":
-			sourceAccess.Spec.Direction = "ingress"
-			targetAccess.Spec.Direction = "egress"
-		case "egress":
-			sourceAccess.Spec.Direction = "egress"
-			targetAccess.Spec.Direction = "ingress"
-		default:
-			sourceAccess.Spec.Direction = "all"
-			targetAccess.Spec.Direction = "all"
-		}
+		sourceAccess.Spec.Direction = sideDirection(request.Spec.Direction, true)
+		targetAccess.Spec.Direction = sideDirection(request.Spec.Direction, false)
 
 		if err := k8s.CreateAccess(p.ctx, approverClient, sourceAccess); err != nil {
 			if cleanupErr := k8s.DeleteService(p.ctx, approverClient, sourceClone.Namespace, sourceClone.Name); cleanupErr != nil &&
@@ -828,6 +1038,8 @@ func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Cli
 			}
 			return fmt.Errorf("could not create target Access policy: %w", err)
 		}
+		notifyLifecycleEvent(newAccessEvent("access.created", cloneID, request.Spec.Requestor, "",
+			[]string{sourceAccess.Namespace + "/" + sourceAccess.Name, targetAccess.Namespace + "/" + targetAccess.Name}))
 
 	case "External":
 		serviceParts := strings.Split(request.Spec.Service, "/")
@@ -859,9 +1071,10 @@ func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Cli
 
 		ea := &vtkiov1alpha1.ExternalAccess{
 			ObjectMeta: metav1.ObjectMeta{
-				Name:      fmt.Sprintf("ea-%s", cloneName),
-				Namespace: serviceNs,
-				Labels:    commonAccessLabels,
+				Name:        fmt.Sprintf("ea-%s", cloneName),
+				Namespace:   serviceNs,
+				Labels:      commonAccessLabels,
+				Annotations: maps.Clone(annotations),
 			},
 			Spec: vtkiov1alpha1.ExternalAccessSpec{
 				TargetCIDRs:     []string{request.Spec.Cidr},
@@ -877,6 +1090,8 @@ func (p *webSocketCommandProcessor) approveFullRequest(approverClient client.Cli
 			}
 			return fmt.Errorf("could not create ExternalAccess policy: %w", err)
 		}
+		notifyLifecycleEvent(newAccessEvent("access.created", cloneID, request.Spec.Requestor, "",
+			[]string{ea.Namespace + "/" + ea.Name}))
 	}
 	return nil
 }
@@ -885,6 +1100,7 @@ func (p *webSocketCommandProcessor) approvePartialRequest(
 	approverClient client.Client,
 	request *netwatchv1alpha1.AccessRequest,
 	approvingSourceSide bool,
+	comment string,
 ) error {
 	var localNs, localName, remoteNs, existingCloneName string
 	sourceParts := strings.Split(request.Spec.SourceService, "/")
@@ -922,7 +1138,12 @@ func (p *webSocketCommandProcessor) approvePartialRequest(
 	}
 
 	newAccess := &vtkiov1alpha1.Access{
-		ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("access-%s", localCloneName), Namespace: localNs, Labels: commonAccessLabels},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("access-%s", localCloneName),
+			Namespace:   localNs,
+			Labels:      commonAccessLabels,
+			Annotations: p.approvalAnnotations(request, comment),
+		},
 		Spec: vtkiov1alpha1.AccessSpec{
 			Duration:        durationStr,
 			ServiceSelector: &metav1.LabelSelector{MatchLabels: commonRequestLabel},
@@ -931,13 +1152,7 @@ func (p *webSocketCommandProcessor) approvePartialRequest(
 	}
 
 	isSource := request.Spec.Status == "PendingSource"
-	if (request.Spec.Direction == "egress" && isSource) || (request.Spec.Direction == "ingress" && !isSource) {
-		newAccess.Spec.Direction = "egress"
-	} else if (request.Spec.Direction == "ingress" && isSource) || (request.Spec.Direction == "egress" && !isSource) {
-		newAccess.Spec.Direction = "ingress"
-	} else {
-		newAccess.Spec.Direction = "all"
-	}
+	newAccess.Spec.Direction = sideDirection(request.Spec.Direction, isSource)
 
 	if err := k8s.CreateAccess(p.ctx, approverClient, newAccess); err != nil {
 		if cleanupErr := k8s.DeleteService(p.ctx, approverClient, localNs, localCloneName); cleanupErr != nil &&
@@ -953,6 +1168,12 @@ func (p *webSocketCommandProcessor) approvePartialRequest(
 	logger.Logger.Info("Updating original partial access with final duration", "name", originalAccessName, "namespace", remoteNs)
 	err = k8s.UpdateAccessWithRetry(p.ctx, appKubeClient, remoteNs, originalAccessName, func(access *vtkiov1alpha1.Access) {
 		access.Spec.Duration = finalDurationStr
+		// The approver may have narrowed the direction since the requestor created this half.
+		access.Spec.Direction = sideDirection(request.Spec.Direction, !isSource)
+		if access.Annotations == nil {
+			access.Annotations = map[string]string{}
+		}
+		maps.Copy(access.Annotations, p.approvalAnnotations(request, comment))
 	})
 	if err != nil {
 		return fmt.Errorf("failed to update original partial access object: %w", err)
@@ -966,5 +1187,7 @@ func (p *webSocketCommandProcessor) approvePartialRequest(
 	if err != nil {
 		return fmt.Errorf("failed to update new partial access object: %w", err)
 	}
+	notifyLifecycleEvent(newAccessEvent("access.created", request.Spec.RequestID, request.Spec.Requestor, "",
+		[]string{remoteNs + "/" + originalAccessName, newAccess.Namespace + "/" + newAccess.Name}))
 	return nil
 }
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+// errConnectionClosed is returned by the read pump once the client is gone. It tears down the sibling goroutines.
+var errConnectionClosed = errors.New("websocket connection closed")
+
+// wsConnection owns a single upgraded WebSocket and every goroutine serving it.
+// All writes go through WriteJSON/writeControl so the underlying connection only ever has one writer.
+type wsConnection struct {
+	conn      *websocket.Conn
+	writeMu   sync.Mutex
+	closeOnce sync.Once
+	ctx       context.Context
+	cancel    context.CancelFunc
+
+	// writeWait, pongWait and pingPeriod default to the package constants.
+	writeWait  time.Duration
+	pongWait   time.Duration
+	pingPeriod time.Duration
+}
+
+func newWSConnection(parent context.Context, conn *websocket.Conn) *wsConnection {
+	ctx, cancel := context.WithCancel(parent)
+	return &wsConnection{conn: conn, ctx: ctx, cancel: cancel, writeWait: writeWait, pongWait: pongWait, pingPeriod: pingPeriod}
+}
+
+// Context is cancelled as soon as the connection starts shutting down.
+func (c *wsConnection) Context() context.Context {
+	return c.ctx
+}
+
+// WriteJSON serializes a message to the client with a write deadline.
+func (c *wsConnection) WriteJSON(v any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(c.writeWait)) //nolint:all
+	return c.conn.WriteJSON(v)
+}
+
+func (c *wsConnection) writeControl(messageType int) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.conn.SetWriteDeadline(time.Now().Add(c.writeWait)) //nolint:all
+	return c.conn.WriteMessage(messageType, nil)
+}
+
+// Close stops every goroutine of the connection and closes the socket. It is safe to call multiple times.
+func (c *wsConnection) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		c.cancel()
+		err = c.conn.Close()
+	})
+	return err
+}
+
+// Run serves the connection until the client disconnects, a ping fails or the parent context is cancelled.
+// Incoming payloads are passed to handle sequentially, in the order they were received. Commands that fail
+// validation are answered with a commandError frame instead, see decodeWebSocketMessage.
+func (c *wsConnection) Run(handle func(webSocketPayload)) error {
+	g, ctx := errgroup.WithContext(c.ctx)
+
+	g.Go(func() error { return c.readPump(handle) })
+	g.Go(func() error { return c.pingLoop(ctx) })
+	g.Go(func() error {
+		// Unblock the read pump when a sibling fails or the parent context goes away.
+		<-ctx.Done()
+		c.Close() //nolint:all
+		return nil
+	})
+
+	err := g.Wait()
+	c.Close() //nolint:all
+	if errors.Is(err, errConnectionClosed) {
+		return nil
+	}
+	return err
+}
+
+func (c *wsConnection) readPump(handle func(webSocketPayload)) error {
+	c.conn.SetReadDeadline(time.Now().Add(c.pongWait)) //nolint:all
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(c.pongWait)) //nolint:all
+		logger.Logger.Debug("Received pong from client")
+		return nil
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				logger.Logger.Error("Unexpected WebSocket close error", "error", err)
+			} else {
+				logger.Logger.Info("Client disconnected gracefully or due to timeout", "error", err)
+			}
+			return errConnectionClosed
+		}
+		payload, cmdErr := decodeWebSocketMessage(data)
+		if cmdErr != nil {
+			logger.Logger.Warn("Rejected WebSocket command", "command", cmdErr.Command, "code", cmdErr.Code, "field", cmdErr.Field, "reason", cmdErr.Message)
+			cmdErr.Timestamp = time.Now().UnixMilli()
+			if err := c.WriteJSON(cmdErr); err != nil {
+				logger.Logger.Warn("Could not write JSON to WebSocket", "error", err)
+			}
+			continue
+		}
+		handle(payload)
+	}
+}
+
+func (c *wsConnection) pingLoop(ctx context.Context) error {
+	ticker := time.NewTicker(c.pingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.writeControl(websocket.PingMessage); err != nil {
+				logger.Logger.Error("Failed to send ping to client, closing connection", "error", err)
+				return err
+			}
+		case <-ctx.Done():
+			logger.Logger.Info("Read pump finished, stopping pinger.")
+			return nil
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+func TestMain(m *testing.M) {
+	logger.InitializeLogger(slog.LevelError)
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testConnection is the server side of a WebSocket served by wsConnection.Run, and the client dialed to it.
+type testConnection struct {
+	server *wsConnection
+	client *websocket.Conn
+	done   chan error
+}
+
+// serveTestConnection upgrades a connection in an httptest server and runs it, after letting configure shorten
+// its timings.
+func serveTestConnection(t *testing.T, parent context.Context, configure func(*wsConnection), handle func(webSocketPayload)) *testConnection {
+	t.Helper()
+	servers := make(chan *wsConnection, 1)
+	done := make(chan error, 1)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		conn := newWSConnection(parent, raw)
+		if configure != nil {
+			configure(conn)
+		}
+		servers <- conn
+		done <- conn.Run(handle)
+	}))
+	t.Cleanup(srv.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { client.Close() }) //nolint:all
+	return &testConnection{server: <-servers, client: client, done: done}
+}
+
+// waitRun returns the error Run returned, failing the test if it is still running after a second.
+func (tc *testConnection) waitRun(t *testing.T) error {
+	t.Helper()
+	select {
+	case err := <-tc.done:
+		return err
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return")
+		return nil
+	}
+}
+
+func TestWSConnectionRunHandlesCommandsInOrder(t *testing.T) {
+	received := make(chan string, 3)
+	tc := serveTestConnection(t, context.Background(), nil, func(payload webSocketPayload) {
+		received <- payload.RequestID
+	})
+
+	for _, id := range []string{"r1", "r2", "r3"} {
+		if err := tc.client.WriteJSON(map[string]any{"command": "resumeAccess", "requestID": id}); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	for _, want := range []string{"r1", "r2", "r3"} {
+		select {
+		case got := <-received:
+			if got != want {
+				t.Fatalf("got request %q, want %q", got, want)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("request %q was not handled", want)
+		}
+	}
+
+	tc.client.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")) //nolint:all
+	if err := tc.waitRun(t); err != nil {
+		t.Fatalf("Run returned %v after the client closed, want nil", err)
+	}
+	if tc.server.Context().Err() == nil {
+		t.Fatal("the connection context is not cancelled after Run returned")
+	}
+}
+
+func TestWSConnectionRunRejectsInvalidCommands(t *testing.T) {
+	var handled atomic.Int32
+	tc := serveTestConnection(t, context.Background(), nil, func(webSocketPayload) { handled.Add(1) })
+
+	if err := tc.client.WriteJSON(map[string]any{"command": "dropDatabase"}); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	var frame commandError
+	tc.client.SetReadDeadline(time.Now().Add(time.Second)) //nolint:all
+	if err := tc.client.ReadJSON(&frame); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if frame.Type != "commandError" || frame.Code != errCodeUnknownCommand || frame.Command != "dropDatabase" || frame.Timestamp == 0 {
+		t.Fatalf("unexpected frame %+v", frame)
+	}
+	if handled.Load() != 0 {
+		t.Fatal("an invalid command reached the handler")
+	}
+}
+
+func TestWSConnectionCloseIsIdempotent(t *testing.T) {
+	tc := serveTestConnection(t, context.Background(), nil, func(webSocketPayload) {})
+
+	if err := tc.server.Close(); err != nil {
+		t.Fatalf("first Close: %v", err)
+	}
+	if err := tc.server.Close(); err != nil {
+		t.Fatalf("second Close: %v, want nil", err)
+	}
+	if err := tc.waitRun(t); err != nil {
+		t.Fatalf("Run returned %v after Close, want nil", err)
+	}
+	if tc.server.Context().Err() == nil {
+		t.Fatal("Close did not cancel the connection context")
+	}
+	tc.client.SetReadDeadline(time.Now().Add(time.Second)) //nolint:all
+	if _, _, err := tc.client.ReadMessage(); err == nil {
+		t.Fatal("the client can still read after Close")
+	}
+}
+
+func TestWSConnectionRunStopsWithParentContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tc := serveTestConnection(t, ctx, nil, func(webSocketPayload) {})
+
+	cancel()
+	if err := tc.waitRun(t); err != nil {
+		t.Fatalf("Run returned %v after the parent context was cancelled, want nil", err)
+	}
+}
+
+func TestWSConnectionPingLoopSendsPings(t *testing.T) {
+	tc := serveTestConnection(t, context.Background(), func(c *wsConnection) {
+		c.pingPeriod = 10 * time.Millisecond
+	}, func(webSocketPayload) {})
+
+	var pings atomic.Int32
+	tc.client.SetPingHandler(func(string) error {
+		pings.Add(1)
+		return nil
+	})
+	// Control frames are only processed while the client reads.
+	go func() {
+		for {
+			if _, _, err := tc.client.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	deadline := time.Now().Add(time.Second)
+	for pings.Load() < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("got %d pings in a second, want at least 3", pings.Load())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestWSConnectionRunStopsWithoutPongs(t *testing.T) {
+	tc := serveTestConnection(t, context.Background(), func(c *wsConnection) {
+		c.pongWait = 50 * time.Millisecond
+	}, func(webSocketPayload) {})
+
+	// The client never reads, so it never answers pings: the read deadline expires.
+	if err := tc.waitRun(t); err != nil {
+		t.Fatalf("Run returned %v once the read deadline expired, want nil", err)
+	}
+}
+
+func TestWSConnectionWriteDeadline(t *testing.T) {
+	tc := serveTestConnection(t, context.Background(), func(c *wsConnection) {
+		c.writeWait = -time.Second
+		c.pingPeriod = time.Hour
+	}, func(webSocketPayload) {})
+
+	err := tc.server.WriteJSON(map[string]string{"type": "hello"})
+	var netErr net.Error
+	if !errors.As(err, &netErr) || !netErr.Timeout() {
+		t.Fatalf("WriteJSON past its deadline returned %v, want a timeout", err)
+	}
+}
+
+func TestWSConnectionRunFailsWhenPingFails(t *testing.T) {
+	tc := serveTestConnection(t, context.Background(), func(c *wsConnection) {
+		c.writeWait = -time.Second
+		c.pingPeriod = 10 * time.Millisecond
+	}, func(webSocketPayload) {})
+
+	if err := tc.waitRun(t); err == nil {
+		t.Fatal("Run returned nil although pings could not be written")
+	}
+}
@@ -0,0 +1,434 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// webSocketSchemaVersion is the version of the WebSocket command messages, sent in the hello message. A command
+// may carry it in its "version" field, commands without it are read as the current version.
+const webSocketSchemaVersion = 1
+
+// Codes of the commandError frames.
+const (
+	errCodeMalformedMessage   = "malformedMessage"
+	errCodeUnsupportedVersion = "unsupportedVersion"
+	errCodeUnknownCommand     = "unknownCommand"
+	errCodeUnknownField       = "unknownField"
+	errCodeMissingField       = "missingField"
+	errCodeInvalidField       = "invalidField"
+)
+
+// commandError is sent to the client alone when a WebSocket command is rejected before it runs. Field is the JSON
+// field at fault, if any.
+type commandError struct {
+	Type         string `json:"type" binding:"required" enums:"commandError"`
+	Timestamp    int64  `json:"timestamp"`
+	Command      string `json:"command,omitempty"`
+	RequestToken string `json:"requestToken,omitempty"`
+	Code         string `json:"code" binding:"required" enums:"malformedMessage,unsupportedVersion,unknownCommand,unknownField,missingField,invalidField"`
+	Field        string `json:"field,omitempty" example:"direction"`
+	Message      string `json:"message" binding:"required"`
+}
+
+func (e *commandError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s: %s: %s", e.Code, e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func newCommandError(code, field, format string, args ...any) *commandError {
+	return &commandError{Type: "commandError", Code: code, Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// messageHeader holds the fields every command message has.
+type messageHeader struct {
+	Version      int    `json:"version" example:"1"`
+	Command      string `json:"command" binding:"required"`
+	RequestToken string `json:"requestToken"`
+}
+
+// webSocketMessage is the typed message of a single command. validate reports the first invalid field, payload
+// converts the message to the webSocketPayload the command handlers take.
+type webSocketMessage interface {
+	validate() *commandError
+	payload() webSocketPayload
+}
+
+// webSocketCommand describes a command: how to build its message, and what it does for /api/schema.
+type webSocketCommand struct {
+	newMessage  func() webSocketMessage
+	description string
+}
+
+// webSocketMessages maps each command to its message.
+var webSocketMessages = map[string]webSocketCommand{
+	"keepAlive": {func() webSocketMessage { return &keepAliveMessage{} },
+		"Marks the login session as active."},
+	"requestClusterAccess": {func() webSocketMessage { return &clusterAccessMessage{} },
+		"Creates an access between two services directly, without review."},
+	"requestExternalAccess": {func() webSocketMessage { return &externalAccessMessage{} },
+		"Creates an access between a service and an external IP or CIDR directly, without review."},
+	"submitAccessRequest": {func() webSocketMessage { return &submitAccessRequestMessage{} },
+		"Submits an access request for review: sourceService and targetService for a service-to-service request, service and cidr for an external one."},
+	"approveAccessRequest": {func() webSocketMessage { return &approveRequestMessage{} },
+		"Approves the pending access request, with an optional comment kept on the accesses for audit. Direction, ports and duration narrow the request before it is approved."},
+	"updateAccessRequest": {func() webSocketMessage { return &updateRequestMessage{} },
+		"Changes the ports, duration or description of the sender's own pending request, until someone approves it. Empty fields are kept."},
+	"denyAccessRequest": {func() webSocketMessage { return &denyRequestMessage{} },
+		"Denies the pending access request, or aborts it when sent by its requestor, with the reason told to the requestor."},
+	"resumeAccess": {func() webSocketMessage { return &requestIDMessage{} },
+		"Resumes the paused access."},
+	"revokeClusterAccess": {func() webSocketMessage { return &accessRefMessage{} },
+		"Revokes the access the Access object belongs to."},
+	"revokeExternalAccess": {func() webSocketMessage { return &accessRefMessage{} },
+		"Revokes the ExternalAccess."},
+	"pauseAccess": {func() webSocketMessage { return &pauseAccessMessage{} },
+		"Pauses the access, keeping its remaining time."},
+}
+
+// decodeWebSocketMessage parses and validates a command received on the WebSocket. Fields the command does not
+// take are refused, so that a typo doesn't silently fall back to a default.
+func decodeWebSocketMessage(data []byte) (webSocketPayload, *commandError) {
+	var header messageHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		return webSocketPayload{}, decodeError(err)
+	}
+	cmdErr := checkHeader(header)
+	if cmdErr == nil {
+		msg := webSocketMessages[header.Command].newMessage()
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(msg); err != nil {
+			cmdErr = decodeError(err)
+		} else if cmdErr = msg.validate(); cmdErr == nil {
+			payload := msg.payload()
+			payload.Command, payload.RequestToken = header.Command, header.RequestToken
+			return payload, nil
+		}
+	}
+	cmdErr.Command, cmdErr.RequestToken = header.Command, header.RequestToken
+	return webSocketPayload{}, cmdErr
+}
+
+func checkHeader(header messageHeader) *commandError {
+	switch {
+	case header.Version < 0 || header.Version > webSocketSchemaVersion:
+		return newCommandError(errCodeUnsupportedVersion, "version", "version %d is not supported, the latest is %d", header.Version, webSocketSchemaVersion)
+	case header.Command == "":
+		return newCommandError(errCodeMissingField, "command", "command is required")
+	case webSocketMessages[header.Command].newMessage == nil:
+		return newCommandError(errCodeUnknownCommand, "command", "unknown command %q", header.Command)
+	case len(header.RequestToken) > maxIdempotencyKeyLength:
+		return newCommandError(errCodeInvalidField, "requestToken", "requestToken is longer than %d characters", maxIdempotencyKeyLength)
+	}
+	return nil
+}
+
+// decodeError turns a JSON decoding error into a commandError naming the field at fault, when there is one.
+func decodeError(err error) *commandError {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return newCommandError(errCodeInvalidField, typeErr.Field, "%s must be of type %s", typeErr.Field, typeErr.Type.String())
+	}
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		field = strings.Trim(field, `"`)
+		return newCommandError(errCodeUnknownField, field, "this command does not take %s", field)
+	}
+	return newCommandError(errCodeMalformedMessage, "", "the message is not a valid JSON object: %s", err)
+}
+
+// requireField reports an empty string field.
+func requireField(field, value string) *commandError {
+	if strings.TrimSpace(value) == "" {
+		return newCommandError(errCodeMissingField, field, "%s is required", field)
+	}
+	return nil
+}
+
+// validateServiceRef checks that a field names a service as "namespace/name".
+func validateServiceRef(field, value string) *commandError {
+	if cmdErr := requireField(field, value); cmdErr != nil {
+		return cmdErr
+	}
+	if namespace, name, ok := strings.Cut(value, "/"); !ok || namespace == "" || name == "" || strings.Contains(name, "/") {
+		return newCommandError(errCodeInvalidField, field, "expected 'namespace/name', got %q", value)
+	}
+	return nil
+}
+
+// validateAccessFields checks the fields shared by direct creations and submissions.
+func validateAccessFields(direction, ports string, duration int64) *commandError {
+	if _, err := normalizeDirection(direction); err != nil {
+		return newCommandError(errCodeInvalidField, "direction", "%s", err)
+	}
+	if _, err := getOverridePorts(ports); err != nil {
+		return newCommandError(errCodeInvalidField, "ports", "%s", err)
+	}
+	if err := validateAccessDuration(duration); err != nil {
+		return newCommandError(errCodeInvalidField, "duration", "%s", err)
+	}
+	return nil
+}
+
+// validateCIDR checks that a field is an IP address or a CIDR block.
+func validateCIDR(field, value string) *commandError {
+	if cmdErr := requireField(field, value); cmdErr != nil {
+		return cmdErr
+	}
+	trimmed := strings.TrimSpace(value)
+	if _, _, err := net.ParseCIDR(trimmed); err != nil && net.ParseIP(trimmed) == nil {
+		return newCommandError(errCodeInvalidField, field, "%q is not a valid IP address or CIDR block", value)
+	}
+	return nil
+}
+
+// firstError returns the first non-nil error, validating fields in order.
+func firstError(errs ...*commandError) *commandError {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// keepAliveMessage only marks the login session as active.
+type keepAliveMessage struct {
+	messageHeader
+}
+
+func (m *keepAliveMessage) validate() *commandError { return nil }
+
+func (m *keepAliveMessage) payload() webSocketPayload { return webSocketPayload{} }
+
+// clusterAccessMessage is the requestClusterAccess command.
+type clusterAccessMessage struct {
+	messageHeader
+	SourceService string `json:"sourceService" binding:"required" example:"frontend/web"`
+	TargetService string `json:"targetService" binding:"required" example:"backend/api"`
+	Direction     string `json:"direction" enums:"ingress,egress,all,both"`
+	Ports         string `json:"ports" example:"8080,9090"`
+	Duration      int64  `json:"duration" example:"3600"`
+	Heartbeat     bool   `json:"heartbeat"`
+	SessionBound  bool   `json:"sessionBound"`
+	Description   string `json:"description" example:"Debugging the checkout flow"`
+}
+
+func (m *clusterAccessMessage) validate() *commandError {
+	return firstError(
+		validateServiceRef("sourceService", m.SourceService),
+		validateServiceRef("targetService", m.TargetService),
+		validateAccessFields(m.Direction, m.Ports, m.Duration),
+	)
+}
+
+func (m *clusterAccessMessage) payload() webSocketPayload {
+	return webSocketPayload{
+		SourceService: m.SourceService, TargetService: m.TargetService, Direction: m.Direction, Ports: m.Ports,
+		Duration: m.Duration, Heartbeat: m.Heartbeat, SessionBound: m.SessionBound, Description: m.Description,
+	}
+}
+
+// externalAccessMessage is the requestExternalAccess command.
+type externalAccessMessage struct {
+	messageHeader
+	Service      string `json:"service" binding:"required" example:"backend/api"`
+	Cidr         string `json:"cidr" binding:"required" example:"203.0.113.0/24"`
+	Direction    string `json:"direction" enums:"ingress,egress,all,both"`
+	Ports        string `json:"ports" example:"443"`
+	Duration     int64  `json:"duration" example:"3600"`
+	Heartbeat    bool   `json:"heartbeat"`
+	SessionBound bool   `json:"sessionBound"`
+	Description  string `json:"description" example:"Partner API tests"`
+}
+
+func (m *externalAccessMessage) validate() *commandError {
+	return firstError(
+		validateServiceRef("service", m.Service),
+		validateCIDR("cidr", m.Cidr),
+		validateAccessFields(m.Direction, m.Ports, m.Duration),
+	)
+}
+
+func (m *externalAccessMessage) payload() webSocketPayload {
+	return webSocketPayload{
+		Service: m.Service, Cidr: m.Cidr, Direction: m.Direction, Ports: m.Ports,
+		Duration: m.Duration, Heartbeat: m.Heartbeat, SessionBound: m.SessionBound, Description: m.Description,
+	}
+}
+
+// submitAccessRequestMessage is the submitAccessRequest command, for a service-to-service request with
+// sourceService and targetService, or an external one with service and cidr.
+type submitAccessRequestMessage struct {
+	messageHeader
+	SourceService string            `json:"sourceService" example:"frontend/web"`
+	TargetService string            `json:"targetService" example:"backend/api"`
+	Service       string            `json:"service"`
+	Cidr          string            `json:"cidr"`
+	Direction     string            `json:"direction" enums:"ingress,egress,all,both"`
+	Ports         string            `json:"ports"`
+	Duration      int64             `json:"duration" example:"3600"`
+	Description   string            `json:"description" example:"Debugging the checkout flow"`
+	Labels        map[string]string `json:"labels"`
+	OnBehalfOf    string            `json:"onBehalfOf"`
+	Priority      string            `json:"priority" enums:"low,normal,urgent"`
+	TicketRef     string            `json:"ticketRef" example:"NET-123"`
+	// PreviousRequestID resubmits a denied or expired request, by RequestID or name.
+	PreviousRequestID string `json:"previousRequestID"`
+}
+
+func (m *submitAccessRequestMessage) validate() *commandError {
+	var target *commandError
+	switch {
+	case m.Service != "" || m.Cidr != "":
+		if m.SourceService != "" || m.TargetService != "" {
+			return newCommandError(errCodeInvalidField, "sourceService", "external requests take service and cidr, not sourceService or targetService")
+		}
+		target = firstError(validateServiceRef("service", m.Service), validateCIDR("cidr", m.Cidr))
+	default:
+		target = firstError(validateServiceRef("sourceService", m.SourceService), validateServiceRef("targetService", m.TargetService))
+	}
+	if target != nil {
+		return target
+	}
+	if _, err := toRequestObjectLabels(m.Labels); err != nil {
+		return newCommandError(errCodeInvalidField, "labels", "%s", err)
+	}
+	return validateAccessFields(m.Direction, m.Ports, m.Duration)
+}
+
+func (m *submitAccessRequestMessage) payload() webSocketPayload {
+	return webSocketPayload{
+		SourceService: m.SourceService, TargetService: m.TargetService, Service: m.Service, Cidr: m.Cidr,
+		Direction: m.Direction, Ports: m.Ports, Duration: m.Duration, Description: m.Description, Labels: m.Labels,
+		OnBehalfOf: m.OnBehalfOf, Priority: m.Priority, TicketRef: m.TicketRef, PreviousRequestID: m.PreviousRequestID,
+	}
+}
+
+// requestIDMessage is a command on a single paused access: resumeAccess.
+type requestIDMessage struct {
+	messageHeader
+	RequestID string `json:"requestID" binding:"required"`
+}
+
+func (m *requestIDMessage) validate() *commandError { return requireField("requestID", m.RequestID) }
+
+func (m *requestIDMessage) payload() webSocketPayload {
+	return webSocketPayload{RequestID: m.RequestID}
+}
+
+// approveRequestMessage is the approveAccessRequest command. Direction, ports and duration narrow the request,
+// when set: a duration of 0 keeps the requested one.
+type approveRequestMessage struct {
+	messageHeader
+	RequestID string `json:"requestID" binding:"required"`
+	Comment   string `json:"comment" example:"Approved for the checkout incident"`
+	Direction string `json:"direction" enums:"ingress,egress,all,both"`
+	Ports     string `json:"ports" example:"443"`
+	Duration  int64  `json:"duration" example:"1800"`
+}
+
+func (m *approveRequestMessage) validate() *commandError {
+	if cmdErr := requireField("requestID", m.RequestID); cmdErr != nil {
+		return cmdErr
+	}
+	if _, err := normalizeDirection(m.Direction); err != nil {
+		return newCommandError(errCodeInvalidField, "direction", "%s", err)
+	}
+	if _, err := getOverridePorts(m.Ports); err != nil {
+		return newCommandError(errCodeInvalidField, "ports", "%s", err)
+	}
+	// The duration is checked against the maximum once applied, 0 keeps the requested one.
+	if m.Duration < 0 {
+		return newCommandError(errCodeInvalidField, "duration", "duration cannot be negative")
+	}
+	return nil
+}
+
+func (m *approveRequestMessage) payload() webSocketPayload {
+	return webSocketPayload{
+		RequestID: m.RequestID, Comment: m.Comment, Direction: m.Direction, Ports: m.Ports, Duration: m.Duration,
+	}
+}
+
+// updateRequestMessage is the updateAccessRequest command. A duration of 0 keeps the requested one.
+type updateRequestMessage struct {
+	messageHeader
+	RequestID   string `json:"requestID" binding:"required"`
+	Ports       string `json:"ports" example:"443,8443"`
+	Duration    int64  `json:"duration" example:"7200"`
+	Description string `json:"description" example:"Debugging the checkout flow, TLS ports too"`
+}
+
+func (m *updateRequestMessage) validate() *commandError {
+	if cmdErr := requireField("requestID", m.RequestID); cmdErr != nil {
+		return cmdErr
+	}
+	if _, err := getOverridePorts(m.Ports); err != nil {
+		return newCommandError(errCodeInvalidField, "ports", "%s", err)
+	}
+	// The duration is checked against the maximum once applied, 0 keeps the requested one.
+	if m.Duration < 0 {
+		return newCommandError(errCodeInvalidField, "duration", "duration cannot be negative")
+	}
+	return nil
+}
+
+func (m *updateRequestMessage) payload() webSocketPayload {
+	return webSocketPayload{RequestID: m.RequestID, Ports: m.Ports, Duration: m.Duration, Description: m.Description}
+}
+
+// denyRequestMessage is the denyAccessRequest command.
+type denyRequestMessage struct {
+	messageHeader
+	RequestID string `json:"requestID" binding:"required"`
+	Reason    string `json:"reason" example:"Use the staging database instead"`
+}
+
+func (m *denyRequestMessage) validate() *commandError {
+	return requireField("requestID", m.RequestID)
+}
+
+func (m *denyRequestMessage) payload() webSocketPayload {
+	return webSocketPayload{RequestID: m.RequestID, Reason: m.Reason}
+}
+
+// accessRefMessage is a command on a single Access or ExternalAccess: revokeClusterAccess and revokeExternalAccess.
+type accessRefMessage struct {
+	messageHeader
+	Namespace string `json:"namespace" binding:"required"`
+	Name      string `json:"name" binding:"required"`
+}
+
+func (m *accessRefMessage) validate() *commandError {
+	return firstError(requireField("namespace", m.Namespace), requireField("name", m.Name))
+}
+
+func (m *accessRefMessage) payload() webSocketPayload {
+	return webSocketPayload{Namespace: m.Namespace, Name: m.Name}
+}
+
+// pauseAccessMessage is the pauseAccess command. AccessType is "Service", the default, or "External".
+type pauseAccessMessage struct {
+	messageHeader
+	Namespace  string `json:"namespace" binding:"required"`
+	Name       string `json:"name" binding:"required"`
+	AccessType string `json:"accessType" enums:"Service,External"`
+}
+
+func (m *pauseAccessMessage) validate() *commandError {
+	if m.AccessType != "" && m.AccessType != "Service" && m.AccessType != "External" {
+		return newCommandError(errCodeInvalidField, "accessType", "expected 'Service' or 'External', got %q", m.AccessType)
+	}
+	return firstError(requireField("namespace", m.Namespace), requireField("name", m.Name))
+}
+
+func (m *pauseAccessMessage) payload() webSocketPayload {
+	return webSocketPayload{Namespace: m.Namespace, Name: m.Name, AccessType: m.AccessType}
+}
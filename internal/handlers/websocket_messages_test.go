@@ -0,0 +1,21 @@
+package handlers
+
+import "testing"
+
+func BenchmarkDecodeWebSocketMessage(b *testing.B) {
+	messages := map[string]string{
+		"submitAccessRequest": `{"command":"submitAccessRequest","version":1,"sourceService":"dev/api","targetService":"dev/db",` +
+			`"direction":"ingress","ports":"5432","duration":3600,"description":"Debugging the checkout flow"}`,
+		"approveAccessRequest": `{"command":"approveAccessRequest","version":1,"requestID":"request-1","comment":"ok"}`,
+	}
+	for name, message := range messages {
+		b.Run(name, func(b *testing.B) {
+			data := []byte(message)
+			for b.Loop() {
+				if _, cmdErr := decodeWebSocketMessage(data); cmdErr != nil {
+					b.Fatal(cmdErr)
+				}
+			}
+		})
+	}
+}
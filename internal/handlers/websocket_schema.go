@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JSONSchema is the subset of JSON Schema used to describe WebSocket messages.
+type JSONSchema struct {
+	Type        string                 `json:"type,omitempty" example:"object"`
+	Description string                 `json:"description,omitempty"`
+	Const       string                 `json:"const,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Examples    []any                  `json:"examples,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	// AdditionalProperties is false for messages, which refuse unknown fields, and the schema of the values for maps.
+	AdditionalProperties any `json:"additionalProperties,omitempty"`
+}
+
+// WebSocketSchema describes the messages of the /ws WebSocket.
+type WebSocketSchema struct {
+	Schema string `json:"$schema" example:"https://json-schema.org/draft/2020-12/schema"`
+	// SchemaVersion is the version of the command messages, also sent in the hello message.
+	SchemaVersion int `json:"schemaVersion" example:"1"`
+	// Commands maps each command to the schema of its message.
+	Commands map[string]*JSONSchema `json:"commands"`
+	// Events maps the messages the server sends to their schema: logEntry for everything but commandError.
+	Events map[string]*JSONSchema `json:"events"`
+}
+
+// webSocketSchema is built once from the typed messages, so it can't drift from what the server accepts.
+var webSocketSchema = sync.OnceValue(func() WebSocketSchema {
+	schema := WebSocketSchema{
+		Schema:        "https://json-schema.org/draft/2020-12/schema",
+		SchemaVersion: webSocketSchemaVersion,
+		Commands:      make(map[string]*JSONSchema, len(webSocketMessages)),
+		Events: map[string]*JSONSchema{
+			"logEntry":     schemaOf(reflect.TypeFor[LogEntry]()),
+			"commandError": schemaOf(reflect.TypeFor[commandError]()),
+		},
+	}
+	for name, command := range webSocketMessages {
+		message := schemaOf(reflect.TypeOf(command.newMessage()))
+		message.Description = command.description
+		message.Properties["command"].Const = name
+		message.AdditionalProperties = false
+		schema.Commands[name] = message
+	}
+	return schema
+})
+
+// schemaOf describes a Go type as it is encoded to JSON. Struct fields follow the tags of the REST inputs:
+// binding:"required", enums and example.
+func schemaOf(t reflect.Type) *JSONSchema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		schema := &JSONSchema{Type: "object", Properties: make(map[string]*JSONSchema)}
+		addFields(schema, t)
+		return schema
+	case reflect.Map:
+		return &JSONSchema{Type: "object", AdditionalProperties: schemaOf(t.Elem())}
+	case reflect.Slice, reflect.Array:
+		return &JSONSchema{Type: "array", Items: schemaOf(t.Elem())}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	default:
+		return &JSONSchema{}
+	}
+}
+
+// addFields adds the exported fields of a struct to an object schema, flattening embedded structs like
+// encoding/json does.
+func addFields(schema *JSONSchema, t reflect.Type) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			addFields(schema, field.Type)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		property := schemaOf(field.Type)
+		if enums := field.Tag.Get("enums"); enums != "" {
+			property.Enum = strings.Split(enums, ",")
+		}
+		if example, ok := field.Tag.Lookup("example"); ok {
+			property.Examples = []any{exampleValue(property.Type, example)}
+		}
+		schema.Properties[name] = property
+		if field.Tag.Get("binding") == "required" {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// exampleValue converts an example tag to the type of its field.
+func exampleValue(schemaType, example string) any {
+	switch schemaType {
+	case "integer":
+		if n, err := strconv.ParseInt(example, 10, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(example); err == nil {
+			return b
+		}
+	}
+	return example
+}
+
+// GetWebSocketSchema returns the JSON Schema of the WebSocket messages.
+// GetWebSocketSchema godoc
+// @Summary      Get the WebSocket message schema
+// @Description  Returns a JSON Schema of every command accepted on the /ws WebSocket and of the messages it sends back, for client generators. Commands are validated against it: a command that does not match is answered with a commandError message instead of running.
+// @Tags         System
+// @Produce      json
+// @Success      200  {object}  handlers.WebSocketSchema
+// @Failure      401  {object}  handlers.HTTPError
+// @Security     ApiKeyAuth
+// @Router       /schema [get]
+func GetWebSocketSchema(c *gin.Context) {
+	c.JSON(http.StatusOK, webSocketSchema())
+}
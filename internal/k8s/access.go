@@ -4,10 +4,14 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"maps"
+	"strconv"
+	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -45,9 +49,23 @@ func ListAllAccessesWithLabelAsApp(ctx context.Context, reqID string) (*vtkiov1a
 }
 
 func ListNetwatchAccesses(ctx context.Context, accessList *vtkiov1alpha1.AccessList) error {
-	labelSelector := labels.SelectorFromSet(map[string]string{"app.kubernetes.io/managed-by": "netwatch"})
-	listOptions := &client.ListOptions{LabelSelector: labelSelector}
-	return appKubeClient.List(ctx, accessList, listOptions)
+	return ListNetwatchAccessesMatching(ctx, accessList, "", nil)
+}
+
+// ListNetwatchAccessesMatching lists the Netwatch-managed Accesses of a namespace, or of all namespaces when it
+// is empty, that also carry every given label. The filtering is done by the API server.
+func ListNetwatchAccessesMatching(
+	ctx context.Context, accessList *vtkiov1alpha1.AccessList, namespace string, matchLabels map[string]string,
+) error {
+	return appKubeClient.List(ctx, accessList, netwatchListOptions(namespace, matchLabels))
+}
+
+// netwatchListOptions selects the Netwatch-managed objects of a namespace carrying the given labels.
+func netwatchListOptions(namespace string, matchLabels map[string]string) *client.ListOptions {
+	set := labels.Set{}
+	maps.Copy(set, matchLabels)
+	set["app.kubernetes.io/managed-by"] = "netwatch"
+	return &client.ListOptions{Namespace: namespace, LabelSelector: labels.SelectorFromSet(set)}
 }
 
 // GetAccessAsApp fetches an Access resource using the privileged application client.
@@ -79,3 +97,84 @@ func UpdateAccessWithRetry(
 		return k8sClient.Update(ctx, access)
 	})
 }
+
+// AutoExtensionsAnnotation counts how many times an access was automatically extended.
+const AutoExtensionsAnnotation = "netwatch.vtk.io/auto-extensions"
+
+// ExtendAccessAsApp adds extra time to an Access and returns the new number of automatic extensions.
+// maxtac computes the expiration timestamp only once, so it is cleared from the status to be recomputed from the new duration.
+func ExtendAccessAsApp(ctx context.Context, namespace, name string, extra time.Duration) (int, error) {
+	var count int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		access, err := GetAccessAsApp(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		duration, err := time.ParseDuration(access.Spec.Duration)
+		if err != nil {
+			return fmt.Errorf("access has no extendable duration: %w", err)
+		}
+		count = extendedCount(access.Annotations)
+		if access.Annotations == nil {
+			access.Annotations = map[string]string{}
+		}
+		access.Annotations[AutoExtensionsAnnotation] = strconv.Itoa(count)
+		access.Spec.Duration = fmt.Sprintf("%ds", int64((duration + extra).Seconds()))
+		return appKubeClient.Update(ctx, access)
+	})
+	if err != nil {
+		return 0, err
+	}
+	// Retried on its own: a conflict on the status must not extend the duration a second time.
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		access, err := GetAccessAsApp(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		access.Status.ExpirationTimestamp = nil
+		return appKubeClient.Status().Update(ctx, access)
+	})
+	return count, err
+}
+
+func extendedCount(annotations map[string]string) int {
+	count, _ := strconv.Atoi(annotations[AutoExtensionsAnnotation])
+	return count + 1
+}
+
+// PausedAnnotation marks an Access or ExternalAccess deleted by a pause. The cleanup controller keeps its service clones.
+const PausedAnnotation = "netwatch.vtk.io/paused"
+
+// MarkPausedAsApp annotates an Access or ExternalAccess as paused before it gets deleted.
+func MarkPausedAsApp(ctx context.Context, obj client.Object) error {
+	patch := []byte(fmt.Sprintf(`{"metadata":{"annotations":{%q:"true"}}}`, PausedAnnotation))
+	return appKubeClient.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch))
+}
+
+// RevokeRequestAsApp deletes every Access and ExternalAccess of a request with the application client.
+// The controller then removes their service clones. It returns how many objects were deleted.
+func RevokeRequestAsApp(ctx context.Context, reqID string) (int, error) {
+	selector := client.MatchingLabels{"netwatch.vtk.io/request-id": reqID, "app.kubernetes.io/managed-by": "netwatch"}
+	var accessList vtkiov1alpha1.AccessList
+	if err := appKubeClient.List(ctx, &accessList, selector); err != nil {
+		return 0, fmt.Errorf("failed to list accesses with app client: %w", err)
+	}
+	var extList vtkiov1alpha1.ExternalAccessList
+	if err := appKubeClient.List(ctx, &extList, selector); err != nil {
+		return 0, fmt.Errorf("failed to list external accesses with app client: %w", err)
+	}
+	deleted := 0
+	for i := range accessList.Items {
+		if err := appKubeClient.Delete(ctx, &accessList.Items[i]); err != nil && !IsNotFound(err) {
+			return deleted, err
+		}
+		deleted++
+	}
+	for i := range extList.Items {
+		if err := appKubeClient.Delete(ctx, &extList.Items[i]); err != nil && !IsNotFound(err) {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
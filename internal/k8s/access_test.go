@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// conflictOnFirstStatusUpdate fails the first status update with a conflict, as when the controller writes the
+// status at the same time.
+func conflictOnFirstStatusUpdate(t *testing.T, objs ...client.Object) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := vtkiov1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	conflicted := false
+	previous := appKubeClient
+	appKubeClient = fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(objs...).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				if !conflicted {
+					conflicted = true
+					return apierrors.NewConflict(schema.GroupResource{Resource: "accesses"}, obj.GetName(), nil)
+				}
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+	t.Cleanup(func() { appKubeClient = previous })
+}
+
+func TestExtendAccessAsAppExtendsOnceOnStatusConflict(t *testing.T) {
+	expiration := metav1.NewTime(time.Now().Add(time.Hour))
+	access := &vtkiov1alpha1.Access{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "api-to-db"},
+		Spec:       vtkiov1alpha1.AccessSpec{Duration: "3600s"},
+		Status:     vtkiov1alpha1.AccessStatus{ExpirationTimestamp: &expiration},
+	}
+	conflictOnFirstStatusUpdate(t, access)
+
+	count, err := ExtendAccessAsApp(context.Background(), "dev", "api-to-db", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtendAccessAsApp: %v", err)
+	}
+	got, err := GetAccessAsApp(context.Background(), "dev", "api-to-db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || got.Annotations[AutoExtensionsAnnotation] != "1" {
+		t.Errorf("got %d extensions, annotation %q, want 1", count, got.Annotations[AutoExtensionsAnnotation])
+	}
+	if got.Spec.Duration != "4500s" {
+		t.Errorf("got duration %s, want 4500s", got.Spec.Duration)
+	}
+	if got.Status.ExpirationTimestamp != nil {
+		t.Error("the expiration timestamp was not cleared")
+	}
+}
+
+func TestExtendExternalAccessAsAppExtendsOnceOnStatusConflict(t *testing.T) {
+	expiration := metav1.NewTime(time.Now().Add(time.Hour))
+	access := &vtkiov1alpha1.ExternalAccess{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "dev", Name: "api-to-vpn"},
+		Spec:       vtkiov1alpha1.ExternalAccessSpec{Duration: "3600s"},
+		Status:     vtkiov1alpha1.ExternalAccessStatus{ExpirationTimestamp: &expiration},
+	}
+	conflictOnFirstStatusUpdate(t, access)
+
+	count, err := ExtendExternalAccessAsApp(context.Background(), "dev", "api-to-vpn", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("ExtendExternalAccessAsApp: %v", err)
+	}
+	got, err := GetExternalAccessAsApp(context.Background(), "dev", "api-to-vpn")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 || got.Annotations[AutoExtensionsAnnotation] != "1" {
+		t.Errorf("got %d extensions, annotation %q, want 1", count, got.Annotations[AutoExtensionsAnnotation])
+	}
+	if got.Spec.Duration != "4500s" {
+		t.Errorf("got duration %s, want 4500s", got.Spec.Duration)
+	}
+	if got.Status.ExpirationTimestamp != nil {
+		t.Error("the expiration timestamp was not cleared")
+	}
+}
@@ -0,0 +1,30 @@
+// internal/k8s/accessdecision.go
+package k8s
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+func CreateAccessDecisionAsApp(ctx context.Context, decision *netwatchv1alpha1.AccessDecision) error {
+	return appKubeClient.Create(ctx, decision)
+}
+
+func ListAccessDecisionsAsApp(ctx context.Context, opts ...client.ListOption) (*netwatchv1alpha1.AccessDecisionList, error) {
+	var list netwatchv1alpha1.AccessDecisionList
+	if err := appKubeClient.List(ctx, &list, opts...); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func GetAccessDecisionAsApp(ctx context.Context, name string) (*netwatchv1alpha1.AccessDecision, error) {
+	var decision netwatchv1alpha1.AccessDecision
+	if err := appKubeClient.Get(ctx, client.ObjectKey{Name: name}, &decision); err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
@@ -14,9 +14,9 @@ func CreateAccessRequestAsApp(ctx context.Context, req *netwatchv1alpha1.AccessR
 	return appKubeClient.Create(ctx, req)
 }
 
-func ListAccessRequestsAsApp(ctx context.Context) (*netwatchv1alpha1.AccessRequestList, error) {
+func ListAccessRequestsAsApp(ctx context.Context, opts ...client.ListOption) (*netwatchv1alpha1.AccessRequestList, error) {
 	var requestList netwatchv1alpha1.AccessRequestList
-	if err := appKubeClient.List(ctx, &requestList); err != nil {
+	if err := appKubeClient.List(ctx, &requestList, opts...); err != nil {
 		return nil, err
 	}
 	return &requestList, nil
@@ -38,3 +38,7 @@ func DeleteAccessRequestAsApp(ctx context.Context, name string) error {
 	}
 	return appKubeClient.Delete(ctx, req)
 }
+
+func UpdateAccessRequestAsApp(ctx context.Context, req *netwatchv1alpha1.AccessRequest) error {
+	return appKubeClient.Update(ctx, req)
+}
@@ -0,0 +1,48 @@
+package k8s
+
+import (
+	"context"
+	"slices"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// AnnotationDecisionPolicy names the ValidatingAdmissionPolicy, and its binding, that only lets users set the approve
+// and deny annotations of AccessRequests to their own username.
+const AnnotationDecisionPolicy = "netwatch-annotation-decisions"
+
+// AnnotationDecisionPolicyEnforced reports whether the API server rejects approve and deny annotations that don't
+// name the user setting them. A missing policy or binding isn't an error.
+func AnnotationDecisionPolicyEnforced(ctx context.Context) (bool, error) {
+	var policy admissionregistrationv1.ValidatingAdmissionPolicy
+	if err := appKubeClient.Get(ctx, client.ObjectKey{Name: AnnotationDecisionPolicy}, &policy); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	var binding admissionregistrationv1.ValidatingAdmissionPolicyBinding
+	if err := appKubeClient.Get(ctx, client.ObjectKey{Name: AnnotationDecisionPolicy}, &binding); err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return enforcesPolicy(&policy, &binding), nil
+}
+
+// enforcesPolicy reports whether a binding denies the requests failing a policy, including when the policy can't be
+// evaluated.
+func enforcesPolicy(
+	policy *admissionregistrationv1.ValidatingAdmissionPolicy,
+	binding *admissionregistrationv1.ValidatingAdmissionPolicyBinding,
+) bool {
+	if binding.Spec.PolicyName != policy.Name || binding.Spec.ParamRef != nil || binding.Spec.MatchResources != nil {
+		return false
+	}
+	if policy.Spec.FailurePolicy != nil && *policy.Spec.FailurePolicy != admissionregistrationv1.Fail {
+		return false
+	}
+	return len(policy.Spec.Validations) > 0 && slices.Contains(binding.Spec.ValidationActions, admissionregistrationv1.Deny)
+}
@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"testing"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestEnforcesPolicy(t *testing.T) {
+	ignore := admissionregistrationv1.Ignore
+	type policyMutation = func(*admissionregistrationv1.ValidatingAdmissionPolicy)
+	type bindingMutation = func(*admissionregistrationv1.ValidatingAdmissionPolicyBinding)
+	policy := func(mutate policyMutation) *admissionregistrationv1.ValidatingAdmissionPolicy {
+		p := &admissionregistrationv1.ValidatingAdmissionPolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: AnnotationDecisionPolicy},
+			Spec: admissionregistrationv1.ValidatingAdmissionPolicySpec{
+				Validations: []admissionregistrationv1.Validation{{Expression: "true"}},
+			},
+		}
+		if mutate != nil {
+			mutate(p)
+		}
+		return p
+	}
+	binding := func(mutate bindingMutation) *admissionregistrationv1.ValidatingAdmissionPolicyBinding {
+		b := &admissionregistrationv1.ValidatingAdmissionPolicyBinding{
+			ObjectMeta: metav1.ObjectMeta{Name: AnnotationDecisionPolicy},
+			Spec: admissionregistrationv1.ValidatingAdmissionPolicyBindingSpec{
+				PolicyName:        AnnotationDecisionPolicy,
+				ValidationActions: []admissionregistrationv1.ValidationAction{admissionregistrationv1.Deny},
+			},
+		}
+		if mutate != nil {
+			mutate(b)
+		}
+		return b
+	}
+
+	tests := []struct {
+		name    string
+		policy  *admissionregistrationv1.ValidatingAdmissionPolicy
+		binding *admissionregistrationv1.ValidatingAdmissionPolicyBinding
+		want    bool
+	}{
+		{"bound with the Deny action", policy(nil), binding(nil), true},
+		{
+			name:   "bound to audit only",
+			policy: policy(nil),
+			binding: binding(func(b *admissionregistrationv1.ValidatingAdmissionPolicyBinding) {
+				b.Spec.ValidationActions = []admissionregistrationv1.ValidationAction{admissionregistrationv1.Audit}
+			}),
+		},
+		{
+			name:    "bound to another policy",
+			policy:  policy(nil),
+			binding: binding(func(b *admissionregistrationv1.ValidatingAdmissionPolicyBinding) { b.Spec.PolicyName = "other" }),
+		},
+		{
+			name:   "bound to a subset of the requests",
+			policy: policy(nil),
+			binding: binding(func(b *admissionregistrationv1.ValidatingAdmissionPolicyBinding) {
+				b.Spec.MatchResources = &admissionregistrationv1.MatchResources{}
+			}),
+		},
+		{
+			name:    "ignoring evaluation failures",
+			policy:  policy(func(p *admissionregistrationv1.ValidatingAdmissionPolicy) { p.Spec.FailurePolicy = &ignore }),
+			binding: binding(nil),
+		},
+		{
+			name:    "without validations",
+			policy:  policy(func(p *admissionregistrationv1.ValidatingAdmissionPolicy) { p.Spec.Validations = nil }),
+			binding: binding(nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := enforcesPolicy(tt.policy, tt.binding); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+// internal/k8s/autoapprovalrule.go
+package k8s
+
+import (
+	"context"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+func ListAutoApprovalRulesAsApp(ctx context.Context) (*netwatchv1alpha1.AutoApprovalRuleList, error) {
+	var list netwatchv1alpha1.AutoApprovalRuleList
+	if err := appKubeClient.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
@@ -4,11 +4,10 @@ package k8s
 import (
 	"context"
 	"fmt"
-	"os"
 	"strings"
+	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
-	"github.com/coreos/go-oidc/v3/oidc"
 	"golang.org/x/sync/errgroup"
 	authv1 "k8s.io/api/authorization/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -20,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
 
 	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+	"github.com/Banh-Canh/netwatch/internal/auth"
 	"github.com/Banh-Canh/netwatch/internal/utils/logger"
 )
 
@@ -27,14 +27,46 @@ var (
 	appKubeConfig *rest.Config
 	appScheme     *runtime.Scheme
 	appKubeClient client.Client
+
+	appClientLimits           ClientLimits
+	impersonationClientLimits ClientLimits
 )
 
+// ClientLimits configures the client-side rate limits and request timeout of a Kubernetes client.
+// Zero values keep the inherited settings.
+type ClientLimits struct {
+	QPS     float32
+	Burst   int
+	Timeout time.Duration
+}
+
+// Apply sets the non-zero limits on a rest config.
+func (l ClientLimits) Apply(cfg *rest.Config) {
+	if l.QPS > 0 {
+		cfg.QPS = l.QPS
+	}
+	if l.Burst > 0 {
+		cfg.Burst = l.Burst
+	}
+	if l.Timeout > 0 {
+		cfg.Timeout = l.Timeout
+	}
+}
+
+// SetClientLimits configures the application client and the user-impersonating clients. Impersonating clients
+// inherit the application limits they don't override. It must be called before InitKubeClient.
+func SetClientLimits(app, impersonation ClientLimits) {
+	appClientLimits = app
+	impersonationClientLimits = impersonation
+}
+
 // InitKubeClient initializes the application's primary Kubernetes client and registers all necessary schemes.
 func InitKubeClient() error {
 	cfg, err := config.GetConfig()
 	if err != nil {
 		return fmt.Errorf("could not get kubernetes config: %w", err)
 	}
+	appClientLimits.Apply(cfg)
 	appKubeConfig = cfg
 
 	s := runtime.NewScheme()
@@ -43,7 +75,7 @@ func InitKubeClient() error {
 	netwatchv1alpha1.AddToScheme(s) //nolint:all
 	appScheme = s
 
-	appKubeClient, err = client.New(appKubeConfig, client.Options{
+	appClient, err := client.New(appKubeConfig, client.Options{
 		Scheme: s,
 		Cache: &client.CacheOptions{
 			DisableFor: []client.Object{
@@ -57,6 +89,7 @@ func InitKubeClient() error {
 	if err != nil {
 		return fmt.Errorf("could not create application client: %w", err)
 	}
+	appKubeClient = newThrottleAwareClient(appClient)
 
 	logger.Logger.Info("Successfully initialized Kubernetes application client.")
 	return nil
@@ -66,6 +99,14 @@ func InitKubeClient() error {
 type UserInfo struct {
 	Email  string
 	Groups []string
+	// Claims holds every claim of the ID token, e.g. a directory-provided employee ID.
+	Claims map[string]any
+	// Provider names the identity provider that authenticated the user: "oidc", "serviceaccount", "certificate" or "automation".
+	// It is "annotation" for users deciding on a request through its annotations, authenticated by the admission
+	// policy of the annotations rather than by Netwatch.
+	Provider string
+	// Scope restricts what automation identities can request. It is nil for users.
+	Scope *auth.Scope
 }
 
 // GetAppKubeClient returns the pre-initialized, privileged application client.
@@ -75,22 +116,11 @@ func GetAppKubeClient() client.Client {
 
 // GetUserInfoFromToken verifies an OIDC token and extracts the user's email and groups.
 func GetUserInfoFromToken(ctx context.Context, idTokenString string) (*UserInfo, error) {
-	provider, err := oidc.NewProvider(ctx, os.Getenv("OIDC_ISSUER_URL"))
-	if err != nil {
-		return nil, fmt.Errorf("oidc provider failed: %w", err)
-	}
-	idToken, err := provider.Verifier(&oidc.Config{ClientID: os.Getenv("OIDC_CLIENT_ID")}).Verify(ctx, idTokenString)
+	identity, err := auth.Verify(ctx, idTokenString)
 	if err != nil {
-		return nil, fmt.Errorf("token verification failed: %w", err)
-	}
-	var claims struct {
-		Email  string   `json:"email"`
-		Groups []string `json:"groups"`
-	}
-	if err := idToken.Claims(&claims); err != nil {
-		return nil, fmt.Errorf("parsing claims failed: %w", err)
+		return nil, err
 	}
-	return &UserInfo{Email: claims.Email, Groups: claims.Groups}, nil
+	return &UserInfo{Email: identity.Email, Groups: identity.Groups, Claims: identity.Claims, Provider: identity.Provider, Scope: identity.Scope}, nil
 }
 
 // GetImpersonatingKubeClient creates a new Kubernetes client that acts on behalf of the user. So we don't need extra permission for the webapp itself.
@@ -99,19 +129,32 @@ func GetImpersonatingKubeClient(idTokenString string) (client.Client, error) {
 	if err != nil {
 		return nil, err
 	}
+	return GetImpersonatingKubeClientFor(userInfo)
+}
+
+// GetImpersonatingKubeClientFor creates a client acting on behalf of an identity known without its token, such as
+// the approver of a pre-approval. Callers must have checked that the identity actually granted the action.
+func GetImpersonatingKubeClientFor(userInfo *UserInfo) (client.Client, error) {
+	key := impersonationKey(userInfo)
+	if cached, ok := cachedImpersonatingClient(key); ok {
+		return cached, nil
+	}
 
 	impersonatingConfig := *appKubeConfig
 	impersonatingConfig.Impersonate = rest.ImpersonationConfig{
 		UserName: userInfo.Email,
 		Groups:   userInfo.Groups,
 	}
+	impersonationClientLimits.Apply(&impersonatingConfig)
 
 	impersonatingClient, err := client.New(&impersonatingConfig, client.Options{Scheme: appScheme})
 	if err != nil {
 		return nil, fmt.Errorf("could not create impersonating client: %w", err)
 	}
 
-	return impersonatingClient, nil
+	throttleAware := newThrottleAwareClient(impersonatingClient)
+	cacheImpersonatingClient(key, throttleAware)
+	return throttleAware, nil
 }
 
 // CanPerformAction uses a SubjectAccessReview to check if a user has permission for a single action.
@@ -174,5 +217,65 @@ func CanPerformAllActions(ctx context.Context, userInfo *UserInfo, perms []Permi
 	return true, nil
 }
 
+// PermissionResult is the outcome of a single permission check.
+type PermissionResult struct {
+	PermissionRequest
+	Allowed bool
+}
+
+// CheckAllActions checks a list of actions concurrently. Unlike CanPerformAllActions, it does not stop on the first
+// failure and reports every result, in the order of the list.
+func CheckAllActions(ctx context.Context, userInfo *UserInfo, perms []PermissionRequest) ([]PermissionResult, error) {
+	results := make([]PermissionResult, len(perms))
+	g, gCtx := errgroup.WithContext(ctx)
+	for i, perm := range perms {
+		g.Go(func() error {
+			allowed, err := CanPerformAction(gCtx, userInfo, perm.Verb, perm.Group, perm.Resource, perm.Namespace, "")
+			results[i] = PermissionResult{PermissionRequest: perm, Allowed: allowed}
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// allowedNamespacesConcurrency bounds the SubjectAccessReviews sent at once by AllowedNamespaces.
+const allowedNamespacesConcurrency = 10
+
+// AllowedNamespaces returns which of the given namespaces a user can perform an action in, one SubjectAccessReview
+// per namespace.
+func AllowedNamespaces(ctx context.Context, userInfo *UserInfo, verb, group, resource string, namespaces []string) (map[string]bool, error) {
+	allowed := make([]bool, len(namespaces))
+	g, gCtx := errgroup.WithContext(ctx)
+	g.SetLimit(allowedNamespacesConcurrency)
+	for i, namespace := range namespaces {
+		g.Go(func() error {
+			ok, err := CanPerformAction(gCtx, userInfo, verb, group, resource, namespace, "")
+			allowed[i] = ok
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(namespaces))
+	for i, namespace := range namespaces {
+		result[namespace] = allowed[i]
+	}
+	return result, nil
+}
+
 // IsNotFound is a helper function to check for 'NotFound' errors. It's put like this for easy access in other packages.
 func IsNotFound(err error) bool { return errors.IsNotFound(err) }
+
+// IsConflict is a helper function to check for 'Conflict' errors, raised by concurrent updates.
+func IsConflict(err error) bool { return errors.IsConflict(err) }
+
+// IsResourceExpired is a helper function to check for expired continue tokens.
+func IsResourceExpired(err error) bool { return errors.IsResourceExpired(err) }
+
+// IsAlreadyExists is a helper function to check for 'AlreadyExists' errors.
+func IsAlreadyExists(err error) bool { return errors.IsAlreadyExists(err) }
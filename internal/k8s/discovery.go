@@ -0,0 +1,39 @@
+// internal/k8s/discovery.go
+package k8s
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/client-go/discovery"
+)
+
+// ServedVersions returns, for each API group, the versions the cluster serves, preferred version first.
+// Groups the cluster doesn't know about, e.g. because a CRD isn't installed, map to an empty list.
+func ServedVersions(groups ...string) (map[string][]string, error) {
+	dc, err := discovery.NewDiscoveryClientForConfig(appKubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create discovery client: %w", err)
+	}
+	apiGroups, err := dc.ServerGroups()
+	if err != nil {
+		return nil, fmt.Errorf("could not discover API groups: %w", err)
+	}
+	served := make(map[string][]string, len(groups))
+	for _, group := range groups {
+		served[group] = []string{}
+	}
+	for _, g := range apiGroups.Groups {
+		if !slices.Contains(groups, g.Name) {
+			continue
+		}
+		versions := []string{g.PreferredVersion.Version}
+		for _, v := range g.Versions {
+			if v.Version != g.PreferredVersion.Version {
+				versions = append(versions, v.Version)
+			}
+		}
+		served[g.Name] = versions
+	}
+	return served, nil
+}
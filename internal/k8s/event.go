@@ -0,0 +1,18 @@
+// internal/k8s/event.go
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ListEventsAsApp lists the Events of a namespace with a given reason.
+func ListEventsAsApp(ctx context.Context, namespace, reason string) (*corev1.EventList, error) {
+	var list corev1.EventList
+	if err := appKubeClient.List(ctx, &list, client.InNamespace(namespace), client.MatchingFields{"reason": reason}); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
@@ -3,10 +3,13 @@ package k8s
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"time"
 
 	vtkiov1alpha1 "github.com/Banh-Canh/maxtac/api/v1alpha1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -20,7 +23,57 @@ func DeleteExternalAccess(ctx context.Context, k8sClient client.Client, namespac
 }
 
 func ListNetwatchExternalAccesses(ctx context.Context, accessList *vtkiov1alpha1.ExternalAccessList) error {
-	labelSelector := labels.SelectorFromSet(map[string]string{"app.kubernetes.io/managed-by": "netwatch"})
-	listOptions := &client.ListOptions{LabelSelector: labelSelector}
-	return appKubeClient.List(ctx, accessList, listOptions)
+	return ListNetwatchExternalAccessesMatching(ctx, accessList, "", nil)
+}
+
+// ListNetwatchExternalAccessesMatching lists the Netwatch-managed ExternalAccesses of a namespace, or of all
+// namespaces when it is empty, that also carry every given label. The filtering is done by the API server.
+func ListNetwatchExternalAccessesMatching(
+	ctx context.Context, accessList *vtkiov1alpha1.ExternalAccessList, namespace string, matchLabels map[string]string,
+) error {
+	return appKubeClient.List(ctx, accessList, netwatchListOptions(namespace, matchLabels))
+}
+
+// GetExternalAccessAsApp fetches an ExternalAccess resource using the privileged application client.
+func GetExternalAccessAsApp(ctx context.Context, namespace, name string) (*vtkiov1alpha1.ExternalAccess, error) {
+	access := &vtkiov1alpha1.ExternalAccess{}
+	if err := appKubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, access); err != nil {
+		return nil, err
+	}
+	return access, nil
+}
+
+// ExtendExternalAccessAsApp is the ExternalAccess counterpart of ExtendAccessAsApp.
+func ExtendExternalAccessAsApp(ctx context.Context, namespace, name string, extra time.Duration) (int, error) {
+	var count int
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		access, err := GetExternalAccessAsApp(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		duration, err := time.ParseDuration(access.Spec.Duration)
+		if err != nil {
+			return fmt.Errorf("external access has no extendable duration: %w", err)
+		}
+		count = extendedCount(access.Annotations)
+		if access.Annotations == nil {
+			access.Annotations = map[string]string{}
+		}
+		access.Annotations[AutoExtensionsAnnotation] = strconv.Itoa(count)
+		access.Spec.Duration = fmt.Sprintf("%ds", int64((duration + extra).Seconds()))
+		return appKubeClient.Update(ctx, access)
+	})
+	if err != nil {
+		return 0, err
+	}
+	// Retried on its own: a conflict on the status must not extend the duration a second time.
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		access, err := GetExternalAccessAsApp(ctx, namespace, name)
+		if err != nil {
+			return err
+		}
+		access.Status.ExpirationTimestamp = nil
+		return appKubeClient.Status().Update(ctx, access)
+	})
+	return count, err
 }
@@ -0,0 +1,67 @@
+package k8s
+
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// impersonatingClientTTL bounds how long the client of an identity is reused, so the cache only holds the
+// identities active recently.
+const impersonatingClientTTL = 10 * time.Minute
+
+type cachedClient struct {
+	client  client.Client
+	expires time.Time
+}
+
+var (
+	impersonatingClientsMu sync.Mutex
+	impersonatingClients   = make(map[string]cachedClient)
+)
+
+// impersonationKey identifies the impersonation config of an identity, whatever the order of its groups.
+func impersonationKey(userInfo *UserInfo) string {
+	groups := slices.Sorted(slices.Values(userInfo.Groups))
+	return userInfo.Email + "\x00" + strings.Join(groups, "\x00")
+}
+
+// cachedImpersonatingClient returns the client built for an identity, unless it expired.
+func cachedImpersonatingClient(key string) (client.Client, bool) {
+	impersonatingClientsMu.Lock()
+	defer impersonatingClientsMu.Unlock()
+	cached, ok := impersonatingClients[key]
+	if !ok || time.Now().After(cached.expires) {
+		return nil, false
+	}
+	return cached.client, true
+}
+
+// cacheImpersonatingClient keeps the client built for an identity, dropping the expired ones.
+func cacheImpersonatingClient(key string, c client.Client) {
+	impersonatingClientsMu.Lock()
+	defer impersonatingClientsMu.Unlock()
+	pruneImpersonatingClients()
+	impersonatingClients[key] = cachedClient{client: c, expires: time.Now().Add(impersonatingClientTTL)}
+}
+
+// pruneImpersonatingClients drops the expired clients. Callers hold impersonatingClientsMu.
+func pruneImpersonatingClients() {
+	now := time.Now()
+	for key, cached := range impersonatingClients {
+		if now.After(cached.expires) {
+			delete(impersonatingClients, key)
+		}
+	}
+}
+
+// ImpersonatingClientCacheSize returns the number of impersonating clients currently cached.
+func ImpersonatingClientCacheSize() int {
+	impersonatingClientsMu.Lock()
+	defer impersonatingClientsMu.Unlock()
+	pruneImpersonatingClients()
+	return len(impersonatingClients)
+}
@@ -0,0 +1,18 @@
+// internal/k8s/namespace.go
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ListNamespaces lists the namespaces of the cluster. Options narrow it down, e.g. with a label selector.
+func ListNamespaces(ctx context.Context, opts ...client.ListOption) (*corev1.NamespaceList, error) {
+	var namespaceList corev1.NamespaceList
+	if err := appKubeClient.List(ctx, &namespaceList, opts...); err != nil {
+		return nil, err
+	}
+	return &namespaceList, nil
+}
@@ -0,0 +1,18 @@
+// internal/k8s/networkpolicy.go
+package k8s
+
+import (
+	"context"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// GetNetworkPolicyAsApp fetches a NetworkPolicy rendered by maxtac using the privileged application client.
+func GetNetworkPolicyAsApp(ctx context.Context, namespace, name string) (*networkingv1.NetworkPolicy, error) {
+	var netpol networkingv1.NetworkPolicy
+	if err := appKubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &netpol); err != nil {
+		return nil, err
+	}
+	return &netpol, nil
+}
@@ -0,0 +1,26 @@
+// internal/k8s/preapproval.go
+package k8s
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	netwatchv1alpha1 "github.com/Banh-Canh/netwatch/api/v1alpha1"
+)
+
+func CreatePreApprovalAsApp(ctx context.Context, preApproval *netwatchv1alpha1.PreApproval) error {
+	return appKubeClient.Create(ctx, preApproval)
+}
+
+func ListPreApprovalsAsApp(ctx context.Context) (*netwatchv1alpha1.PreApprovalList, error) {
+	var list netwatchv1alpha1.PreApprovalList
+	if err := appKubeClient.List(ctx, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+func DeletePreApprovalAsApp(ctx context.Context, name string) error {
+	return appKubeClient.Delete(ctx, &netwatchv1alpha1.PreApproval{ObjectMeta: metav1.ObjectMeta{Name: name}})
+}
@@ -12,20 +12,47 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func ListAllServices(ctx context.Context) (*corev1.ServiceList, error) {
+// ListAllServices lists the services of the cluster. Options narrow it down, e.g. to a namespace, or paginate it
+// with client.Limit and client.Continue.
+func ListAllServices(ctx context.Context, opts ...client.ListOption) (*corev1.ServiceList, error) {
 	var serviceList corev1.ServiceList
-	if err := appKubeClient.List(ctx, &serviceList); err != nil {
+	if err := appKubeClient.List(ctx, &serviceList, opts...); err != nil {
 		return nil, err
 	}
 	return &serviceList, nil
 }
 
+func GetServiceAsApp(ctx context.Context, namespace, name string) (*corev1.Service, error) {
+	var service corev1.Service
+	if err := appKubeClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &service); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
 func CloneService(
 	ctx context.Context,
 	k8sClient client.Client,
 	originalNamespace, originalName, newName string,
 	uniqueLabel map[string]string,
 	overridePorts []corev1.ServicePort,
+) (*corev1.Service, error) {
+	clonedService, err := BuildServiceClone(ctx, originalNamespace, originalName, newName, uniqueLabel, overridePorts)
+	if err != nil {
+		return nil, err
+	}
+	if err := CreateService(ctx, k8sClient, clonedService); err != nil {
+		return nil, err
+	}
+	return clonedService, nil
+}
+
+// BuildServiceClone returns the clone of a service that CloneService would create, without creating it.
+func BuildServiceClone(
+	ctx context.Context,
+	originalNamespace, originalName, newName string,
+	uniqueLabel map[string]string,
+	overridePorts []corev1.ServicePort,
 ) (*corev1.Service, error) {
 	var originalService corev1.Service
 	if err := appKubeClient.Get(ctx, client.ObjectKey{Namespace: originalNamespace, Name: originalName}, &originalService); err != nil {
@@ -65,13 +92,17 @@ func CloneService(
 		clonedService.Spec.ClusterIPs = nil
 	}
 
-	if err := k8sClient.Create(ctx, clonedService); err != nil {
-		return nil, fmt.Errorf("could not create service clone %s/%s: %w", clonedService.Namespace, clonedService.Name, err)
-	}
-
 	return clonedService, nil
 }
 
+// CreateService creates a service clone built by BuildServiceClone.
+func CreateService(ctx context.Context, k8sClient client.Client, service *corev1.Service) error {
+	if err := k8sClient.Create(ctx, service); err != nil {
+		return fmt.Errorf("could not create service clone %s/%s: %w", service.Namespace, service.Name, err)
+	}
+	return nil
+}
+
 func DeleteService(ctx context.Context, k8sClient client.Client, namespace, name string) error {
 	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
 	return k8sClient.Delete(ctx, svc)
@@ -0,0 +1,105 @@
+// internal/k8s/throttle.go
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// throttleMaxRetries bounds how many times a throttled call is retried on top of client-go's own retries.
+	throttleMaxRetries = 3
+	// throttleDefaultDelay is used when the apiserver does not send a Retry-After header.
+	throttleDefaultDelay = 2 * time.Second
+	throttleMaxDelay     = 30 * time.Second
+)
+
+type throttleNotifierKey struct{}
+
+// WithThrottleNotifier returns a context whose Kubernetes calls report apiserver throttling to notify,
+// so long-running user operations can tell the user why they are slow.
+func WithThrottleNotifier(ctx context.Context, notify func(delay time.Duration)) context.Context {
+	return context.WithValue(ctx, throttleNotifierKey{}, notify)
+}
+
+// IsTooManyRequests reports whether the apiserver rejected a call because of priority-and-fairness throttling.
+func IsTooManyRequests(err error) bool {
+	return errors.IsTooManyRequests(err)
+}
+
+// retryOnThrottle runs fn, waiting and retrying while the apiserver answers 429 Too Many Requests.
+func retryOnThrottle(ctx context.Context, fn func() error) error {
+	err := fn()
+	for attempt := 1; attempt <= throttleMaxRetries && IsTooManyRequests(err); attempt++ {
+		delay := throttleDefaultDelay * time.Duration(attempt)
+		if seconds, ok := errors.SuggestsClientDelay(err); ok && seconds > 0 {
+			delay = time.Duration(seconds) * time.Second
+		}
+		delay = min(delay, throttleMaxDelay)
+		logger.Logger.Warn("Kubernetes API is throttling requests, retrying", "delay", delay, "attempt", attempt)
+		if notify, ok := ctx.Value(throttleNotifierKey{}).(func(time.Duration)); ok {
+			notify(delay)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		err = fn()
+	}
+	return err
+}
+
+// throttleAwareClient retries every call rejected by the apiserver's priority-and-fairness throttling.
+type throttleAwareClient struct {
+	client.Client
+}
+
+func newThrottleAwareClient(c client.Client) client.Client {
+	return &throttleAwareClient{Client: c}
+}
+
+func (c *throttleAwareClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	return retryOnThrottle(ctx, func() error { return c.Client.Get(ctx, key, obj, opts...) })
+}
+
+func (c *throttleAwareClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	return retryOnThrottle(ctx, func() error { return c.Client.List(ctx, list, opts...) })
+}
+
+func (c *throttleAwareClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	return retryOnThrottle(ctx, func() error { return c.Client.Create(ctx, obj, opts...) })
+}
+
+func (c *throttleAwareClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	return retryOnThrottle(ctx, func() error { return c.Client.Delete(ctx, obj, opts...) })
+}
+
+func (c *throttleAwareClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	return retryOnThrottle(ctx, func() error { return c.Client.Update(ctx, obj, opts...) })
+}
+
+func (c *throttleAwareClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	return retryOnThrottle(ctx, func() error { return c.Client.Patch(ctx, obj, patch, opts...) })
+}
+
+func (c *throttleAwareClient) Status() client.SubResourceWriter {
+	return &throttleAwareStatusWriter{SubResourceWriter: c.Client.Status()}
+}
+
+type throttleAwareStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (w *throttleAwareStatusWriter) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	return retryOnThrottle(ctx, func() error { return w.SubResourceWriter.Update(ctx, obj, opts...) })
+}
+
+func (w *throttleAwareStatusWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	return retryOnThrottle(ctx, func() error { return w.SubResourceWriter.Patch(ctx, obj, patch, opts...) })
+}
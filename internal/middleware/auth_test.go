@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+func TestAuthMiddlewareChecksTheStaticAPIKey(t *testing.T) {
+	logger.InitializeLogger(slog.LevelError)
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		configuredKey string
+		header        string
+		want          int
+	}{
+		{"matching key", "secret", "ApiKey secret", http.StatusOK},
+		{"wrong key", "secret", "ApiKey guess", http.StatusUnauthorized},
+		{"empty key without a configured key", "", "ApiKey ", http.StatusUnauthorized},
+		{"any key without a configured key", "", "ApiKey guess", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/api/ping", AuthMiddleware(tt.configuredKey), func(c *gin.Context) {
+				c.String(http.StatusOK, c.GetString("user"))
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/ping", nil)
+			req.Header.Set("Authorization", tt.header)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("got status %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
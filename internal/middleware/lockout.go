@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	authFailuresKeyPrefix = "netwatch:auth_failures:"
+	authBanKeyPrefix      = "netwatch:auth_ban:"
+	authBanCountKeyPrefix = "netwatch:auth_ban_count:"
+	// authBanCountTTL is how long past bans keep making new ones longer.
+	authBanCountTTL = 24 * time.Hour
+)
+
+// LockoutConfig configures the temporary bans of clients failing authentication.
+type LockoutConfig struct {
+	// MaxFailures failed attempts within Window get the client banned.
+	MaxFailures int
+	Window      time.Duration
+	// BaseBan is the first ban. Each further ban within a day doubles, up to MaxBan.
+	BaseBan time.Duration
+	MaxBan  time.Duration
+}
+
+// LockoutAuditor records a ban, e.g. in the activity log.
+type LockoutAuditor func(ip string, failures int64, ban time.Duration)
+
+var (
+	lockoutClient  *redis.Client
+	lockoutConfig  LockoutConfig
+	lockoutAuditor LockoutAuditor
+
+	authFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "netwatch_auth_failures_total",
+		Help: "Number of rejected Bearer, ApiKey and client certificate credentials.",
+	}, []string{"type"})
+	authLockoutsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "netwatch_auth_lockouts_total",
+		Help: "Number of clients temporarily banned after repeated authentication failures.",
+	})
+	authLockedOutRequestsTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "netwatch_auth_locked_out_requests_total",
+		Help: "Number of requests rejected because their client is banned.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(authFailuresTotal, authLockoutsTotal, authLockedOutRequestsTotal)
+}
+
+// EnableLockout turns on brute-force protection. Failures are tracked per client IP in Redis, so bans are
+// shared by every replica. It must be called before the router starts serving.
+func EnableLockout(client *redis.Client, cfg LockoutConfig) {
+	lockoutClient = client
+	lockoutConfig = cfg
+}
+
+// SetLockoutAuditor sets the function called whenever a client gets banned.
+func SetLockoutAuditor(auditor LockoutAuditor) {
+	lockoutAuditor = auditor
+}
+
+// lockedOut returns how long the client stays banned, if it is.
+func lockedOut(ctx context.Context, ip string) (time.Duration, bool) {
+	if lockoutClient == nil {
+		return 0, false
+	}
+	ttl, err := lockoutClient.PTTL(ctx, authBanKeyPrefix+ip).Result()
+	if err != nil {
+		// Fail open: an unavailable Redis must not lock everyone out.
+		logger.Logger.Warn("Failed to check authentication ban", "ip", ip, "error", err)
+		return 0, false
+	}
+	return ttl, ttl > 0
+}
+
+// recordAuthFailure counts a failed attempt and bans the client once it reaches the threshold.
+func recordAuthFailure(ctx context.Context, ip, credentialType string) {
+	authFailuresTotal.WithLabelValues(credentialType).Inc()
+	if lockoutClient == nil {
+		return
+	}
+	failures, err := lockoutClient.Incr(ctx, authFailuresKeyPrefix+ip).Result()
+	if err != nil {
+		logger.Logger.Warn("Failed to record authentication failure", "ip", ip, "error", err)
+		return
+	}
+	if failures == 1 {
+		// The window starts with the first failure.
+		lockoutClient.Expire(ctx, authFailuresKeyPrefix+ip, lockoutConfig.Window) //nolint:all
+	}
+	if failures < int64(lockoutConfig.MaxFailures) {
+		return
+	}
+
+	pipe := lockoutClient.TxPipeline()
+	bans := pipe.Incr(ctx, authBanCountKeyPrefix+ip)
+	pipe.Expire(ctx, authBanCountKeyPrefix+ip, authBanCountTTL)
+	pipe.Del(ctx, authFailuresKeyPrefix+ip)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Logger.Warn("Failed to record authentication ban", "ip", ip, "error", err)
+		return
+	}
+	ban := lockoutConfig.BaseBan
+	for i := int64(1); i < bans.Val() && ban < lockoutConfig.MaxBan; i++ {
+		ban *= 2
+	}
+	ban = min(ban, lockoutConfig.MaxBan)
+	if err := lockoutClient.Set(ctx, authBanKeyPrefix+ip, bans.Val(), ban).Err(); err != nil {
+		logger.Logger.Warn("Failed to ban client", "ip", ip, "error", err)
+		return
+	}
+	authLockoutsTotal.Inc()
+	logger.Logger.Warn("Client banned after repeated authentication failures", "ip", ip, "failures", failures, "ban", ban)
+	if lockoutAuditor != nil {
+		lockoutAuditor(ip, failures, ban)
+	}
+}
+
+// rejectLockedOut aborts the request if its client is banned.
+func rejectLockedOut(c *gin.Context) bool {
+	ttl, banned := lockedOut(c.Request.Context(), c.ClientIP())
+	if !banned {
+		return false
+	}
+	authLockedOutRequestsTotal.Inc()
+	c.Header("Retry-After", strconv.Itoa(int((ttl+time.Second-1)/time.Second)))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many failed authentication attempts, try again later"})
+	return true
+}
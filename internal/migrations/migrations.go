@@ -0,0 +1,69 @@
+// Package migrations runs the versioned upgrade steps netwatch needs when its naming schemes, labels or stored
+// payloads change, so upgrades don't require manual changes in the cluster.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Banh-Canh/netwatch/internal/utils/logger"
+)
+
+const (
+	// appliedKey is a Redis hash of applied migration versions to the time they were applied.
+	appliedKey = "netwatch:migrations"
+	lockKey    = "netwatch:migrations:lock"
+	lockTTL    = 5 * time.Minute
+
+	// SchemaVersionAnnotation records on each AccessRequest the last migration applied to it.
+	SchemaVersionAnnotation = "netwatch.vtk.io/schema-version"
+)
+
+// Migration is a single upgrade step. Steps must be idempotent, a failed run is retried at the next startup.
+type Migration struct {
+	Version int
+	Name    string
+	Run     func(ctx context.Context) error
+}
+
+// migrations lists every step in order. New steps are appended with the next version, never reordered.
+var migrations = []Migration{
+	{Version: 1, Name: "backfill-request-display-names", Run: backfillRequestDisplayNames},
+}
+
+// Run applies the pending migrations. A Redis lock makes sure only one replica migrates at a time,
+// the others skip and rely on it.
+func Run(ctx context.Context, redisClient *redis.Client) error {
+	acquired, err := redisClient.SetNX(ctx, lockKey, time.Now().Unix(), lockTTL).Result()
+	if err != nil {
+		return fmt.Errorf("could not acquire migration lock: %w", err)
+	}
+	if !acquired {
+		logger.Logger.Info("Another replica is running migrations, skipping.")
+		return nil
+	}
+	defer redisClient.Del(context.Background(), lockKey) //nolint:all
+
+	applied, err := redisClient.HGetAll(ctx, appliedKey).Result()
+	if err != nil {
+		return fmt.Errorf("could not read applied migrations: %w", err)
+	}
+	for _, m := range migrations {
+		if _, done := applied[strconv.Itoa(m.Version)]; done {
+			continue
+		}
+		logger.Logger.Info("Running migration", "version", m.Version, "name", m.Name)
+		if err := m.Run(ctx); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		if err := redisClient.HSet(ctx, appliedKey, strconv.Itoa(m.Version), time.Now().Unix()).Err(); err != nil {
+			return fmt.Errorf("could not record migration %d: %w", m.Version, err)
+		}
+		logger.Logger.Info("Migration applied", "version", m.Version, "name", m.Name)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package migrations
+
+import (
+	"context"
+	"strconv"
+
+	"k8s.io/client-go/util/retry"
+
+	"github.com/Banh-Canh/netwatch/internal/k8s"
+)
+
+// displayNameAnnotation mirrors the annotation set by the handlers on requests with generated names.
+const displayNameAnnotation = "netwatch.vtk.io/display-name"
+
+// backfillRequestDisplayNames gives requests created before display names existed their current name as display name,
+// so they keep the same name in the UI once generated names are enabled.
+func backfillRequestDisplayNames(ctx context.Context) error {
+	requests, err := k8s.ListAccessRequestsAsApp(ctx)
+	if err != nil {
+		return err
+	}
+	for _, item := range requests.Items {
+		if item.Annotations[displayNameAnnotation] != "" {
+			continue
+		}
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			request, err := k8s.GetAccessRequestAsApp(ctx, item.Name)
+			if err != nil {
+				return err
+			}
+			if request.Annotations == nil {
+				request.Annotations = map[string]string{}
+			}
+			request.Annotations[displayNameAnnotation] = request.Name
+			request.Annotations[SchemaVersionAnnotation] = strconv.Itoa(1)
+			return k8s.UpdateAccessRequestAsApp(ctx, request)
+		})
+		if err != nil && !k8s.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
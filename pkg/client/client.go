@@ -0,0 +1,347 @@
+// Package client calls the REST API of a running Netwatch server, so tools can integrate with Netwatch without
+// reimplementing its payloads.
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// continueHeader carries the token of the next page of a paginated list.
+const continueHeader = "X-Netwatch-Continue"
+
+// Client calls the REST API of a Netwatch server.
+type Client struct {
+	server     string
+	authHeader string
+	httpClient *http.Client
+}
+
+// New returns a client of the server at the given base URL. It authenticates with an OIDC ID token when given, or
+// with the static API key otherwise. Approving and denying requests needs a token, as the API key has no user.
+func New(server, token, apiKey string, timeout time.Duration) (*Client, error) {
+	c := &Client{server: strings.TrimRight(server, "/"), httpClient: &http.Client{Timeout: timeout}}
+	switch {
+	case token != "":
+		c.authHeader = "Bearer " + token
+	case apiKey != "":
+		c.authHeader = "ApiKey " + apiKey
+	default:
+		return nil, fmt.Errorf("an OIDC token or an API key is required")
+	}
+	return c, nil
+}
+
+// StatusError is returned when the server answers with an error status.
+type StatusError struct {
+	StatusCode int
+	// Message is the error the server gave, if any.
+	Message string
+	Body    []byte
+}
+
+func (e *StatusError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("server answered %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("server answered %d", e.StatusCode)
+}
+
+// ListServices lists the services of a namespace, or of every namespace when empty, matching the label selector.
+func (c *Client) ListServices(ctx context.Context, namespace, labelSelector string) ([]ServiceInfo, error) {
+	query := url.Values{}
+	if namespace != "" {
+		query.Set("namespace", namespace)
+	}
+	if labelSelector != "" {
+		query.Set("labelSelector", labelSelector)
+	}
+	var services []ServiceInfo
+	for {
+		var page []ServiceInfo
+		header, err := c.do(ctx, http.MethodGet, withQuery("/services", query), "", nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, page...)
+		next := header.Get(continueHeader)
+		if next == "" {
+			return services, nil
+		}
+		query.Set("continue", next)
+	}
+}
+
+// ListNamespaces lists the namespaces accesses can be requested in.
+func (c *Client) ListNamespaces(ctx context.Context) ([]NamespaceInfo, error) {
+	var namespaces []NamespaceInfo
+	_, err := c.do(ctx, http.MethodGet, "/namespaces", "", nil, &namespaces)
+	return namespaces, err
+}
+
+// ListActiveAccesses lists the accesses managed by Netwatch matching the filter.
+func (c *Client) ListActiveAccesses(ctx context.Context, filter ActiveAccessFilter) ([]ActiveAccessInfo, error) {
+	query := url.Values{}
+	for key, value := range map[string]string{
+		"user": filter.User, "namespace": filter.Namespace, "type": filter.Type, "status": filter.Status,
+	} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+	var accesses []ActiveAccessInfo
+	_, err := c.do(ctx, http.MethodGet, withQuery("/active-accesses", query), "", nil, &accesses)
+	return accesses, err
+}
+
+// GetAccess returns an Access with its pair, service clones and provenance.
+func (c *Client) GetAccess(ctx context.Context, namespace, name string) (*AccessDetail, error) {
+	var detail AccessDetail
+	if _, err := c.do(ctx, http.MethodGet, objectPath("/accesses", namespace, name), "", nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// ListPending lists the pending access requests, only those carrying every key=value label given.
+func (c *Client) ListPending(ctx context.Context, labels ...string) ([]AccessRequestPayload, error) {
+	query := url.Values{"label": labels}
+	var requests []AccessRequestPayload
+	_, err := c.do(ctx, http.MethodGet, withQuery("/pending-requests", query), "", nil, &requests)
+	return requests, err
+}
+
+// PreviewAccess returns the manifests creating an access would apply, without applying them.
+func (c *Client) PreviewAccess(ctx context.Context, input PreviewAccessInput) (*AccessPreview, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var preview AccessPreview
+	if _, err := c.do(ctx, http.MethodPost, "/accesses/preview", "application/json", bytes.NewReader(body), &preview); err != nil {
+		return nil, err
+	}
+	return &preview, nil
+}
+
+// CreateAccess creates a service-to-service access directly, without review.
+func (c *Client) CreateAccess(ctx context.Context, input CreateAccessInput) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPost, "/accesses", input)
+}
+
+// CreateExternalAccess creates an access between a service and an external CIDR directly, without review.
+func (c *Client) CreateExternalAccess(ctx context.Context, input CreateExternalAccessInput) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPost, "/external-accesses", input)
+}
+
+// RevokeAccess revokes an Access, and the other side of its service access.
+func (c *Client) RevokeAccess(ctx context.Context, namespace, name string) (*CommandResult, error) {
+	return c.command(ctx, http.MethodDelete, objectPath("/accesses", namespace, name), "", nil)
+}
+
+// RevokeExternalAccess revokes an ExternalAccess.
+func (c *Client) RevokeExternalAccess(ctx context.Context, namespace, name string) (*CommandResult, error) {
+	return c.command(ctx, http.MethodDelete, objectPath("/external-accesses", namespace, name), "", nil)
+}
+
+// RevokeBatch revokes the accesses of several requests at once.
+func (c *Client) RevokeBatch(ctx context.Context, input RevokeBatchInput) (*RevokeBatchResult, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	var result RevokeBatchResult
+	if _, err := c.do(ctx, http.MethodPost, "/accesses/revoke-batch", "application/json", bytes.NewReader(body), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SubmitRequest submits an access request for review.
+func (c *Client) SubmitRequest(ctx context.Context, input SubmitAccessRequestInput) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPost, "/access-requests", input)
+}
+
+// ImportRequest submits an AccessRequest manifest, YAML or JSON, for review. With onBehalfOf, the request is filed
+// for that user.
+func (c *Client) ImportRequest(ctx context.Context, manifest []byte, onBehalfOf string) (*CommandResult, error) {
+	path := "/access-requests/import"
+	if onBehalfOf != "" {
+		path += "?onBehalfOf=" + url.QueryEscape(onBehalfOf)
+	}
+	return c.command(ctx, http.MethodPost, path, "application/yaml", bytes.NewReader(manifest))
+}
+
+// Approve approves a pending access request, or the side of a partial request still waiting.
+func (c *Client) Approve(ctx context.Context, request string) (*CommandResult, error) {
+	return c.command(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(request)+"/approve", "", nil)
+}
+
+// ApproveWithComment approves a pending access request, keeping comment in the activity log and on the accesses.
+func (c *Client) ApproveWithComment(ctx context.Context, request, comment string) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(request)+"/approve",
+		ApproveAccessRequestInput{Comment: comment})
+}
+
+// ApproveWithChanges approves a pending access request after narrowing it with the direction, ports or duration
+// of input.
+func (c *Client) ApproveWithChanges(ctx context.Context, request string, input ApproveAccessRequestInput) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(request)+"/approve", input)
+}
+
+// UpdateRequest changes the ports, duration or description of one of the user's pending requests, until someone
+// approves it.
+func (c *Client) UpdateRequest(ctx context.Context, request string, input UpdateAccessRequestInput) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPatch, "/pending-requests/"+url.PathEscape(request), input)
+}
+
+// Deny denies a pending access request, or aborts it when called by its requestor.
+func (c *Client) Deny(ctx context.Context, request string) (*CommandResult, error) {
+	return c.command(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(request)+"/deny", "", nil)
+}
+
+// DenyWithReason denies a pending access request, or aborts it, telling the requestor why.
+func (c *Client) DenyWithReason(ctx context.Context, request, reason string) (*CommandResult, error) {
+	return c.commandJSON(ctx, http.MethodPost, "/pending-requests/"+url.PathEscape(request)+"/deny",
+		DenyAccessRequestInput{Reason: reason})
+}
+
+// ExposureReport returns a signed snapshot of every network path currently open.
+func (c *Client) ExposureReport(ctx context.Context) (*SignedExposureReport, error) {
+	var report SignedExposureReport
+	if _, err := c.do(ctx, http.MethodGet, "/reports/exposure", "", nil, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// StreamLogs follows the activity log. The channel gets every entry written after the call and is closed when the
+// stream ends, e.g. when ctx is done or the server restarts.
+func (c *Client) StreamLogs(ctx context.Context) (<-chan LogEntry, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, "/logs/stream", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	// The stream lasts as long as ctx, the timeout of the other calls does not apply.
+	resp, err := (&http.Client{Transport: c.httpClient.Transport}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resp); err != nil {
+		resp.Body.Close() //nolint:all
+		return nil, err
+	}
+
+	entries := make(chan LogEntry)
+	go func() {
+		defer close(entries)
+		defer resp.Body.Close() //nolint:all
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				continue
+			}
+			var entry LogEntry
+			if err := json.Unmarshal([]byte(data), &entry); err != nil {
+				continue
+			}
+			select {
+			case entries <- entry:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return entries, nil
+}
+
+// commandJSON runs a command endpoint with a JSON body.
+func (c *Client) commandJSON(ctx context.Context, method, path string, input any) (*CommandResult, error) {
+	body, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+	return c.command(ctx, method, path, "application/json", bytes.NewReader(body))
+}
+
+// command calls an endpoint running a WebSocket command. A command that fails answers with its result too, whose
+// messages explain why, so the result is returned along with the error.
+func (c *Client) command(ctx context.Context, method, path, contentType string, body io.Reader) (*CommandResult, error) {
+	var result CommandResult
+	_, err := c.do(ctx, method, path, contentType, body, &result)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		json.Unmarshal(statusErr.Body, &result) //nolint:all
+	}
+	return &result, err
+}
+
+func (c *Client) newRequest(ctx context.Context, method, path, contentType string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.server+"/api/"+APIVersion+path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", c.authHeader)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	return req, nil
+}
+
+// do calls an API path, relative to the versioned API prefix, and decodes a successful JSON answer into out.
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader, out any) (http.Header, error) {
+	req, err := c.newRequest(ctx, method, path, contentType, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close() //nolint:all
+	if err := checkStatus(resp); err != nil {
+		return resp.Header, err
+	}
+	return resp.Header, json.NewDecoder(resp.Body).Decode(out)
+}
+
+// checkStatus returns a StatusError for an answer with an error status.
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode <= 299 {
+		return nil
+	}
+	statusErr := &StatusError{StatusCode: resp.StatusCode}
+	statusErr.Body, _ = io.ReadAll(resp.Body)
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(statusErr.Body, &apiErr) == nil {
+		statusErr.Message = apiErr.Error
+	}
+	return statusErr
+}
+
+// withQuery appends the query to a path, when it has parameters.
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return path + "?" + query.Encode()
+}
+
+// objectPath returns the path of a namespaced object under a resource path.
+func objectPath(resource, namespace, name string) string {
+	return resource + "/" + url.PathEscape(namespace) + "/" + url.PathEscape(name)
+}
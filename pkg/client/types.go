@@ -0,0 +1,41 @@
+package client
+
+import "github.com/Banh-Canh/netwatch/internal/handlers"
+
+// APIVersion is the version of the REST API the client calls.
+const APIVersion = handlers.APIVersion
+
+// The payloads of the REST API. They are the structs the server encodes, so their fields and JSON names always
+// match what it sends and accepts.
+type (
+	ServiceInfo               = handlers.ServiceInfo
+	NamespaceInfo             = handlers.NamespaceInfo
+	ActiveAccessInfo          = handlers.ActiveAccessInfo
+	AccessDetail              = handlers.AccessDetail
+	AccessRequestPayload      = handlers.AccessRequestPayload
+	CommandResult             = handlers.CommandResult
+	CreateAccessInput         = handlers.CreateAccessInput
+	CreateExternalAccessInput = handlers.CreateExternalAccessInput
+	SubmitAccessRequestInput  = handlers.SubmitAccessRequestInput
+	ApproveAccessRequestInput = handlers.ApproveAccessRequestInput
+	DenyAccessRequestInput    = handlers.DenyAccessRequestInput
+	UpdateAccessRequestInput  = handlers.UpdateAccessRequestInput
+	PreviewAccessInput        = handlers.PreviewAccessInput
+	AccessPreview             = handlers.AccessPreview
+	RevokeBatchInput          = handlers.RevokeBatchInput
+	RevokeBatchResult         = handlers.RevokeBatchResult
+	SignedExposureReport      = handlers.SignedExposureReport
+	LogEntry                  = handlers.LogEntry
+)
+
+// ActiveAccessFilter selects active accesses. Empty fields match everything.
+type ActiveAccessFilter struct {
+	// User is the owner of the accesses, by email or username.
+	User string
+	// Namespace is the namespace of the Access or ExternalAccess object.
+	Namespace string
+	// Type is Service or External.
+	Type string
+	// Status is Active, Pending or Paused.
+	Status string
+}
@@ -0,0 +1,1412 @@
+// Netwatch gRPC API. It mirrors the REST API under /api/v1: every RPC runs the same command as its REST endpoint,
+// with the same checks, and messages carry the same fields as the JSON payloads.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: netwatch/v1/netwatch.proto
+
+package netwatchv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type SubmitAccessRequestRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Set source_service and target_service for a service-to-service request, or service and cidr for an external one.
+	SourceService string `protobuf:"bytes,1,opt,name=source_service,json=sourceService,proto3" json:"source_service,omitempty"`
+	TargetService string `protobuf:"bytes,2,opt,name=target_service,json=targetService,proto3" json:"target_service,omitempty"`
+	Service       string `protobuf:"bytes,3,opt,name=service,proto3" json:"service,omitempty"`
+	Cidr          string `protobuf:"bytes,4,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	// Direction is ingress, egress or all, all when empty.
+	Direction string `protobuf:"bytes,5,opt,name=direction,proto3" json:"direction,omitempty"`
+	Ports     string `protobuf:"bytes,6,opt,name=ports,proto3" json:"ports,omitempty"`
+	// Duration is in seconds.
+	Duration    int64             `protobuf:"varint,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	Description string            `protobuf:"bytes,8,opt,name=description,proto3" json:"description,omitempty"`
+	Labels      map[string]string `protobuf:"bytes,9,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// OnBehalfOf files the request for another user, see the file-on-behalf verb.
+	OnBehalfOf string `protobuf:"bytes,10,opt,name=on_behalf_of,json=onBehalfOf,proto3" json:"on_behalf_of,omitempty"`
+	// TicketRef is the Jira issue or ServiceNow record tracking the request.
+	TicketRef string `protobuf:"bytes,11,opt,name=ticket_ref,json=ticketRef,proto3" json:"ticket_ref,omitempty"`
+	// Priority is low, normal or urgent, normal when empty.
+	Priority string `protobuf:"bytes,12,opt,name=priority,proto3" json:"priority,omitempty"`
+	// PreviousRequestID is the denied or expired request this one resubmits, by request ID or name.
+	PreviousRequestId string `protobuf:"bytes,13,opt,name=previous_request_id,json=previousRequestId,proto3" json:"previous_request_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SubmitAccessRequestRequest) Reset() {
+	*x = SubmitAccessRequestRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitAccessRequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitAccessRequestRequest) ProtoMessage() {}
+
+func (x *SubmitAccessRequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitAccessRequestRequest.ProtoReflect.Descriptor instead.
+func (*SubmitAccessRequestRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *SubmitAccessRequestRequest) GetSourceService() string {
+	if x != nil {
+		return x.SourceService
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetTargetService() string {
+	if x != nil {
+		return x.TargetService
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetPorts() string {
+	if x != nil {
+		return x.Ports
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *SubmitAccessRequestRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *SubmitAccessRequestRequest) GetOnBehalfOf() string {
+	if x != nil {
+		return x.OnBehalfOf
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetTicketRef() string {
+	if x != nil {
+		return x.TicketRef
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetPriority() string {
+	if x != nil {
+		return x.Priority
+	}
+	return ""
+}
+
+func (x *SubmitAccessRequestRequest) GetPreviousRequestId() string {
+	if x != nil {
+		return x.PreviousRequestId
+	}
+	return ""
+}
+
+type CreateAccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SourceService string                 `protobuf:"bytes,1,opt,name=source_service,json=sourceService,proto3" json:"source_service,omitempty"`
+	TargetService string                 `protobuf:"bytes,2,opt,name=target_service,json=targetService,proto3" json:"target_service,omitempty"`
+	Direction     string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	Ports         string                 `protobuf:"bytes,4,opt,name=ports,proto3" json:"ports,omitempty"`
+	Duration      int64                  `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	Heartbeat     bool                   `protobuf:"varint,6,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAccessRequest) Reset() {
+	*x = CreateAccessRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAccessRequest) ProtoMessage() {}
+
+func (x *CreateAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAccessRequest.ProtoReflect.Descriptor instead.
+func (*CreateAccessRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreateAccessRequest) GetSourceService() string {
+	if x != nil {
+		return x.SourceService
+	}
+	return ""
+}
+
+func (x *CreateAccessRequest) GetTargetService() string {
+	if x != nil {
+		return x.TargetService
+	}
+	return ""
+}
+
+func (x *CreateAccessRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *CreateAccessRequest) GetPorts() string {
+	if x != nil {
+		return x.Ports
+	}
+	return ""
+}
+
+func (x *CreateAccessRequest) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *CreateAccessRequest) GetHeartbeat() bool {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return false
+}
+
+type CreateExternalAccessRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Service       string                 `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Cidr          string                 `protobuf:"bytes,2,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Direction     string                 `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	Ports         string                 `protobuf:"bytes,4,opt,name=ports,proto3" json:"ports,omitempty"`
+	Duration      int64                  `protobuf:"varint,5,opt,name=duration,proto3" json:"duration,omitempty"`
+	Heartbeat     bool                   `protobuf:"varint,6,opt,name=heartbeat,proto3" json:"heartbeat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateExternalAccessRequest) Reset() {
+	*x = CreateExternalAccessRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateExternalAccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateExternalAccessRequest) ProtoMessage() {}
+
+func (x *CreateExternalAccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateExternalAccessRequest.ProtoReflect.Descriptor instead.
+func (*CreateExternalAccessRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateExternalAccessRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *CreateExternalAccessRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *CreateExternalAccessRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *CreateExternalAccessRequest) GetPorts() string {
+	if x != nil {
+		return x.Ports
+	}
+	return ""
+}
+
+func (x *CreateExternalAccessRequest) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *CreateExternalAccessRequest) GetHeartbeat() bool {
+	if x != nil {
+		return x.Heartbeat
+	}
+	return false
+}
+
+type AccessRequestRef struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Id is the name of the AccessRequest.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Comment is the approval comment, or the reason of a denial.
+	Comment       string `protobuf:"bytes,2,opt,name=comment,proto3" json:"comment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRequestRef) Reset() {
+	*x = AccessRequestRef{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRequestRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRequestRef) ProtoMessage() {}
+
+func (x *AccessRequestRef) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRequestRef.ProtoReflect.Descriptor instead.
+func (*AccessRequestRef) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AccessRequestRef) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *AccessRequestRef) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+type AccessRef struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// External is set to revoke an ExternalAccess instead of an Access.
+	External      bool `protobuf:"varint,3,opt,name=external,proto3" json:"external,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccessRef) Reset() {
+	*x = AccessRef{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRef) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRef) ProtoMessage() {}
+
+func (x *AccessRef) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRef.ProtoReflect.Descriptor instead.
+func (*AccessRef) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AccessRef) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *AccessRef) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AccessRef) GetExternal() bool {
+	if x != nil {
+		return x.External
+	}
+	return false
+}
+
+type CommandResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Accesses      []string               `protobuf:"bytes,5,rep,name=accesses,proto3" json:"accesses,omitempty"`
+	Revoked       []string               `protobuf:"bytes,6,rep,name=revoked,proto3" json:"revoked,omitempty"`
+	Clones        []string               `protobuf:"bytes,7,rep,name=clones,proto3" json:"clones,omitempty"`
+	Messages      []*LogEntry            `protobuf:"bytes,8,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommandResult) Reset() {
+	*x = CommandResult{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommandResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommandResult) ProtoMessage() {}
+
+func (x *CommandResult) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommandResult.ProtoReflect.Descriptor instead.
+func (*CommandResult) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CommandResult) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *CommandResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CommandResult) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *CommandResult) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CommandResult) GetAccesses() []string {
+	if x != nil {
+		return x.Accesses
+	}
+	return nil
+}
+
+func (x *CommandResult) GetRevoked() []string {
+	if x != nil {
+		return x.Revoked
+	}
+	return nil
+}
+
+func (x *CommandResult) GetClones() []string {
+	if x != nil {
+		return x.Clones
+	}
+	return nil
+}
+
+func (x *CommandResult) GetMessages() []*LogEntry {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type ListPendingRequestsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Labels only keeps the requests carrying every key=value label given.
+	Labels        []string `protobuf:"bytes,1,rep,name=labels,proto3" json:"labels,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingRequestsRequest) Reset() {
+	*x = ListPendingRequestsRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingRequestsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingRequestsRequest) ProtoMessage() {}
+
+func (x *ListPendingRequestsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingRequestsRequest.ProtoReflect.Descriptor instead.
+func (*ListPendingRequestsRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListPendingRequestsRequest) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type ListPendingRequestsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requests      []*AccessRequest       `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPendingRequestsResponse) Reset() {
+	*x = ListPendingRequestsResponse{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPendingRequestsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPendingRequestsResponse) ProtoMessage() {}
+
+func (x *ListPendingRequestsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPendingRequestsResponse.ProtoReflect.Descriptor instead.
+func (*ListPendingRequestsResponse) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPendingRequestsResponse) GetRequests() []*AccessRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+type AccessRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RequestId      string                 `protobuf:"bytes,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	DisplayName    string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Requestor      string                 `protobuf:"bytes,3,opt,name=requestor,proto3" json:"requestor,omitempty"`
+	FiledBy        string                 `protobuf:"bytes,4,opt,name=filed_by,json=filedBy,proto3" json:"filed_by,omitempty"`
+	Timestamp      int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	RequestType    string                 `protobuf:"bytes,6,opt,name=request_type,json=requestType,proto3" json:"request_type,omitempty"`
+	SourceService  string                 `protobuf:"bytes,7,opt,name=source_service,json=sourceService,proto3" json:"source_service,omitempty"`
+	TargetService  string                 `protobuf:"bytes,8,opt,name=target_service,json=targetService,proto3" json:"target_service,omitempty"`
+	Cidr           string                 `protobuf:"bytes,9,opt,name=cidr,proto3" json:"cidr,omitempty"`
+	Service        string                 `protobuf:"bytes,10,opt,name=service,proto3" json:"service,omitempty"`
+	Direction      string                 `protobuf:"bytes,11,opt,name=direction,proto3" json:"direction,omitempty"`
+	Ports          string                 `protobuf:"bytes,12,opt,name=ports,proto3" json:"ports,omitempty"`
+	Duration       int64                  `protobuf:"varint,13,opt,name=duration,proto3" json:"duration,omitempty"`
+	Description    string                 `protobuf:"bytes,14,opt,name=description,proto3" json:"description,omitempty"`
+	CanSelfApprove bool                   `protobuf:"varint,15,opt,name=can_self_approve,json=canSelfApprove,proto3" json:"can_self_approve,omitempty"`
+	Status         string                 `protobuf:"bytes,16,opt,name=status,proto3" json:"status,omitempty"`
+	Labels         map[string]string      `protobuf:"bytes,17,rep,name=labels,proto3" json:"labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AccessRequest) Reset() {
+	*x = AccessRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccessRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccessRequest) ProtoMessage() {}
+
+func (x *AccessRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccessRequest.ProtoReflect.Descriptor instead.
+func (*AccessRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AccessRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetRequestor() string {
+	if x != nil {
+		return x.Requestor
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetFiledBy() string {
+	if x != nil {
+		return x.FiledBy
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetRequestType() string {
+	if x != nil {
+		return x.RequestType
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetSourceService() string {
+	if x != nil {
+		return x.SourceService
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetTargetService() string {
+	if x != nil {
+		return x.TargetService
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetCidr() string {
+	if x != nil {
+		return x.Cidr
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetService() string {
+	if x != nil {
+		return x.Service
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetPorts() string {
+	if x != nil {
+		return x.Ports
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetDuration() int64 {
+	if x != nil {
+		return x.Duration
+	}
+	return 0
+}
+
+func (x *AccessRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetCanSelfApprove() bool {
+	if x != nil {
+		return x.CanSelfApprove
+	}
+	return false
+}
+
+func (x *AccessRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AccessRequest) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+type ListActiveAccessesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Namespace     string                 `protobuf:"bytes,2,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveAccessesRequest) Reset() {
+	*x = ListActiveAccessesRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveAccessesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveAccessesRequest) ProtoMessage() {}
+
+func (x *ListActiveAccessesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveAccessesRequest.ProtoReflect.Descriptor instead.
+func (*ListActiveAccessesRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListActiveAccessesRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ListActiveAccessesRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ListActiveAccessesRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListActiveAccessesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type ListActiveAccessesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accesses      []*ActiveAccess        `protobuf:"bytes,1,rep,name=accesses,proto3" json:"accesses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListActiveAccessesResponse) Reset() {
+	*x = ListActiveAccessesResponse{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListActiveAccessesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListActiveAccessesResponse) ProtoMessage() {}
+
+func (x *ListActiveAccessesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListActiveAccessesResponse.ProtoReflect.Descriptor instead.
+func (*ListActiveAccessesResponse) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListActiveAccessesResponse) GetAccesses() []*ActiveAccess {
+	if x != nil {
+		return x.Accesses
+	}
+	return nil
+}
+
+type ActiveAccess struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Namespace     string                 `protobuf:"bytes,3,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Target        string                 `protobuf:"bytes,5,opt,name=target,proto3" json:"target,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Direction     string                 `protobuf:"bytes,7,opt,name=direction,proto3" json:"direction,omitempty"`
+	Ports         string                 `protobuf:"bytes,8,opt,name=ports,proto3" json:"ports,omitempty"`
+	Status        string                 `protobuf:"bytes,9,opt,name=status,proto3" json:"status,omitempty"`
+	Owner         string                 `protobuf:"bytes,10,opt,name=owner,proto3" json:"owner,omitempty"`
+	Requestor     string                 `protobuf:"bytes,11,opt,name=requestor,proto3" json:"requestor,omitempty"`
+	ApprovedBy    string                 `protobuf:"bytes,12,opt,name=approved_by,json=approvedBy,proto3" json:"approved_by,omitempty"`
+	CreatedAt     int64                  `protobuf:"varint,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActiveAccess) Reset() {
+	*x = ActiveAccess{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActiveAccess) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActiveAccess) ProtoMessage() {}
+
+func (x *ActiveAccess) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActiveAccess.ProtoReflect.Descriptor instead.
+func (*ActiveAccess) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ActiveAccess) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *ActiveAccess) GetDirection() string {
+	if x != nil {
+		return x.Direction
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetPorts() string {
+	if x != nil {
+		return x.Ports
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetRequestor() string {
+	if x != nil {
+		return x.Requestor
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetApprovedBy() string {
+	if x != nil {
+		return x.ApprovedBy
+	}
+	return ""
+}
+
+func (x *ActiveAccess) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+type WatchLogsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Since also sends the entries written at or after this Unix timestamp, in milliseconds.
+	Since         int64 `protobuf:"varint,1,opt,name=since,proto3" json:"since,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchLogsRequest) Reset() {
+	*x = WatchLogsRequest{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchLogsRequest) ProtoMessage() {}
+
+func (x *WatchLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchLogsRequest.ProtoReflect.Descriptor instead.
+func (*WatchLogsRequest) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchLogsRequest) GetSince() int64 {
+	if x != nil {
+		return x.Since
+	}
+	return 0
+}
+
+type LogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Payload       string                 `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	ClassName     string                 `protobuf:"bytes,3,opt,name=class_name,json=className,proto3" json:"class_name,omitempty"`
+	LogType       string                 `protobuf:"bytes,4,opt,name=log_type,json=logType,proto3" json:"log_type,omitempty"`
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`
+	User          string                 `protobuf:"bytes,6,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogEntry) Reset() {
+	*x = LogEntry{}
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogEntry) ProtoMessage() {}
+
+func (x *LogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_netwatch_v1_netwatch_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogEntry.ProtoReflect.Descriptor instead.
+func (*LogEntry) Descriptor() ([]byte, []int) {
+	return file_netwatch_v1_netwatch_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LogEntry) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *LogEntry) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *LogEntry) GetClassName() string {
+	if x != nil {
+		return x.ClassName
+	}
+	return ""
+}
+
+func (x *LogEntry) GetLogType() string {
+	if x != nil {
+		return x.LogType
+	}
+	return ""
+}
+
+func (x *LogEntry) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *LogEntry) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+var File_netwatch_v1_netwatch_proto protoreflect.FileDescriptor
+
+const file_netwatch_v1_netwatch_proto_rawDesc = "" +
+	"\n" +
+	"\x1anetwatch/v1/netwatch.proto\x12\vnetwatch.v1\"\x9f\x04\n" +
+	"\x1aSubmitAccessRequestRequest\x12%\n" +
+	"\x0esource_service\x18\x01 \x01(\tR\rsourceService\x12%\n" +
+	"\x0etarget_service\x18\x02 \x01(\tR\rtargetService\x12\x18\n" +
+	"\aservice\x18\x03 \x01(\tR\aservice\x12\x12\n" +
+	"\x04cidr\x18\x04 \x01(\tR\x04cidr\x12\x1c\n" +
+	"\tdirection\x18\x05 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05ports\x18\x06 \x01(\tR\x05ports\x12\x1a\n" +
+	"\bduration\x18\a \x01(\x03R\bduration\x12 \n" +
+	"\vdescription\x18\b \x01(\tR\vdescription\x12K\n" +
+	"\x06labels\x18\t \x03(\v23.netwatch.v1.SubmitAccessRequestRequest.LabelsEntryR\x06labels\x12 \n" +
+	"\fon_behalf_of\x18\n" +
+	" \x01(\tR\n" +
+	"onBehalfOf\x12\x1d\n" +
+	"\n" +
+	"ticket_ref\x18\v \x01(\tR\tticketRef\x12\x1a\n" +
+	"\bpriority\x18\f \x01(\tR\bpriority\x12.\n" +
+	"\x13previous_request_id\x18\r \x01(\tR\x11previousRequestId\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd1\x01\n" +
+	"\x13CreateAccessRequest\x12%\n" +
+	"\x0esource_service\x18\x01 \x01(\tR\rsourceService\x12%\n" +
+	"\x0etarget_service\x18\x02 \x01(\tR\rtargetService\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05ports\x18\x04 \x01(\tR\x05ports\x12\x1a\n" +
+	"\bduration\x18\x05 \x01(\x03R\bduration\x12\x1c\n" +
+	"\theartbeat\x18\x06 \x01(\bR\theartbeat\"\xb9\x01\n" +
+	"\x1bCreateExternalAccessRequest\x12\x18\n" +
+	"\aservice\x18\x01 \x01(\tR\aservice\x12\x12\n" +
+	"\x04cidr\x18\x02 \x01(\tR\x04cidr\x12\x1c\n" +
+	"\tdirection\x18\x03 \x01(\tR\tdirection\x12\x14\n" +
+	"\x05ports\x18\x04 \x01(\tR\x05ports\x12\x1a\n" +
+	"\bduration\x18\x05 \x01(\x03R\bduration\x12\x1c\n" +
+	"\theartbeat\x18\x06 \x01(\bR\theartbeat\"<\n" +
+	"\x10AccessRequestRef\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\acomment\x18\x02 \x01(\tR\acomment\"Y\n" +
+	"\tAccessRef\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bexternal\x18\x03 \x01(\bR\bexternal\"\xfe\x01\n" +
+	"\rCommandResult\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\baccesses\x18\x05 \x03(\tR\baccesses\x12\x18\n" +
+	"\arevoked\x18\x06 \x03(\tR\arevoked\x12\x16\n" +
+	"\x06clones\x18\a \x03(\tR\x06clones\x121\n" +
+	"\bmessages\x18\b \x03(\v2\x15.netwatch.v1.LogEntryR\bmessages\"4\n" +
+	"\x1aListPendingRequestsRequest\x12\x16\n" +
+	"\x06labels\x18\x01 \x03(\tR\x06labels\"U\n" +
+	"\x1bListPendingRequestsResponse\x126\n" +
+	"\brequests\x18\x01 \x03(\v2\x1a.netwatch.v1.AccessRequestR\brequests\"\xf6\x04\n" +
+	"\rAccessRequest\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\tR\trequestId\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\x12\x1c\n" +
+	"\trequestor\x18\x03 \x01(\tR\trequestor\x12\x19\n" +
+	"\bfiled_by\x18\x04 \x01(\tR\afiledBy\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\x12!\n" +
+	"\frequest_type\x18\x06 \x01(\tR\vrequestType\x12%\n" +
+	"\x0esource_service\x18\a \x01(\tR\rsourceService\x12%\n" +
+	"\x0etarget_service\x18\b \x01(\tR\rtargetService\x12\x12\n" +
+	"\x04cidr\x18\t \x01(\tR\x04cidr\x12\x18\n" +
+	"\aservice\x18\n" +
+	" \x01(\tR\aservice\x12\x1c\n" +
+	"\tdirection\x18\v \x01(\tR\tdirection\x12\x14\n" +
+	"\x05ports\x18\f \x01(\tR\x05ports\x12\x1a\n" +
+	"\bduration\x18\r \x01(\x03R\bduration\x12 \n" +
+	"\vdescription\x18\x0e \x01(\tR\vdescription\x12(\n" +
+	"\x10can_self_approve\x18\x0f \x01(\bR\x0ecanSelfApprove\x12\x16\n" +
+	"\x06status\x18\x10 \x01(\tR\x06status\x12>\n" +
+	"\x06labels\x18\x11 \x03(\v2&.netwatch.v1.AccessRequest.LabelsEntryR\x06labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"y\n" +
+	"\x19ListActiveAccessesRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x1c\n" +
+	"\tnamespace\x18\x02 \x01(\tR\tnamespace\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"S\n" +
+	"\x1aListActiveAccessesResponse\x125\n" +
+	"\baccesses\x18\x01 \x03(\v2\x19.netwatch.v1.ActiveAccessR\baccesses\"\xe3\x02\n" +
+	"\fActiveAccess\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\tnamespace\x18\x03 \x01(\tR\tnamespace\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x16\n" +
+	"\x06target\x18\x05 \x01(\tR\x06target\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\x12\x1c\n" +
+	"\tdirection\x18\a \x01(\tR\tdirection\x12\x14\n" +
+	"\x05ports\x18\b \x01(\tR\x05ports\x12\x16\n" +
+	"\x06status\x18\t \x01(\tR\x06status\x12\x14\n" +
+	"\x05owner\x18\n" +
+	" \x01(\tR\x05owner\x12\x1c\n" +
+	"\trequestor\x18\v \x01(\tR\trequestor\x12\x1f\n" +
+	"\vapproved_by\x18\f \x01(\tR\n" +
+	"approvedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\x03R\tcreatedAt\"(\n" +
+	"\x10WatchLogsRequest\x12\x14\n" +
+	"\x05since\x18\x01 \x01(\x03R\x05since\"\xa4\x01\n" +
+	"\bLogEntry\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x18\n" +
+	"\apayload\x18\x02 \x01(\tR\apayload\x12\x1d\n" +
+	"\n" +
+	"class_name\x18\x03 \x01(\tR\tclassName\x12\x19\n" +
+	"\blog_type\x18\x04 \x01(\tR\alogType\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x12\n" +
+	"\x04user\x18\x06 \x01(\tR\x04user2\x8f\x06\n" +
+	"\bNetwatch\x12Z\n" +
+	"\x13SubmitAccessRequest\x12'.netwatch.v1.SubmitAccessRequestRequest\x1a\x1a.netwatch.v1.CommandResult\x12L\n" +
+	"\fCreateAccess\x12 .netwatch.v1.CreateAccessRequest\x1a\x1a.netwatch.v1.CommandResult\x12\\\n" +
+	"\x14CreateExternalAccess\x12(.netwatch.v1.CreateExternalAccessRequest\x1a\x1a.netwatch.v1.CommandResult\x12Q\n" +
+	"\x14ApproveAccessRequest\x12\x1d.netwatch.v1.AccessRequestRef\x1a\x1a.netwatch.v1.CommandResult\x12N\n" +
+	"\x11DenyAccessRequest\x12\x1d.netwatch.v1.AccessRequestRef\x1a\x1a.netwatch.v1.CommandResult\x12B\n" +
+	"\fRevokeAccess\x12\x16.netwatch.v1.AccessRef\x1a\x1a.netwatch.v1.CommandResult\x12h\n" +
+	"\x13ListPendingRequests\x12'.netwatch.v1.ListPendingRequestsRequest\x1a(.netwatch.v1.ListPendingRequestsResponse\x12e\n" +
+	"\x12ListActiveAccesses\x12&.netwatch.v1.ListActiveAccessesRequest\x1a'.netwatch.v1.ListActiveAccessesResponse\x12C\n" +
+	"\tWatchLogs\x12\x1d.netwatch.v1.WatchLogsRequest\x1a\x15.netwatch.v1.LogEntry0\x01B@Z>github.com/Banh-Canh/netwatch/pkg/proto/netwatch/v1;netwatchv1b\x06proto3"
+
+var (
+	file_netwatch_v1_netwatch_proto_rawDescOnce sync.Once
+	file_netwatch_v1_netwatch_proto_rawDescData []byte
+)
+
+func file_netwatch_v1_netwatch_proto_rawDescGZIP() []byte {
+	file_netwatch_v1_netwatch_proto_rawDescOnce.Do(func() {
+		file_netwatch_v1_netwatch_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_netwatch_v1_netwatch_proto_rawDesc), len(file_netwatch_v1_netwatch_proto_rawDesc)))
+	})
+	return file_netwatch_v1_netwatch_proto_rawDescData
+}
+
+var file_netwatch_v1_netwatch_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_netwatch_v1_netwatch_proto_goTypes = []any{
+	(*SubmitAccessRequestRequest)(nil),  // 0: netwatch.v1.SubmitAccessRequestRequest
+	(*CreateAccessRequest)(nil),         // 1: netwatch.v1.CreateAccessRequest
+	(*CreateExternalAccessRequest)(nil), // 2: netwatch.v1.CreateExternalAccessRequest
+	(*AccessRequestRef)(nil),            // 3: netwatch.v1.AccessRequestRef
+	(*AccessRef)(nil),                   // 4: netwatch.v1.AccessRef
+	(*CommandResult)(nil),               // 5: netwatch.v1.CommandResult
+	(*ListPendingRequestsRequest)(nil),  // 6: netwatch.v1.ListPendingRequestsRequest
+	(*ListPendingRequestsResponse)(nil), // 7: netwatch.v1.ListPendingRequestsResponse
+	(*AccessRequest)(nil),               // 8: netwatch.v1.AccessRequest
+	(*ListActiveAccessesRequest)(nil),   // 9: netwatch.v1.ListActiveAccessesRequest
+	(*ListActiveAccessesResponse)(nil),  // 10: netwatch.v1.ListActiveAccessesResponse
+	(*ActiveAccess)(nil),                // 11: netwatch.v1.ActiveAccess
+	(*WatchLogsRequest)(nil),            // 12: netwatch.v1.WatchLogsRequest
+	(*LogEntry)(nil),                    // 13: netwatch.v1.LogEntry
+	nil,                                 // 14: netwatch.v1.SubmitAccessRequestRequest.LabelsEntry
+	nil,                                 // 15: netwatch.v1.AccessRequest.LabelsEntry
+}
+var file_netwatch_v1_netwatch_proto_depIdxs = []int32{
+	14, // 0: netwatch.v1.SubmitAccessRequestRequest.labels:type_name -> netwatch.v1.SubmitAccessRequestRequest.LabelsEntry
+	13, // 1: netwatch.v1.CommandResult.messages:type_name -> netwatch.v1.LogEntry
+	8,  // 2: netwatch.v1.ListPendingRequestsResponse.requests:type_name -> netwatch.v1.AccessRequest
+	15, // 3: netwatch.v1.AccessRequest.labels:type_name -> netwatch.v1.AccessRequest.LabelsEntry
+	11, // 4: netwatch.v1.ListActiveAccessesResponse.accesses:type_name -> netwatch.v1.ActiveAccess
+	0,  // 5: netwatch.v1.Netwatch.SubmitAccessRequest:input_type -> netwatch.v1.SubmitAccessRequestRequest
+	1,  // 6: netwatch.v1.Netwatch.CreateAccess:input_type -> netwatch.v1.CreateAccessRequest
+	2,  // 7: netwatch.v1.Netwatch.CreateExternalAccess:input_type -> netwatch.v1.CreateExternalAccessRequest
+	3,  // 8: netwatch.v1.Netwatch.ApproveAccessRequest:input_type -> netwatch.v1.AccessRequestRef
+	3,  // 9: netwatch.v1.Netwatch.DenyAccessRequest:input_type -> netwatch.v1.AccessRequestRef
+	4,  // 10: netwatch.v1.Netwatch.RevokeAccess:input_type -> netwatch.v1.AccessRef
+	6,  // 11: netwatch.v1.Netwatch.ListPendingRequests:input_type -> netwatch.v1.ListPendingRequestsRequest
+	9,  // 12: netwatch.v1.Netwatch.ListActiveAccesses:input_type -> netwatch.v1.ListActiveAccessesRequest
+	12, // 13: netwatch.v1.Netwatch.WatchLogs:input_type -> netwatch.v1.WatchLogsRequest
+	5,  // 14: netwatch.v1.Netwatch.SubmitAccessRequest:output_type -> netwatch.v1.CommandResult
+	5,  // 15: netwatch.v1.Netwatch.CreateAccess:output_type -> netwatch.v1.CommandResult
+	5,  // 16: netwatch.v1.Netwatch.CreateExternalAccess:output_type -> netwatch.v1.CommandResult
+	5,  // 17: netwatch.v1.Netwatch.ApproveAccessRequest:output_type -> netwatch.v1.CommandResult
+	5,  // 18: netwatch.v1.Netwatch.DenyAccessRequest:output_type -> netwatch.v1.CommandResult
+	5,  // 19: netwatch.v1.Netwatch.RevokeAccess:output_type -> netwatch.v1.CommandResult
+	7,  // 20: netwatch.v1.Netwatch.ListPendingRequests:output_type -> netwatch.v1.ListPendingRequestsResponse
+	10, // 21: netwatch.v1.Netwatch.ListActiveAccesses:output_type -> netwatch.v1.ListActiveAccessesResponse
+	13, // 22: netwatch.v1.Netwatch.WatchLogs:output_type -> netwatch.v1.LogEntry
+	14, // [14:23] is the sub-list for method output_type
+	5,  // [5:14] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_netwatch_v1_netwatch_proto_init() }
+func file_netwatch_v1_netwatch_proto_init() {
+	if File_netwatch_v1_netwatch_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_netwatch_v1_netwatch_proto_rawDesc), len(file_netwatch_v1_netwatch_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_netwatch_v1_netwatch_proto_goTypes,
+		DependencyIndexes: file_netwatch_v1_netwatch_proto_depIdxs,
+		MessageInfos:      file_netwatch_v1_netwatch_proto_msgTypes,
+	}.Build()
+	File_netwatch_v1_netwatch_proto = out.File
+	file_netwatch_v1_netwatch_proto_goTypes = nil
+	file_netwatch_v1_netwatch_proto_depIdxs = nil
+}
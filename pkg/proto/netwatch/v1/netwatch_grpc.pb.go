@@ -0,0 +1,462 @@
+// Netwatch gRPC API. It mirrors the REST API under /api/v1: every RPC runs the same command as its REST endpoint,
+// with the same checks, and messages carry the same fields as the JSON payloads.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: netwatch/v1/netwatch.proto
+
+package netwatchv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Netwatch_SubmitAccessRequest_FullMethodName  = "/netwatch.v1.Netwatch/SubmitAccessRequest"
+	Netwatch_CreateAccess_FullMethodName         = "/netwatch.v1.Netwatch/CreateAccess"
+	Netwatch_CreateExternalAccess_FullMethodName = "/netwatch.v1.Netwatch/CreateExternalAccess"
+	Netwatch_ApproveAccessRequest_FullMethodName = "/netwatch.v1.Netwatch/ApproveAccessRequest"
+	Netwatch_DenyAccessRequest_FullMethodName    = "/netwatch.v1.Netwatch/DenyAccessRequest"
+	Netwatch_RevokeAccess_FullMethodName         = "/netwatch.v1.Netwatch/RevokeAccess"
+	Netwatch_ListPendingRequests_FullMethodName  = "/netwatch.v1.Netwatch/ListPendingRequests"
+	Netwatch_ListActiveAccesses_FullMethodName   = "/netwatch.v1.Netwatch/ListActiveAccesses"
+	Netwatch_WatchLogs_FullMethodName            = "/netwatch.v1.Netwatch/WatchLogs"
+)
+
+// NetwatchClient is the client API for Netwatch service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Netwatch requests, approves, revokes and lists temporary network accesses. Calls authenticate with the
+// "authorization" metadata, like the Authorization header of the REST API: "Bearer <OIDC ID token>" or
+// "ApiKey <static key>".
+type NetwatchClient interface {
+	// SubmitAccessRequest submits an access request for review. POST /api/v1/access-requests.
+	SubmitAccessRequest(ctx context.Context, in *SubmitAccessRequestRequest, opts ...grpc.CallOption) (*CommandResult, error)
+	// CreateAccess creates a service-to-service access without review. POST /api/v1/accesses.
+	CreateAccess(ctx context.Context, in *CreateAccessRequest, opts ...grpc.CallOption) (*CommandResult, error)
+	// CreateExternalAccess creates an access between a service and a CIDR without review. POST /api/v1/external-accesses.
+	CreateExternalAccess(ctx context.Context, in *CreateExternalAccessRequest, opts ...grpc.CallOption) (*CommandResult, error)
+	// ApproveAccessRequest approves a pending request. POST /api/v1/pending-requests/{id}/approve.
+	ApproveAccessRequest(ctx context.Context, in *AccessRequestRef, opts ...grpc.CallOption) (*CommandResult, error)
+	// DenyAccessRequest denies a pending request, or aborts it when called by its requestor.
+	// POST /api/v1/pending-requests/{id}/deny.
+	DenyAccessRequest(ctx context.Context, in *AccessRequestRef, opts ...grpc.CallOption) (*CommandResult, error)
+	// RevokeAccess revokes an Access or an ExternalAccess. DELETE /api/v1/accesses/{namespace}/{name} and
+	// DELETE /api/v1/external-accesses/{namespace}/{name}.
+	RevokeAccess(ctx context.Context, in *AccessRef, opts ...grpc.CallOption) (*CommandResult, error)
+	// ListPendingRequests lists the pending access requests. GET /api/v1/pending-requests.
+	ListPendingRequests(ctx context.Context, in *ListPendingRequestsRequest, opts ...grpc.CallOption) (*ListPendingRequestsResponse, error)
+	// ListActiveAccesses lists the accesses managed by Netwatch. GET /api/v1/active-accesses.
+	ListActiveAccesses(ctx context.Context, in *ListActiveAccessesRequest, opts ...grpc.CallOption) (*ListActiveAccessesResponse, error)
+	// WatchLogs streams the activity log entries written after the call. GET /api/v1/logs/stream.
+	WatchLogs(ctx context.Context, in *WatchLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error)
+}
+
+type netwatchClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewNetwatchClient(cc grpc.ClientConnInterface) NetwatchClient {
+	return &netwatchClient{cc}
+}
+
+func (c *netwatchClient) SubmitAccessRequest(ctx context.Context, in *SubmitAccessRequestRequest, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Netwatch_SubmitAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) CreateAccess(ctx context.Context, in *CreateAccessRequest, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Netwatch_CreateAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) CreateExternalAccess(ctx context.Context, in *CreateExternalAccessRequest, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Netwatch_CreateExternalAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) ApproveAccessRequest(ctx context.Context, in *AccessRequestRef, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Netwatch_ApproveAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) DenyAccessRequest(ctx context.Context, in *AccessRequestRef, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Netwatch_DenyAccessRequest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) RevokeAccess(ctx context.Context, in *AccessRef, opts ...grpc.CallOption) (*CommandResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommandResult)
+	err := c.cc.Invoke(ctx, Netwatch_RevokeAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) ListPendingRequests(ctx context.Context, in *ListPendingRequestsRequest, opts ...grpc.CallOption) (*ListPendingRequestsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPendingRequestsResponse)
+	err := c.cc.Invoke(ctx, Netwatch_ListPendingRequests_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) ListActiveAccesses(ctx context.Context, in *ListActiveAccessesRequest, opts ...grpc.CallOption) (*ListActiveAccessesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListActiveAccessesResponse)
+	err := c.cc.Invoke(ctx, Netwatch_ListActiveAccesses_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *netwatchClient) WatchLogs(ctx context.Context, in *WatchLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Netwatch_ServiceDesc.Streams[0], Netwatch_WatchLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchLogsRequest, LogEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Netwatch_WatchLogsClient = grpc.ServerStreamingClient[LogEntry]
+
+// NetwatchServer is the server API for Netwatch service.
+// All implementations must embed UnimplementedNetwatchServer
+// for forward compatibility.
+//
+// Netwatch requests, approves, revokes and lists temporary network accesses. Calls authenticate with the
+// "authorization" metadata, like the Authorization header of the REST API: "Bearer <OIDC ID token>" or
+// "ApiKey <static key>".
+type NetwatchServer interface {
+	// SubmitAccessRequest submits an access request for review. POST /api/v1/access-requests.
+	SubmitAccessRequest(context.Context, *SubmitAccessRequestRequest) (*CommandResult, error)
+	// CreateAccess creates a service-to-service access without review. POST /api/v1/accesses.
+	CreateAccess(context.Context, *CreateAccessRequest) (*CommandResult, error)
+	// CreateExternalAccess creates an access between a service and a CIDR without review. POST /api/v1/external-accesses.
+	CreateExternalAccess(context.Context, *CreateExternalAccessRequest) (*CommandResult, error)
+	// ApproveAccessRequest approves a pending request. POST /api/v1/pending-requests/{id}/approve.
+	ApproveAccessRequest(context.Context, *AccessRequestRef) (*CommandResult, error)
+	// DenyAccessRequest denies a pending request, or aborts it when called by its requestor.
+	// POST /api/v1/pending-requests/{id}/deny.
+	DenyAccessRequest(context.Context, *AccessRequestRef) (*CommandResult, error)
+	// RevokeAccess revokes an Access or an ExternalAccess. DELETE /api/v1/accesses/{namespace}/{name} and
+	// DELETE /api/v1/external-accesses/{namespace}/{name}.
+	RevokeAccess(context.Context, *AccessRef) (*CommandResult, error)
+	// ListPendingRequests lists the pending access requests. GET /api/v1/pending-requests.
+	ListPendingRequests(context.Context, *ListPendingRequestsRequest) (*ListPendingRequestsResponse, error)
+	// ListActiveAccesses lists the accesses managed by Netwatch. GET /api/v1/active-accesses.
+	ListActiveAccesses(context.Context, *ListActiveAccessesRequest) (*ListActiveAccessesResponse, error)
+	// WatchLogs streams the activity log entries written after the call. GET /api/v1/logs/stream.
+	WatchLogs(*WatchLogsRequest, grpc.ServerStreamingServer[LogEntry]) error
+	mustEmbedUnimplementedNetwatchServer()
+}
+
+// UnimplementedNetwatchServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedNetwatchServer struct{}
+
+func (UnimplementedNetwatchServer) SubmitAccessRequest(context.Context, *SubmitAccessRequestRequest) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SubmitAccessRequest not implemented")
+}
+func (UnimplementedNetwatchServer) CreateAccess(context.Context, *CreateAccessRequest) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAccess not implemented")
+}
+func (UnimplementedNetwatchServer) CreateExternalAccess(context.Context, *CreateExternalAccessRequest) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateExternalAccess not implemented")
+}
+func (UnimplementedNetwatchServer) ApproveAccessRequest(context.Context, *AccessRequestRef) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveAccessRequest not implemented")
+}
+func (UnimplementedNetwatchServer) DenyAccessRequest(context.Context, *AccessRequestRef) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DenyAccessRequest not implemented")
+}
+func (UnimplementedNetwatchServer) RevokeAccess(context.Context, *AccessRef) (*CommandResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeAccess not implemented")
+}
+func (UnimplementedNetwatchServer) ListPendingRequests(context.Context, *ListPendingRequestsRequest) (*ListPendingRequestsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPendingRequests not implemented")
+}
+func (UnimplementedNetwatchServer) ListActiveAccesses(context.Context, *ListActiveAccessesRequest) (*ListActiveAccessesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListActiveAccesses not implemented")
+}
+func (UnimplementedNetwatchServer) WatchLogs(*WatchLogsRequest, grpc.ServerStreamingServer[LogEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchLogs not implemented")
+}
+func (UnimplementedNetwatchServer) mustEmbedUnimplementedNetwatchServer() {}
+func (UnimplementedNetwatchServer) testEmbeddedByValue()                  {}
+
+// UnsafeNetwatchServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to NetwatchServer will
+// result in compilation errors.
+type UnsafeNetwatchServer interface {
+	mustEmbedUnimplementedNetwatchServer()
+}
+
+func RegisterNetwatchServer(s grpc.ServiceRegistrar, srv NetwatchServer) {
+	// If the following call pancis, it indicates UnimplementedNetwatchServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Netwatch_ServiceDesc, srv)
+}
+
+func _Netwatch_SubmitAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SubmitAccessRequestRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).SubmitAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_SubmitAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).SubmitAccessRequest(ctx, req.(*SubmitAccessRequestRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_CreateAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).CreateAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_CreateAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).CreateAccess(ctx, req.(*CreateAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_CreateExternalAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateExternalAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).CreateExternalAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_CreateExternalAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).CreateExternalAccess(ctx, req.(*CreateExternalAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_ApproveAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessRequestRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).ApproveAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_ApproveAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).ApproveAccessRequest(ctx, req.(*AccessRequestRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_DenyAccessRequest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessRequestRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).DenyAccessRequest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_DenyAccessRequest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).DenyAccessRequest(ctx, req.(*AccessRequestRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_RevokeAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccessRef)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).RevokeAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_RevokeAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).RevokeAccess(ctx, req.(*AccessRef))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_ListPendingRequests_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPendingRequestsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).ListPendingRequests(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_ListPendingRequests_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).ListPendingRequests(ctx, req.(*ListPendingRequestsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_ListActiveAccesses_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListActiveAccessesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(NetwatchServer).ListActiveAccesses(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Netwatch_ListActiveAccesses_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(NetwatchServer).ListActiveAccesses(ctx, req.(*ListActiveAccessesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Netwatch_WatchLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(NetwatchServer).WatchLogs(m, &grpc.GenericServerStream[WatchLogsRequest, LogEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Netwatch_WatchLogsServer = grpc.ServerStreamingServer[LogEntry]
+
+// Netwatch_ServiceDesc is the grpc.ServiceDesc for Netwatch service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Netwatch_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "netwatch.v1.Netwatch",
+	HandlerType: (*NetwatchServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "SubmitAccessRequest",
+			Handler:    _Netwatch_SubmitAccessRequest_Handler,
+		},
+		{
+			MethodName: "CreateAccess",
+			Handler:    _Netwatch_CreateAccess_Handler,
+		},
+		{
+			MethodName: "CreateExternalAccess",
+			Handler:    _Netwatch_CreateExternalAccess_Handler,
+		},
+		{
+			MethodName: "ApproveAccessRequest",
+			Handler:    _Netwatch_ApproveAccessRequest_Handler,
+		},
+		{
+			MethodName: "DenyAccessRequest",
+			Handler:    _Netwatch_DenyAccessRequest_Handler,
+		},
+		{
+			MethodName: "RevokeAccess",
+			Handler:    _Netwatch_RevokeAccess_Handler,
+		},
+		{
+			MethodName: "ListPendingRequests",
+			Handler:    _Netwatch_ListPendingRequests_Handler,
+		},
+		{
+			MethodName: "ListActiveAccesses",
+			Handler:    _Netwatch_ListActiveAccesses_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchLogs",
+			Handler:       _Netwatch_WatchLogs_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "netwatch/v1/netwatch.proto",
+}
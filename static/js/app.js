@@ -7,6 +7,7 @@ import {
   clearLogs,
   renderAccessList,
   renderPendingRequests,
+  parseLabels,
   updateNamespaceFilters,
   updateServiceDropdown,
 } from './ui.js'
@@ -104,7 +105,14 @@ async function fetchAndRenderPendingRequests() {
   document.getElementById('pending-requests-list').innerHTML =
     '<p>Refreshing pending requests...</p>'
   try {
-    const response = await fetch('/api/pending-requests')
+    const params = new URLSearchParams()
+    const filter = parseLabels(
+      document.getElementById('hub-label-filter').value,
+    )
+    Object.entries(filter).forEach(([key, value]) =>
+      params.append('label', `${key}=${value}`),
+    )
+    const response = await fetch(`/api/pending-requests?${params}`)
     if (!response.ok) throw new Error('Failed to fetch pending requests')
     const requests = await response.json()
     renderPendingRequests(requests)
@@ -115,6 +123,43 @@ async function fetchAndRenderPendingRequests() {
   }
 }
 
+async function uploadAttachment(requestID, file) {
+  const formData = new FormData()
+  formData.append('file', file)
+  try {
+    const response = await fetch(
+      `/api/pending-requests/${encodeURIComponent(requestID)}/attachments`,
+      { method: 'POST', body: formData },
+    )
+    if (!response.ok) {
+      const body = await response.json().catch(() => ({}))
+      throw new Error(body.error || 'Failed to upload attachment')
+    }
+  } catch (error) {
+    console.error('Error uploading attachment:', error)
+    alert(error.message)
+  }
+  fetchAndRenderPendingRequests()
+}
+
+async function copyShareLink(requestID) {
+  try {
+    const response = await fetch(
+      `/api/pending-requests/${encodeURIComponent(requestID)}/share`,
+      { method: 'POST' },
+    )
+    const body = await response.json().catch(() => ({}))
+    if (!response.ok) {
+      throw new Error(body.error || 'Failed to create share link')
+    }
+    await navigator.clipboard.writeText(body.url)
+    alert('Read-only link copied. It expires in 24 hours.')
+  } catch (error) {
+    console.error('Error sharing request:', error)
+    alert(error.message)
+  }
+}
+
 // --- INITIALIZATION ---
 let serviceViewInitialized = false
 let externalViewInitialized = false
@@ -153,18 +198,27 @@ async function initializeEaFilters() {
 function selectivelyResetCaForm() {
   document.getElementById('ca-ports').value = ''
   document.getElementById('ca-description').value = ''
+  document.getElementById('ca-ticket').value = ''
+  document.getElementById('ca-previous').value = ''
+  document.getElementById('ca-priority').value = 'normal'
+  document.getElementById('ca-labels').value = ''
 }
 
 function selectivelyResetEaForm() {
   document.getElementById('ea-cidr').value = ''
   document.getElementById('ea-ports').value = ''
   document.getElementById('ea-description').value = ''
+  document.getElementById('ea-ticket').value = ''
+  document.getElementById('ea-previous').value = ''
+  document.getElementById('ea-priority').value = 'normal'
+  document.getElementById('ea-labels').value = ''
 }
 
 // --- MAIN EXECUTION & WEBSOCKET MANAGEMENT ---
 document.addEventListener('DOMContentLoaded', () => {
   let socket
   let pingTimeout
+  let sessionEnded = false
 
   function connect() {
     const wsProtocol = window.location.protocol === 'https:' ? 'wss:' : 'ws:'
@@ -196,6 +250,11 @@ document.addEventListener('DOMContentLoaded', () => {
     socket.onclose = (event) => {
       console.log('WebSocket connection closed.', event)
       clearTimeout(pingTimeout)
+      if (sessionEnded) {
+        // The session ended after inactivity, reconnecting would be refused.
+        window.location.href = '/'
+        return
+      }
       renderLogEntry({
         payload: 'Connection lost. Attempting to reconnect in 5 seconds...',
         className: 'log-warning',
@@ -209,16 +268,40 @@ document.addEventListener('DOMContentLoaded', () => {
     socket.onmessage = (event) => {
       heartbeat() // Reset the timeout on any incoming message
       const data = JSON.parse(event.data)
+      if (data.type === 'hello') {
+        return
+      }
+      if (data.type === 'commandError') {
+        renderLogEntry({
+          payload: `REQUEST REJECTED: ${data.message}`,
+          className: 'log-error',
+          logType: 'Global',
+        })
+        return
+      }
       renderLogEntry(data)
 
+      if (data.type === 'sessionExpired') {
+        sessionEnded = true
+        return
+      }
+      if (data.type === 'idleWarning') {
+        if (confirm(`${data.payload} Stay signed in?`)) {
+          socket.send(JSON.stringify({ command: 'keepAlive' }))
+        }
+        return
+      }
+
       const isApprovalOrDenial =
         data.payload.includes('approved') ||
         data.payload.includes('denied') ||
-        data.payload.includes('aborted')
+        data.payload.includes('aborted') ||
+        data.payload.startsWith('UPDATED:')
       const isCreationComplete = data.type === 'applyComplete'
-      const isRevocationInitiated = data.payload.includes(
-        'Revocation initiated',
-      )
+      const isRevocationInitiated =
+        data.payload.includes('Revocation initiated') ||
+        data.payload.includes('has been paused') ||
+        data.payload.includes('has been resumed')
       const isSuccessfulSubmission = data.payload.includes(
         'submitted for review',
       )
@@ -270,6 +353,8 @@ document.addEventListener('DOMContentLoaded', () => {
       initializeEaFilters,
       fetchAndDisplayActiveAccesses,
       fetchAndRenderPendingRequests,
+      uploadAttachment,
+      copyShareLink,
     })
   }
 
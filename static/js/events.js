@@ -1,6 +1,6 @@
 // This module sets up all event listeners for the application.
 
-import { showView, updateServiceDropdown } from './ui.js'
+import { parseLabels, showView, updateServiceDropdown } from './ui.js'
 
 // Get only the elements needed for attaching events.
 const elements = {
@@ -63,6 +63,9 @@ export function setupEventListeners(socket, handlers) {
         duration: parseInt(document.getElementById('ca-duration').value, 10),
         direction: document.getElementById('ca-direction').value,
         ports: document.getElementById('ca-ports').value,
+        description: document.getElementById('ca-description').value,
+        sessionBound:
+          document.getElementById('ca-session-bound').value === 'true',
       }),
     )
   })
@@ -83,6 +86,10 @@ export function setupEventListeners(socket, handlers) {
           direction: document.getElementById('ca-direction').value,
           ports: document.getElementById('ca-ports').value,
           description: document.getElementById('ca-description').value,
+          ticketRef: document.getElementById('ca-ticket').value,
+          previousRequestID: document.getElementById('ca-previous').value,
+          priority: document.getElementById('ca-priority').value,
+          labels: parseLabels(document.getElementById('ca-labels').value),
         }),
       )
     })
@@ -100,6 +107,9 @@ export function setupEventListeners(socket, handlers) {
         duration: parseInt(document.getElementById('ea-duration').value, 10),
         direction: document.getElementById('ea-direction').value,
         ports: document.getElementById('ea-ports').value,
+        description: document.getElementById('ea-description').value,
+        sessionBound:
+          document.getElementById('ea-session-bound').value === 'true',
       }),
     )
   })
@@ -120,6 +130,10 @@ export function setupEventListeners(socket, handlers) {
           direction: document.getElementById('ea-direction').value,
           ports: document.getElementById('ea-ports').value,
           description: document.getElementById('ea-description').value,
+          ticketRef: document.getElementById('ea-ticket').value,
+          previousRequestID: document.getElementById('ea-previous').value,
+          priority: document.getElementById('ea-priority').value,
+          labels: parseLabels(document.getElementById('ea-labels').value),
         }),
       )
     })
@@ -149,6 +163,36 @@ export function setupEventListeners(socket, handlers) {
       }
     }
 
+    const pauseBtn = event.target.closest('.pause-btn')
+    if (pauseBtn) {
+      if (
+        confirm(
+          'Pause this access? Traffic will be blocked until you resume it, the remaining time is kept.',
+        )
+      ) {
+        pauseBtn.disabled = true
+        socket.send(
+          JSON.stringify({
+            command: 'pauseAccess',
+            accessType: pauseBtn.dataset.type,
+            name: pauseBtn.dataset.name,
+            namespace: pauseBtn.dataset.namespace,
+          }),
+        )
+      }
+    }
+
+    const resumeBtn = event.target.closest('.resume-btn')
+    if (resumeBtn) {
+      resumeBtn.disabled = true
+      socket.send(
+        JSON.stringify({
+          command: 'resumeAccess',
+          requestID: resumeBtn.dataset.requestId,
+        }),
+      )
+    }
+
     const viewRequestBtn = event.target.closest('.view-request-btn')
     if (viewRequestBtn) {
       showView('access-request-hub-view')
@@ -157,36 +201,130 @@ export function setupEventListeners(socket, handlers) {
 
     const approveBtn = event.target.closest('.approve-btn')
     if (approveBtn) {
-      if (confirm('Are you sure you want to approve this access request?')) {
+      const comment = prompt(
+        'Approve this access request? You can leave a comment for the audit trail.',
+        '',
+      )
+      if (comment !== null) {
         approveBtn.disabled = true
         socket.send(
           JSON.stringify({
             command: 'approveAccessRequest',
             requestID: approveBtn.dataset.id,
+            comment: comment.trim(),
           }),
         )
       }
     }
 
+    // Approvers can only narrow a request: some of its ports, a shorter duration or a single direction.
+    const approveChangesBtn = event.target.closest('.approve-changes-btn')
+    if (approveChangesBtn) {
+      const { id, ports, duration, direction } = approveChangesBtn.dataset
+      const newPorts = prompt(
+        'Ports to keep, comma-separated (empty keeps them all):',
+        ports,
+      )
+      if (newPorts === null) return
+      const newDuration = prompt(
+        'Duration in minutes (empty keeps the requested one):',
+        duration > 0 ? duration / 60 : '',
+      )
+      if (newDuration === null) return
+      const newDirection = prompt(
+        'Direction: ingress, egress or all (empty keeps the requested one):',
+        direction,
+      )
+      if (newDirection === null) return
+      const comment = prompt('Comment for the audit trail (optional):', '')
+      if (comment === null) return
+      approveChangesBtn.disabled = true
+      socket.send(
+        JSON.stringify({
+          command: 'approveAccessRequest',
+          requestID: id,
+          ports: newPorts.trim() === ports ? '' : newPorts.trim(),
+          duration: Math.round(Number(newDuration || 0) * 60),
+          direction: newDirection.trim(),
+          comment: comment.trim(),
+        }),
+      )
+    }
+
+    // Requestors can change any of these, empty answers keep the current value.
+    const editRequestBtn = event.target.closest('.edit-request-btn')
+    if (editRequestBtn) {
+      const { id, ports, duration } = editRequestBtn.dataset
+      const newPorts = prompt(
+        'Ports, comma-separated (empty keeps the current ones):',
+        ports,
+      )
+      if (newPorts === null) return
+      const newDuration = prompt(
+        'Duration in minutes (empty keeps the current one):',
+        duration > 0 ? duration / 60 : '',
+      )
+      if (newDuration === null) return
+      const description = prompt(
+        'Description (empty keeps the current one):',
+        '',
+      )
+      if (description === null) return
+      editRequestBtn.disabled = true
+      socket.send(
+        JSON.stringify({
+          command: 'updateAccessRequest',
+          requestID: id,
+          ports: newPorts.trim() === ports ? '' : newPorts.trim(),
+          duration: Math.round(Number(newDuration || 0) * 60),
+          description: description.trim(),
+        }),
+      )
+    }
+
+    const attachBtn = event.target.closest('.attach-btn')
+    if (attachBtn) {
+      const input = document.createElement('input')
+      input.type = 'file'
+      input.addEventListener('change', () => {
+        if (input.files.length > 0) {
+          attachBtn.disabled = true
+          handlers.uploadAttachment(attachBtn.dataset.id, input.files[0])
+        }
+      })
+      input.click()
+    }
+
+    const shareBtn = event.target.closest('.share-btn')
+    if (shareBtn) {
+      handlers.copyShareLink(shareBtn.dataset.id)
+    }
+
     const denyBtn = event.target.closest('.deny-btn')
     if (denyBtn) {
-      const confirmText =
+      const promptText =
         denyBtn.textContent.trim() === 'Abort'
-          ? 'Are you sure you want to abort your access request?'
-          : 'Are you sure you want to deny this access request?'
+          ? 'Abort your access request? You can say why.'
+          : 'Deny this access request? Tell the requestor why.'
 
-      if (confirm(confirmText)) {
+      const reason = prompt(promptText, '')
+      if (reason !== null) {
         denyBtn.disabled = true
         socket.send(
           JSON.stringify({
             command: 'denyAccessRequest',
             requestID: denyBtn.dataset.id,
+            reason: reason.trim(),
           }),
         )
       }
     }
   })
 
+  document
+    .getElementById('hub-label-filter')
+    .addEventListener('change', handlers.fetchAndRenderPendingRequests)
+
   // --- Refresh Buttons ---
   elements.refreshServicesBtn.addEventListener(
     'click',
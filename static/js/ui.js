@@ -41,6 +41,9 @@ export function showView(viewId) {
 export function renderLogEntry(entry) {
   const newLog = document.createElement('div')
   newLog.textContent = entry.payload
+  if (entry.groups && entry.groups.length > 0) {
+    newLog.textContent += ` (for the approvers of ${entry.groups.join(', ')})`
+  }
   newLog.className = entry.className || 'log-info'
 
   elements.logDashboard.appendChild(newLog.cloneNode(true))
@@ -71,10 +74,16 @@ export function renderAccessList(container, accessData) {
   table.innerHTML = `<thead><tr><th style="width: 60%;">Access Details</th><th>Expires</th><th>Action</th></tr></thead><tbody></tbody>`
   const tbody = table.querySelector('tbody')
   accessData.forEach((access) => {
-    const expires =
+    let expires =
       access.expiresAt === -1
         ? 'Infinite'
         : new Date(access.expiresAt * 1000).toLocaleTimeString()
+    if (access.status === 'Paused') {
+      expires =
+        access.remaining > 0
+          ? `Paused (${Math.ceil(access.remaining / 60)} mins left)`
+          : 'Paused'
+    }
 
     let directionArrow = ''
     let detailsHtml = ''
@@ -127,12 +136,21 @@ export function renderAccessList(container, accessData) {
                     </div>
                     <div style="color: #5f6368; font-size: 0.8rem; margin-top: 4px;">Ports: ${portsDisplay}</div>`
     }
+    if (access.drift && access.drift.length > 0) {
+      detailsHtml += `<div style="color: var(--md-sys-color-error); font-size: 0.8rem; margin-top: 4px;" title="${access.drift.join('\n')}">⚠ Enforcement drift: ${access.drift.length} problem(s)</div>`
+    }
 
     let actionButtonHtml = ''
     if (access.status === 'Pending') {
       actionButtonHtml = `<button class="btn btn-filled btn-small view-request-btn">View Request</button>`
+    } else if (access.status === 'Paused') {
+      actionButtonHtml = `<button class="btn btn-filled btn-small resume-btn" data-request-id="${access.requestID}">Resume</button>`
     } else {
-      actionButtonHtml = `<button class="btn btn-filled btn-small revoke-btn" data-type="${access.type}" data-name="${access.name}" data-namespace="${access.namespace}">Revoke</button>`
+      actionButtonHtml = `
+                <div style="display: flex; flex-direction: column; gap: 8px;">
+                    <button class="btn btn-filled btn-small revoke-btn" data-type="${access.type}" data-name="${access.name}" data-namespace="${access.namespace}">Revoke</button>
+                    <button class="btn btn-text btn-small pause-btn" data-type="${access.type}" data-name="${access.name}" data-namespace="${access.namespace}">Pause</button>
+                </div>`
     }
 
     const row = document.createElement('tr')
@@ -148,6 +166,27 @@ export function renderAccessList(container, accessData) {
   container.appendChild(table)
 }
 
+// parseLabels turns 'team=payments, environment=prod' into an object.
+export function parseLabels(text) {
+  const labels = {}
+  text
+    .split(',')
+    .map((pair) => pair.trim())
+    .filter((pair) => pair.includes('='))
+    .forEach((pair) => {
+      const index = pair.indexOf('=')
+      labels[pair.slice(0, index).trim()] = pair.slice(index + 1).trim()
+    })
+  return labels
+}
+
+// formatAge tells how long a request has been waiting, in its largest unit.
+function formatAge(seconds) {
+  if (seconds >= 86400) return `${Math.floor(seconds / 86400)}d`
+  if (seconds >= 3600) return `${Math.floor(seconds / 3600)}h`
+  return `${Math.max(1, Math.floor(seconds / 60))}m`
+}
+
 export function renderPendingRequests(requests) {
   if (requests && requests.length > 0) {
     elements.pendingRequestsList.style.display = 'block'
@@ -185,6 +224,38 @@ export function renderPendingRequests(requests) {
         details += `<br><strong>Desc:</strong> ${req.description}`
       }
 
+      if (req.ticketRef) {
+        details += `<br><strong>Ticket:</strong> ${req.ticketRef}`
+      }
+
+      if (req.history && req.history.length > 0) {
+        const earlier = req.history
+          .map((d) => {
+            const when = new Date(d.decidedAt * 1000).toLocaleString()
+            const reason = d.reason ? `: ${d.reason}` : ''
+            return `${d.decision} ${d.decidedBy ? `by ${d.decidedBy} ` : ''}on ${when}${reason}`
+          })
+          .join('<br>')
+        details += `<br><strong>Resubmitted after:</strong><br><small>${earlier}</small>`
+      }
+
+      if (req.labels) {
+        const labels = Object.entries(req.labels)
+          .map(([key, value]) => `${key}=${value}`)
+          .join(', ')
+        details += `<br><strong>Labels:</strong> ${labels}`
+      }
+
+      if (req.attachments && req.attachments.length > 0) {
+        const links = req.attachments
+          .map(
+            (a) =>
+              `<a href="${a.url}" target="_blank" rel="noopener">${a.name}</a> <small>(${Math.ceil(a.size / 1024)} KB)</small>`,
+          )
+          .join(', ')
+        details += `<br><strong>Attachments:</strong> ${links}`
+      }
+
       let permissionsHtml = ''
       const permissionsList = []
 
@@ -233,43 +304,87 @@ export function renderPendingRequests(requests) {
                                     </div>`
       }
       details += permissionsHtml
+      if (req.permissionCheckFailed) {
+        details += `<div style="margin-top: 4px;"><small style="color: var(--log-color-warning);">Could not check your permissions for this request, self-approval status is unknown.</small></div>`
+      }
 
       let typeAndStatus = req.requestType
+      if (req.priority === 'urgent') {
+        typeAndStatus += `<br><small style="color: var(--log-color-error);"><strong>Urgent</strong></small>`
+      } else if (req.priority === 'low') {
+        typeAndStatus += `<br><small>Low priority</small>`
+      }
       if (req.status && req.status !== 'PendingFull') {
         const friendlyStatus = req.status.replace('Pending', 'Pending ')
         typeAndStatus += `<br><small style="color: var(--log-color-warning);">${friendlyStatus}</small>`
       }
+      // Overdue reviews stand out, the others just show how long they have been waiting.
+      if (req.slaBreached) {
+        typeAndStatus += `<br><small style="color: var(--log-color-error);"><strong>Overdue</strong>, waiting ${formatAge(req.age)}</small>`
+      } else {
+        typeAndStatus += `<br><small>Waiting ${formatAge(req.age)}</small>`
+      }
+      if (req.dualApproval) {
+        const sides = req.approvedSides || {}
+        typeAndStatus += ['source', 'target']
+          .map((side) =>
+            sides[side]
+              ? `<br><small title="${sides[side]}">${side} side approved</small>`
+              : `<br><small style="color: var(--log-color-warning);">Waiting for the ${side} side</small>`,
+          )
+          .join('')
+      }
+      if (req.requiredApprovals > 1) {
+        const approvedBy = req.approvedBy || []
+        typeAndStatus += `<br><small title="${approvedBy.join(', ')}">${approvedBy.length} of ${req.requiredApprovals} approvals</small>`
+      }
 
       let actionButtonsHtml = ''
       const isOwner =
         typeof currentUserEmail !== 'undefined' &&
-        currentUserEmail === req.requestor
+        (currentUserEmail === req.requestor ||
+          currentUserEmail === req.filedBy)
+      // Requestors can change their request until someone approves it, unless their side already exists.
+      const editBtnHtml =
+        req.status === 'PendingFull' && !(req.approvedBy || []).length
+          ? `<button class="btn btn-text btn-small edit-request-btn" data-id="${req.requestID}" data-ports="${req.ports || ''}" data-duration="${req.duration}">Edit request</button>`
+          : ''
 
       if (isOwner && req.canSelfApprove) {
         actionButtonsHtml = `
                 <div style="display: flex; flex-direction: column; gap: 8px;">
                     <button class="btn btn-filled btn-small approve-btn" data-id="${req.requestID}" style="--md-filled-button-container-height: 32px;">Approve</button>
                     <button class="btn btn-filled btn-small deny-btn" data-id="${req.requestID}" style="--md-filled-button-container-height: 32px;">Abort</button>
+                    ${editBtnHtml}
+                    <button class="btn btn-text btn-small attach-btn" data-id="${req.requestID}">Attach file</button>
+                    <button class="btn btn-text btn-small share-btn" data-id="${req.requestID}">Copy share link</button>
                 </div>
                 `
       } else if (isOwner) {
         actionButtonsHtml = `
-                <button class="btn btn-filled btn-small deny-btn" data-id="${req.requestID}" style="--md-filled-button-container-height: 32px;">
-                    Abort
-                </button>
+                <div style="display: flex; flex-direction: column; gap: 8px;">
+                    <button class="btn btn-filled btn-small deny-btn" data-id="${req.requestID}" style="--md-filled-button-container-height: 32px;">Abort</button>
+                    ${editBtnHtml}
+                    <button class="btn btn-text btn-small attach-btn" data-id="${req.requestID}">Attach file</button>
+                    <button class="btn btn-text btn-small share-btn" data-id="${req.requestID}">Copy share link</button>
+                </div>
                 `
       } else {
         actionButtonsHtml = `
                 <div style="display: flex; flex-direction: column; gap: 8px;">
                     <button class="btn btn-filled btn-small approve-btn" data-id="${req.requestID}" style="--md-filled-button-container-height: 32px;">Approve</button>
                     <button class="btn btn-filled btn-small deny-btn" data-id="${req.requestID}" style="--md-filled-button-container-height: 32px;">Deny</button>
+                    <button class="btn btn-text btn-small approve-changes-btn" data-id="${req.requestID}" data-ports="${req.ports || ''}" data-duration="${req.duration}" data-direction="${req.direction}">Approve with changes</button>
                 </div>
                 `
       }
 
       const row = document.createElement('tr')
+      if (req.slaBreached) {
+        row.style.borderLeft = '4px solid var(--log-color-error)'
+      }
       row.innerHTML = `
-                    <td>${req.requestor}</td>
+                    <td>${req.requestor}${req.filedBy ? `<br><small>filed by ${req.filedBy}</small>` : ''}</td>
                     <td>${typeAndStatus}</td>
                     <td>${details}<br><small><strong>Ports:</strong> ${req.ports || 'Default'}</small></td>
                     <td>${req.duration > 0 ? `${req.duration / 60} mins` : 'Infinite'}</td>